@@ -9,6 +9,8 @@ import (
 
 	"hepic-cli/internal/admin"
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/models"
 	"hepic-cli/internal/output"
 
 	"github.com/spf13/cobra"
@@ -69,10 +71,17 @@ var advancedCreateCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		dataStr, _ := cmd.Flags().GetString("data")
 
-		var data interface{}
+		var data map[string]interface{}
 		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
 			return fmt.Errorf("invalid JSON in --data: %w", err)
 		}
+		var spec models.GlobalSettingsStruct
+		if err := body.Decode(data, &spec); err != nil {
+			return err
+		}
+		if err := spec.Validate(); err != nil {
+			return err
+		}
 
 		client, err := api.NewClient()
 		if err != nil {
@@ -97,10 +106,17 @@ var advancedUpdateCmd = &cobra.Command{
 		uuid := args[0]
 		dataStr, _ := cmd.Flags().GetString("data")
 
-		var data interface{}
+		var data map[string]interface{}
 		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
 			return fmt.Errorf("invalid JSON in --data: %w", err)
 		}
+		var spec models.GlobalSettingsStruct
+		if err := body.Decode(data, &spec); err != nil {
+			return err
+		}
+		if err := spec.ValidatePartial(); err != nil {
+			return err
+		}
 
 		client, err := api.NewClient()
 		if err != nil {
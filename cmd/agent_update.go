@@ -1,11 +1,12 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"hepic-cli/internal/agent"
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/models"
 	"hepic-cli/internal/output"
 
 	"github.com/spf13/cobra"
@@ -16,11 +17,14 @@ var agentUpdateCmd = &cobra.Command{
 	Short: "Update an agent by UUID",
 	Long: `Update an existing capture agent with new configuration.
 
-The --data flag accepts a JSON string with the fields to update.
+The body is read from --file and/or --data (inline JSON, or @file) and only
+the given fields are sent. Field names and types are checked against the
+agent schema before the request is made.
 
 Examples:
   hepic agent update abc-123-def --data '{"host":"10.0.0.2","port":9061}'
-  hepic agent update abc-123-def --data '{"active":false}'`,
+  hepic agent update abc-123-def --data '{"active":false}'
+  hepic agent update abc-123-def -f agent.yaml`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAgentUpdate,
 }
@@ -28,17 +32,25 @@ Examples:
 func init() {
 	agentCmd.AddCommand(agentUpdateCmd)
 
-	agentUpdateCmd.Flags().String("data", "", "JSON data for the update (required)")
-	agentUpdateCmd.MarkFlagRequired("data")
+	addBodyFlags(agentUpdateCmd, "Fields to update")
 }
 
 func runAgentUpdate(cmd *cobra.Command, args []string) error {
 	uuid := args[0]
-	dataStr, _ := cmd.Flags().GetString("data")
+	data, err := readBody(cmd, true)
+	if err != nil {
+		return err
+	}
 
-	var data interface{}
-	if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-		return fmt.Errorf("invalid JSON in --data flag: %w", err)
+	var location models.AgentsLocation
+	if err := body.Decode(data, &location); err != nil {
+		return err
+	}
+	if location.UUID != "" && location.UUID != uuid {
+		return fmt.Errorf("body uuid %q does not match %q", location.UUID, uuid)
+	}
+	if err := agent.ValidateLocation(location); err != nil {
+		return err
 	}
 
 	client, err := api.NewClient()
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/apply"
+
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:     "apply",
+	Short:   "Apply declarative manifests of several resource kinds",
+	GroupID: "config",
+	Long: `Make the server match a set of YAML manifests. Each document declares
+one object:
+
+  apiVersion: hepic/v1      # optional
+  kind: Mapping
+  spec:
+    hepid: 1
+    profile: call
+    ...
+  ---
+  kind: Script
+  spec:
+    hepid: 1
+    profile: call
+    file: scripts/sip-call.lua   # relative to the manifest; or give data
+
+Kinds: ` + strings.Join(apply.Kinds, ", ") + `.
+
+Objects are matched to the server's by their natural key (hepid and
+profile for mappings, category and param for advanced settings, the LIID
+or search_ip, search_caller and search_callee for interceptions, ...).
+Only fields the manifest sets are compared. The plan is printed in a
+terraform-like form and applied in dependency order: mappings before the
+scripts and subscriptions that refer to them. With --prune, server objects
+of the kinds the manifests declare but that no manifest lists are deleted.
+
+Examples:
+  hepic apply -f manifests/ --dry-run
+  hepic apply -f manifests/
+  hepic apply -f mappings.yaml -f scripts.yaml --prune --force`,
+	RunE: runApply,
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringSliceP("file", "f", nil, "Manifest file or directory (repeatable, required)")
+	applyCmd.Flags().Bool("dry-run", false, "Print the plan without applying it")
+	applyCmd.Flags().Bool("prune", false, "Delete server objects of the declared kinds that are not in the manifests")
+	applyCmd.Flags().Bool("force", false, "Skip confirmation prompt")
+	applyCmd.MarkFlagRequired("file")
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	paths, _ := cmd.Flags().GetStringSlice("file")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	prune, _ := cmd.Flags().GetBool("prune")
+	force, _ := cmd.Flags().GetBool("force")
+
+	objs, err := apply.Load(paths)
+	if err != nil {
+		return err
+	}
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	current, err := apply.Fetch(cmd.Context(), client, objs)
+	if err != nil {
+		return err
+	}
+	changes, err := apply.Plan(objs, current, prune)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		apply.WritePlan(os.Stdout, changes)
+		return nil
+	}
+	apply.WritePlan(os.Stderr, changes)
+	if len(changes) == 0 {
+		return nil
+	}
+	if !force && !confirmAction(fmt.Sprintf("Apply %d change(s)?", len(changes))) {
+		return fmt.Errorf("operation cancelled")
+	}
+
+	return applyChanges(changes, func(c *apply.Change) (string, error) {
+		return changeOK, apply.Apply(cmd.Context(), client, *c)
+	})
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/backup"
+	"hepic-cli/internal/output"
+	"hepic-cli/internal/resource"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var backupCmd = &cobra.Command{
+	Use:     "backup",
+	Short:   "Back up and restore platform configuration",
+	GroupID: "admin",
+	Long: `Snapshot a HEPIC instance's configuration into a tar.gz archive and
+restore it, for example before an upgrade or to copy settings between
+instances.
+
+Resources: ` + strings.Join(resource.Names(), ", ") + `.`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Write the platform configuration to a backup archive",
+	Long: `Collect the configuration of every resource (or those selected with
+--only and --exclude) into a versioned tar.gz archive with a manifest.
+User passwords and other secrets are not included.
+
+Examples:
+  hepic backup create -o backup.tar.gz
+  hepic backup create -o aliases.tar.gz --only ipaliases,mappings`,
+	RunE: runBackupCreate,
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore platform configuration from a backup archive",
+	Long: `Restore a backup made with "hepic backup create".
+
+Items are matched to the server's by a natural key (hepid and profile for
+mappings, username for users, ...). Missing items are created and equal
+ones left alone. Items that exist with different content are handled by
+--conflict:
+
+  skip        leave the server's version (default)
+  overwrite   replace it with the backed up version
+  rename      create a copy with a "-restored" suffix (users, dashboards)
+
+Protocols are read-only and agents can only be updated. Restored users get
+a random password that has to be reset.
+
+Examples:
+  hepic backup restore backup.tar.gz --dry-run --format table
+  hepic backup restore backup.tar.gz --only scripts,hepsubs --conflict overwrite
+  hepic backup restore backup.tar.gz --exclude users --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBackupRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCreateCmd.Flags().StringP("output", "o", "", "Archive file to write (required)")
+	backupCreateCmd.Flags().StringSlice("only", nil, "Only back up these resources (comma-separated)")
+	backupCreateCmd.Flags().StringSlice("exclude", nil, "Skip these resources (comma-separated)")
+	backupCreateCmd.MarkFlagRequired("output")
+
+	backupCmd.AddCommand(backupRestoreCmd)
+	backupRestoreCmd.Flags().StringSlice("only", nil, "Only restore these resources (comma-separated)")
+	backupRestoreCmd.Flags().StringSlice("exclude", nil, "Skip these resources (comma-separated)")
+	backupRestoreCmd.Flags().String("conflict", backup.PolicySkip, "How to handle items that exist with different content: "+strings.Join(backup.Policies, ", "))
+	backupRestoreCmd.Flags().Bool("dry-run", false, "Show the plan without applying it")
+	backupRestoreCmd.Flags().Bool("force", false, "Skip confirmation prompt")
+}
+
+func runBackupCreate(cmd *cobra.Command, args []string) error {
+	out, _ := cmd.Flags().GetString("output")
+	only, _ := cmd.Flags().GetStringSlice("only")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+
+	resources, err := resource.Select(only, exclude)
+	if err != nil {
+		return err
+	}
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	b, err := backup.Collect(cmd.Context(), client, resources, viper.GetString("host"), Version)
+	if err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	if err := backup.WriteFile(out, b); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	fmt.Fprintf(os.Stderr, "Backup written to %s\n", out)
+	return output.Print(b.Manifest.Resources)
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	only, _ := cmd.Flags().GetStringSlice("only")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+	policy, _ := cmd.Flags().GetString("conflict")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	b, err := backup.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	resources, err := resource.Select(only, exclude)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Backup of %s taken %s (hepic-cli %s)\n", b.Manifest.Host, b.Manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"), b.Manifest.CLIVersion)
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]resource.Resource, len(resources))
+	var changes []backup.Change
+	for _, r := range resources {
+		items, ok := b.Items[r.Name]
+		if !ok {
+			if len(only) > 0 {
+				fmt.Fprintf(os.Stderr, "Warning: the backup does not contain %s\n", r.Name)
+			}
+			continue
+		}
+		byName[r.Name] = r
+		current, err := r.List(cmd.Context(), client)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", r.Name, err)
+		}
+		planned, err := backup.Plan(r, items, current, policy)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, planned...)
+	}
+
+	pending := 0
+	for _, c := range changes {
+		if c.Action != backup.ActionSkip {
+			pending++
+		}
+	}
+	if dryRun || pending == 0 {
+		if changes == nil {
+			changes = []backup.Change{}
+		}
+		return output.Print(changes)
+	}
+	if !force && !confirmAction(fmt.Sprintf("Apply %d change(s) to %s?", pending, viper.GetString("host"))) {
+		return fmt.Errorf("operation cancelled")
+	}
+
+	return applyChanges(changes, func(c *backup.Change) (string, error) {
+		if c.Action == backup.ActionSkip {
+			return changeSkipped, nil
+		}
+		note, err := backup.Apply(cmd.Context(), client, byName[c.Resource], *c)
+		if note != "" {
+			c.Details = strings.TrimPrefix(c.Details+"; "+note, "; ")
+		}
+		return changeOK, err
+	})
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/config_resources"
+
+	"github.com/spf13/cobra"
+)
+
+// addBodyFlags registers --data and -f/--file for a command that sends a
+// request body.
+func addBodyFlags(cmd *cobra.Command, what string) {
+	cmd.Flags().String("data", "", what+" as JSON, or @file to read a JSON or YAML file")
+	cmd.Flags().StringP("file", "f", "", what+" from a YAML or JSON file")
+}
+
+// readBody returns the request body from -f/--file and --data, with keys
+// in --data overriding the file. It errors if required and neither is set.
+func readBody(cmd *cobra.Command, required bool) (map[string]interface{}, error) {
+	file, _ := cmd.Flags().GetString("file")
+	data, _ := cmd.Flags().GetString("data")
+	if required && file == "" && data == "" {
+		return nil, fmt.Errorf("--data or --file is required")
+	}
+
+	var m map[string]interface{}
+	if file != "" {
+		fromFile, err := body.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		m = body.Merge(m, fromFile)
+	}
+	if data != "" {
+		fromData, err := body.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		m = body.Merge(m, fromData)
+	}
+	if m == nil {
+		m = make(map[string]interface{})
+	}
+	return m, nil
+}
+
+// setFromFlags copies the changed flags into the body under the given keys,
+// so explicit flags win over --data and --file.
+func setFromFlags(cmd *cobra.Command, m map[string]interface{}, keys map[string]string) {
+	for flag, key := range keys {
+		if !cmd.Flags().Changed(flag) {
+			continue
+		}
+		f := cmd.Flags().Lookup(flag)
+		switch f.Value.Type() {
+		case "int":
+			v, _ := cmd.Flags().GetInt(flag)
+			m[key] = v
+		case "bool":
+			v, _ := cmd.Flags().GetBool(flag)
+			m[key] = v
+		default:
+			m[key] = f.Value.String()
+		}
+	}
+}
+
+// checkProtocol verifies a hepid/profile combination against the server's
+// protocol list. Failing to load the list is only a warning.
+func checkProtocol(cmd *cobra.Command, client *api.Client, hepid uint16, profile string) error {
+	protocols, err := config_resources.ListProtocols(cmd.Context(), client)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot check hepid/profile: %v\n", err)
+		return nil
+	}
+	return config_resources.CheckProtocol(protocols, hepid, profile)
+}
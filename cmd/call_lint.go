@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/call"
+	"hepic-cli/internal/output"
+	"hepic-cli/internal/sip"
+	"hepic-cli/internal/siplint"
+
+	"github.com/spf13/cobra"
+)
+
+var callLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check captured SIP dialogs for protocol violations",
+	Long: `Check captured SIP dialogs against a set of RFC compliance rules.
+
+Messages are fetched from HEPIC by Call-ID, or read from a local text dump
+or PCAP file with --file. Each finding names the rule, its severity, the
+offending message and the RFC section it refers to.
+
+The command exits non-zero when any finding reaches the --fail-on severity,
+so it can be used as a CI check.
+
+Examples:
+  hepic call lint --call-id "abc123" --from 2025-01-01
+  hepic call lint --file call.pcap --format table
+  hepic call lint --file messages.txt --rules cseq-order,missing-ack
+  hepic call lint --call-id "abc123" --from 2025-01-01 --disable session-timer --fail-on warning
+  hepic call lint --list-rules`,
+	RunE: runCallLint,
+}
+
+func init() {
+	callCmd.AddCommand(callLintCmd)
+
+	callLintCmd.Flags().String("call-id", "", "SIP Call-ID to fetch from HEPIC")
+	callLintCmd.Flags().String("from", "", "Start time (RFC3339 or YYYY-MM-DD, required with --call-id)")
+	callLintCmd.Flags().String("to", "", "End time (RFC3339 or YYYY-MM-DD, default: now)")
+	callLintCmd.Flags().String("file", "", "Read messages from a text dump or PCAP file instead of the API")
+	callLintCmd.Flags().StringSlice("rules", nil, "Only run these rules (comma-separated rule IDs)")
+	callLintCmd.Flags().StringSlice("disable", nil, "Skip these rules (comma-separated rule IDs)")
+	callLintCmd.Flags().String("fail-on", "error", "Exit non-zero on findings of this severity or higher: info, warning, error, none")
+	callLintCmd.Flags().Bool("list-rules", false, "List available rules and exit")
+
+	callLintCmd.MarkFlagsMutuallyExclusive("call-id", "file")
+}
+
+func runCallLint(cmd *cobra.Command, args []string) error {
+	listRules, _ := cmd.Flags().GetBool("list-rules")
+	if listRules {
+		return output.Print(siplint.Rules())
+	}
+
+	only, _ := cmd.Flags().GetStringSlice("rules")
+	disable, _ := cmd.Flags().GetStringSlice("disable")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+
+	rules, err := siplint.Select(only, disable)
+	if err != nil {
+		return err
+	}
+
+	var threshold siplint.Severity
+	if failOn != "none" {
+		threshold, err = siplint.ParseSeverity(failOn)
+		if err != nil {
+			return fmt.Errorf("invalid --fail-on value: %w", err)
+		}
+	}
+
+	msgs, err := loadSIPMessages(cmd)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return fmt.Errorf("no SIP messages found")
+	}
+
+	findings := siplint.Lint(msgs, rules)
+	if err := output.Print(findings); err != nil {
+		return err
+	}
+
+	if failOn != "none" {
+		if n := siplint.CountAtLeast(findings, threshold); n > 0 {
+			return fmt.Errorf("%d finding(s) at severity %s or higher", n, threshold)
+		}
+	}
+	return nil
+}
+
+// loadSIPMessages reads messages from --file or fetches them by --call-id.
+func loadSIPMessages(cmd *cobra.Command) ([]*sip.Message, error) {
+	file, _ := cmd.Flags().GetString("file")
+	if file != "" {
+		msgs, err := sip.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		sip.SortByTime(msgs)
+		return msgs, nil
+	}
+
+	callID, _ := cmd.Flags().GetString("call-id")
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	if callID == "" {
+		return nil, fmt.Errorf("either --call-id or --file is required")
+	}
+	if from == "" {
+		return nil, fmt.Errorf("--from is required with --call-id")
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := call.NewSearchParams(from, to, "", "", callID)
+	if err != nil {
+		return nil, err
+	}
+
+	return call.FetchMessages(cmd.Context(), client, params)
+}
@@ -17,7 +17,8 @@ Examples:
   hepic call search --from 2025-01-01 --to 2025-01-31
   hepic call search --from 2025-01-01 --caller "+49123"
   hepic call search --from 2025-01-01 --callee "+49456" --format table
-  hepic call search --from 2025-01-01 --call-id "abc123"`,
+  hepic call search --from 2025-01-01 --call-id "abc123"
+  hepic call search --from 2025-01-01 --resolve-aliases --format table`,
 	RunE: runCallSearch,
 }
 
@@ -55,5 +56,21 @@ func runCallSearch(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	aliases, err := resolveAliases(cmd, client)
+	if err != nil {
+		return err
+	}
+	if aliases != nil {
+		for i := range result.Data {
+			e := &result.Data[i]
+			if name := aliases.Name(e.SrcIP, int(e.SrcPort)); name != "" {
+				e.AliasSrc = name
+			}
+			if name := aliases.Name(e.DstIP, int(e.DstPort)); name != "" {
+				e.AliasDst = name
+			}
+		}
+	}
+
 	return output.Print(result)
 }
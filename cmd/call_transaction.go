@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"hepic-cli/internal/alias"
 	"hepic-cli/internal/api"
 	"hepic-cli/internal/call"
 	"hepic-cli/internal/output"
@@ -15,7 +16,8 @@ var callTransactionCmd = &cobra.Command{
 
 Examples:
   hepic call transaction --call-id "abc123" --from 2025-01-01
-  hepic call transaction --call-id "abc123" --from 2025-01-01 --to 2025-01-31`,
+  hepic call transaction --call-id "abc123" --from 2025-01-01 --to 2025-01-31
+  hepic call transaction --call-id "abc123" --from 2025-01-01 --resolve-aliases`,
 	RunE: runCallTransaction,
 }
 
@@ -50,5 +52,25 @@ func runCallTransaction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	aliases, err := resolveAliases(cmd, client)
+	if err != nil {
+		return err
+	}
+	if aliases != nil {
+		for _, row := range result.Data {
+			annotateRow(aliases, row, "srcIp", "srcPort", "aliasSrc")
+			annotateRow(aliases, row, "dstIp", "dstPort", "aliasDst")
+		}
+	}
+
 	return output.Print(result)
 }
+
+// annotateRow sets row[aliasKey] to the alias of the row's IP and port.
+func annotateRow(aliases *alias.Table, row map[string]interface{}, ipKey, portKey, aliasKey string) {
+	ip, _ := row[ipKey].(string)
+	port, _ := row[portKey].(float64)
+	if name := aliases.Name(ip, int(port)); name != "" {
+		row[aliasKey] = name
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"hepic-cli/internal/output"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Statuses of an applied change.
+const (
+	changeOK      = "ok"
+	changeSkipped = "skipped"
+	changeFailed  = "failed"
+)
+
+// changeResult is the outcome of one applied change. It marshals as the
+// change's own fields followed by status and error.
+type changeResult struct {
+	change interface{}
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (r changeResult) MarshalJSON() ([]byte, error) {
+	change, err := json.Marshal(r.change)
+	if err != nil {
+		return nil, err
+	}
+	type status changeResult
+	result, err := json.Marshal(status(r))
+	if err != nil {
+		return nil, err
+	}
+	change = bytes.TrimSuffix(change, []byte("}"))
+	if len(change) > 1 {
+		change = append(change, ',')
+	}
+	return append(change, result[1:]...), nil
+}
+
+// MarshalYAML keeps the JSON field names and order.
+func (r changeResult) MarshalYAML() (interface{}, error) {
+	data, err := r.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	plainStyle(&node)
+	return node.Content[0], nil
+}
+
+// plainStyle drops the JSON flow style and quoting yaml keeps from the
+// source.
+func plainStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		plainStyle(n)
+	}
+}
+
+// applyChanges applies changes in order, then prints a result for each.
+// apply returns the status of a change, changeOK or changeSkipped, and may
+// add to its details. The error reports how many changes failed.
+func applyChanges[C any](changes []C, apply func(c *C) (string, error)) error {
+	results := make([]changeResult, 0, len(changes))
+	applied, failed := 0, 0
+	for i := range changes {
+		c := &changes[i]
+		status, err := apply(c)
+		r := changeResult{change: c, Status: status}
+		if err != nil {
+			r.Status = changeFailed
+			r.Error = err.Error()
+			failed++
+		}
+		if r.Status != changeSkipped {
+			applied++
+		}
+		results = append(results, r)
+	}
+
+	if err := output.Print(results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d change(s) failed", failed, applied)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+)
+
+func TestChangeResultMarshal(t *testing.T) {
+	type change struct {
+		Action string `json:"action"`
+		Key    string `json:"key"`
+		Body   string `json:"-"`
+	}
+	r := changeResult{change: &change{Action: "create", Key: "a", Body: "x"}, Status: changeFailed, Error: "boom"}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"action":"create","key":"a","status":"failed","error":"boom"}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+
+	data, err = yaml.Marshal(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "action: create\nkey: a\nstatus: failed\nerror: boom\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+
+	data, _ = json.Marshal(changeResult{change: struct{}{}, Status: changeOK})
+	if want := `{"status":"ok"}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
@@ -1,46 +1,203 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
-	"hepic-cli/internal/admin"
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/clickhouse"
+	"hepic-cli/internal/config"
 	"hepic-cli/internal/output"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var clickhouseCmd = &cobra.Command{
 	Use:     "clickhouse",
 	Short:   "ClickHouse database operations",
-	Long:    "Execute queries against the ClickHouse database backend.",
+	Long:    "Execute queries against the ClickHouse database backend, interactively or from a library of saved queries.",
 	GroupID: "admin",
 }
 
+// clickhouseParamsHelp describes query parameters for query, run and repl.
+const clickhouseParamsHelp = `Queries take ClickHouse parameters of the form {name:Type}, set with
+--param name=value. Values are inserted as literals of their type, so they
+cannot change the query: strings are quoted, numbers checked, identifiers
+backquoted, and Date, DateTime and DateTime64 values accept times such as
+now-1h, RFC3339 or YYYY-MM-DD. A comment line "-- param name=value" in the
+query sets a default.
+
+Results are printed in query column order with --format json, yaml, table,
+csv or ndjson.`
+
 var clickhouseQueryCmd = &cobra.Command{
-	Use:   "query <sql>",
+	Use:   "query [sql]",
 	Short: "Execute a raw ClickHouse query",
 	Long: `Execute a raw SQL query against the ClickHouse database and return the results.
 
-The query is provided as a positional argument.
+The query is provided as a positional argument or read from a file with -f
+("-" reads stdin).
+
+` + clickhouseParamsHelp + `
 
 Examples:
   hepic clickhouse query "SELECT count() FROM hep"
-  hepic clickhouse query "SELECT * FROM hep LIMIT 10"`,
-	Args: cobra.ExactArgs(1),
+  hepic clickhouse query "SELECT * FROM hep LIMIT 10" --format table
+  hepic clickhouse query -f slow-invites.sql --param from=now-1h --param callid=abc@host --format csv
+  hepic clickhouse query "SELECT * FROM hep WHERE sid = {callid:String}" --param callid=abc@host --dry-run`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{formatsAnnotation: "csv,ndjson"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		query := args[0]
+		file, _ := cmd.Flags().GetString("file")
+		if (file == "") == (len(args) == 0) {
+			return fmt.Errorf("specify either a query or --file")
+		}
+
+		sql := ""
+		if file != "" {
+			data, err := readQueryFile(file)
+			if err != nil {
+				return err
+			}
+			sql = data
+		} else {
+			sql = args[0]
+		}
+
+		return runClickhouse(cmd, sql)
+	},
+}
+
+var clickhouseRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a saved query",
+	Long: `Run a query from the library of saved queries in ~/.hepic/queries.
 
-		client, err := api.NewClient()
+` + clickhouseParamsHelp + `
+
+Examples:
+  hepic clickhouse run slow-invites
+  hepic clickhouse run slow-invites --param from=now-6h --format table`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSavedQueries,
+	Annotations:       map[string]string{formatsAnnotation: "csv,ndjson"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		library, err := clickhouseLibrary()
 		if err != nil {
 			return err
 		}
+		saved, err := library.Load(args[0])
+		if err != nil {
+			return err
+		}
+		return runClickhouse(cmd, saved.SQL)
+	},
+}
+
+var clickhouseSavedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: "Manage saved queries",
+	Long: `Manage the library of saved queries in ~/.hepic/queries, one <name>.sql
+file each. The first comment line of a query is its description.`,
+}
 
-		result, err := admin.ClickhouseQuery(cmd.Context(), client, query)
+var clickhouseSavedListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved queries",
+	Long:  "List the saved queries with their descriptions and parameters.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		library, err := clickhouseLibrary()
+		if err != nil {
+			return err
+		}
+		saved, err := library.List()
 		if err != nil {
 			return err
 		}
+		return output.Print(saved)
+	},
+}
 
-		return output.Print(result)
+var clickhouseSavedShowCmd = &cobra.Command{
+	Use:               "show <name>",
+	Short:             "Print a saved query",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSavedQueries,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		library, err := clickhouseLibrary()
+		if err != nil {
+			return err
+		}
+		saved, err := library.Load(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Print(strings.TrimRight(saved.SQL, "\n") + "\n")
+		return nil
+	},
+}
+
+var clickhouseSavedSaveCmd = &cobra.Command{
+	Use:   "save <name> [sql]",
+	Short: "Save a query",
+	Long: `Save a query given as an argument or read from a file with -f. An
+existing query of the same name is only replaced with --force.
+
+Examples:
+  hepic clickhouse saved save slow-invites -f slow-invites.sql
+  hepic clickhouse saved save hep-count "SELECT count() FROM hep WHERE create_date > {from:DateTime}"`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		force, _ := cmd.Flags().GetBool("force")
+		if (file == "") == (len(args) == 1) {
+			return fmt.Errorf("specify either a query or --file")
+		}
+
+		sql := ""
+		if file != "" {
+			data, err := readQueryFile(file)
+			if err != nil {
+				return err
+			}
+			sql = data
+		} else {
+			sql = args[1]
+		}
+
+		library, err := clickhouseLibrary()
+		if err != nil {
+			return err
+		}
+		if _, err := library.Load(args[0]); err == nil && !force {
+			return fmt.Errorf("saved query %q exists; use --force to replace it", args[0])
+		}
+		if err := library.Save(args[0], sql); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Saved query %s\n", args[0])
+		return nil
+	},
+}
+
+var clickhouseSavedDeleteCmd = &cobra.Command{
+	Use:               "delete <name>",
+	Short:             "Delete a saved query",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSavedQueries,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		library, err := clickhouseLibrary()
+		if err != nil {
+			return err
+		}
+		return library.Delete(args[0])
 	},
 }
 
@@ -48,4 +205,139 @@ func init() {
 	rootCmd.AddCommand(clickhouseCmd)
 
 	clickhouseCmd.AddCommand(clickhouseQueryCmd)
+	clickhouseQueryCmd.Flags().StringP("file", "f", "", `Read the query from a file ("-" for stdin)`)
+
+	clickhouseCmd.AddCommand(clickhouseRunCmd)
+
+	for _, c := range []*cobra.Command{clickhouseQueryCmd, clickhouseRunCmd} {
+		c.Flags().StringArray("param", nil, "Query parameter as name=value (repeatable)")
+		c.Flags().Bool("dry-run", false, "Print the query with its parameters bound instead of running it")
+		c.Flags().Int("width", 60, "Cut table cells to this width; 0 keeps them whole")
+	}
+
+	clickhouseCmd.AddCommand(clickhouseSavedCmd)
+	clickhouseSavedCmd.AddCommand(clickhouseSavedListCmd)
+	clickhouseSavedCmd.AddCommand(clickhouseSavedShowCmd)
+	clickhouseSavedCmd.AddCommand(clickhouseSavedSaveCmd)
+	clickhouseSavedSaveCmd.Flags().StringP("file", "f", "", `Read the query from a file ("-" for stdin)`)
+	clickhouseSavedSaveCmd.Flags().Bool("force", false, "Replace a saved query of the same name")
+	clickhouseSavedCmd.AddCommand(clickhouseSavedDeleteCmd)
+}
+
+// runClickhouse binds the --param flags to sql, runs it and prints the
+// result.
+func runClickhouse(cmd *cobra.Command, sql string) error {
+	paramFlags, _ := cmd.Flags().GetStringArray("param")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	width, _ := cmd.Flags().GetInt("width")
+
+	params, err := clickhouse.ParseParams(paramFlags)
+	if err != nil {
+		return err
+	}
+	bound, err := bindClickhouse(sql, params)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Println(bound)
+		return nil
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+
+	raw, err := clickhouse.Run(cmd.Context(), client, bound)
+	if err != nil {
+		return err
+	}
+	result, err := clickhouse.ParseResult(raw)
+	if err != nil {
+		return err
+	}
+	return printClickhouse(viper.GetString("format"), result, width)
+}
+
+// bindClickhouse binds params, and the defaults declared in sql for the
+// parameters not given, to sql.
+func bindClickhouse(sql string, params map[string]string) (string, error) {
+	all := make(map[string]string)
+	defaults := clickhouse.Defaults(sql)
+	for _, name := range clickhouse.Placeholders(sql) {
+		if v, ok := defaults[name]; ok {
+			all[name] = v
+		}
+	}
+	for k, v := range params {
+		all[k] = v
+	}
+	return clickhouse.Bind(sql, all, time.Now())
+}
+
+// printClickhouse prints a query result in column order.
+func printClickhouse(format string, result *clickhouse.Result, width int) error {
+	switch format {
+	case "table":
+		return result.WriteTable(os.Stdout, width)
+	case "csv":
+		return result.WriteCSV(os.Stdout)
+	case "ndjson":
+		return result.WriteNDJSON(os.Stdout)
+	}
+	rows := result.Rows
+	if rows == nil {
+		rows = []clickhouse.Row{}
+	}
+	if format != viper.GetString("format") {
+		return output.Fprint(os.Stdout, format, rows)
+	}
+	return output.Print(rows)
+}
+
+// readQueryFile reads a query from a file, or from stdin for "-".
+func readQueryFile(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read query: %w", err)
+	}
+	return string(data), nil
+}
+
+// clickhouseLibrary returns the library of saved queries.
+func clickhouseLibrary() (clickhouse.Library, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return clickhouse.Library{}, err
+	}
+	return clickhouse.Library{Dir: filepath.Join(dir, "queries")}, nil
+}
+
+// completeSavedQueries completes the names of saved queries, described
+// by their descriptions.
+func completeSavedQueries(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	library, err := clickhouseLibrary()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	saved, err := library.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names := make([]string, 0, len(saved))
+	for _, s := range saved {
+		names = append(names, s.Name+"\t"+s.Description)
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
 }
@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/clickhouse"
+	"hepic-cli/internal/config"
+	"hepic-cli/internal/output"
+	"hepic-cli/internal/shell"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// clickhouseFormats are the output formats of ClickHouse results.
+var clickhouseFormats = []string{"json", "yaml", "table", "csv", "ndjson"}
+
+// clickhouseMeta are the REPL's backslash commands.
+var clickhouseMeta = []string{`\q`, `\h`, `\format`, `\set`, `\unset`, `\l`, `\run`, `\save`, `\show`}
+
+// clickhouseReplHelp is the long help of the command and of \h.
+const clickhouseReplHelp = `Start an interactive ClickHouse session. A statement may span several lines
+and runs when it ends with ";". History is kept in ~/.hepic/clickhouse_history,
+one statement per line. Ctrl-C discards the statement being typed or cancels
+a running query; Ctrl-D exits.
+
+Commands:
+  \set                 list parameters
+  \set <name> <value>  set a parameter for the {name:Type} placeholders
+  \unset <name>        remove a parameter
+  \format [format]     show or set the output format (json, yaml, table, csv, ndjson)
+  \l                   list saved queries
+  \show <name>         print a saved query
+  \run <name>          run a saved query
+  \save <name>         save the previous statement
+  \h                   show this help
+  \q                   exit
+
+Parameters set with \set apply to the statements that use them; a comment
+line "-- param name=value" in a statement sets a default. Results are
+printed as tables unless --format is given.
+
+Without a terminal, statements are read from stdin.
+
+Examples:
+  hepic clickhouse repl
+  clickhouse> \set from now-1h
+  clickhouse> SELECT sid, count() AS n FROM hep
+          ->  WHERE create_date > {from:DateTime}
+          ->  GROUP BY sid ORDER BY n DESC LIMIT 10;
+  clickhouse> \save top-calls
+  echo "SELECT count() FROM hep;" | hepic clickhouse repl --format csv`
+
+var clickhouseReplCmd = &cobra.Command{
+	Use:         "repl",
+	Short:       "Start an interactive ClickHouse session",
+	Long:        clickhouseReplHelp,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{formatsAnnotation: "csv,ndjson"},
+	RunE:        runClickhouseRepl,
+}
+
+func init() {
+	clickhouseCmd.AddCommand(clickhouseReplCmd)
+	clickhouseReplCmd.Flags().Int("width", 60, "Cut table cells to this width; 0 keeps them whole")
+}
+
+// clickhouseSession is the state of a REPL.
+type clickhouseSession struct {
+	client  *api.Client
+	library clickhouse.Library
+	format  string
+	width   int
+	params  map[string]string
+	// last is the previous statement, for \save.
+	last string
+}
+
+func runClickhouseRepl(cmd *cobra.Command, args []string) error {
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	library, err := clickhouseLibrary()
+	if err != nil {
+		return err
+	}
+	width, _ := cmd.Flags().GetInt("width")
+	session := &clickhouseSession{
+		client:  client,
+		library: library,
+		format:  "table",
+		width:   width,
+		params:  make(map[string]string),
+	}
+	if cmd.Flags().Changed("format") {
+		session.format = viper.GetString("format")
+	}
+
+	ctx := cmd.Context()
+	fd := int(os.Stdin.Fd())
+	interactive := term.IsTerminal(fd)
+
+	var history []string
+	historyPath := ""
+	if interactive {
+		if dir, err := config.ConfigDir(); err == nil {
+			historyPath = filepath.Join(dir, "clickhouse_history")
+			history = loadHistory(historyPath)
+		}
+	}
+	editor := &shell.Editor{
+		History:  history,
+		Complete: session.complete,
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	var statement strings.Builder
+	for {
+		editor.Prompt = "clickhouse> "
+		if statement.Len() > 0 {
+			editor.Prompt = "         -> "
+		}
+
+		var line string
+		var err error
+		if interactive {
+			line, err = readShellLine(fd, editor, in)
+		} else {
+			line, err = in.ReadString('\n')
+			if errors.Is(err, io.EOF) && line != "" {
+				err = nil
+			}
+		}
+		if errors.Is(err, shell.ErrInterrupted) {
+			statement.Reset()
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			// Run a statement stdin ends without a semicolon.
+			if !interactive && strings.TrimSpace(statement.String()) != "" {
+				return session.run(ctx, statement.String())
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if statement.Len() == 0 {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" {
+				continue
+			}
+			if strings.HasPrefix(trimmed, `\`) {
+				if interactive {
+					editor.History = addHistory(editor.History, historyPath, trimmed)
+				}
+				done, err := session.meta(ctx, strings.Fields(trimmed))
+				if err != nil {
+					output.PrintError(err)
+				}
+				if done {
+					return nil
+				}
+				continue
+			}
+		}
+
+		statement.WriteString(line)
+		statement.WriteByte('\n')
+		if !clickhouse.Complete(statement.String()) {
+			continue
+		}
+		sql := statement.String()
+		statement.Reset()
+		if interactive {
+			editor.History = addHistory(editor.History, historyPath, clickhouse.OneLine(sql))
+		}
+		if err := session.run(ctx, sql); err != nil {
+			output.PrintError(err)
+		}
+	}
+}
+
+// addHistory appends line to the history unless it repeats the last
+// entry.
+func addHistory(history []string, path, line string) []string {
+	if line == "" || (len(history) > 0 && history[len(history)-1] == line) {
+		return history
+	}
+	appendHistory(path, line)
+	return append(history, line)
+}
+
+// run binds the session parameters a statement uses, runs it and prints
+// the result, with the row count and time taken on stderr. Ctrl-C cancels
+// the query rather than the session.
+func (s *clickhouseSession) run(ctx context.Context, sql string) error {
+	sql = strings.TrimSpace(sql)
+	s.last = sql
+
+	params := make(map[string]string)
+	for _, name := range clickhouse.Placeholders(sql) {
+		if v, ok := s.params[name]; ok {
+			params[name] = v
+		}
+	}
+	bound, err := bindClickhouse(sql, params)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	start := time.Now()
+	raw, err := clickhouse.Run(ctx, s.client, bound)
+	if err != nil {
+		return err
+	}
+	result, err := clickhouse.ParseResult(raw)
+	if err != nil {
+		return err
+	}
+	if err := printClickhouse(s.format, result, s.width); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d rows in %.3fs\n", result.Total, time.Since(start).Seconds())
+	return nil
+}
+
+// meta runs a backslash command. done reports that the session should
+// exit.
+func (s *clickhouseSession) meta(ctx context.Context, words []string) (done bool, err error) {
+	switch words[0] {
+	case `\q`, `\quit`:
+		return true, nil
+	case `\h`, `\?`, `\help`:
+		fmt.Println(clickhouseReplHelp)
+	case `\format`:
+		switch len(words) {
+		case 1:
+			fmt.Println(s.format)
+		case 2:
+			for _, f := range clickhouseFormats {
+				if words[1] == f {
+					s.format = f
+					return false, nil
+				}
+			}
+			return false, fmt.Errorf("unknown format %q: use %s", words[1], strings.Join(clickhouseFormats, ", "))
+		default:
+			return false, fmt.Errorf(`usage: \format [format]`)
+		}
+	case `\set`:
+		switch len(words) {
+		case 1:
+			return false, output.Fprint(os.Stdout, s.format, s.params)
+		case 2:
+			return false, fmt.Errorf(`usage: \set <name> <value>`)
+		}
+		s.params[words[1]] = strings.Join(words[2:], " ")
+	case `\unset`:
+		if len(words) != 2 {
+			return false, fmt.Errorf(`usage: \unset <name>`)
+		}
+		delete(s.params, words[1])
+	case `\l`:
+		saved, err := s.library.List()
+		if err != nil {
+			return false, err
+		}
+		for _, q := range saved {
+			fmt.Printf("%-24s %s\n", q.Name, q.Description)
+		}
+	case `\show`, `\run`:
+		if len(words) != 2 {
+			return false, fmt.Errorf(`usage: %s <name>`, words[0])
+		}
+		saved, err := s.library.Load(words[1])
+		if err != nil {
+			return false, err
+		}
+		if words[0] == `\show` {
+			fmt.Print(strings.TrimRight(saved.SQL, "\n") + "\n")
+			return false, nil
+		}
+		return false, s.run(ctx, saved.SQL)
+	case `\save`:
+		if len(words) != 2 {
+			return false, fmt.Errorf(`usage: \save <name>`)
+		}
+		if s.last == "" {
+			return false, fmt.Errorf("no previous statement")
+		}
+		if err := s.library.Save(words[1], s.last); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(os.Stderr, "Saved query %s\n", words[1])
+	default:
+		return false, fmt.Errorf(`unknown command %s (\h for help)`, words[0])
+	}
+	return false, nil
+}
+
+// complete completes backslash commands, formats and saved query names.
+func (s *clickhouseSession) complete(line string) (int, []string) {
+	start := strings.LastIndexByte(line, ' ') + 1
+	word := line[start:]
+	words := strings.Fields(line[:start])
+
+	var candidates []string
+	switch {
+	case len(words) == 0 && strings.HasPrefix(word, `\`):
+		candidates = clickhouseMeta
+	case len(words) == 1 && words[0] == `\format`:
+		candidates = clickhouseFormats
+	case len(words) == 1 && (words[0] == `\run` || words[0] == `\show` || words[0] == `\save`):
+		saved, _ := s.library.List()
+		for _, q := range saved {
+			candidates = append(candidates, q.Name)
+		}
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+	return start, matches
+}
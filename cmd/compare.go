@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/compare"
+	"hepic-cli/internal/config"
+	"hepic-cli/internal/output"
+	"hepic-cli/internal/resource"
+
+	"github.com/spf13/cobra"
+)
+
+var compareCmd = &cobra.Command{
+	Use:     "compare",
+	Short:   "Compare configuration between two HEPIC instances",
+	GroupID: "config",
+	Long: `Fetch the same resources from two instances and report items that were
+added (only on the target), removed (only on the source) or changed, with
+field-level differences. Server-generated fields (` + strings.Join(compare.Ignored, ", ") + `)
+are ignored and items are matched on their natural key, such as hepid and
+profile for mappings.
+
+Instances are named in the hosts section of ~/.hepic/config.yaml; add one
+with "hepic init --name <name>". "default" is the main configured host.
+
+Resources: ` + strings.Join(resource.Names(), ", ") + `
+(singular forms such as ipalias or mapping are accepted). Default: ` + strings.Join(compare.DefaultResources, ", ") + `.
+
+Examples:
+  hepic compare --source staging --target prod
+  hepic compare --source staging --target prod --resources ipalias,mapping --report
+  hepic compare --source default --target dr --fail-on-drift`,
+	RunE: runCompare,
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().String("source", "", "Source instance name (required)")
+	compareCmd.Flags().String("target", "", "Target instance name (required)")
+	compareCmd.Flags().StringSlice("resources", nil, "Resources to compare (comma-separated)")
+	compareCmd.Flags().Bool("report", false, "Print a human-readable report instead of structured output")
+	compareCmd.Flags().Bool("fail-on-drift", false, "Exit non-zero when differences are found")
+	compareCmd.MarkFlagRequired("source")
+	compareCmd.MarkFlagRequired("target")
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	sourceName, _ := cmd.Flags().GetString("source")
+	targetName, _ := cmd.Flags().GetString("target")
+	names, _ := cmd.Flags().GetStringSlice("resources")
+	report, _ := cmd.Flags().GetBool("report")
+	failOnDrift, _ := cmd.Flags().GetBool("fail-on-drift")
+
+	resources, err := compare.Resources(names)
+	if err != nil {
+		return err
+	}
+	source, err := config.Lookup(sourceName)
+	if err != nil {
+		return err
+	}
+	target, err := config.Lookup(targetName)
+	if err != nil {
+		return err
+	}
+	srcClient := api.NewClientFor(source.Host, source.Token)
+	tgtClient := api.NewClientFor(target.Host, target.Token)
+
+	result := &compare.Report{Source: sourceName, Target: targetName, Differences: []compare.Difference{}}
+	for _, r := range resources {
+		srcItems, err := r.List(cmd.Context(), srcClient)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", sourceName, r.Name, err)
+		}
+		tgtItems, err := r.List(cmd.Context(), tgtClient)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", targetName, r.Name, err)
+		}
+		result.Resource(r, srcItems, tgtItems)
+	}
+
+	if report {
+		result.Write(os.Stdout)
+	} else if err := output.Print(result); err != nil {
+		return err
+	}
+	if failOnDrift && result.Drift() {
+		return fmt.Errorf("%d item(s) differ between %s and %s", len(result.Differences), sourceName, targetName)
+	}
+	return nil
+}
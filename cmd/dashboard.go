@@ -3,6 +3,8 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
 	"hepic-cli/internal/api"
 	"hepic-cli/internal/dashboard"
@@ -14,7 +16,7 @@ import (
 var dashboardCmd = &cobra.Command{
 	Use:     "dashboard",
 	Short:   "Manage dashboards",
-	Long:    "List, update, and delete dashboards on the HEPIC platform.",
+	Long:    "List, update, delete, export, and import dashboards on the HEPIC platform.",
 	GroupID: "data",
 }
 
@@ -101,6 +103,204 @@ Examples:
 	},
 }
 
+var dashboardExportCmd = &cobra.Command{
+	Use:   "export [id]",
+	Short: "Export dashboards to a file",
+	Long: `Export a dashboard's full configuration, or with --all a bundle of all
+dashboards, as JSON. The file can be imported with "dashboard import" by
+another user or on another instance.
+
+Examples:
+  hepic dashboard export home -o home.json
+  hepic dashboard export --all -o dashboards.json`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeDashboardIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		outputPath, _ := cmd.Flags().GetString("output")
+		if all == (len(args) == 1) {
+			return fmt.Errorf("specify either a dashboard ID or --all")
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		var export interface{}
+		count := 1
+		if all {
+			ids, err := dashboard.IDs(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+			bundle := dashboard.Bundle{ExportedAt: time.Now().UTC(), Dashboards: []dashboard.Dashboard{}}
+			for _, id := range ids {
+				d, err := dashboard.Fetch(cmd.Context(), client, id)
+				if err != nil {
+					return fmt.Errorf("dashboard %s: %w", id, err)
+				}
+				bundle.Dashboards = append(bundle.Dashboards, d)
+			}
+			export, count = bundle, len(ids)
+		} else {
+			d, err := dashboard.Fetch(cmd.Context(), client, args[0])
+			if err != nil {
+				return fmt.Errorf("dashboard %s: %w", args[0], err)
+			}
+			export = d
+		}
+
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if outputPath == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(outputPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", outputPath, err)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d dashboard(s) to %s\n", count, outputPath)
+		return nil
+	},
+}
+
+var dashboardImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import dashboards from a file",
+	Long: `Import the dashboard or bundle of dashboards written by "dashboard export".
+
+The owner recorded in the file is dropped, so the dashboards belong to the
+importing user, unless --owner sets one. Existing dashboards with the same
+ID are only replaced with --force; --id imports a single dashboard under a
+new ID instead.
+
+Examples:
+  hepic dashboard import -f home.json --id home-copy
+  hepic dashboard import -f dashboards.json --force
+  hepic dashboard import -f home.json --owner alice`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		newID, _ := cmd.Flags().GetString("id")
+		owner, _ := cmd.Flags().GetString("owner")
+		force, _ := cmd.Flags().GetBool("force")
+
+		dashboards, err := dashboard.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if newID != "" && len(dashboards) != 1 {
+			return fmt.Errorf("--id needs a file with one dashboard, %s has %d", file, len(dashboards))
+		}
+
+		seen := make(map[string]bool)
+		for i, d := range dashboards {
+			if newID != "" {
+				d["id"] = newID
+			}
+			delete(d, "owner")
+			if owner != "" {
+				d["owner"] = owner
+			}
+			if err := dashboard.Validate(d); err != nil {
+				return fmt.Errorf("dashboard %d in %s: %w", i+1, file, err)
+			}
+			id := fmt.Sprint(d["id"])
+			if seen[id] {
+				return fmt.Errorf("dashboard %s appears twice in %s", id, file)
+			}
+			seen[id] = true
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		ids, err := dashboard.IDs(cmd.Context(), client)
+		if err != nil {
+			return err
+		}
+		existing := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if seen[id] && !force {
+				return fmt.Errorf("dashboard %s already exists; use --force to replace it or --id to import under a new ID", id)
+			}
+			existing[id] = true
+		}
+
+		type imported struct {
+			ID     string `json:"id"`
+			Name   string `json:"name"`
+			Action string `json:"action"`
+		}
+		result := []imported{}
+		for _, d := range dashboards {
+			id := fmt.Sprint(d["id"])
+			// New dashboards are created; only the ones --force replaces
+			// are updated.
+			store, action := dashboard.Create, "created"
+			if existing[id] {
+				store, action = dashboard.Store, "replaced"
+			}
+			if _, err := store(cmd.Context(), client, id, d); err != nil {
+				return fmt.Errorf("dashboard %s: %w", id, err)
+			}
+			result = append(result, imported{ID: id, Name: fmt.Sprint(d["name"]), Action: action})
+		}
+
+		return output.Print(result)
+	},
+}
+
+var dashboardResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Reset dashboards to the defaults",
+	Long: `Restore the default dashboards of the current user, discarding changes.
+Requires confirmation unless --force is specified; export the dashboards
+first to keep them.
+
+Examples:
+  hepic dashboard export --all -o dashboards.json && hepic dashboard reset`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		if !force && !confirmAction("Reset all dashboards to the defaults?") {
+			return fmt.Errorf("operation cancelled")
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		result, err := dashboard.Reset(cmd.Context(), client)
+		if err != nil {
+			return err
+		}
+
+		return output.Print(result)
+	},
+}
+
+// completeDashboardIDs completes the IDs of the current user's dashboards.
+func completeDashboardIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	client, err := api.NewClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	ids, err := dashboard.IDs(cmd.Context(), client)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
 func init() {
 	rootCmd.AddCommand(dashboardCmd)
 
@@ -111,4 +311,18 @@ func init() {
 
 	dashboardCmd.AddCommand(dashboardDeleteCmd)
 	dashboardDeleteCmd.Flags().Bool("force", false, "Confirm deletion")
+
+	dashboardCmd.AddCommand(dashboardExportCmd)
+	dashboardExportCmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
+	dashboardExportCmd.Flags().Bool("all", false, "Export all dashboards as a bundle")
+
+	dashboardCmd.AddCommand(dashboardImportCmd)
+	dashboardImportCmd.Flags().StringP("file", "f", "", "Dashboard or bundle file to import (required)")
+	dashboardImportCmd.Flags().String("id", "", "Import a single dashboard under this ID")
+	dashboardImportCmd.Flags().String("owner", "", "Owner of the imported dashboards (default: the importing user)")
+	dashboardImportCmd.Flags().Bool("force", false, "Replace existing dashboards with the same ID")
+	dashboardImportCmd.MarkFlagRequired("file")
+
+	dashboardCmd.AddCommand(dashboardResetCmd)
+	dashboardResetCmd.Flags().Bool("force", false, "Skip confirmation prompt")
 }
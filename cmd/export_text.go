@@ -18,7 +18,8 @@ var exportTextCmd = &cobra.Command{
 
 Examples:
   hepic export text --call-id abc123
-  hepic export text --call-id abc123 --from 2025-01-01 --to 2025-01-02`,
+  hepic export text --call-id abc123 --from 2025-01-01 --to 2025-01-02
+  hepic export text --call-id abc123 --resolve-aliases`,
 	RunE: runExportText,
 }
 
@@ -46,15 +47,33 @@ func runExportText(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	aliases, err := resolveAliases(cmd, client)
+	if err != nil {
+		return err
+	}
+
 	body, err := export.ExportText(cmd.Context(), client, params)
 	if err != nil {
 		return err
 	}
 	defer body.Close()
 
-	n, err := io.Copy(os.Stdout, body)
-	if err != nil {
-		return fmt.Errorf("failed to write text output: %w", err)
+	var n int64
+	if aliases != nil {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("failed to read text output: %w", err)
+		}
+		written, err := io.WriteString(os.Stdout, aliases.Annotate(string(data)))
+		n = int64(written)
+		if err != nil {
+			return fmt.Errorf("failed to write text output: %w", err)
+		}
+	} else {
+		n, err = io.Copy(os.Stdout, body)
+		if err != nil {
+			return fmt.Errorf("failed to write text output: %w", err)
+		}
 	}
 
 	fmt.Fprintf(os.Stderr, "Exported %d bytes\n", n)
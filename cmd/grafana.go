@@ -1,18 +1,29 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"hepic-cli/internal/api"
 	"hepic-cli/internal/output"
 	"hepic-cli/internal/statistic"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var grafanaCmd = &cobra.Command{
 	Use:     "grafana",
 	Short:   "Interact with Grafana via proxy",
-	Long:    "Retrieve Grafana dashboards, folders, organization info, and status via the HEPIC proxy.",
+	Long:    "Retrieve and export Grafana dashboards, panels, folders, organization info, and status via the HEPIC proxy.",
 	GroupID: "monitoring",
 }
 
@@ -80,12 +91,13 @@ var grafanaOrgCmd = &cobra.Command{
 }
 
 var grafanaSearchCmd = &cobra.Command{
-	Use:   "search <uid>",
-	Short: "Search for a Grafana dashboard",
-	Long: `Search for a Grafana dashboard by UID.
+	Use:   "search <folder-id>",
+	Short: "List the dashboards of a Grafana folder",
+	Long: `Search for the dashboards of a Grafana folder by its ID (see "grafana
+folders"); 0 is the General folder.
 
 Examples:
-  hepic grafana search abc123`,
+  hepic grafana search 0`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		uid := args[0]
@@ -123,6 +135,304 @@ var grafanaStatusCmd = &cobra.Command{
 	},
 }
 
+var grafanaExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export Grafana dashboards as JSON files",
+	Long: `Write every dashboard, or those of one folder, to <uid>.json in the
+output directory. The files hold the dashboard model, as Grafana imports
+it.
+
+Examples:
+  hepic grafana export -o dashboards/
+  hepic grafana export --folder HEPIC -o dashboards/`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("output")
+		folderName, _ := cmd.Flags().GetString("folder")
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		folders, err := statistic.ListFolders(ctx, client)
+		if err != nil {
+			return err
+		}
+		if folderName != "" {
+			folder, err := statistic.FindFolder(folders, folderName)
+			if err != nil {
+				return err
+			}
+			folders = []statistic.Folder{folder}
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+
+		type exported struct {
+			UID    string `json:"uid"`
+			Title  string `json:"title"`
+			Folder string `json:"folder"`
+			File   string `json:"file"`
+		}
+		result := []exported{}
+		for _, folder := range folders {
+			refs, err := statistic.FolderDashboards(ctx, client, folder)
+			if err != nil {
+				return fmt.Errorf("folder %s: %w", folder.Title, err)
+			}
+			for _, ref := range refs {
+				if ref.UID == "" || ref.UID != filepath.Base(ref.UID) {
+					fmt.Fprintf(os.Stderr, "Warning: skipping dashboard %q with UID %q\n", ref.Title, ref.UID)
+					continue
+				}
+				d, err := statistic.LoadDashboard(ctx, client, ref.UID)
+				if err != nil {
+					return err
+				}
+				data, err := json.MarshalIndent(d.Model, "", "  ")
+				if err != nil {
+					return err
+				}
+				file := filepath.Join(dir, ref.UID+".json")
+				if err := os.WriteFile(file, append(data, '\n'), 0644); err != nil {
+					return fmt.Errorf("failed to write %s: %w", file, err)
+				}
+				result = append(result, exported{UID: ref.UID, Title: d.Title(), Folder: ref.FolderTitle, File: file})
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Exported %d dashboards to %s\n", len(result), dir)
+		return output.Print(result)
+	},
+}
+
+var grafanaURLCmd = &cobra.Command{
+	Use:   "url [uid]",
+	Short: "Print a link to a Grafana dashboard",
+	Long: `Print a link to a dashboard on the Grafana server configured in HEPIC,
+or without a UID the Grafana URL itself.
+
+--from and --to take absolute times (RFC3339, YYYY-MM-DD, unix ms) or
+Grafana relative times such as now-6h, which stay relative in the link.
+
+Examples:
+  hepic grafana url
+  hepic grafana url abc123 --from now-6h --to now
+  hepic grafana url abc123 --from 2025-01-01 --to 2025-01-02 --var node=hepic01 --panel 4`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeGrafanaDashboards,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromFlag, _ := cmd.Flags().GetString("from")
+		toFlag, _ := cmd.Flags().GetString("to")
+		vars, _ := cmd.Flags().GetStringArray("var")
+		panel, _ := cmd.Flags().GetInt("panel")
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		base, err := statistic.BaseURL(cmd.Context(), client)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			fmt.Println(base)
+			return nil
+		}
+
+		from, err := grafanaTime(fromFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --from value: %w", err)
+		}
+		to, err := grafanaTime(toFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --to value: %w", err)
+		}
+
+		d, err := statistic.LoadDashboard(cmd.Context(), client, args[0])
+		if err != nil {
+			return err
+		}
+		if panel > 0 {
+			if _, ok := d.Panel(panel); !ok {
+				return fmt.Errorf("dashboard %s has no panel %d", args[0], panel)
+			}
+		}
+
+		link, err := statistic.DashboardURL(base, args[0], d.Slug(), from, to, vars, panel)
+		if err != nil {
+			return err
+		}
+		fmt.Println(link)
+		return nil
+	},
+}
+
+var grafanaPanelCmd = &cobra.Command{
+	Use:   "panel <uid> <panel-id>",
+	Short: "Fetch the rendered data of a dashboard panel",
+	Long: `Fetch a dashboard panel through the HEPIC proxy, such as a PNG rendering.
+
+JSON responses are printed; other responses are written to --output, or to
+stdout when it is not a terminal.
+
+Examples:
+  hepic grafana panel abc123 4 --from now-6h -o panel.png
+  hepic grafana panel abc123 4 --var node=hepic01 --width 1200 --height 400 > panel.png`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeGrafanaDashboards(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		uid := args[0]
+		panel, err := strconv.Atoi(args[1])
+		if err != nil || panel <= 0 {
+			return fmt.Errorf("invalid panel ID %q", args[1])
+		}
+		fromFlag, _ := cmd.Flags().GetString("from")
+		toFlag, _ := cmd.Flags().GetString("to")
+		vars, _ := cmd.Flags().GetStringArray("var")
+		width, _ := cmd.Flags().GetInt("width")
+		height, _ := cmd.Flags().GetInt("height")
+		outputPath, _ := cmd.Flags().GetString("output")
+
+		from, err := grafanaTime(fromFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --from value: %w", err)
+		}
+		to, err := grafanaTime(toFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --to value: %w", err)
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		d, err := statistic.LoadDashboard(cmd.Context(), client, uid)
+		if err != nil {
+			return err
+		}
+		if _, ok := d.Panel(panel); !ok {
+			return fmt.Errorf("dashboard %s has no panel %d", uid, panel)
+		}
+
+		query := url.Values{}
+		query.Set("panelId", strconv.Itoa(panel))
+		query.Set("from", from)
+		query.Set("to", to)
+		if width > 0 {
+			query.Set("width", strconv.Itoa(width))
+		}
+		if height > 0 {
+			query.Set("height", strconv.Itoa(height))
+		}
+		if err := statistic.AddVars(query, vars); err != nil {
+			return err
+		}
+
+		body, err := statistic.Request(cmd.Context(), client, uid, d.Slug(), query)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+
+		if outputPath != "" {
+			file, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer file.Close()
+
+			n, err := io.Copy(file, body)
+			if err != nil {
+				return fmt.Errorf("failed to write panel data: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Exported %d bytes to %s\n", n, outputPath)
+			return nil
+		}
+
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("failed to read panel data: %w", err)
+		}
+		if json.Valid(data) {
+			return output.Print(json.RawMessage(data))
+		}
+		if term.IsTerminal(int(os.Stdout.Fd())) {
+			return fmt.Errorf("the panel data is binary (%d bytes); write it with -o or redirect stdout", len(data))
+		}
+		w := bufio.NewWriter(os.Stdout)
+		w.Write(data)
+		return w.Flush()
+	},
+}
+
+// grafanaTime converts a time flag to a Grafana time: relative times such
+// as now-6h are kept, absolute ones become unix ms.
+func grafanaTime(s string) (string, error) {
+	if s == "" || strings.HasPrefix(s, "now") {
+		return s, nil
+	}
+	t, err := statistic.ParseTime(s, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(t.UnixMilli(), 10), nil
+}
+
+// completeGrafanaDashboards completes dashboard UIDs, described by their
+// titles.
+func completeGrafanaDashboards(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	client, err := api.NewClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	folders, err := statistic.ListFolders(cmd.Context(), client)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var uids []string
+	for _, f := range folders {
+		refs, err := statistic.FolderDashboards(cmd.Context(), client, f)
+		if err != nil {
+			continue
+		}
+		for _, r := range refs {
+			uids = append(uids, r.UID+"\t"+r.Title)
+		}
+	}
+	return uids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeGrafanaFolders completes folder titles.
+func completeGrafanaFolders(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, err := api.NewClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	folders, err := statistic.ListFolders(cmd.Context(), client)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	titles := make([]string, len(folders))
+	for i, f := range folders {
+		titles[i] = f.Title
+	}
+	return titles, cobra.ShellCompDirectiveNoFileComp
+}
+
 func init() {
 	rootCmd.AddCommand(grafanaCmd)
 
@@ -131,4 +441,22 @@ func init() {
 	grafanaCmd.AddCommand(grafanaOrgCmd)
 	grafanaCmd.AddCommand(grafanaSearchCmd)
 	grafanaCmd.AddCommand(grafanaStatusCmd)
+
+	grafanaCmd.AddCommand(grafanaExportCmd)
+	grafanaExportCmd.Flags().StringP("output", "o", "", "Output directory (required)")
+	grafanaExportCmd.Flags().String("folder", "", "Export only the dashboards of this folder (title, UID or ID)")
+	grafanaExportCmd.MarkFlagRequired("output")
+	grafanaExportCmd.RegisterFlagCompletionFunc("folder", completeGrafanaFolders)
+
+	grafanaCmd.AddCommand(grafanaURLCmd)
+	grafanaCmd.AddCommand(grafanaPanelCmd)
+	for _, c := range []*cobra.Command{grafanaURLCmd, grafanaPanelCmd} {
+		c.Flags().String("from", "now-6h", "Start time (RFC3339, YYYY-MM-DD, unix ms or now-<duration>)")
+		c.Flags().String("to", "now", "End time (RFC3339, YYYY-MM-DD, unix ms or now-<duration>)")
+		c.Flags().StringArray("var", nil, "Template variable as name=value (repeatable)")
+	}
+	grafanaURLCmd.Flags().Int("panel", 0, "Open this panel alone")
+	grafanaPanelCmd.Flags().Int("width", 1000, "Width of the rendering in pixels")
+	grafanaPanelCmd.Flags().Int("height", 500, "Height of the rendering in pixels")
+	grafanaPanelCmd.Flags().StringP("output", "o", "", "Output file path")
 }
@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
 	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/models"
 	"hepic-cli/internal/output"
 
 	"github.com/spf13/cobra"
@@ -13,7 +15,7 @@ import (
 var hepsubCmd = &cobra.Command{
 	Use:     "hepsub",
 	Short:   "Manage HEP subscriptions",
-	Long:    "List, create, update, delete, and search HEP subscriptions.",
+	Long:    "List, create, update, delete, search, apply and export HEP subscriptions.",
 	GroupID: "config",
 }
 
@@ -36,34 +38,40 @@ var hepsubListCmd = &cobra.Command{
 var hepsubCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new HEP subscription",
+	Long: `Create a new HEP subscription.
+
+The body is built from --file, then --data, then the individual flags, and
+validated before it is sent: hepid and profile are required, mapping must be
+JSON, and the hepid/profile combination must exist in "hepic mapping protocols".
+
+Examples:
+  hepic hepsub create --hepid 1 --profile call --hep-alias SIP --data '{"mapping":{"lookup_id":100,"lookup_profile":"default"}}'
+  hepic hepsub create -f hepsub.yaml
+  hepic hepsub create --data @hepsub.json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client, err := api.NewClient()
+		data, err := readBody(cmd, false)
 		if err != nil {
 			return err
 		}
+		setFromFlags(cmd, data, hepsubFlagKeys)
 
-		hepid, _ := cmd.Flags().GetInt("hepid")
-		profile, _ := cmd.Flags().GetString("profile")
-		hepAlias, _ := cmd.Flags().GetString("hep-alias")
-
-		data := map[string]interface{}{
-			"hepid":     hepid,
-			"profile":   profile,
-			"hep_alias": hepAlias,
+		var sub models.HepsubSchema
+		if err := body.Decode(data, &sub); err != nil {
+			return err
+		}
+		if err := config_resources.ValidateHepsub(sub, false); err != nil {
+			return err
 		}
 
-		dataFlag, _ := cmd.Flags().GetString("data")
-		if dataFlag != "" {
-			extra, err := parseJSONFlag(dataFlag)
-			if err != nil {
-				return err
-			}
-			for k, v := range extra {
-				data[k] = v
-			}
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		if err := checkProtocol(cmd, client, sub.Hepid, sub.Profile); err != nil {
+			return err
 		}
 
-		result, err := config_resources.CreateHepsub(cmd.Context(), client, data)
+		result, err := config_resources.CreateHepsub(cmd.Context(), client, sub)
 		if err != nil {
 			return err
 		}
@@ -74,41 +82,41 @@ var hepsubCreateCmd = &cobra.Command{
 var hepsubUpdateCmd = &cobra.Command{
 	Use:   "update <uuid>",
 	Short: "Update an existing HEP subscription",
-	Args:  cobra.ExactArgs(1),
+	Long: `Update an existing HEP subscription. Only the given fields are sent.
+
+Examples:
+  hepic hepsub update <uuid> --hep-alias SIP
+  hepic hepsub update <uuid> -f hepsub.yaml`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client, err := api.NewClient()
+		data, err := readBody(cmd, false)
 		if err != nil {
 			return err
 		}
-
-		uuid := args[0]
-		data := make(map[string]interface{})
-
-		if cmd.Flags().Changed("hepid") {
-			v, _ := cmd.Flags().GetInt("hepid")
-			data["hepid"] = v
+		setFromFlags(cmd, data, hepsubFlagKeys)
+		if len(data) == 0 {
+			return fmt.Errorf("nothing to update: use flags, --data or --file")
 		}
-		if cmd.Flags().Changed("profile") {
-			v, _ := cmd.Flags().GetString("profile")
-			data["profile"] = v
+
+		var sub models.HepsubSchema
+		if err := body.Decode(data, &sub); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("hep-alias") {
-			v, _ := cmd.Flags().GetString("hep-alias")
-			data["hep_alias"] = v
+		if err := config_resources.ValidateHepsub(sub, true); err != nil {
+			return err
 		}
 
-		dataFlag, _ := cmd.Flags().GetString("data")
-		if dataFlag != "" {
-			extra, err := parseJSONFlag(dataFlag)
-			if err != nil {
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		if sub.Hepid != 0 && sub.Profile != "" {
+			if err := checkProtocol(cmd, client, sub.Hepid, sub.Profile); err != nil {
 				return err
 			}
-			for k, v := range extra {
-				data[k] = v
-			}
 		}
 
-		result, err := config_resources.UpdateHepsub(cmd.Context(), client, uuid, data)
+		result, err := config_resources.UpdateHepsub(cmd.Context(), client, args[0], data)
 		if err != nil {
 			return err
 		}
@@ -116,6 +124,13 @@ var hepsubUpdateCmd = &cobra.Command{
 	},
 }
 
+// hepsubFlagKeys maps hepsub create/update flags to body fields.
+var hepsubFlagKeys = map[string]string{
+	"hepid":     "hepid",
+	"profile":   "profile",
+	"hep-alias": "hep_alias",
+}
+
 var hepsubDeleteCmd = &cobra.Command{
 	Use:   "delete <uuid>",
 	Short: "Delete a HEP subscription",
@@ -175,13 +190,13 @@ func init() {
 	hepsubCreateCmd.Flags().Int("hepid", 0, "HEP ID")
 	hepsubCreateCmd.Flags().String("profile", "", "Profile name")
 	hepsubCreateCmd.Flags().String("hep-alias", "", "HEP alias")
-	hepsubCreateCmd.Flags().String("data", "", "Additional data as JSON")
+	addBodyFlags(hepsubCreateCmd, "Subscription body")
 
 	hepsubCmd.AddCommand(hepsubUpdateCmd)
 	hepsubUpdateCmd.Flags().Int("hepid", 0, "HEP ID")
 	hepsubUpdateCmd.Flags().String("profile", "", "Profile name")
 	hepsubUpdateCmd.Flags().String("hep-alias", "", "HEP alias")
-	hepsubUpdateCmd.Flags().String("data", "", "Additional data as JSON")
+	addBodyFlags(hepsubUpdateCmd, "Fields to update")
 
 	hepsubCmd.AddCommand(hepsubDeleteCmd)
 	hepsubDeleteCmd.Flags().Bool("force", false, "Skip confirmation prompt")
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/hepsub"
+	"hepic-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var hepsubApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Sync HEP subscriptions from a templated YAML file",
+	Long: `Make the server's HEP subscriptions match a definitions file.
+
+Subscriptions are matched on hepid and profile. Matching entries whose
+hep_alias or mapping differ are updated, missing ones are created. Server
+entries that are not in the file are only deleted with --prune.
+
+String values in the file are Go templates rendered with per-environment
+variables: "vars" apply everywhere, "environments.<name>" (selected with
+--env) override them, and --set overrides both. A value that is exactly
+"{{ .name }}" keeps the variable's type.
+
+  vars:
+    lookup_range: [-300, 200]
+  environments:
+    prod:
+      lookup_id: 100
+    staging:
+      lookup_id: 101
+  hepsubs:
+    - hepid: 1
+      profile: call
+      hep_alias: SIP
+      mapping:
+        lookup_id: "{{ .lookup_id }}"
+        lookup_profile: default
+        lookup_range: "{{ .lookup_range }}"
+
+"hepic hepsub export" writes the same format.
+
+Examples:
+  hepic hepsub apply -f hepsubs.yaml --env staging --dry-run --format table
+  hepic hepsub apply -f hepsubs.yaml --env prod --set lookup_id=102
+  hepic hepsub apply -f hepsubs.yaml --env prod --prune --force`,
+	RunE: runHepsubApply,
+}
+
+var hepsubExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export HEP subscriptions as a YAML definitions file",
+	Long: `Export all HEP subscriptions in the format read by "hepic hepsub apply".
+
+Examples:
+  hepic hepsub export > hepsubs.yaml
+  hepic hepsub export -o hepsubs.yaml`,
+	RunE: runHepsubExport,
+}
+
+func init() {
+	hepsubCmd.AddCommand(hepsubApplyCmd)
+	hepsubApplyCmd.Flags().StringP("file", "f", "", "YAML definitions file (required)")
+	hepsubApplyCmd.Flags().String("env", "", "Environment whose variables to use")
+	hepsubApplyCmd.Flags().StringArray("set", nil, "Set a template variable (key=value, repeatable)")
+	hepsubApplyCmd.Flags().Bool("dry-run", false, "Show the plan without applying it")
+	hepsubApplyCmd.Flags().Bool("prune", false, "Delete subscriptions that are not in the file")
+	hepsubApplyCmd.Flags().Bool("force", false, "Skip confirmation prompt for deletes")
+	hepsubApplyCmd.MarkFlagRequired("file")
+
+	hepsubCmd.AddCommand(hepsubExportCmd)
+	hepsubExportCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+}
+
+func runHepsubApply(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	env, _ := cmd.Flags().GetString("env")
+	set, _ := cmd.Flags().GetStringArray("set")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	prune, _ := cmd.Flags().GetBool("prune")
+	force, _ := cmd.Flags().GetBool("force")
+
+	overrides, err := hepsub.ParseSet(set)
+	if err != nil {
+		return err
+	}
+	specs, err := hepsub.Load(file, env, overrides)
+	if err != nil {
+		return err
+	}
+	for _, s := range specs {
+		if err := config_resources.ValidateHepsub(s, false); err != nil {
+			return fmt.Errorf("hepsub %s: %w", hepsub.Key(s), err)
+		}
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	protocols, err := config_resources.ListProtocols(cmd.Context(), client)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot check hepid/profile: %v\n", err)
+	} else {
+		for _, s := range specs {
+			if err := config_resources.CheckProtocol(protocols, s.Hepid, s.Profile); err != nil {
+				return err
+			}
+		}
+	}
+
+	current, err := hepsub.Fetch(cmd.Context(), client)
+	if err != nil {
+		return fmt.Errorf("failed to load existing HEP subscriptions: %w", err)
+	}
+	changes := hepsub.Plan(specs, current, prune)
+	if dryRun || len(changes) == 0 {
+		return output.Print(changes)
+	}
+
+	deletes := 0
+	for _, c := range changes {
+		fmt.Fprintf(os.Stderr, "  %-6s %d/%s %s\n", c.Action, c.Hepid, c.Profile, c.Details)
+		if c.Action == hepsub.ActionDelete {
+			deletes++
+		}
+	}
+	if deletes > 0 && !force {
+		if !confirmAction(fmt.Sprintf("Apply %d change(s), deleting %d HEP subscription(s)?", len(changes), deletes)) {
+			return fmt.Errorf("operation cancelled")
+		}
+	}
+
+	return applyChanges(changes, func(c *hepsub.Change) (string, error) {
+		var err error
+		switch c.Action {
+		case hepsub.ActionCreate:
+			_, err = config_resources.CreateHepsub(cmd.Context(), client, c.Body)
+		case hepsub.ActionUpdate:
+			_, err = config_resources.UpdateHepsub(cmd.Context(), client, c.UUID, c.Body)
+		case hepsub.ActionDelete:
+			_, err = config_resources.DeleteHepsub(cmd.Context(), client, c.UUID)
+		}
+		return changeOK, err
+	})
+}
+
+func runHepsubExport(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("output")
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	subs, err := hepsub.Fetch(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+	data, err := hepsub.Export(subs)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return output.Print(map[string]interface{}{
+		"file":    path,
+		"hepsubs": len(subs),
+	})
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 
 	"hepic-cli/internal/admin"
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/importfile"
 	"hepic-cli/internal/output"
 
 	"github.com/spf13/cobra"
@@ -65,3 +67,84 @@ func init() {
 	importPcapCmd.Flags().Bool("now", false, "Import immediately instead of queuing")
 	importPcapCmd.MarkFlagRequired("file")
 }
+
+// csvImport describes a CSV import endpoint family (merge/replace, csv/h2).
+type csvImport struct {
+	noun       string
+	schema     importfile.Schema
+	serverKeys func(cmd *cobra.Command, client *api.Client) ([]string, error)
+	upload     func(cmd *cobra.Command, client *api.Client, file, mode, source string) (json.RawMessage, error)
+}
+
+// addCSVImportFlags registers the flags shared by CSV import commands.
+func addCSVImportFlags(c *cobra.Command) {
+	c.Flags().String("mode", importfile.ModeMerge, "Import mode: merge (add to existing) or replace (delete everything not in the file)")
+	c.Flags().String("source", importfile.SourceCSV, "File format: csv or h2 (legacy H2 database export)")
+	c.Flags().Bool("dry-run", false, "Validate the file (and preview a replace) without importing")
+	c.Flags().Bool("force", false, "Skip confirmation prompt for replace imports")
+}
+
+// run validates the file, previews a replace, and uploads it.
+func (imp csvImport) run(cmd *cobra.Command, filePath string) error {
+	mode, _ := cmd.Flags().GetString("mode")
+	source, _ := cmd.Flags().GetString("source")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if _, err := importfile.Endpoint("", mode, source); err != nil {
+		return err
+	}
+
+	file, err := importfile.Read(filePath)
+	if err != nil {
+		return err
+	}
+	if errs := file.Validate(imp.schema); len(errs) > 0 {
+		if err := output.Print(errs); err != nil {
+			return err
+		}
+		return fmt.Errorf("%s has %d error(s); nothing was imported", filePath, len(errs))
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+
+	if mode == importfile.ModeReplace {
+		current, err := imp.serverKeys(cmd, client)
+		if err != nil {
+			return err
+		}
+		preview := importfile.Preview(file.Keys(imp.schema), current)
+		add := importfile.Count(preview, importfile.ActionAdd)
+		remove := importfile.Count(preview, importfile.ActionRemove)
+		keep := importfile.Count(preview, importfile.ActionKeep)
+		if dryRun {
+			return output.Print(preview)
+		}
+		if !force {
+			for _, c := range preview {
+				if c.Action != importfile.ActionKeep {
+					fmt.Fprintf(os.Stderr, "  %-6s %s\n", c.Action, c.Key)
+				}
+			}
+			prompt := fmt.Sprintf("Replace all %s: add %d, remove %d, keep %d?", imp.noun, add, remove, keep)
+			if !confirmAction(prompt) {
+				return fmt.Errorf("operation cancelled")
+			}
+		}
+	} else if dryRun {
+		return output.Print(map[string]interface{}{
+			"status": "ok",
+			"file":   filePath,
+			"rows":   len(file.Rows),
+		})
+	}
+
+	result, err := imp.upload(cmd, client, filePath, mode, source)
+	if err != nil {
+		return err
+	}
+	return output.Print(result)
+}
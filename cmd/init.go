@@ -23,12 +23,16 @@ Interactive mode (default):
   hepic init
 
 Non-interactive mode:
-  hepic init --host https://hepic.example.com --token your-api-key`,
+  hepic init --host https://hepic.example.com --token your-api-key
+
+Add a named instance (for "hepic compare") without changing the default:
+  hepic init --name staging --host https://staging.example.com --token key`,
 	RunE: runInit,
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().String("name", "", "Save as a named instance in the hosts section instead of the default")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -73,9 +77,18 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("connection validation failed: %w", err)
 	}
 
-	cfg := &config.Config{
-		Host:  host,
-		Token: token,
+	name, _ := cmd.Flags().GetString("name")
+	cfg, err := config.ReadFile()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		cfg.Host, cfg.Token = host, token
+	} else {
+		if cfg.Hosts == nil {
+			cfg.Hosts = make(map[string]config.Instance)
+		}
+		cfg.Hosts[name] = config.Instance{Host: host, Token: token}
 	}
 
 	if err := config.Save(cfg); err != nil {
@@ -89,6 +102,9 @@ func runInit(cmd *cobra.Command, args []string) error {
 		"config_path": configPath,
 		"host":        host,
 	}
+	if name != "" {
+		result["name"] = name
+	}
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	enc.Encode(result)
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 
 	"hepic-cli/internal/api"
 	"hepic-cli/internal/models"
@@ -28,8 +29,8 @@ func init() {
 	interceptionCreateCmd.Flags().String("description", "", "Description of the interception")
 	interceptionCreateCmd.Flags().String("ip", "", "IP address to filter")
 	interceptionCreateCmd.Flags().Bool("status", true, "Enable or disable the interception")
-	interceptionCreateCmd.Flags().String("start-date", "", "Start date for the interception")
-	interceptionCreateCmd.Flags().String("stop-date", "", "Stop date for the interception")
+	interceptionCreateCmd.Flags().String("start-date", "", "Start date for the interception (RFC3339, YYYY-MM-DD or unix ms)")
+	interceptionCreateCmd.Flags().String("stop-date", "", "Stop date for the interception (RFC3339, YYYY-MM-DD or unix ms)")
 }
 
 func runInterceptionCreate(cmd *cobra.Command, args []string) error {
@@ -57,10 +58,21 @@ func runInterceptionCreate(cmd *cobra.Command, args []string) error {
 		data.Status = status
 	}
 	if v, _ := cmd.Flags().GetString("start-date"); v != "" {
-		data.StartDate = v
+		t, err := models.ParseTime(v)
+		if err != nil {
+			return fmt.Errorf("invalid --start-date: %w", err)
+		}
+		data.StartDate = &t
 	}
 	if v, _ := cmd.Flags().GetString("stop-date"); v != "" {
-		data.StopDate = v
+		t, err := models.ParseTime(v)
+		if err != nil {
+			return fmt.Errorf("invalid --stop-date: %w", err)
+		}
+		data.StopDate = &t
+	}
+	if err := data.Validate(); err != nil {
+		return err
 	}
 
 	result, err := recording.CreateInterception(cmd.Context(), client, data)
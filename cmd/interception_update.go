@@ -30,8 +30,8 @@ func init() {
 	interceptionUpdateCmd.Flags().String("description", "", "Description of the interception")
 	interceptionUpdateCmd.Flags().String("ip", "", "IP address to filter")
 	interceptionUpdateCmd.Flags().Bool("status", true, "Enable or disable the interception")
-	interceptionUpdateCmd.Flags().String("start-date", "", "Start date for the interception")
-	interceptionUpdateCmd.Flags().String("stop-date", "", "Stop date for the interception")
+	interceptionUpdateCmd.Flags().String("start-date", "", "Start date for the interception (RFC3339, YYYY-MM-DD or unix ms)")
+	interceptionUpdateCmd.Flags().String("stop-date", "", "Stop date for the interception (RFC3339, YYYY-MM-DD or unix ms)")
 }
 
 func runInterceptionUpdate(cmd *cobra.Command, args []string) error {
@@ -64,10 +64,21 @@ func runInterceptionUpdate(cmd *cobra.Command, args []string) error {
 		data.Status = status
 	}
 	if v, _ := cmd.Flags().GetString("start-date"); v != "" {
-		data.StartDate = v
+		t, err := models.ParseTime(v)
+		if err != nil {
+			return fmt.Errorf("invalid --start-date: %w", err)
+		}
+		data.StartDate = &t
 	}
 	if v, _ := cmd.Flags().GetString("stop-date"); v != "" {
-		data.StopDate = v
+		t, err := models.ParseTime(v)
+		if err != nil {
+			return fmt.Errorf("invalid --stop-date: %w", err)
+		}
+		data.StopDate = &t
+	}
+	if err := data.ValidatePartial(); err != nil {
+		return err
 	}
 
 	result, err := recording.UpdateInterception(cmd.Context(), client, uuid, data)
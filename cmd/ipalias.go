@@ -6,13 +6,19 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"hepic-cli/internal/alias"
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/config"
 	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/models"
 	"hepic-cli/internal/output"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var ipaliasCmd = &cobra.Command{
@@ -41,6 +47,17 @@ var ipaliasListCmd = &cobra.Command{
 var ipaliasCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new IP alias",
+	Long: `Create a new IP alias for an address or CIDR block.
+
+The address, mask (0-32 for IPv4, 0-128 for IPv6) and port are validated
+before anything is sent. An alias that duplicates an existing entry is
+rejected; overlaps with other entries are reported as warnings because the
+most specific alias wins on lookup.
+
+Examples:
+  hepic ipalias create --ip 10.0.0.10 --alias sbc1
+  hepic ipalias create --ip 10.1.0.0 --mask 16 --alias office
+  hepic ipalias create --ip 2001:db8:: --mask 32 --alias v6-core`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := api.NewClient()
 		if err != nil {
@@ -48,27 +65,37 @@ var ipaliasCreateCmd = &cobra.Command{
 		}
 
 		ip, _ := cmd.Flags().GetString("ip")
-		alias, _ := cmd.Flags().GetString("alias")
+		name, _ := cmd.Flags().GetString("alias")
 		port, _ := cmd.Flags().GetInt("port")
 		mask, _ := cmd.Flags().GetInt("mask")
+		if !cmd.Flags().Changed("mask") {
+			mask = alias.HostMask(ip)
+		}
 		group, _ := cmd.Flags().GetString("group")
 		servertype, _ := cmd.Flags().GetString("servertype")
 		status, _ := cmd.Flags().GetBool("status")
 
-		data := map[string]interface{}{
-			"ip":         ip,
-			"alias":      alias,
-			"port":       port,
-			"mask":       mask,
-			"group":      group,
-			"servertype": servertype,
-			"status":     status,
+		data := models.AliasSwaggerStruct{
+			IP:         ip,
+			Alias:      name,
+			Port:       uint16(port),
+			Mask:       uint16(mask),
+			Group:      group,
+			Servertype: servertype,
+			Status:     status,
+		}
+		if err := data.Validate(); err != nil {
+			return err
+		}
+		if err := checkAlias(cmd, client, ip, mask, port, ""); err != nil {
+			return err
 		}
 
 		result, err := config_resources.CreateAlias(cmd.Context(), client, data)
 		if err != nil {
 			return err
 		}
+		invalidateAliasCache(client)
 		return output.Print(result)
 	},
 }
@@ -89,6 +116,9 @@ var ipaliasUpdateCmd = &cobra.Command{
 		if cmd.Flags().Changed("ip") {
 			v, _ := cmd.Flags().GetString("ip")
 			data["ip"] = v
+			if !cmd.Flags().Changed("mask") {
+				data["mask"] = alias.HostMask(v)
+			}
 		}
 		if cmd.Flags().Changed("alias") {
 			v, _ := cmd.Flags().GetString("alias")
@@ -115,10 +145,46 @@ var ipaliasUpdateCmd = &cobra.Command{
 			data["status"] = v
 		}
 
+		if cmd.Flags().Changed("ip") || cmd.Flags().Changed("mask") || cmd.Flags().Changed("port") {
+			table, err := freshAliasTable(cmd, client)
+			if err != nil {
+				return err
+			}
+			current, ok := table.Find(uuid)
+			if !ok && !cmd.Flags().Changed("ip") {
+				return fmt.Errorf("IP alias %s not found", uuid)
+			}
+			ip, mask, port := current.IP, int(current.Mask), int(current.Port)
+			if !ok {
+				mask = 32
+			}
+			if v, ok := data["ip"].(string); ok {
+				ip = v
+			}
+			if v, ok := data["mask"].(int); ok {
+				mask = v
+			}
+			if v, ok := data["port"].(int); ok {
+				port = v
+			}
+			if err := checkAliasAgainst(table, ip, mask, port, uuid); err != nil {
+				return err
+			}
+		}
+
+		var spec models.AliasSwaggerStruct
+		if err := body.Decode(data, &spec); err != nil {
+			return err
+		}
+		if err := spec.ValidatePartial(); err != nil {
+			return err
+		}
+
 		result, err := config_resources.UpdateAlias(cmd.Context(), client, uuid, data)
 		if err != nil {
 			return err
 		}
+		invalidateAliasCache(client)
 		return output.Print(result)
 	},
 }
@@ -143,6 +209,7 @@ var ipaliasDeleteCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		invalidateAliasCache(client)
 		return output.Print(result)
 	},
 }
@@ -166,6 +233,7 @@ var ipaliasDeleteAllCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		invalidateAliasCache(client)
 		return output.Print(result)
 	},
 }
@@ -213,25 +281,121 @@ var ipaliasExportCmd = &cobra.Command{
 var ipaliasImportCmd = &cobra.Command{
 	Use:   "import",
 	Short: "Import IP aliases from a CSV file",
+	Long: `Import IP aliases from a CSV file.
+
+The file is validated before upload: it needs ip and alias columns, and ip,
+mask, port and status values are checked line by line. With --mode replace
+all aliases not in the file are removed; a preview of what will be added and
+removed is shown before anything changes.
+
+Examples:
+  hepic ipalias import --file aliases.csv
+  hepic ipalias import --file aliases.csv --dry-run
+  hepic ipalias import --file aliases.csv --mode replace --dry-run --format table
+  hepic ipalias import --file legacy.csv --source h2 --mode replace --force`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filePath, _ := cmd.Flags().GetString("file")
 		if filePath == "" {
 			return fmt.Errorf("--file is required")
 		}
+		return ipaliasImport.run(cmd, filePath)
+	},
+}
 
-		client, err := api.NewClient()
+var ipaliasImport = csvImport{
+	noun:   "IP aliases",
+	schema: config_resources.AliasCSVSchema,
+	serverKeys: func(cmd *cobra.Command, client *api.Client) ([]string, error) {
+		aliases, err := alias.Fetch(cmd.Context(), client)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("failed to load existing IP aliases: %w", err)
 		}
-
-		result, err := config_resources.ImportAliases(cmd.Context(), client, filePath)
-		if err != nil {
-			return err
+		return config_resources.AliasKeys(aliases), nil
+	},
+	upload: func(cmd *cobra.Command, client *api.Client, file, mode, source string) (json.RawMessage, error) {
+		result, err := config_resources.ImportAliasesMode(cmd.Context(), client, file, mode, source)
+		if err == nil {
+			invalidateAliasCache(client)
 		}
-		return output.Print(result)
+		return result, err
 	},
 }
 
+// checkAlias validates an alias address and rejects it when it duplicates an
+// existing entry. Overlaps with other entries are legitimate (the most
+// specific alias wins) and are only reported on stderr.
+func checkAlias(cmd *cobra.Command, client *api.Client, ip string, mask, port int, skipUUID string) error {
+	if _, err := alias.Validate(ip, mask, port); err != nil {
+		return err
+	}
+	table, err := freshAliasTable(cmd, client)
+	if err != nil {
+		return err
+	}
+	return checkAliasAgainst(table, ip, mask, port, skipUUID)
+}
+
+func checkAliasAgainst(table *alias.Table, ip string, mask, port int, skipUUID string) error {
+	prefix, err := alias.Validate(ip, mask, port)
+	if err != nil {
+		return err
+	}
+	for _, c := range table.Conflicts(prefix, port, skipUUID) {
+		if c.Kind == alias.Duplicate {
+			return fmt.Errorf("%s/%d is a %s", prefix.Addr(), prefix.Bits(), c)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %s/%d is an %s\n", prefix.Addr(), prefix.Bits(), c)
+	}
+	return nil
+}
+
+// freshAliasTable fetches the current alias list, bypassing the cache.
+func freshAliasTable(cmd *cobra.Command, client *api.Client) (*alias.Table, error) {
+	aliases, err := alias.Fetch(cmd.Context(), client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing IP aliases: %w", err)
+	}
+	return alias.NewTable(aliases), nil
+}
+
+// resolveAliases returns the (cached) alias table when --resolve-aliases is
+// set, or nil otherwise. A nil table resolves nothing.
+func resolveAliases(cmd *cobra.Command, client *api.Client) (*alias.Table, error) {
+	if !viper.GetBool("resolve-aliases") {
+		return nil, nil
+	}
+	return loadAliasTable(cmd, client)
+}
+
+// loadAliasTable returns the alias table, served from the on-disk cache in
+// ~/.hepic/cache while it is younger than alias_cache_ttl.
+func loadAliasTable(cmd *cobra.Command, client *api.Client) (*alias.Table, error) {
+	dir, err := aliasCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	table, err := alias.Load(cmd.Context(), client, dir, viper.GetDuration("alias_cache_ttl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load IP aliases: %w", err)
+	}
+	return table, nil
+}
+
+func aliasCacheDir() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache"), nil
+}
+
+// invalidateAliasCache drops the cached alias table after a change.
+func invalidateAliasCache(client *api.Client) {
+	if dir, err := aliasCacheDir(); err == nil {
+		alias.Invalidate(client, dir)
+	}
+}
+
 // confirmAction prompts the user for confirmation on stderr.
 // Returns true if the user answers "y" or "yes".
 func confirmAction(prompt string) bool {
@@ -260,7 +424,7 @@ func init() {
 	ipaliasCreateCmd.Flags().String("ip", "", "IP address (required)")
 	ipaliasCreateCmd.Flags().String("alias", "", "Alias name (required)")
 	ipaliasCreateCmd.Flags().Int("port", 0, "Port number")
-	ipaliasCreateCmd.Flags().Int("mask", 32, "Network mask")
+	ipaliasCreateCmd.Flags().Int("mask", 0, "Network mask (default: 32 for IPv4, 128 for IPv6)")
 	ipaliasCreateCmd.Flags().String("group", "", "Group name")
 	ipaliasCreateCmd.Flags().String("servertype", "", "Server type")
 	ipaliasCreateCmd.Flags().Bool("status", true, "Status (active/inactive)")
@@ -271,7 +435,7 @@ func init() {
 	ipaliasUpdateCmd.Flags().String("ip", "", "IP address")
 	ipaliasUpdateCmd.Flags().String("alias", "", "Alias name")
 	ipaliasUpdateCmd.Flags().Int("port", 0, "Port number")
-	ipaliasUpdateCmd.Flags().Int("mask", 32, "Network mask")
+	ipaliasUpdateCmd.Flags().Int("mask", 0, "Network mask (default with --ip: 32 for IPv4, 128 for IPv6)")
 	ipaliasUpdateCmd.Flags().String("group", "", "Group name")
 	ipaliasUpdateCmd.Flags().String("servertype", "", "Server type")
 	ipaliasUpdateCmd.Flags().Bool("status", true, "Status (active/inactive)")
@@ -288,4 +452,5 @@ func init() {
 	ipaliasCmd.AddCommand(ipaliasImportCmd)
 	ipaliasImportCmd.Flags().String("file", "", "CSV file to import (required)")
 	ipaliasImportCmd.MarkFlagRequired("file")
+	addCSVImportFlags(ipaliasImportCmd)
 }
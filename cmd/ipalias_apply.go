@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"hepic-cli/internal/alias"
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var ipaliasApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Sync IP aliases from a YAML or JSON file",
+	Long: `Make the server's IP aliases match a version-controlled file.
+
+Aliases are matched on ip, mask and port. Matching entries whose alias,
+group, servertype or status differ are updated, missing ones are created.
+Server entries that are not in the file are only deleted with --prune.
+
+The file is a list of aliases or a mapping with an "aliases" list:
+
+  aliases:
+    - ip: 10.0.0.10
+      alias: sbc1
+      port: 5060
+    - ip: 10.1.0.0
+      mask: 16
+      alias: office
+      group: branches
+
+mask defaults to a single host (32 or 128) and status defaults to true.
+
+Examples:
+  hepic ipalias apply -f aliases.yaml --dry-run --format table
+  hepic ipalias apply -f aliases.yaml
+  hepic ipalias apply -f aliases.yaml --prune --force`,
+	RunE: runIPAliasApply,
+}
+
+var ipaliasDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show how the server's IP aliases differ from a file",
+	Long: `Show the changes "hepic ipalias apply" would make, without applying them.
+
+Exits with a non-zero status when the server has drifted from the file,
+which makes it suitable for CI checks.
+
+Examples:
+  hepic ipalias diff -f aliases.yaml --format table
+  hepic ipalias diff -f aliases.yaml --prune`,
+	RunE: runIPAliasDiff,
+}
+
+func init() {
+	ipaliasCmd.AddCommand(ipaliasApplyCmd)
+	ipaliasApplyCmd.Flags().StringP("file", "f", "", "YAML or JSON alias file (required)")
+	ipaliasApplyCmd.Flags().Bool("dry-run", false, "Print the plan without changing anything")
+	ipaliasApplyCmd.Flags().Bool("prune", false, "Delete server aliases that are not in the file")
+	ipaliasApplyCmd.Flags().Bool("force", false, "Skip confirmation prompt when deleting")
+	ipaliasApplyCmd.MarkFlagRequired("file")
+
+	ipaliasCmd.AddCommand(ipaliasDiffCmd)
+	ipaliasDiffCmd.Flags().StringP("file", "f", "", "YAML or JSON alias file (required)")
+	ipaliasDiffCmd.Flags().Bool("prune", false, "Also report server aliases that are not in the file")
+	ipaliasDiffCmd.MarkFlagRequired("file")
+}
+
+// planAliases reads the alias file and diffs it against the server.
+func planAliases(cmd *cobra.Command) (*api.Client, []alias.Change, error) {
+	file, _ := cmd.Flags().GetString("file")
+	prune, _ := cmd.Flags().GetBool("prune")
+
+	specs, err := alias.ReadSpecs(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	current, err := alias.Fetch(cmd.Context(), client)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load existing IP aliases: %w", err)
+	}
+
+	changes, err := alias.Plan(specs, current, prune)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, changes, nil
+}
+
+func runIPAliasDiff(cmd *cobra.Command, args []string) error {
+	_, changes, err := planAliases(cmd)
+	if err != nil {
+		return err
+	}
+	if err := output.Print(changes); err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("server has drifted: %d change(s) pending", len(changes))
+	}
+	return nil
+}
+
+func runIPAliasApply(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	client, changes, err := planAliases(cmd)
+	if err != nil {
+		return err
+	}
+	if dryRun || len(changes) == 0 {
+		return output.Print(changes)
+	}
+
+	deletes := 0
+	for _, c := range changes {
+		fmt.Fprintf(os.Stderr, "  %-6s %s/%d port %d %q %s\n", c.Action, c.IP, c.Mask, c.Port, c.Alias, c.Details)
+		if c.Action == alias.ActionDelete {
+			deletes++
+		}
+	}
+	if deletes > 0 && !force {
+		if !confirmAction(fmt.Sprintf("Apply %d change(s), deleting %d IP alias(es)?", len(changes), deletes)) {
+			return fmt.Errorf("operation cancelled")
+		}
+	}
+
+	defer invalidateAliasCache(client)
+	return applyChanges(changes, func(c *alias.Change) (string, error) {
+		var err error
+		switch c.Action {
+		case alias.ActionCreate:
+			_, err = config_resources.CreateAlias(cmd.Context(), client, c.Body)
+		case alias.ActionUpdate:
+			_, err = config_resources.UpdateAlias(cmd.Context(), client, c.UUID, c.Body)
+		case alias.ActionDelete:
+			_, err = config_resources.DeleteAlias(cmd.Context(), client, c.UUID)
+		}
+		return changeOK, err
+	})
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/agent"
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/call"
+	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/lookup"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var lookupCmd = &cobra.Command{
+	Use:     "lookup",
+	Short:   "Look up what an IP address is",
+	GroupID: "call",
+	Long: `Look up IP addresses against IP aliases, capture agents and SIP traffic.
+
+Available subcommands:
+  ip        Describe an IP address
+  update    Update an IP alias from a lookup`,
+}
+
+var lookupIPCmd = &cobra.Command{
+	Use:   "ip <addr>",
+	Short: "Describe an IP address",
+	Long: `Describe an IP address: its IP alias (most specific match), group and
+server type, the capture agents running on it or in the same alias network,
+and when it was last seen in SIP traffic.
+
+Examples:
+  hepic lookup ip 10.0.0.10
+  hepic lookup ip 10.0.0.10 --since 2h --format table
+  hepic lookup ip 2001:db8::1 --no-traffic`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLookupIP,
+}
+
+var lookupUpdateCmd = &cobra.Command{
+	Use:   "update <uuid>",
+	Short: "Update an IP alias from a lookup",
+	Long: `Update an existing IP alias based upon the lookup.
+
+Examples:
+  hepic lookup update <uuid> --data '{"alias":"sbc1","group":"core"}'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dataStr, _ := cmd.Flags().GetString("data")
+		data, err := parseJSONFlag(dataStr)
+		if err != nil {
+			return err
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		result, err := config_resources.UpdateLookupIP(cmd.Context(), client, args[0], data)
+		if err != nil {
+			return err
+		}
+		invalidateAliasCache(client)
+		return output.Print(result)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lookupCmd)
+
+	lookupCmd.AddCommand(lookupIPCmd)
+	lookupIPCmd.Flags().Duration("since", 24*time.Hour, "How far back to search SIP traffic")
+	lookupIPCmd.Flags().Bool("no-traffic", false, "Skip the SIP traffic search")
+
+	lookupCmd.AddCommand(lookupUpdateCmd)
+	lookupUpdateCmd.Flags().String("data", "", "JSON body with the fields to update (required)")
+	lookupUpdateCmd.MarkFlagRequired("data")
+}
+
+func runLookupIP(cmd *cobra.Command, args []string) error {
+	addr, err := netip.ParseAddr(strings.Trim(args[0], "[]"))
+	if err != nil {
+		return fmt.Errorf("invalid IP address %q", args[0])
+	}
+	since, _ := cmd.Flags().GetDuration("since")
+	noTraffic, _ := cmd.Flags().GetBool("no-traffic")
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+
+	table, err := loadAliasTable(cmd, client)
+	if err != nil {
+		return err
+	}
+
+	agents, err := agent.ListLocations(cmd.Context(), client)
+	if err != nil {
+		return fmt.Errorf("failed to load capture agents: %w", err)
+	}
+
+	var calls []models.CallElement
+	from := time.Now().Add(-since)
+	if !noTraffic {
+		params, err := lookup.NewSearchParams(addr, from)
+		if err != nil {
+			return err
+		}
+		result, err := call.SearchData(cmd.Context(), client, params)
+		if err != nil {
+			return fmt.Errorf("failed to search SIP traffic: %w", err)
+		}
+		calls = result.Data
+		if calls == nil {
+			calls = []models.CallElement{}
+		}
+	}
+
+	return output.Print(lookup.Build(addr, table, agents, calls, from))
+}
@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
 	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/models"
 	"hepic-cli/internal/output"
 
 	"github.com/spf13/cobra"
@@ -13,7 +16,7 @@ import (
 var mappingCmd = &cobra.Command{
 	Use:     "mapping",
 	Short:   "Manage protocol mappings",
-	Long:    "List, create, update, delete, and reset protocol mappings.",
+	Long:    "List, create, update, delete, reset, pull, push and edit protocol mappings.",
 	GroupID: "config",
 }
 
@@ -36,32 +39,43 @@ var mappingListCmd = &cobra.Command{
 var mappingCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new protocol mapping",
+	Long: `Create a new protocol mapping.
+
+The body is built from --file, then --data, then the individual flags, and
+validated before it is sent: hepid and profile are required, the JSON-valued
+fields (fields_mapping, correlation_mapping, ...) must hold JSON objects or
+arrays, and the hepid/profile combination must not be mapped already.
+
+Examples:
+  hepic mapping create -f mapping.yaml
+  hepic mapping create --hepid 100 --profile default --data @mapping.json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client, err := api.NewClient()
+		data, err := readBody(cmd, false)
 		if err != nil {
 			return err
 		}
+		setFromFlags(cmd, data, mappingFlagKeys)
 
-		hepid, _ := cmd.Flags().GetInt("hepid")
-		profile, _ := cmd.Flags().GetString("profile")
-
-		data := map[string]interface{}{
-			"hepid":   hepid,
-			"profile": profile,
+		var mapping models.MappingSchema
+		if err := body.Decode(data, &mapping); err != nil {
+			return err
+		}
+		if err := config_resources.ValidateMapping(mapping, false); err != nil {
+			return err
 		}
 
-		dataFlag, _ := cmd.Flags().GetString("data")
-		if dataFlag != "" {
-			extra, err := parseJSONFlag(dataFlag)
-			if err != nil {
-				return err
-			}
-			for k, v := range extra {
-				data[k] = v
-			}
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		protocols, err := config_resources.ListProtocols(cmd.Context(), client)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cannot check hepid/profile: %v\n", err)
+		} else if config_resources.CheckProtocol(protocols, mapping.Hepid, mapping.Profile) == nil {
+			return fmt.Errorf("hepid %d profile %q is already mapped: use mapping update", mapping.Hepid, mapping.Profile)
 		}
 
-		result, err := config_resources.CreateMapping(cmd.Context(), client, data)
+		result, err := config_resources.CreateMapping(cmd.Context(), client, mapping)
 		if err != nil {
 			return err
 		}
@@ -72,37 +86,35 @@ var mappingCreateCmd = &cobra.Command{
 var mappingUpdateCmd = &cobra.Command{
 	Use:   "update <uuid>",
 	Short: "Update an existing protocol mapping",
-	Args:  cobra.ExactArgs(1),
+	Long: `Update an existing protocol mapping. Only the given fields are sent.
+
+Examples:
+  hepic mapping update <uuid> --data '{"retention":14}'
+  hepic mapping update <uuid> -f mapping.yaml`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client, err := api.NewClient()
+		data, err := readBody(cmd, false)
 		if err != nil {
 			return err
 		}
+		setFromFlags(cmd, data, mappingFlagKeys)
+		if len(data) == 0 {
+			return fmt.Errorf("nothing to update: use flags, --data or --file")
+		}
 
-		uuid := args[0]
-		data := make(map[string]interface{})
-
-		if cmd.Flags().Changed("hepid") {
-			v, _ := cmd.Flags().GetInt("hepid")
-			data["hepid"] = v
+		var mapping models.MappingSchema
+		if err := body.Decode(data, &mapping); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("profile") {
-			v, _ := cmd.Flags().GetString("profile")
-			data["profile"] = v
+		if err := config_resources.ValidateMapping(mapping, true); err != nil {
+			return err
 		}
 
-		dataFlag, _ := cmd.Flags().GetString("data")
-		if dataFlag != "" {
-			extra, err := parseJSONFlag(dataFlag)
-			if err != nil {
-				return err
-			}
-			for k, v := range extra {
-				data[k] = v
-			}
+		client, err := api.NewClient()
+		if err != nil {
+			return err
 		}
-
-		result, err := config_resources.UpdateMapping(cmd.Context(), client, uuid, data)
+		result, err := config_resources.UpdateMapping(cmd.Context(), client, args[0], data)
 		if err != nil {
 			return err
 		}
@@ -110,6 +122,12 @@ var mappingUpdateCmd = &cobra.Command{
 	},
 }
 
+// mappingFlagKeys maps mapping create/update flags to body fields.
+var mappingFlagKeys = map[string]string{
+	"hepid":   "hepid",
+	"profile": "profile",
+}
+
 var mappingDeleteCmd = &cobra.Command{
 	Use:   "delete <uuid>",
 	Short: "Delete a protocol mapping",
@@ -194,12 +212,12 @@ func init() {
 	mappingCmd.AddCommand(mappingCreateCmd)
 	mappingCreateCmd.Flags().Int("hepid", 0, "HEP ID")
 	mappingCreateCmd.Flags().String("profile", "", "Profile name")
-	mappingCreateCmd.Flags().String("data", "", "Additional mapping data as JSON")
+	addBodyFlags(mappingCreateCmd, "Mapping body")
 
 	mappingCmd.AddCommand(mappingUpdateCmd)
 	mappingUpdateCmd.Flags().Int("hepid", 0, "HEP ID")
 	mappingUpdateCmd.Flags().String("profile", "", "Profile name")
-	mappingUpdateCmd.Flags().String("data", "", "Additional mapping data as JSON")
+	addBodyFlags(mappingUpdateCmd, "Fields to update")
 
 	mappingCmd.AddCommand(mappingDeleteCmd)
 	mappingDeleteCmd.Flags().Bool("force", false, "Skip confirmation prompt")
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/jsondiff"
+	"hepic-cli/internal/mappingdir"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var mappingPullCmd = &cobra.Command{
+	Use:   "pull <uuid>",
+	Short: "Download a protocol mapping into a directory of files",
+	Long: `Download a protocol mapping and split it into readable files:
+
+  mapping.yaml              hepid, profile, retention and other scalar fields
+  create_table.sql          the create_table statement, if set
+  fields_mapping.json       one pretty-printed file per JSON sub-document
+  correlation_mapping.json
+  ...
+
+Edit the files and upload them with "hepic mapping push".
+
+Examples:
+  hepic mapping pull <uuid>
+  hepic mapping pull <uuid> -o mappings/sip-call`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMappingPull,
+}
+
+var mappingPushCmd = &cobra.Command{
+	Use:   "push <dir>",
+	Short: "Validate and upload a mapping directory",
+	Long: `Validate a directory written by "hepic mapping pull", show how it differs
+from the mapping on the server and upload it.
+
+JSON sub-documents are compared by value; fields_mapping entries are matched
+by their id, so reordering them is not reported as a change.
+
+Examples:
+  hepic mapping push mappings/sip-call --dry-run --format table
+  hepic mapping push mappings/sip-call
+  hepic mapping push mappings/sip-call --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMappingPush,
+}
+
+var mappingEditCmd = &cobra.Command{
+	Use:   "edit <uuid>",
+	Short: "Edit a protocol mapping in $EDITOR",
+	Long: `Open a protocol mapping in $VISUAL or $EDITOR (default vi), split into the
+same files as "hepic mapping pull". The files are validated when the editor
+exits; on error you can re-open the editor. Nothing is saved unless the
+mapping validates and has changed.
+
+Examples:
+  hepic mapping edit <uuid>
+  EDITOR="code --wait" hepic mapping edit <uuid>`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMappingEdit,
+}
+
+func init() {
+	mappingCmd.AddCommand(mappingPullCmd)
+	mappingPullCmd.Flags().StringP("output", "o", "", "Directory to write to (default: the mapping UUID)")
+
+	mappingCmd.AddCommand(mappingPushCmd)
+	mappingPushCmd.Flags().Bool("dry-run", false, "Show the changes without uploading")
+	mappingPushCmd.Flags().Bool("force", false, "Skip confirmation prompt")
+
+	mappingCmd.AddCommand(mappingEditCmd)
+}
+
+func runMappingPull(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("output")
+	if dir == "" {
+		dir = args[0]
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	mapping, err := config_resources.GetMapping(cmd.Context(), client, args[0])
+	if err != nil {
+		return err
+	}
+	if mapping.Guid == "" {
+		mapping.Guid = args[0]
+	}
+
+	files, err := mappingdir.Write(dir, mapping)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", dir, err)
+	}
+	return output.Print(map[string]interface{}{
+		"uuid":  mapping.Guid,
+		"dir":   dir,
+		"files": files,
+	})
+}
+
+// readMappingDir loads and validates a mapping directory.
+func readMappingDir(dir string) (models.MappingSchema, error) {
+	mapping, err := mappingdir.Read(dir)
+	if err != nil {
+		return mapping, err
+	}
+	if mapping.Guid == "" {
+		return mapping, fmt.Errorf("%s has no guid: use mapping create for new mappings", filepath.Join(dir, mappingdir.MetaFile))
+	}
+	if err := config_resources.ValidateMapping(mapping, false); err != nil {
+		return mapping, err
+	}
+	return mapping, nil
+}
+
+func runMappingPush(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	local, err := readMappingDir(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	remote, err := config_resources.GetMapping(cmd.Context(), client, local.Guid)
+	if err != nil {
+		return err
+	}
+
+	changes := mappingdir.Diff(remote, local)
+	if dryRun || len(changes) == 0 {
+		return output.Print(changes)
+	}
+	if !force {
+		printMappingChanges(changes)
+		if !confirmAction(fmt.Sprintf("Push %d change(s) to mapping %s?", len(changes), local.Guid)) {
+			return fmt.Errorf("operation cancelled")
+		}
+	}
+	return updateMapping(cmd, client, local)
+}
+
+func runMappingEdit(cmd *cobra.Command, args []string) error {
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	remote, err := config_resources.GetMapping(cmd.Context(), client, args[0])
+	if err != nil {
+		return err
+	}
+	if remote.Guid == "" {
+		remote.Guid = args[0]
+	}
+
+	dir, err := os.MkdirTemp("", "hepic-mapping-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if _, err := mappingdir.Write(dir, remote); err != nil {
+		return err
+	}
+
+	var local models.MappingSchema
+	for {
+		if err := runEditor(mappingdir.Files(dir)); err != nil {
+			return err
+		}
+		local, err = readMappingDir(dir)
+		if err == nil && local.Guid != remote.Guid {
+			err = fmt.Errorf("guid cannot be changed (was %s)", remote.Guid)
+		}
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if !confirmAction("Re-open the editor?") {
+			return fmt.Errorf("edit cancelled, no changes made")
+		}
+	}
+
+	changes := mappingdir.Diff(remote, local)
+	if len(changes) == 0 {
+		fmt.Fprintln(os.Stderr, "Edit cancelled, no changes made.")
+		return nil
+	}
+	printMappingChanges(changes)
+	return updateMapping(cmd, client, local)
+}
+
+func printMappingChanges(changes []jsondiff.Change) {
+	for _, c := range changes {
+		switch c.Action {
+		case jsondiff.Added:
+			fmt.Fprintf(os.Stderr, "  + %s: %s\n", c.Path, c.New)
+		case jsondiff.Removed:
+			fmt.Fprintf(os.Stderr, "  - %s: %s\n", c.Path, c.Old)
+		default:
+			fmt.Fprintf(os.Stderr, "  ~ %s: %s -> %s\n", c.Path, c.Old, c.New)
+		}
+	}
+}
+
+func updateMapping(cmd *cobra.Command, client *api.Client, mapping models.MappingSchema) error {
+	data, err := mappingdir.Body(mapping)
+	if err != nil {
+		return err
+	}
+	result, err := config_resources.UpdateMapping(cmd.Context(), client, mapping.Guid, data)
+	if err != nil {
+		return err
+	}
+	return output.Print(result)
+}
+
+// runEditor opens files in $VISUAL or $EDITOR, which may include arguments
+// (e.g. "code --wait").
+func runEditor(files []string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], files...)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
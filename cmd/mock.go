@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"hepic-cli/internal/mocksrv"
+
+	"github.com/spf13/cobra"
+)
+
+// SwaggerSpec is the swagger.json the binary was built with, set by main.
+var SwaggerSpec []byte
+
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Run a mock HEPIC API for offline testing",
+}
+
+var mockServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a mock HEPIC API generated from swagger.json",
+	Long: `Serve every path of the HEPIC API with sample responses built from
+the swagger.json schemas, for developing scripts and demos without a HEPIC
+instance. Requests must carry the Auth-Token given with --accept-token (any
+token if unset); POST /auth returns it.
+
+IP aliases, users, scripts, hepsub and mappings keep in-memory state, so
+created items show up in lists until the server stops.
+
+With --fixtures, call searches (/search/call/data, /search/call/message,
+/call/transaction) answer from the *.json files in that directory. Each
+holds a list of message rows, or an object with a "data" list, with the
+fields the API returns: sid, create_date (ms) or micro_ts (us), srcIp,
+srcPort, dstIp, dstPort, method, from_user, ruri_user and raw. Rows are
+filtered by time range, Call-ID and caller/callee.
+
+Examples:
+  hepic mock serve --port 8080 --accept-token secret
+  hepic --host http://127.0.0.1:8080 --token secret ipalias list
+  hepic mock serve --fixtures ./testdata/calls --listen 0.0.0.0`,
+	Args: cobra.NoArgs,
+	RunE: runMockServe,
+}
+
+func init() {
+	rootCmd.AddCommand(mockCmd)
+	mockCmd.AddCommand(mockServeCmd)
+	mockServeCmd.Flags().Int("port", 8080, "Port to listen on")
+	mockServeCmd.Flags().String("listen", "127.0.0.1", "Address to listen on")
+	mockServeCmd.Flags().String("accept-token", "", "Auth-Token to accept (any token if empty)")
+	mockServeCmd.Flags().String("spec", "", "swagger.json to serve (default: the one built in)")
+	mockServeCmd.Flags().String("fixtures", "", "Directory of call fixture *.json files")
+	mockServeCmd.Flags().Bool("quiet", false, "Do not log requests")
+}
+
+func runMockServe(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	listen, _ := cmd.Flags().GetString("listen")
+	token, _ := cmd.Flags().GetString("accept-token")
+	specPath, _ := cmd.Flags().GetString("spec")
+	fixtures, _ := cmd.Flags().GetString("fixtures")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	spec := SwaggerSpec
+	if specPath != "" {
+		data, err := os.ReadFile(specPath)
+		if err != nil {
+			return fmt.Errorf("failed to read spec: %w", err)
+		}
+		spec = data
+	}
+	if len(spec) == 0 {
+		return fmt.Errorf("no swagger spec built in; use --spec")
+	}
+
+	opts := mocksrv.Options{Token: token, Fixtures: fixtures}
+	if !quiet {
+		opts.Log = os.Stderr
+	}
+	srv, err := mocksrv.New(spec, opts)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(listen, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Mock HEPIC API listening on http://%s/api/v3\n", ln.Addr())
+	if token == "" {
+		fmt.Fprintln(os.Stderr, "Any Auth-Token is accepted")
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Handler: srv}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	if err := server.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
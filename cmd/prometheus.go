@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"hepic-cli/internal/api"
 	"hepic-cli/internal/output"
@@ -19,66 +22,41 @@ var prometheusCmd = &cobra.Command{
 }
 
 var prometheusQueryCmd = &cobra.Command{
-	Use:   "query",
+	Use:   "query [promql...]",
 	Short: "Query Prometheus data",
-	Long: `Query Prometheus time-series data.
+	Long: `Query Prometheus time-series data over a time range.
+
+Each PromQL expression is evaluated from --from to --to every --step.
+--match adds label matchers to every selector in the expressions. With
+--graph, each series is drawn as a chart; with --format csv or table, the
+result has one row per timestamp and one column per series.
+Times are absolute (RFC3339, YYYY-MM-DD, unix ms) or relative to now, as
+in now-6h or now-7d.
 
 Examples:
-  hepic prometheus query --data '{"param":{"query":"up"},"timestamp":{"from":1700000000,"to":1700003600}}'`,
+  hepic prometheus query 'rate(heplify_packets_total[5m])' --from now-6h --step 1m
+  hepic prometheus query up --match job=hepic --graph
+  hepic prometheus query 'sum by (method) (rate(sip_requests_total[5m]))' --format csv > methods.csv
+  hepic prometheus query --data '{"param":{"metrics":["up"]},"timestamp":{"from":1700000000000,"to":1700003600000}}'`,
+	Annotations: map[string]string{formatsAnnotation: "csv"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dataStr, _ := cmd.Flags().GetString("data")
-		if dataStr == "" {
-			return fmt.Errorf("--data flag is required")
-		}
-
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			return fmt.Errorf("invalid JSON in --data: %w", err)
-		}
-
-		client, err := api.NewClient()
-		if err != nil {
-			return err
-		}
-
-		result, err := statistic.QueryData(cmd.Context(), client, data)
-		if err != nil {
-			return err
-		}
-
-		return output.Print(result)
+		return runPrometheus(cmd, args, statistic.QueryData)
 	},
 }
 
 var prometheusValueCmd = &cobra.Command{
-	Use:   "value",
+	Use:   "value [promql...]",
 	Short: "Query a Prometheus metric value",
-	Long: `Query a specific Prometheus metric value.
+	Long: `Query the current value of Prometheus expressions.
+
+Takes the same flags as query.
 
 Examples:
-  hepic prometheus value --data '{"param":{"query":"up"}}'`,
+  hepic prometheus value up --match job=hepic
+  hepic prometheus value --data '{"param":{"metrics":["up"]}}'`,
+	Annotations: map[string]string{formatsAnnotation: "csv"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dataStr, _ := cmd.Flags().GetString("data")
-		if dataStr == "" {
-			return fmt.Errorf("--data flag is required")
-		}
-
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			return fmt.Errorf("invalid JSON in --data: %w", err)
-		}
-
-		client, err := api.NewClient()
-		if err != nil {
-			return err
-		}
-
-		result, err := statistic.QueryValue(cmd.Context(), client, data)
-		if err != nil {
-			return err
-		}
-
-		return output.Print(result)
+		return runPrometheus(cmd, args, statistic.QueryValue)
 	},
 }
 
@@ -108,7 +86,8 @@ var prometheusLabelCmd = &cobra.Command{
 
 Examples:
   hepic prometheus label instance`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completePrometheusLabels,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		label := args[0]
 
@@ -129,13 +108,145 @@ Examples:
 func init() {
 	rootCmd.AddCommand(prometheusCmd)
 
-	prometheusCmd.AddCommand(prometheusQueryCmd)
-	prometheusQueryCmd.Flags().String("data", "", "Query data as JSON string (required)")
-
-	prometheusCmd.AddCommand(prometheusValueCmd)
-	prometheusValueCmd.Flags().String("data", "", "Query data as JSON string (required)")
+	for _, c := range []*cobra.Command{prometheusQueryCmd, prometheusValueCmd} {
+		prometheusCmd.AddCommand(c)
+		c.Flags().String("data", "", "Raw query body as JSON, instead of expressions")
+		c.Flags().String("from", "now-1h", "Start time (RFC3339, YYYY-MM-DD, unix ms or now-<duration>)")
+		c.Flags().String("to", "now", "End time (RFC3339, YYYY-MM-DD, unix ms or now-<duration>)")
+		c.Flags().Duration("step", time.Minute, "Resolution of the result")
+		c.Flags().StringArray("match", nil, "Label matcher added to every selector, e.g. job=hepic (repeatable)")
+		addSeriesFlags(c)
+		c.RegisterFlagCompletionFunc("match", completePrometheusMatch)
+	}
 
 	prometheusCmd.AddCommand(prometheusLabelsCmd)
 
 	prometheusCmd.AddCommand(prometheusLabelCmd)
 }
+
+// runPrometheus sends a query built from the expressions and flags, or
+// the raw --data, and prints the result.
+func runPrometheus(cmd *cobra.Command, args []string, query func(context.Context, *api.Client, interface{}) (json.RawMessage, error)) error {
+	dataStr, _ := cmd.Flags().GetString("data")
+	var data interface{}
+	switch {
+	case dataStr != "" && len(args) > 0:
+		return fmt.Errorf("use either --data or expressions, not both")
+	case dataStr != "":
+		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
+			return fmt.Errorf("invalid JSON in --data: %w", err)
+		}
+	case len(args) == 0:
+		return fmt.Errorf("a PromQL expression or --data is required")
+	default:
+		q, err := prometheusQuery(cmd, args)
+		if err != nil {
+			return err
+		}
+		data = q
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := query(cmd.Context(), client, data)
+	if err != nil {
+		return err
+	}
+
+	return printSeries(cmd, result, statistic.ParseMatrix)
+}
+
+// prometheusQuery builds the query body from the expressions and flags.
+func prometheusQuery(cmd *cobra.Command, exprs []string) (interface{}, error) {
+	now := time.Now()
+	fromFlag, _ := cmd.Flags().GetString("from")
+	toFlag, _ := cmd.Flags().GetString("to")
+	step, _ := cmd.Flags().GetDuration("step")
+	matchFlags, _ := cmd.Flags().GetStringArray("match")
+
+	from, err := statistic.ParseTime(fromFlag, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --from value: %w", err)
+	}
+	to, err := statistic.ParseTime(toFlag, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --to value: %w", err)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("--from must be before --to")
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("--step must be positive")
+	}
+
+	var matchers []string
+	for _, m := range matchFlags {
+		matcher, err := statistic.ParseMatcher(m)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+	queries := make([]string, len(exprs))
+	for i, e := range exprs {
+		q, err := statistic.AddMatchers(e, matchers)
+		if err != nil {
+			return nil, err
+		}
+		queries[i] = q
+	}
+	return statistic.NewPrometheusQuery(queries, from, to, step), nil
+}
+
+// completePrometheusLabels completes label names from the server.
+func completePrometheusLabels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	client, err := api.NewClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	labels, err := statistic.LabelNames(cmd.Context(), client)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return labels, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePrometheusMatch completes --match: label names, then the values
+// of the label once "label=" is typed.
+func completePrometheusMatch(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, err := api.NewClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	i := strings.IndexAny(toComplete, "=!")
+	if i < 0 {
+		labels, err := statistic.LabelNames(cmd.Context(), client)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		for i, l := range labels {
+			labels[i] = l + "="
+		}
+		return labels, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+	label := toComplete[:i]
+	j := i
+	for j < len(toComplete) && strings.ContainsRune("=!~", rune(toComplete[j])) {
+		j++
+	}
+	prefix := toComplete[:j]
+	values, err := statistic.LabelValues(cmd.Context(), client, label)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	for i, v := range values {
+		values[i] = prefix + v
+	}
+	return values, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/output"
+	"hepic-cli/internal/registration"
+	"hepic-cli/internal/sip"
+
+	"github.com/spf13/cobra"
+)
+
+var registrationCmd = &cobra.Command{
+	Use:     "registration",
+	Short:   "Analyze SIP registrations",
+	GroupID: "call",
+	Long: `Search and analyze SIP REGISTER transactions.
+
+Available subcommands:
+  search    List REGISTER transactions with their outcome
+  stats     Aggregate success, challenges, expires, churn and NAT per group`,
+}
+
+var registrationSearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "List REGISTER transactions",
+	Long: `List REGISTER transactions with their final status, requested and granted
+expires, contact, user agent and source IP alias.
+
+Examples:
+  hepic registration search --from 2025-01-01 --user 1001
+  hepic registration search --from 2025-01-01 --result challenged --format table
+  hepic registration search --from 2025-01-01 --stale
+  hepic registration search --file registrations.pcap`,
+	RunE: runRegistrationSearch,
+}
+
+var registrationStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Aggregate registration statistics",
+	Long: `Aggregate REGISTER transactions by user agent and source IP alias.
+
+Each row reports successes, failures, 401/407 challenges, challenge loops
+(credentials rejected again), granted expires, contact churn, NAT'd contacts
+and devices whose binding expired without re-registering.
+
+Examples:
+  hepic registration stats --from 2025-01-01 --format table
+  hepic registration stats --from 2025-01-01 --group-by alias
+  hepic registration stats --from 2025-01-01 --group-by user-agent,source-ip`,
+	RunE: runRegistrationStats,
+}
+
+func init() {
+	rootCmd.AddCommand(registrationCmd)
+	registrationCmd.AddCommand(registrationSearchCmd)
+	registrationCmd.AddCommand(registrationStatsCmd)
+
+	for _, c := range []*cobra.Command{registrationSearchCmd, registrationStatsCmd} {
+		c.Flags().String("from", "", "Start time (RFC3339 or YYYY-MM-DD, required unless --file is set)")
+		c.Flags().String("to", "", "End time (RFC3339 or YYYY-MM-DD, default: now)")
+		c.Flags().String("user", "", "Filter by registering user (From/To user)")
+		c.Flags().String("call-id", "", "Filter by SIP Call-ID")
+		c.Flags().String("file", "", "Read messages from a text dump or PCAP file instead of the API")
+		c.Flags().Bool("no-aliases", false, "Do not resolve source IPs to IP alias names")
+	}
+
+	registrationSearchCmd.Flags().String("result", "", "Only show transactions with this result: success, unregistered, challenged, failed, unanswered")
+	registrationSearchCmd.Flags().Bool("stale", false, "Only show devices whose binding expired without re-registering")
+
+	registrationStatsCmd.Flags().StringSlice("group-by", []string{"user-agent", "alias"}, "Group by: user-agent, alias, source-ip, user")
+}
+
+func runRegistrationSearch(cmd *cobra.Command, args []string) error {
+	result, _ := cmd.Flags().GetString("result")
+	if result != "" {
+		known := false
+		for _, r := range registration.Results {
+			known = known || r == result
+		}
+		if !known {
+			return fmt.Errorf("unknown result %q (valid: %s)", result, strings.Join(registration.Results, ", "))
+		}
+	}
+
+	regs, end, err := loadRegistrations(cmd)
+	if err != nil {
+		return err
+	}
+
+	stale, _ := cmd.Flags().GetBool("stale")
+	if stale {
+		regs = registration.Stale(regs, end)
+	}
+
+	if result != "" {
+		filtered := []registration.Registration{}
+		for _, r := range regs {
+			if r.Result == result {
+				filtered = append(filtered, r)
+			}
+		}
+		regs = filtered
+	}
+
+	return output.Print(regs)
+}
+
+func runRegistrationStats(cmd *cobra.Command, args []string) error {
+	groupBy, _ := cmd.Flags().GetStringSlice("group-by")
+
+	regs, end, err := loadRegistrations(cmd)
+	if err != nil {
+		return err
+	}
+
+	stats, err := registration.Aggregate(regs, groupBy, end)
+	if err != nil {
+		return err
+	}
+	return output.Print(stats)
+}
+
+// loadRegistrations reads REGISTER traffic from --file or the API and returns
+// the transactions together with the end of the analysed window.
+func loadRegistrations(cmd *cobra.Command) ([]registration.Registration, time.Time, error) {
+	file, _ := cmd.Flags().GetString("file")
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	user, _ := cmd.Flags().GetString("user")
+	callID, _ := cmd.Flags().GetString("call-id")
+	noAliases, _ := cmd.Flags().GetBool("no-aliases")
+
+	var (
+		msgs   []*sip.Message
+		client *api.Client
+		err    error
+	)
+	end := time.Now()
+	if file != "" {
+		msgs, err = sip.ReadFile(file)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		sip.SortByTime(msgs)
+		if len(msgs) > 0 {
+			end = msgs[len(msgs)-1].Timestamp
+		}
+	} else {
+		if from == "" {
+			return nil, time.Time{}, fmt.Errorf("--from is required unless --file is set")
+		}
+		client, err = api.NewClient()
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		params, err := registration.NewSearchParams(from, to, user, callID)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if ms, ok := params.Timestamp["to"].(int64); ok {
+			end = time.UnixMilli(ms)
+		}
+		rows, err := registration.Search(cmd.Context(), client, params)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		msgs = registration.Messages(rows)
+	}
+
+	var aliases func(ip string, port int) string
+	if client != nil && !noAliases {
+		table, err := loadAliasTable(cmd, client)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		aliases = table.Name
+	}
+
+	regs := registration.Build(msgs, aliases)
+	if file != "" && (user != "" || callID != "") {
+		filtered := regs[:0]
+		for _, r := range regs {
+			if (user == "" || r.User == user) && (callID == "" || r.CallID == callID) {
+				filtered = append(filtered, r)
+			}
+		}
+		regs = filtered
+	}
+	return regs, end, nil
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/output"
+	"hepic-cli/internal/remote"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var remoteCmd = &cobra.Command{
+	Use:     "remote",
+	Short:   "Query remote log servers (Loki)",
+	GroupID: "call",
+	Long: `Query the remote log servers HEPIC proxies, such as Loki.
+
+Available subcommands:
+  labels    List label names
+  values    List the values of a label
+  status    Check the remote server
+  query     Run a LogQL query, optionally following new lines`,
+}
+
+var remoteLabelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "List remote label names",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		server, _ := cmd.Flags().GetString("server")
+		labels, err := remote.Labels(cmd.Context(), client, server)
+		if err != nil {
+			return err
+		}
+		return output.Print(labels)
+	},
+}
+
+var remoteValuesCmd = &cobra.Command{
+	Use:               "values <label>",
+	Short:             "List the values of a remote label",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRemoteLabels,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		server, _ := cmd.Flags().GetString("server")
+		values, err := remote.Values(cmd.Context(), client, args[0], server)
+		if err != nil {
+			return err
+		}
+		return output.Print(values)
+	},
+}
+
+var remoteStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check the remote log server",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+		server, _ := cmd.Flags().GetString("server")
+		result, err := remote.Status(cmd.Context(), client, server)
+		if err != nil {
+			return err
+		}
+		return output.Print(result)
+	},
+}
+
+var remoteQueryCmd = &cobra.Command{
+	Use:   "query <logql>",
+	Short: "Run a LogQL query against the remote log server",
+	Long: `Run a LogQL query and print the matching lines, oldest first.
+
+Lines are streamed one per line: with --format json as one JSON object
+per line, otherwise as time, labels and text. With --follow, the query is
+repeated every --interval and new lines are printed until interrupted.
+
+Examples:
+  hepic remote query '{job="kamailio"}'
+  hepic remote query '{job="kamailio"} |= "error"' --from 2025-01-01T10:00:00Z --to 2025-01-01T11:00:00Z
+  hepic remote query '{job="kamailio"} |= "error"' --since 15m --follow`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRemoteQuery,
+}
+
+func init() {
+	rootCmd.AddCommand(remoteCmd)
+	remoteCmd.AddCommand(remoteLabelsCmd)
+	remoteCmd.AddCommand(remoteValuesCmd)
+	remoteCmd.AddCommand(remoteStatusCmd)
+	remoteCmd.AddCommand(remoteQueryCmd)
+
+	remoteCmd.PersistentFlags().String("server", "", "Remote server to query (default: the server's default)")
+
+	remoteQueryCmd.Flags().String("from", "", "Start time (RFC3339, YYYY-MM-DD or unix ms; default: --since ago)")
+	remoteQueryCmd.Flags().String("to", "", "End time (RFC3339, YYYY-MM-DD or unix ms; default: now)")
+	remoteQueryCmd.Flags().Duration("since", time.Hour, "Look back this far when --from is not set")
+	remoteQueryCmd.Flags().Int("limit", 100, "Maximum number of lines per query")
+	remoteQueryCmd.Flags().BoolP("follow", "F", false, "Keep polling for new lines")
+	remoteQueryCmd.Flags().Duration("interval", 2*time.Second, "Polling interval for --follow")
+}
+
+// completeRemoteLabels completes label names from the server.
+func completeRemoteLabels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	client, err := api.NewClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	server, _ := cmd.Flags().GetString("server")
+	labels, err := remote.Labels(cmd.Context(), client, server)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return labels, cobra.ShellCompDirectiveNoFileComp
+}
+
+func runRemoteQuery(cmd *cobra.Command, args []string) error {
+	fromFlag, _ := cmd.Flags().GetString("from")
+	toFlag, _ := cmd.Flags().GetString("to")
+	since, _ := cmd.Flags().GetDuration("since")
+	limit, _ := cmd.Flags().GetInt("limit")
+	follow, _ := cmd.Flags().GetBool("follow")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	server, _ := cmd.Flags().GetString("server")
+
+	if follow && toFlag != "" {
+		return fmt.Errorf("--to cannot be used with --follow")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	from := time.Now().Add(-since)
+	if fromFlag != "" {
+		t, err := models.ParseTime(fromFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --from value: %w", err)
+		}
+		from = t.Time
+	}
+	var to time.Time
+	if toFlag != "" {
+		t, err := models.ParseTime(toFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --to value: %w", err)
+		}
+		to = t.Time
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	emit := remotePrinter()
+	follower := remote.NewFollower(from)
+	for {
+		end := to
+		if end.IsZero() {
+			end = time.Now()
+		}
+		resp, err := remote.Query(ctx, client, remote.NewQuery(args[0], follower.Since, end, limit, server))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for _, e := range follower.Next(remote.Entries(resp)) {
+			if err := emit(e); err != nil {
+				return err
+			}
+		}
+		if !follow {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// remotePrinter returns a function writing one log line to stdout in the
+// --format: JSON lines for json, text otherwise.
+func remotePrinter() func(remote.Entry) error {
+	if viper.GetString("format") == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		return func(e remote.Entry) error { return enc.Encode(e) }
+	}
+	return func(e remote.Entry) error {
+		_, err := fmt.Fprintln(os.Stdout, e)
+		return err
+	}
+}
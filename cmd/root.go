@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
+	"hepic-cli/internal/alias"
 	"hepic-cli/internal/output"
 
 	"github.com/spf13/cobra"
@@ -14,8 +18,9 @@ var rootCmd = &cobra.Command{
 searching, analyzing, and managing SIP telecommunication data.
 
 Use "hepic <command> --help" for more information about a command.`,
-	SilenceUsage:  true,
-	SilenceErrors: true,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+	PersistentPreRunE: checkFormat,
 }
 
 func Execute() error {
@@ -39,22 +44,58 @@ func init() {
 
 	rootCmd.PersistentFlags().String("host", "", "HEPIC API host URL (overrides config/env)")
 	rootCmd.PersistentFlags().String("token", "", "API key for authentication (overrides config/env)")
-	rootCmd.PersistentFlags().String("format", "json", "Output format: json, table, yaml")
+	rootCmd.PersistentFlags().String("format", "json", "Output format: json, table, yaml; some commands also take csv or ndjson")
 	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose output (debug logging to stderr)")
 	rootCmd.PersistentFlags().Bool("no-color", false, "Disable ANSI colors in output")
+	rootCmd.PersistentFlags().Bool("resolve-aliases", false, "Show IP alias names next to IP addresses in output")
 
 	viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("host"))
 	viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token"))
 	viper.BindPFlag("format", rootCmd.PersistentFlags().Lookup("format"))
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color"))
+	viper.BindPFlag("resolve-aliases", rootCmd.PersistentFlags().Lookup("resolve-aliases"))
 }
 
+// formatsAnnotation is the command annotation listing, comma-separated,
+// the output formats a command takes besides output.Formats.
+const formatsAnnotation = "formats"
+
+// checkFormat rejects a --format the command does not support, before it
+// sends any request.
+func checkFormat(cmd *cobra.Command, args []string) error {
+	format := viper.GetString("format")
+	if format == "" {
+		return nil
+	}
+	valid := append([]string{}, output.Formats...)
+	if extra := cmd.Annotations[formatsAnnotation]; extra != "" {
+		valid = append(valid, strings.Split(extra, ",")...)
+	}
+	for _, f := range valid {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format %q for %s (valid: %s)", format, cmd.CommandPath(), strings.Join(valid, ", "))
+}
+
+// configLoaded is set once the config file has been read, so that commands
+// run from hepic shell do not read it again.
+var configLoaded bool
+
 func initConfig() {
+	if configLoaded {
+		return
+	}
+	configLoaded = true
+
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("$HOME/.hepic")
 
+	viper.SetDefault("alias_cache_ttl", alias.DefaultTTL)
+
 	viper.SetEnvPrefix("HEPIC")
 	viper.AutomaticEnv()
 
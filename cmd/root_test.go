@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func TestRootCommand_Properties(t *testing.T) {
@@ -68,6 +69,7 @@ func TestRootCommand_PersistentFlags(t *testing.T) {
 		{"format flag", "format"},
 		{"verbose flag", "verbose"},
 		{"no-color flag", "no-color"},
+		{"resolve-aliases flag", "resolve-aliases"},
 	}
 
 	for _, tt := range tests {
@@ -187,3 +189,25 @@ func TestRootCommand_CompletionIsSubcommand(t *testing.T) {
 		t.Errorf("expected completion to have no group, got %q", found.GroupID)
 	}
 }
+
+func TestCheckFormat(t *testing.T) {
+	defer viper.Set("format", "json")
+	tests := []struct {
+		cmd    *cobra.Command
+		format string
+		ok     bool
+	}{
+		{dashboardListCmd, "yaml", true},
+		{dashboardListCmd, "csv", false},
+		{statisticDataCmd, "csv", true},
+		{statisticDataCmd, "ndjson", false},
+		{clickhouseQueryCmd, "ndjson", true},
+		{clickhouseQueryCmd, "xml", false},
+	}
+	for _, tt := range tests {
+		viper.Set("format", tt.format)
+		if err := checkFormat(tt.cmd, nil); (err == nil) != tt.ok {
+			t.Errorf("checkFormat(%s, %s) = %v", tt.cmd.CommandPath(), tt.format, err)
+		}
+	}
+}
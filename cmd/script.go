@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/models"
 	"hepic-cli/internal/output"
 	"hepic-cli/internal/script"
 
@@ -18,7 +20,7 @@ var scriptCmd = &cobra.Command{
 	Use:     "script",
 	Short:   "Manage scripts",
 	GroupID: "data",
-	Long:  "Create, list, update, and delete scripts on the HEPIC platform.",
+	Long:  "Create, list, update, delete, push, pull and sync scripts on the HEPIC platform.",
 }
 
 var scriptListCmd = &cobra.Command{
@@ -43,21 +45,47 @@ var scriptListCmd = &cobra.Command{
 var scriptCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new script",
-	Long:  "Create a new script on the HEPIC platform. Provide the script data as JSON via --data.",
+	Long: `Create a new script on the HEPIC platform.
+
+The body is read from --file and/or --data (inline JSON, or @file) and
+validated before it is sent: data (the script source) and type (lua or
+javascript) are required, the source must parse, and hepid/profile, when set, must exist in
+"hepic mapping protocols". status defaults to true.
+
+Examples:
+  hepic script create -f script.yaml
+  hepic script create --data '{"type":"lua","data":"return true","hepid":1,"profile":"call"}'`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dataStr, _ := cmd.Flags().GetString("data")
+		data, err := readBody(cmd, true)
+		if err != nil {
+			return err
+		}
+		if _, ok := data["status"]; !ok {
+			data["status"] = true
+		}
 
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			return fmt.Errorf("invalid JSON in --data: %w", err)
+		var s models.ScriptDataStruct
+		if err := body.Decode(data, &s); err != nil {
+			return err
+		}
+		if err := script.Validate(s); err != nil {
+			return err
+		}
+		if err := script.Check(s.Type, "script", s.Data); err != nil {
+			return err
 		}
 
 		client, err := api.NewClient()
 		if err != nil {
 			return err
 		}
+		if s.Hepid != 0 {
+			if err := checkProtocol(cmd, client, s.Hepid, s.Profile); err != nil {
+				return err
+			}
+		}
 
-		result, err := script.Create(cmd.Context(), client, data)
+		result, err := script.Create(cmd.Context(), client, s)
 		if err != nil {
 			return err
 		}
@@ -137,8 +165,7 @@ func init() {
 	scriptCmd.AddCommand(scriptUpdateCmd)
 	scriptCmd.AddCommand(scriptDeleteCmd)
 
-	scriptCreateCmd.Flags().String("data", "", "Script data as JSON")
-	scriptCreateCmd.MarkFlagRequired("data")
+	addBodyFlags(scriptCreateCmd, "Script body")
 
 	scriptUpdateCmd.Flags().String("data", "", "Script data as JSON")
 	scriptUpdateCmd.MarkFlagRequired("data")
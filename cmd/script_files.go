@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/output"
+	"hepic-cli/internal/script"
+
+	"github.com/spf13/cobra"
+)
+
+var scriptPushCmd = &cobra.Command{
+	Use:   "push <file>",
+	Short: "Upload a local Lua or JavaScript file as a script",
+	Long: `Upload a local script file. The type is taken from the file extension
+(.lua or .js) unless --type is given.
+
+The script is syntax-checked before upload and a unified diff against the
+server version is shown. Without --uuid, the server script with the same
+type, hepid and profile is updated; if there is none, a new script is
+created.
+
+Examples:
+  hepic script push sip-call.lua --hepid 1 --profile call --dry-run
+  hepic script push sip-call.lua --hepid 1 --profile call
+  hepic script push fix.js --uuid <uuid> --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScriptPush,
+}
+
+var scriptPullCmd = &cobra.Command{
+	Use:   "pull [uuid]",
+	Short: "Download scripts to local files",
+	Long: `Download a script's source to a file, or with --all every script into a
+directory together with a scripts.yaml manifest for "hepic script sync".
+
+Examples:
+  hepic script pull <uuid>
+  hepic script pull <uuid> -o sip-call.lua
+  hepic script pull --all -o scripts/`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScriptPull,
+}
+
+var scriptSyncCmd = &cobra.Command{
+	Use:   "sync <dir>",
+	Short: "Sync a directory of scripts to the server",
+	Long: `Make the server's scripts match a directory described by scripts.yaml:
+
+  scripts:
+    - file: sip-call.lua
+      uuid: 5a3b...          # optional: otherwise matched on type, hepid and profile
+      hepid: 1
+      profile: call
+    - file: log-default.js
+      hepid: 100
+      profile: default
+      status: false          # default true
+
+Every file is syntax-checked first. Changed scripts are shown as unified
+diffs. Server scripts that are not in the manifest are only deleted with
+--prune. "hepic script pull --all -o <dir>" writes a directory in this form.
+
+Examples:
+  hepic script sync scripts/ --dry-run
+  hepic script sync scripts/
+  hepic script sync scripts/ --prune --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScriptSync,
+}
+
+func init() {
+	scriptCmd.AddCommand(scriptPushCmd)
+	scriptPushCmd.Flags().String("uuid", "", "UUID of the script to update")
+	scriptPushCmd.Flags().String("type", "", "Script type: lua or javascript (default: from the file extension)")
+	scriptPushCmd.Flags().Int("hepid", 0, "HEP ID the script applies to")
+	scriptPushCmd.Flags().String("profile", "", "Profile the script applies to")
+	scriptPushCmd.Flags().String("hep-alias", "", "HEP alias")
+	scriptPushCmd.Flags().Int("partid", 0, "Partition ID")
+	scriptPushCmd.Flags().Bool("status", true, "Enable the script")
+	scriptPushCmd.Flags().Bool("dry-run", false, "Check the script and show the diff without uploading")
+	scriptPushCmd.Flags().Bool("force", false, "Skip confirmation prompt")
+
+	scriptCmd.AddCommand(scriptPullCmd)
+	scriptPullCmd.Flags().StringP("output", "o", "", "File (or directory with --all) to write to")
+	scriptPullCmd.Flags().Bool("all", false, "Download every script with a scripts.yaml manifest")
+
+	scriptCmd.AddCommand(scriptSyncCmd)
+	scriptSyncCmd.Flags().Bool("dry-run", false, "Check the scripts and show the plan without applying it")
+	scriptSyncCmd.Flags().Bool("prune", false, "Delete server scripts that are not in the manifest")
+	scriptSyncCmd.Flags().Bool("force", false, "Skip confirmation prompt")
+}
+
+func runScriptPush(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	source, err := script.ReadSource(path)
+	if err != nil {
+		return err
+	}
+	local := models.ScriptDataStruct{Data: source, Type: script.TypeFromPath(path), Status: true}
+	local.UUID, _ = cmd.Flags().GetString("uuid")
+	if cmd.Flags().Changed("type") {
+		typ, _ := cmd.Flags().GetString("type")
+		local.Type = models.ScriptType(typ)
+	}
+	hepid, _ := cmd.Flags().GetInt("hepid")
+	local.Hepid = uint16(hepid)
+	local.Profile, _ = cmd.Flags().GetString("profile")
+
+	if err := script.Check(local.Type, filepath.Base(path), source); err != nil {
+		return err
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	current, err := script.Fetch(cmd.Context(), client)
+	if err != nil {
+		return fmt.Errorf("failed to load scripts: %w", err)
+	}
+	old, exists, err := script.Match(local, current)
+	if err != nil {
+		return err
+	}
+
+	// Start from the server version so unset flags keep their values.
+	if exists {
+		merged := old
+		merged.Data, merged.Type = local.Data, local.Type
+		if cmd.Flags().Changed("hepid") || cmd.Flags().Changed("profile") {
+			merged.Hepid, merged.Profile = local.Hepid, local.Profile
+		}
+		local = merged
+	}
+	if cmd.Flags().Changed("hep-alias") {
+		local.HEPAlias, _ = cmd.Flags().GetString("hep-alias")
+	}
+	if cmd.Flags().Changed("partid") {
+		partid, _ := cmd.Flags().GetInt("partid")
+		local.Partid = uint16(partid)
+	}
+	if cmd.Flags().Changed("status") {
+		local.Status, _ = cmd.Flags().GetBool("status")
+	}
+	if err := script.Validate(local); err != nil {
+		return err
+	}
+	if local.Hepid != 0 {
+		if err := checkProtocol(cmd, client, local.Hepid, local.Profile); err != nil {
+			return err
+		}
+	}
+
+	change := script.Change{Action: script.ActionCreate, File: filepath.Base(path), Type: local.Type, Hepid: local.Hepid, Profile: local.Profile, Body: local}
+	if exists {
+		change.Action, change.UUID, change.Old = script.ActionUpdate, old.UUID, old
+		change.Details = script.Compare(old, local)
+		if change.Details == "" {
+			fmt.Fprintf(os.Stderr, "Script %s is up to date.\n", old.UUID)
+			return output.Print([]script.Change{})
+		}
+	}
+
+	if dryRun {
+		fmt.Fprint(os.Stdout, change.Diff())
+		return nil
+	}
+	if !force {
+		fmt.Fprint(os.Stderr, change.Diff())
+		prompt := fmt.Sprintf("Create %s script for hepid %d profile %q?", local.Type, local.Hepid, local.Profile)
+		if exists {
+			prompt = fmt.Sprintf("Update script %s (%s)?", old.UUID, change.Details)
+		}
+		if !confirmAction(prompt) {
+			return fmt.Errorf("operation cancelled")
+		}
+	}
+
+	if exists {
+		result, err := script.Update(cmd.Context(), client, old.UUID, local)
+		if err != nil {
+			return err
+		}
+		return output.Print(result)
+	}
+	result, err := script.Create(cmd.Context(), client, local)
+	if err != nil {
+		return err
+	}
+	return output.Print(result)
+}
+
+func runScriptPull(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	out, _ := cmd.Flags().GetString("output")
+	if all == (len(args) == 1) {
+		return fmt.Errorf("give a script UUID or --all")
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	current, err := script.Fetch(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	if all {
+		if out == "" {
+			return fmt.Errorf("--output directory is required with --all")
+		}
+		files, err := script.WriteDir(out, current)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", out, err)
+		}
+		return output.Print(map[string]interface{}{
+			"dir":     out,
+			"scripts": len(current),
+			"files":   files,
+		})
+	}
+
+	s, ok := script.Find(current, args[0])
+	if !ok {
+		return fmt.Errorf("script %s not found", args[0])
+	}
+	if out == "" {
+		out = script.FileName(s)
+	}
+	if err := os.WriteFile(out, []byte(s.Data), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	return output.Print(map[string]interface{}{
+		"file":      out,
+		"uuid":      s.UUID,
+		"type":      s.Type,
+		"hepid":     s.Hepid,
+		"profile":   s.Profile,
+		"hep_alias": s.HEPAlias,
+		"status":    s.Status,
+	})
+}
+
+func runScriptSync(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	prune, _ := cmd.Flags().GetBool("prune")
+	force, _ := cmd.Flags().GetBool("force")
+
+	locals, err := script.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, l := range locals {
+		if err := script.Check(l.Script.Type, l.File, l.Script.Data); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filepath.Join(dir, l.File), err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d script(s) failed the syntax check; nothing was synced", failed)
+	}
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	for _, l := range locals {
+		if l.Script.Hepid != 0 {
+			if err := checkProtocol(cmd, client, l.Script.Hepid, l.Script.Profile); err != nil {
+				return fmt.Errorf("%s: %w", l.File, err)
+			}
+		}
+	}
+	current, err := script.Fetch(cmd.Context(), client)
+	if err != nil {
+		return fmt.Errorf("failed to load scripts: %w", err)
+	}
+	changes, err := script.Plan(locals, current, prune)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		return output.Print(changes)
+	}
+	for _, c := range changes {
+		fmt.Fprint(os.Stderr, c.Diff())
+	}
+	if dryRun {
+		return output.Print(changes)
+	}
+	if !force && !confirmAction(fmt.Sprintf("Apply %d script change(s)?", len(changes))) {
+		return fmt.Errorf("operation cancelled")
+	}
+
+	return applyChanges(changes, func(c *script.Change) (string, error) {
+		var err error
+		switch c.Action {
+		case script.ActionCreate:
+			_, err = script.Create(cmd.Context(), client, c.Body)
+		case script.ActionUpdate:
+			_, err = script.Update(cmd.Context(), client, c.UUID, c.Body)
+		case script.ActionDelete:
+			_, err = script.Delete(cmd.Context(), client, c.UUID)
+		}
+		return changeOK, err
+	})
+}
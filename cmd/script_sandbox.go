@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"hepic-cli/internal/output"
+	"hepic-cli/internal/script"
+
+	"github.com/spf13/cobra"
+)
+
+var scriptTestCmd = &cobra.Command{
+	Use:   "test <file>",
+	Short: "Dry-run a Lua script against captured SIP messages",
+	Long: `Run a Lua script locally in a sandboxed VM against SIP messages, either
+fetched by Call-ID or read from a text dump or PCAP file. Nothing is sent
+to the server.
+
+The VM provides stubs for the HEPIC scripting API (GetHEPStruct,
+GetSIPStruct, GetRawMessage, SetSIPHeader, SetCustomSIPHeader, SetHEPField,
+SetRawMessage, HashTable, HashString, Logp, ...). By default the entry
+points the server calls, checkRAW and checkSIP, are called for each message,
+in that order; use --func to call other functions. Globals and HashTable
+entries persist across messages.
+
+For each message the modifications the script made, its log output, any
+error and the execution time are printed.
+
+Examples:
+  hepic script test sip-call.lua --call-id abc123@host --from 2024-01-15
+  hepic script test sip-call.lua --file capture.pcap
+  hepic script test sip-call.lua --file dump.txt --func checkRaw --show-raw`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScriptTest,
+}
+
+func init() {
+	scriptCmd.AddCommand(scriptTestCmd)
+	scriptTestCmd.Flags().String("call-id", "", "SIP Call-ID to fetch from HEPIC")
+	scriptTestCmd.Flags().String("from", "", "Start time (RFC3339 or YYYY-MM-DD, required with --call-id)")
+	scriptTestCmd.Flags().String("to", "", "End time (RFC3339 or YYYY-MM-DD, default: now)")
+	scriptTestCmd.Flags().String("file", "", "Read messages from a text dump or PCAP file instead of the API")
+	scriptTestCmd.Flags().StringSlice("func", nil, "Functions to call per message (default: checkRAW and checkSIP)")
+	scriptTestCmd.Flags().Duration("timeout", time.Second, "Maximum execution time per message")
+	scriptTestCmd.Flags().Int("node-id", 0, "Value returned by GetHEPNodeID")
+	scriptTestCmd.Flags().Bool("show-raw", false, "Include the modified raw message in the output")
+}
+
+func runScriptTest(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	functions, _ := cmd.Flags().GetStringSlice("func")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	nodeID, _ := cmd.Flags().GetInt("node-id")
+	showRaw, _ := cmd.Flags().GetBool("show-raw")
+
+	source, err := script.ReadSource(path)
+	if err != nil {
+		return err
+	}
+	if script.TypeFromPath(path) == script.TypeJavaScript {
+		return fmt.Errorf("only Lua scripts can be tested")
+	}
+	if err := script.Check(script.TypeLua, filepath.Base(path), source); err != nil {
+		return err
+	}
+
+	msgs, err := loadSIPMessages(cmd)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return fmt.Errorf("no SIP messages found")
+	}
+
+	sb, err := script.NewSandbox(source, functions, timeout, nodeID)
+	if err != nil {
+		return err
+	}
+	defer sb.Close()
+
+	results := make([]script.Result, 0, len(msgs))
+	failed := 0
+	for i, m := range msgs {
+		r := sb.Run(i+1, m)
+		if r.Error != "" {
+			failed++
+		}
+		if !showRaw {
+			r.Raw = ""
+		}
+		results = append(results, r)
+	}
+
+	if err := output.Print(results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("script failed on %d of %d message(s)", failed, len(msgs))
+	}
+	return nil
+}
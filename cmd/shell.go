@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"hepic-cli/internal/config"
+	"hepic-cli/internal/output"
+	"hepic-cli/internal/shell"
+	"hepic-cli/internal/tui"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// historyLimit is the number of lines kept in ~/.hepic/shell_history.
+const historyLimit = 1000
+
+// shellBuiltins are the commands the shell handles itself.
+var shellBuiltins = []string{"set", "unset", "last", "history", "help", "exit", "quit"}
+
+// shellHelp is the long help of the command and of the help builtin.
+const shellHelp = `Start a REPL that runs hepic commands without the "hepic" prefix. The
+configuration is read once and HTTP connections are kept open between
+commands.
+
+Session variables:
+  set                  list variables
+  set <name> <value>   set a variable
+  unset <name>         remove a variable
+
+A variable named after a flag is passed to every command that has the flag
+and does not set it, so "set from now-1h" and "set format table" apply to
+all following commands. Times of the form now, now-1h or now+30m (units s,
+m, h, d, w) are evaluated each time they are used.
+
+References (not expanded inside single quotes):
+  $name          a session variable
+  $last          the Call-ID of the previous result
+  $last.<path>   a field of the previous result, e.g. $last.data.0.srcIp;
+                 paths are also tried on the first row
+
+Other builtins: last (print the previous result), history, help, exit.
+History is kept in ~/.hepic/shell_history. Tab completes commands, flags
+and variables. Ctrl-C cancels a running command; Ctrl-D exits.
+
+Without a terminal, commands are read from stdin, one per line.
+
+Examples:
+  hepic shell
+  hepic> set from now-1h
+  hepic> call search --caller "+49123" --format table
+  hepic> call transaction --call-id $last
+  hepic> export pcap --call-id $last -o call.pcap`
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start an interactive shell with session variables",
+	Long:  shellHelp,
+	Args:  cobra.NoArgs,
+	RunE:  runShell,
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
+
+// inShell is set while the shell runs, to refuse nesting it.
+var inShell bool
+
+func runShell(cmd *cobra.Command, args []string) error {
+	if inShell {
+		return fmt.Errorf("already in a shell")
+	}
+	inShell = true
+	defer func() { inShell = false }()
+
+	// Commands get cancellable children of this context; cmd.Context()
+	// itself is replaced by the first of them.
+	ctx := cmd.Context()
+	session := shell.NewSession()
+	fd := int(os.Stdin.Fd())
+	interactive := term.IsTerminal(fd)
+
+	var history []string
+	historyPath := ""
+	if interactive {
+		if dir, err := config.ConfigDir(); err == nil {
+			historyPath = filepath.Join(dir, "shell_history")
+			history = loadHistory(historyPath)
+		}
+	}
+	editor := &shell.Editor{
+		Prompt:  "hepic> ",
+		History: history,
+		Complete: func(line string) (int, []string) {
+			return shell.Complete(rootCmd, shellBuiltins, session, line)
+		},
+	}
+
+	// The shell shares its reader with "ui", which runs in this process.
+	in := tui.Stdin()
+	for {
+		var line string
+		var err error
+		if interactive {
+			line, err = readShellLine(fd, editor, in)
+		} else {
+			line, err = in.ReadString('\n')
+			if errors.Is(err, io.EOF) && line != "" {
+				err = nil
+			}
+		}
+		if errors.Is(err, shell.ErrInterrupted) {
+			continue
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if interactive && (len(editor.History) == 0 || editor.History[len(editor.History)-1] != line) {
+			editor.History = append(editor.History, line)
+			appendHistory(historyPath, line)
+		}
+
+		words, err := shell.Split(line, session.Resolve)
+		if err != nil {
+			output.PrintError(err)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		done, err := runShellLine(ctx, session, editor.History, words)
+		if err != nil {
+			output.PrintError(err)
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// readShellLine reads one line with the terminal in raw mode, restoring it
+// before the command runs.
+func readShellLine(fd int, editor *shell.Editor, in *bufio.Reader) (string, error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(fd, state)
+	return editor.ReadLine(in, os.Stdout)
+}
+
+// runShellLine runs a builtin or a hepic command. done reports that the
+// shell should exit.
+func runShellLine(ctx context.Context, session *shell.Session, history []string, words []string) (done bool, err error) {
+	switch words[0] {
+	case "exit", "quit":
+		return true, nil
+	case "set":
+		switch len(words) {
+		case 1:
+			return false, output.Fprint(os.Stdout, formatFor(session), session.Vars())
+		case 2:
+			return false, fmt.Errorf("usage: set <name> <value>")
+		}
+		return false, session.Set(words[1], strings.Join(words[2:], " "))
+	case "unset":
+		if len(words) != 2 {
+			return false, fmt.Errorf("usage: unset <name>")
+		}
+		return false, session.Unset(words[1])
+	case "last":
+		last := session.LastResult()
+		if last == nil {
+			return false, fmt.Errorf("no previous result")
+		}
+		return false, output.Fprint(os.Stdout, formatFor(session), last)
+	case "history":
+		for i, h := range history {
+			fmt.Printf("%5d  %s\n", i+1, h)
+		}
+		return false, nil
+	case "help":
+		if len(words) == 1 {
+			fmt.Println(shellHelp)
+			fmt.Println()
+		}
+	case "shell":
+		return false, fmt.Errorf("already in a shell")
+	}
+	return false, runInShell(ctx, session, words)
+}
+
+// formatFor returns the output format a command would use: the session's
+// format variable if set, otherwise the configured one.
+func formatFor(session *shell.Session) string {
+	if v, ok := session.Value("format"); ok {
+		return v
+	}
+	return viper.GetString("format")
+}
+
+// runInShell runs a hepic command in this process. Ctrl-C cancels the
+// command rather than the shell.
+func runInShell(ctx context.Context, session *shell.Session, args []string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	args = session.Inject(rootCmd, args)
+	shell.Reset(rootCmd, ctx)
+	output.ResetLast()
+	rootCmd.SetArgs(args)
+	err := rootCmd.ExecuteContext(ctx)
+
+	if last := output.Last(); last != nil {
+		if serr := session.SetLast(last); serr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: $last is not updated: %v\n", serr)
+		}
+	}
+	if c, _, ferr := rootCmd.Find(args); ferr == nil && c == initCmd {
+		// Pick up the configuration init just wrote.
+		configLoaded = false
+		initConfig()
+	}
+	return err
+}
+
+func loadHistory(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > historyLimit {
+		lines = lines[len(lines)-historyLimit:]
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	return lines
+}
+
+func appendHistory(path, line string) {
+	if path == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
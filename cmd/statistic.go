@@ -1,20 +1,28 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/chart"
+	"hepic-cli/internal/models"
 	"hepic-cli/internal/output"
 	"hepic-cli/internal/statistic"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
 )
 
 var statisticCmd = &cobra.Command{
 	Use:     "statistic",
 	Short:   "Query statistics and metrics",
-	Long:    "Query database statistics, measurements, metrics, retentions, and time-series data.",
+	Long:    "Browse and query database statistics, measurements, metrics, tags, retentions, and time-series data.",
 	GroupID: "monitoring",
 }
 
@@ -37,46 +45,205 @@ var statisticDBCmd = &cobra.Command{
 	},
 }
 
+var statisticBrowseCmd = &cobra.Command{
+	Use:   "browse [database [measurement]]",
+	Short: "Browse databases, measurements, metrics and tags",
+	Long: `List what can be queried with "statistic data", one level at a time.
+
+Without arguments, the databases are listed; with a database, its retention
+policies and measurements; with a database and a measurement, its metrics
+and tags.
+
+Examples:
+  hepic statistic browse
+  hepic statistic browse homer
+  hepic statistic browse homer heplify_method_response --format table`,
+	Args:              cobra.MaximumNArgs(2),
+	ValidArgsFunction: completeStatisticBrowse,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		retention, _ := cmd.Flags().GetString("retention")
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		type item struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		}
+		var items []item
+		add := func(kind string, names []string, err error) error {
+			if err != nil {
+				return err
+			}
+			for _, n := range names {
+				items = append(items, item{Kind: kind, Name: n})
+			}
+			return nil
+		}
+
+		ctx := cmd.Context()
+		switch len(args) {
+		case 0:
+			names, err := statistic.Databases(ctx, client)
+			if err := add("database", names, err); err != nil {
+				return err
+			}
+		case 1:
+			names, err := statistic.RetentionNames(ctx, client, args[0])
+			if err := add("retention", names, err); err != nil {
+				return err
+			}
+			names, err = statistic.MeasurementNames(ctx, client, args[0])
+			if err := add("measurement", names, err); err != nil {
+				return err
+			}
+		default:
+			target := statistic.Target{Database: args[0], Retention: retention, Measurement: args[1]}
+			names, err := statistic.MetricNames(ctx, client, target)
+			if err := add("metric", names, err); err != nil {
+				return err
+			}
+			names, err = statistic.TagNames(ctx, client, target)
+			if err := add("tag", names, err); err != nil {
+				return err
+			}
+		}
+
+		if items == nil {
+			items = []item{}
+		}
+		return output.Print(items)
+	},
+}
+
 var statisticDataCmd = &cobra.Command{
 	Use:   "data",
 	Short: "Query statistical data",
-	Long: `Query statistical time-series data with optional time range.
+	Long: `Query statistical time-series data.
+
+The query is built from --db, --measurement and --metric (see "statistic
+browse"), optionally grouped by tags, from --from to --to aggregated every
+--interval. Alternatively, --data sends a raw query body. With --graph,
+each series is drawn as a chart; with --format csv or table, the result
+has one row per timestamp and one column per series. Times are absolute
+(RFC3339, YYYY-MM-DD, unix ms) or relative to now, as in now-6h or now-7d.
 
 Examples:
-  hepic statistic data --data '{"param":{"search":{"query":"sip"}}}'
+  hepic statistic data --db homer --measurement heplify_method_response --metric counter --group-by method --from now-6h --interval 5m
+  hepic statistic data --db homer --measurement heplify_method_response --metric counter --graph
+  hepic statistic data --db homer --measurement heplify_method_response --metric counter --format csv > methods.csv
   hepic statistic data --from 2025-01-01 --to 2025-01-02 --data '{"param":{}}'`,
+	Annotations: map[string]string{formatsAnnotation: "csv"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		dataStr, _ := cmd.Flags().GetString("data")
-		from, _ := cmd.Flags().GetString("from")
-		to, _ := cmd.Flags().GetString("to")
 
-		var data map[string]interface{}
+		var data interface{}
 		if dataStr != "" {
-			if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-				return fmt.Errorf("invalid JSON in --data: %w", err)
+			for _, name := range []string{"db", "retention", "measurement", "metric", "group-by", "interval", "limit"} {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("--%s cannot be combined with --data", name)
+				}
+			}
+			raw, err := statisticRawQuery(cmd, dataStr)
+			if err != nil {
+				return err
 			}
+			data = raw
 		} else {
-			data = make(map[string]interface{})
+			q, err := statisticQuery(cmd)
+			if err != nil {
+				return err
+			}
+			data = q
+		}
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
+		}
+
+		result, err := statistic.Data(cmd.Context(), client, data)
+		if err != nil {
+			return err
+		}
+
+		return printSeries(cmd, result, statistic.ParseData)
+	},
+}
+
+var statisticTagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List the tags of a measurement",
+	Long: `List the tag keys of a measurement, which data can be grouped by.
+
+Examples:
+  hepic statistic tags --db homer --measurement heplify_method_response
+  hepic statistic tags --data '{"param":{"query":[{"database":"homer","main":"heplify_method_response"}]}}'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dataStr, _ := cmd.Flags().GetString("data")
+		db, _ := cmd.Flags().GetString("db")
+		retention, _ := cmd.Flags().GetString("retention")
+		measurement, _ := cmd.Flags().GetString("measurement")
+
+		client, err := api.NewClient()
+		if err != nil {
+			return err
 		}
 
-		// Inject timestamp if from/to provided
-		if from != "" || to != "" {
-			ts := make(map[string]interface{})
-			if from != "" {
-				ts["from"] = from
+		if dataStr == "" {
+			if db == "" || measurement == "" {
+				return fmt.Errorf("--db and --measurement, or --data, are required")
 			}
-			if to != "" {
-				ts["to"] = to
+			target := statistic.Target{Database: db, Retention: retention, Measurement: measurement}
+			tags, err := statistic.TagNames(cmd.Context(), client, target)
+			if err != nil {
+				return err
 			}
-			data["timestamp"] = ts
+			return output.Print(tags)
 		}
 
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(dataStr), &m); err != nil {
+			return fmt.Errorf("invalid JSON in --data: %w", err)
+		}
+		var data models.StatisticObject
+		if err := body.Decode(m, &data); err != nil {
+			return err
+		}
+
+		result, err := statistic.Tags(cmd.Context(), client, data)
+		if err != nil {
+			return err
+		}
+
+		return output.Print(result)
+	},
+}
+
+var statisticInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show statistic database information",
+	Long: `Retrieve information about the statistic database, or with --configdb
+about the configuration database.
+
+Examples:
+  hepic statistic info
+  hepic statistic info --configdb`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configDB, _ := cmd.Flags().GetBool("configdb")
+
 		client, err := api.NewClient()
 		if err != nil {
 			return err
 		}
 
-		result, err := statistic.Data(cmd.Context(), client, data)
+		info := statistic.DatabaseInfo
+		if configDB {
+			info = statistic.ConfigDBInfo
+		}
+		result, err := info(cmd.Context(), client)
 		if err != nil {
 			return err
 		}
@@ -191,10 +358,32 @@ func init() {
 
 	statisticCmd.AddCommand(statisticDBCmd)
 
+	statisticCmd.AddCommand(statisticBrowseCmd)
+	statisticBrowseCmd.Flags().String("retention", "", "Retention policy of the measurement")
+
 	statisticCmd.AddCommand(statisticDataCmd)
-	statisticDataCmd.Flags().String("data", "", "Query data as JSON string")
-	statisticDataCmd.Flags().String("from", "", "Start time (RFC3339, date, or unix ms)")
-	statisticDataCmd.Flags().String("to", "", "End time (RFC3339, date, or unix ms)")
+	statisticDataCmd.Flags().String("data", "", "Raw query body as JSON, instead of --db, --measurement and --metric")
+	statisticDataCmd.Flags().String("from", "now-1h", "Start time (RFC3339, YYYY-MM-DD, unix ms or now-<duration>)")
+	statisticDataCmd.Flags().String("to", "now", "End time (RFC3339, YYYY-MM-DD, unix ms or now-<duration>)")
+	statisticDataCmd.Flags().String("db", "", "Database to query")
+	statisticDataCmd.Flags().String("retention", "", "Retention policy of the measurement")
+	statisticDataCmd.Flags().String("measurement", "", "Measurement to query")
+	statisticDataCmd.Flags().StringArray("metric", nil, "Metric of the measurement (repeatable)")
+	statisticDataCmd.Flags().StringArray("group-by", nil, "Tag to group the series by (repeatable)")
+	statisticDataCmd.Flags().Duration("interval", time.Minute, "Aggregation interval")
+	statisticDataCmd.Flags().Int("limit", 200, "Maximum number of points per series")
+	addSeriesFlags(statisticDataCmd)
+	addStatisticTargetCompletion(statisticDataCmd)
+
+	statisticCmd.AddCommand(statisticTagsCmd)
+	statisticTagsCmd.Flags().String("data", "", "Raw query body as JSON, instead of --db and --measurement")
+	statisticTagsCmd.Flags().String("db", "", "Database of the measurement")
+	statisticTagsCmd.Flags().String("retention", "", "Retention policy of the measurement")
+	statisticTagsCmd.Flags().String("measurement", "", "Measurement to list the tags of")
+	addStatisticTargetCompletion(statisticTagsCmd)
+
+	statisticCmd.AddCommand(statisticInfoCmd)
+	statisticInfoCmd.Flags().Bool("configdb", false, "Show the configuration database instead")
 
 	statisticCmd.AddCommand(statisticMetricsCmd)
 	statisticMetricsCmd.Flags().String("data", "", "Query data as JSON string")
@@ -205,3 +394,184 @@ func init() {
 	statisticCmd.AddCommand(statisticRetentionsCmd)
 	statisticRetentionsCmd.Flags().String("data", "", "Query data as JSON string")
 }
+
+// statisticRawQuery decodes --data, setting its timestamp from --from and
+// --to when they are given.
+func statisticRawQuery(cmd *cobra.Command, dataStr string) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
+		return nil, fmt.Errorf("invalid JSON in --data: %w", err)
+	}
+
+	now := time.Now()
+	ts, _ := data["timestamp"].(map[string]interface{})
+	for _, name := range []string{"from", "to"} {
+		if !cmd.Flags().Changed(name) {
+			continue
+		}
+		value, _ := cmd.Flags().GetString(name)
+		t, err := statistic.ParseTime(value, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s value: %w", name, err)
+		}
+		if ts == nil {
+			ts = make(map[string]interface{})
+			data["timestamp"] = ts
+		}
+		ts[name] = t.UnixMilli()
+	}
+	return data, nil
+}
+
+// statisticQuery builds the query body from the flags.
+func statisticQuery(cmd *cobra.Command) (interface{}, error) {
+	now := time.Now()
+	fromFlag, _ := cmd.Flags().GetString("from")
+	toFlag, _ := cmd.Flags().GetString("to")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	var target statistic.Target
+	target.Database, _ = cmd.Flags().GetString("db")
+	target.Retention, _ = cmd.Flags().GetString("retention")
+	target.Measurement, _ = cmd.Flags().GetString("measurement")
+	target.Metrics, _ = cmd.Flags().GetStringArray("metric")
+	target.GroupBy, _ = cmd.Flags().GetStringArray("group-by")
+	if target.Database == "" || target.Measurement == "" || len(target.Metrics) == 0 {
+		return nil, fmt.Errorf("--db, --measurement and --metric, or --data, are required")
+	}
+
+	from, err := statistic.ParseTime(fromFlag, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --from value: %w", err)
+	}
+	to, err := statistic.ParseTime(toFlag, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --to value: %w", err)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("--from must be before --to")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("--interval must be positive")
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("--limit must be positive")
+	}
+	return statistic.NewDataQuery(target, from, to, interval, limit), nil
+}
+
+// addSeriesFlags adds the flags of commands that print time series.
+func addSeriesFlags(c *cobra.Command) {
+	c.Flags().Bool("graph", false, "Draw each series as a chart")
+	c.Flags().Int("height", 8, "Chart height in lines; 1 draws sparklines")
+	c.Flags().Int("width", 0, "Chart width (default: terminal width)")
+}
+
+// printSeries prints a time-series result: as returned for json and
+// yaml, as charts with --graph, and as rows of the series parsed from it
+// for csv and table.
+func printSeries(cmd *cobra.Command, result json.RawMessage, parse func(json.RawMessage) ([]statistic.Series, error)) error {
+	graph, _ := cmd.Flags().GetBool("graph")
+	format := viper.GetString("format")
+	if !graph && format != "csv" && format != "table" {
+		return output.Print(result)
+	}
+	series, err := parse(result)
+	if err != nil {
+		return fmt.Errorf("failed to parse the result: %w", err)
+	}
+	switch {
+	case !graph && format == "csv":
+		return statistic.WriteCSV(os.Stdout, series)
+	case !graph:
+		return statistic.WriteTable(os.Stdout, series)
+	}
+	if len(series) == 0 {
+		fmt.Fprintln(os.Stderr, "No series returned")
+		return nil
+	}
+	width, _ := cmd.Flags().GetInt("width")
+	height, _ := cmd.Flags().GetInt("height")
+	if width <= 0 {
+		width = terminalWidth()
+	}
+	for i, s := range series {
+		if i > 0 {
+			fmt.Println()
+		}
+		values := make([]float64, len(s.Points))
+		for j, p := range s.Points {
+			values[j] = p.Value
+		}
+		chart.Render(os.Stdout, s.Name(), values, width, height)
+	}
+	return nil
+}
+
+// terminalWidth returns the width of stdout, or 80 if it is not a
+// terminal.
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	return 80
+}
+
+// completeStatisticBrowse completes the database, then the measurement.
+func completeStatisticBrowse(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, err := api.NewClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var names []string
+	switch len(args) {
+	case 0:
+		names, err = statistic.Databases(cmd.Context(), client)
+	case 1:
+		names, err = statistic.MeasurementNames(cmd.Context(), client, args[0])
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// addStatisticTargetCompletion completes --db, --measurement, --metric and
+// --group-by from the server, each from the flags given before it.
+func addStatisticTargetCompletion(c *cobra.Command) {
+	complete := func(list func(ctx context.Context, client *api.Client, t statistic.Target) ([]string, error)) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			client, err := api.NewClient()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			var target statistic.Target
+			target.Database, _ = cmd.Flags().GetString("db")
+			target.Retention, _ = cmd.Flags().GetString("retention")
+			target.Measurement, _ = cmd.Flags().GetString("measurement")
+			names, err := list(cmd.Context(), client, target)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+	c.RegisterFlagCompletionFunc("db", complete(func(ctx context.Context, client *api.Client, t statistic.Target) ([]string, error) {
+		return statistic.Databases(ctx, client)
+	}))
+	c.RegisterFlagCompletionFunc("retention", complete(func(ctx context.Context, client *api.Client, t statistic.Target) ([]string, error) {
+		return statistic.RetentionNames(ctx, client, t.Database)
+	}))
+	c.RegisterFlagCompletionFunc("measurement", complete(func(ctx context.Context, client *api.Client, t statistic.Target) ([]string, error) {
+		return statistic.MeasurementNames(ctx, client, t.Database)
+	}))
+	if c.Flags().Lookup("metric") != nil {
+		c.RegisterFlagCompletionFunc("metric", complete(statistic.MetricNames))
+	}
+	if c.Flags().Lookup("group-by") != nil {
+		c.RegisterFlagCompletionFunc("group-by", complete(statistic.TagNames))
+	}
+}
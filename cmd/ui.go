@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"hepic-cli/internal/admin"
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/call"
+	"hepic-cli/internal/export"
+	"hepic-cli/internal/ladder"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/sip"
+	"hepic-cli/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+var uiCmd = &cobra.Command{
+	Use:     "ui",
+	Short:   "Browse calls in an interactive terminal UI",
+	GroupID: "call",
+	Long: `Open a full-screen terminal UI for searching calls without remembering flags.
+
+The search form takes a time range, caller, callee and Call-ID. Results are
+listed in a scrollable table; Enter opens a call with its ladder diagram and
+the raw SIP messages.
+
+Keys in the results list and call view:
+  Enter      open the selected call
+  Up/Down    move (j/k also work); PgUp/PgDn scroll the raw message
+  p / t      export the call as PCAP / text (asks for a file name)
+  r / d      show the QoS / DTMF report
+  c          create a share link and copy it to the clipboard
+  /          back to the search form
+  Esc / q    back / quit; Ctrl-C quits from anywhere
+
+The clipboard is set with the OSC 52 escape sequence, which most terminal
+emulators support. The link is also shown in the status line.
+
+Flags prefill the search form; with --from the search runs right away.
+
+Examples:
+  hepic ui
+  hepic ui --from 2025-01-01 --caller "+49123"
+  hepic ui --from 2025-01-01 --call-id abc123 --resolve-aliases`,
+	RunE: runUI,
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+	uiCmd.Flags().String("from", "", "Start time (RFC3339 or YYYY-MM-DD, default: one hour ago)")
+	uiCmd.Flags().String("to", "", "End time (RFC3339 or YYYY-MM-DD, default: now)")
+	uiCmd.Flags().String("caller", "", "Filter by caller (from_user)")
+	uiCmd.Flags().String("callee", "", "Filter by callee (ruri_user)")
+	uiCmd.Flags().String("call-id", "", "Filter by SIP Call-ID")
+}
+
+func runUI(cmd *cobra.Command, args []string) error {
+	var q tui.Query
+	q.From, _ = cmd.Flags().GetString("from")
+	q.To, _ = cmd.Flags().GetString("to")
+	q.Caller, _ = cmd.Flags().GetString("caller")
+	q.Callee, _ = cmd.Flags().GetString("callee")
+	q.CallID, _ = cmd.Flags().GetString("call-id")
+
+	client, err := api.NewClient()
+	if err != nil {
+		return err
+	}
+	var names ladder.Namer
+	aliases, err := resolveAliases(cmd, client)
+	if err != nil {
+		return err
+	}
+	if aliases != nil {
+		names = aliases.Name
+	}
+
+	app := tui.New(cmd.Context(), uiBackend{client}, q, names)
+	if q.From != "" {
+		app.Search()
+	}
+	return tui.Run(cmd.Context(), app)
+}
+
+// uiBackend serves the UI from the HEPIC API.
+type uiBackend struct {
+	client *api.Client
+}
+
+func (b uiBackend) params(q tui.Query, callID string) (call.SearchParams, error) {
+	return call.NewSearchParams(q.From, q.To, "", "", callID)
+}
+
+func (b uiBackend) Search(ctx context.Context, q tui.Query) ([]models.CallElement, error) {
+	params, err := call.NewSearchParams(q.From, q.To, q.Caller, q.Callee, q.CallID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := call.SearchData(ctx, b.client, params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (b uiBackend) Messages(ctx context.Context, q tui.Query, callID string) ([]*sip.Message, error) {
+	params, err := b.params(q, callID)
+	if err != nil {
+		return nil, err
+	}
+	return call.FetchMessages(ctx, b.client, params)
+}
+
+func (b uiBackend) Export(ctx context.Context, q tui.Query, callID, format, path string) (int64, error) {
+	params, err := export.NewExportParams(q.From, q.To, callID)
+	if err != nil {
+		return 0, err
+	}
+	fetch := export.ExportPCAPData
+	if format == "text" {
+		fetch = export.ExportText
+	}
+	body, err := fetch(ctx, b.client, params)
+	if err != nil {
+		return 0, err
+	}
+	return writeToFile(body, path)
+}
+
+func (b uiBackend) Report(ctx context.Context, q tui.Query, callID, kind string) (json.RawMessage, error) {
+	params, err := b.params(q, callID)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "dtmf" {
+		return call.ReportDTMF(ctx, b.client, params)
+	}
+	return call.ReportQOS(ctx, b.client, params)
+}
+
+func (b uiBackend) Share(ctx context.Context, callID string) (string, error) {
+	raw, err := admin.ShareTransaction(ctx, b.client, callID)
+	if err != nil {
+		return "", err
+	}
+	return shareLink(raw)
+}
+
+// shareLink extracts the link from a share response, which is either a
+// bare string or an object with the link under a well-known key.
+func shareLink(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil && s != "" {
+		return s, nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		if data, ok := obj["data"].(map[string]interface{}); ok {
+			obj = data
+		}
+		for _, key := range []string{"url", "link", "href", "data"} {
+			if s, ok := obj[key].(string); ok && s != "" {
+				return s, nil
+			}
+		}
+	}
+	text := strings.TrimSpace(string(raw))
+	if text == "" || text == "null" {
+		return "", fmt.Errorf("the server returned no share link")
+	}
+	return text, nil
+}
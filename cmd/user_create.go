@@ -1,8 +1,9 @@
 package cmd
 
 import (
-
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/models"
 	"hepic-cli/internal/output"
 	"hepic-cli/internal/user"
 
@@ -38,6 +39,13 @@ var userCreateCmd = &cobra.Command{
 			"department": department,
 			"partid":     partid,
 		}
+		var spec models.CreateUserStruct
+		if err := body.Decode(data, &spec); err != nil {
+			return err
+		}
+		if err := spec.Validate(); err != nil {
+			return err
+		}
 
 		result, err := user.Create(cmd.Context(), client, data)
 		if err != nil {
@@ -1,9 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
 
 	"hepic-cli/internal/api"
-	"hepic-cli/internal/output"
 	"hepic-cli/internal/user"
 
 	"github.com/spf13/cobra"
@@ -12,21 +12,35 @@ import (
 var userImportCmd = &cobra.Command{
 	Use:   "import",
 	Short: "Import users from a CSV file",
-	Long:  "Import users from a CSV file into the HEPIC platform.",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		client, err := api.NewClient()
-		if err != nil {
-			return err
-		}
+	Long: `Import users from a CSV file into the HEPIC platform.
 
+The file is validated before upload: it needs a username column, and email
+and partid values are checked line by line. With --mode replace all users
+not in the file are removed; a preview of what will be added and removed is
+shown before anything changes.
+
+Examples:
+  hepic user import --file users.csv
+  hepic user import --file users.csv --mode replace --dry-run --format table
+  hepic user import --file legacy.csv --source h2`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		filePath, _ := cmd.Flags().GetString("file")
+		return userImport.run(cmd, filePath)
+	},
+}
 
-		result, err := user.Import(cmd.Context(), client, filePath)
+var userImport = csvImport{
+	noun:   "users",
+	schema: user.CSVSchema,
+	serverKeys: func(cmd *cobra.Command, client *api.Client) ([]string, error) {
+		list, err := user.List(cmd.Context(), client)
 		if err != nil {
-			return err
+			return nil, err
 		}
-
-		return output.Print(result)
+		return user.Usernames(list)
+	},
+	upload: func(cmd *cobra.Command, client *api.Client, file, mode, source string) (json.RawMessage, error) {
+		return user.ImportMode(cmd.Context(), client, file, mode, source)
 	},
 }
 
@@ -35,4 +49,5 @@ func init() {
 
 	userImportCmd.Flags().String("file", "", "Path to CSV file to import")
 	userImportCmd.MarkFlagRequired("file")
+	addCSVImportFlags(userImportCmd)
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/models"
 	"hepic-cli/internal/output"
 	"hepic-cli/internal/user"
 
@@ -57,6 +59,13 @@ var userUpdateCmd = &cobra.Command{
 		if len(data) == 0 {
 			return fmt.Errorf("no fields specified to update")
 		}
+		var spec models.CreateUserStruct
+		if err := body.Decode(data, &spec); err != nil {
+			return err
+		}
+		if err := spec.ValidatePartial(); err != nil {
+			return err
+		}
 
 		result, err := user.Update(cmd.Context(), client, uuid, data)
 		if err != nil {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
 )
 
 // Profiles retrieves admin profiles. GET /admin/profiles
@@ -67,8 +68,11 @@ func ImportPCAPNow(ctx context.Context, client *api.Client, filePath string) (js
 
 // ClickhouseQuery executes a raw ClickHouse query. POST /clickhouse/query/raw
 func ClickhouseQuery(ctx context.Context, client *api.Client, query string) (json.RawMessage, error) {
+	body := models.ClickhouseObject{Query: query}
+	if err := body.Validate(); err != nil {
+		return nil, err
+	}
 	var result json.RawMessage
-	body := map[string]string{"query": query}
 	err := client.Post(ctx, "/clickhouse/query/raw", body, &result)
 	return result, err
 }
@@ -3,8 +3,13 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
 )
 
 // List retrieves all registered capture agents.
@@ -15,6 +20,16 @@ func List(ctx context.Context, client *api.Client) (json.RawMessage, error) {
 	return result, err
 }
 
+// ListLocations retrieves all registered capture agents as typed locations.
+// GET /agent/subscribe
+func ListLocations(ctx context.Context, client *api.Client) ([]models.AgentsLocation, error) {
+	var result models.AgentsLocationList
+	if err := client.Get(ctx, "/agent/subscribe", &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
 // Get retrieves a single agent by UUID.
 // GET /agent/subscribe/{uuid}
 func Get(ctx context.Context, client *api.Client, uuid string) (json.RawMessage, error) {
@@ -62,3 +77,22 @@ func AddSubscription(ctx context.Context, client *api.Client, data interface{})
 	err := client.Post(ctx, "/agentsub/protocol", data, &result)
 	return result, err
 }
+
+var hostnameRe = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?)*\.?$`)
+
+// ValidateLocation checks an agent update body client-side. Only fields
+// that are set are checked, as updates are partial.
+func ValidateLocation(a models.AgentsLocation) error {
+	if a.Host != "" {
+		if strings.Contains(a.Host, "://") {
+			return fmt.Errorf("host %q must be an address or hostname, not a URL", a.Host)
+		}
+		if _, err := netip.ParseAddr(strings.Trim(a.Host, "[]")); err != nil && !hostnameRe.MatchString(a.Host) {
+			if strings.Contains(a.Host, ":") {
+				return fmt.Errorf("invalid host %q: set the port in the port field", a.Host)
+			}
+			return fmt.Errorf("invalid host %q", a.Host)
+		}
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
 )
 
 func TestList(t *testing.T) {
@@ -224,3 +225,35 @@ func TestList_Error(t *testing.T) {
 		t.Fatal("expected error for 500 response")
 	}
 }
+
+func TestListLocations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/agent/subscribe" {
+			t.Errorf("expected path /agent/subscribe, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"data":[{"uuid":"abc","host":"10.0.0.1","port":9060,"node":"probe1"}]}`))
+	}))
+	defer srv.Close()
+
+	client := api.NewClientWith(srv.URL, "test-token")
+	agents, err := ListLocations(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(agents) != 1 || agents[0].Host != "10.0.0.1" || agents[0].Port != 9060 || agents[0].Node != "probe1" {
+		t.Errorf("unexpected agents %+v", agents)
+	}
+}
+
+func TestValidateLocation(t *testing.T) {
+	for _, host := range []string{"", "10.0.0.2", "2001:db8::1", "[2001:db8::1]", "probe-1.example.com"} {
+		if err := ValidateLocation(models.AgentsLocation{Host: host}); err != nil {
+			t.Errorf("ValidateLocation(%q) = %v", host, err)
+		}
+	}
+	for _, host := range []string{"http://10.0.0.2", "10.0.0.2:9060", "bad host"} {
+		if err := ValidateLocation(models.AgentsLocation{Host: host}); err == nil {
+			t.Errorf("expected error for host %q", host)
+		}
+	}
+}
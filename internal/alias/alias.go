@@ -0,0 +1,264 @@
+// Package alias resolves IP addresses to HEPIC IP alias names.
+//
+// Aliases are CIDR blocks with an optional port. A Table answers lookups by
+// longest prefix match, preferring port-specific entries over port-agnostic
+// ones of the same length, and reports duplicate or overlapping entries.
+package alias
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strings"
+
+	"hepic-cli/internal/models"
+)
+
+// Validate checks an alias address, mask and port and returns the network
+// prefix it covers. The mask must be 0-32 for IPv4 and 0-128 for IPv6, and
+// the address must not have host bits set beyond the mask.
+func Validate(ip string, mask, port int) (netip.Prefix, error) {
+	prefix, err := ParsePrefix(ip, mask, port)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if masked := prefix.Masked(); masked != prefix {
+		return netip.Prefix{}, fmt.Errorf("%s has host bits set; did you mean %s?", prefix, masked)
+	}
+	return prefix, nil
+}
+
+// HostMask returns the mask of a single address: 32 for IPv4 and 128 for
+// IPv6. It returns 32 for an invalid address, which Validate then reports.
+func HostMask(ip string) int {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return 32
+	}
+	return addr.Unmap().BitLen()
+}
+
+// ParsePrefix is Validate without the host bits check, for entries that
+// already exist on the server or in import files.
+func ParsePrefix(ip string, mask, port int) (netip.Prefix, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address %q", ip)
+	}
+	if addr.Zone() != "" {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address %q: zones are not supported", ip)
+	}
+	addr = addr.Unmap()
+
+	if mask < 0 || mask > addr.BitLen() {
+		return netip.Prefix{}, fmt.Errorf("invalid mask %d for %s: must be 0-%d", mask, ipVersion(addr), addr.BitLen())
+	}
+	if port < 0 || port > 65535 {
+		return netip.Prefix{}, fmt.Errorf("invalid port %d: must be 0-65535", port)
+	}
+	return netip.PrefixFrom(addr, mask), nil
+}
+
+func ipVersion(addr netip.Addr) string {
+	if addr.Is4() {
+		return "IPv4"
+	}
+	return "IPv6"
+}
+
+// Key identifies an alias by network and port, e.g. "10.0.0.0/8" or
+// "10.0.0.1/32:5060".
+func Key(prefix netip.Prefix, port int) string {
+	k := prefix.Masked().String()
+	if port != 0 {
+		k += fmt.Sprintf(":%d", port)
+	}
+	return k
+}
+
+// Entry is an alias together with its parsed network prefix.
+type Entry struct {
+	models.AliasSwaggerStruct
+	Prefix netip.Prefix `json:"-"`
+}
+
+// Table is an in-memory alias table.
+type Table struct {
+	entries []Entry
+	invalid []models.AliasSwaggerStruct
+}
+
+// NewTable builds a table from the aliases returned by GET /ipalias. Entries
+// that fail validation are kept aside (see Invalid) and never match lookups.
+func NewTable(aliases []models.AliasSwaggerStruct) *Table {
+	t := &Table{}
+	for _, a := range aliases {
+		prefix, err := ParsePrefix(a.IP, int(a.Mask), int(a.Port))
+		if err != nil {
+			t.invalid = append(t.invalid, a)
+			continue
+		}
+		t.entries = append(t.entries, Entry{AliasSwaggerStruct: a, Prefix: prefix.Masked()})
+	}
+
+	// Most specific first, so the first hit of a linear scan is the best one.
+	sort.SliceStable(t.entries, func(i, j int) bool {
+		a, b := t.entries[i], t.entries[j]
+		if a.Prefix.Bits() != b.Prefix.Bits() {
+			return a.Prefix.Bits() > b.Prefix.Bits()
+		}
+		return a.Port != 0 && b.Port == 0
+	})
+	return t
+}
+
+// Entries returns the valid entries, most specific first.
+func (t *Table) Entries() []Entry {
+	return t.entries
+}
+
+// Invalid returns the aliases that could not be parsed.
+func (t *Table) Invalid() []models.AliasSwaggerStruct {
+	return t.invalid
+}
+
+// Find returns the UUID's entry, if present.
+func (t *Table) Find(uuid string) (Entry, bool) {
+	for _, e := range t.entries {
+		if e.UUID == uuid {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Lookup returns the most specific active alias containing ip. A port of 0
+// matches only port-agnostic aliases.
+func (t *Table) Lookup(ip string, port int) (Entry, bool) {
+	if t == nil {
+		return Entry{}, false
+	}
+	addr, err := netip.ParseAddr(strings.Trim(ip, "[]"))
+	if err != nil {
+		return Entry{}, false
+	}
+	addr = addr.Unmap().WithZone("")
+
+	for _, e := range t.entries {
+		if !e.Status {
+			continue
+		}
+		if e.Port != 0 && int(e.Port) != port {
+			continue
+		}
+		if e.Prefix.Contains(addr) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Matches returns every alias containing ip regardless of port or status,
+// most specific first.
+func (t *Table) Matches(ip string) []Entry {
+	if t == nil {
+		return nil
+	}
+	addr, err := netip.ParseAddr(strings.Trim(ip, "[]"))
+	if err != nil {
+		return nil
+	}
+	addr = addr.Unmap().WithZone("")
+
+	var out []Entry
+	for _, e := range t.entries {
+		if e.Prefix.Contains(addr) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Name returns the alias name for ip and port, or "" when nothing matches.
+// It is safe to call on a nil table.
+func (t *Table) Name(ip string, port int) string {
+	e, ok := t.Lookup(ip, port)
+	if !ok {
+		return ""
+	}
+	return e.Alias
+}
+
+// Conflict kinds reported by Conflicts.
+const (
+	Duplicate = "duplicate"
+	Overlap   = "overlap"
+)
+
+// Conflict describes an existing alias that clashes with a candidate.
+type Conflict struct {
+	Kind  string `json:"kind"`
+	UUID  string `json:"uuid"`
+	Alias string `json:"alias"`
+	IP    string `json:"ip"`
+	Mask  uint16 `json:"mask"`
+	Port  uint16 `json:"port"`
+}
+
+func (c Conflict) String() string {
+	target := fmt.Sprintf("%s/%d", c.IP, c.Mask)
+	if c.Port != 0 {
+		target += fmt.Sprintf(" port %d", c.Port)
+	}
+	return fmt.Sprintf("%s of %q (%s, %s)", c.Kind, c.Alias, target, c.UUID)
+}
+
+// Conflicts compares a candidate prefix and port against the table. Entries
+// covering exactly the same prefix and port are duplicates; entries whose
+// prefixes intersect on a shared port are overlaps. The entry with skipUUID
+// is ignored so an update does not conflict with itself.
+func (t *Table) Conflicts(prefix netip.Prefix, port int, skipUUID string) []Conflict {
+	var out []Conflict
+	for _, e := range t.entries {
+		if skipUUID != "" && e.UUID == skipUUID {
+			continue
+		}
+		if e.Port != 0 && port != 0 && int(e.Port) != port {
+			continue
+		}
+		if !e.Prefix.Overlaps(prefix) {
+			continue
+		}
+		kind := Overlap
+		if e.Prefix == prefix && int(e.Port) == port {
+			kind = Duplicate
+		}
+		out = append(out, Conflict{Kind: kind, UUID: e.UUID, Alias: e.Alias, IP: e.IP, Mask: e.Mask, Port: e.Port})
+	}
+	return out
+}
+
+// addrToken matches bracketed IPv6 addresses and anything that could be an
+// IPv4 or IPv6 address, optionally followed by a port.
+var addrToken = regexp.MustCompile(`\[[0-9A-Fa-f:.]+\](?::\d+)?|[0-9A-Fa-f:.]*[:.][0-9A-Fa-f:.]*`)
+
+// Annotate appends " (alias)" after every address in text that resolves to
+// an alias, e.g. "10.0.0.1:5060" becomes "10.0.0.1:5060 (proxy1)".
+func (t *Table) Annotate(text string) string {
+	if t == nil || len(t.entries) == 0 {
+		return text
+	}
+	return addrToken.ReplaceAllStringFunc(text, func(tok string) string {
+		var name string
+		if ap, err := netip.ParseAddrPort(tok); err == nil {
+			name = t.Name(ap.Addr().String(), int(ap.Port()))
+		} else if addr, err := netip.ParseAddr(strings.Trim(tok, "[]")); err == nil {
+			name = t.Name(addr.String(), 0)
+		}
+		if name == "" {
+			return tok
+		}
+		return tok + " (" + name + ")"
+	})
+}
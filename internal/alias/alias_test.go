@@ -0,0 +1,179 @@
+package alias
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+)
+
+func testAliases() []models.AliasSwaggerStruct {
+	return []models.AliasSwaggerStruct{
+		{UUID: "net", Alias: "carrier", IP: "10.0.0.0", Mask: 8, Status: true},
+		{UUID: "lan", Alias: "office", IP: "10.1.0.0", Mask: 16, Status: true},
+		{UUID: "sbc", Alias: "sbc-sip", IP: "10.1.2.3", Mask: 32, Port: 5060, Status: true},
+		{UUID: "off", Alias: "disabled", IP: "10.1.2.0", Mask: 24, Status: false},
+		{UUID: "v6", Alias: "v6-core", IP: "2001:db8::", Mask: 32, Status: true},
+		{UUID: "bad", Alias: "broken", IP: "not-an-ip", Mask: 32, Status: true},
+		{UUID: "lab", Alias: "lab", IP: "172.16.5.9", Mask: 24, Status: true},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []struct {
+		ip         string
+		mask, port int
+	}{
+		{"192.0.2.1", 32, 5060},
+		{"192.0.2.0", 24, 0},
+		{"2001:db8::", 48, 0},
+		{"::ffff:192.0.2.1", 32, 0},
+	}
+	for _, v := range valid {
+		if _, err := Validate(v.ip, v.mask, v.port); err != nil {
+			t.Errorf("Validate(%q, %d, %d) unexpected error: %v", v.ip, v.mask, v.port, err)
+		}
+	}
+
+	invalid := []struct {
+		ip         string
+		mask, port int
+	}{
+		{"192.0.2.300", 32, 0},
+		{"192.0.2.1", 33, 0},
+		{"2001:db8::1", 129, 0},
+		{"192.0.2.1", 24, 0},
+		{"192.0.2.1", 32, 70000},
+		{"fe80::1%eth0", 128, 0},
+	}
+	for _, v := range invalid {
+		if _, err := Validate(v.ip, v.mask, v.port); err == nil {
+			t.Errorf("Validate(%q, %d, %d) expected error", v.ip, v.mask, v.port)
+		}
+	}
+}
+
+func TestHostMask(t *testing.T) {
+	for ip, want := range map[string]int{"192.0.2.1": 32, "2001:db8::1": 128, "::ffff:192.0.2.1": 32, "bogus": 32} {
+		if got := HostMask(ip); got != want {
+			t.Errorf("HostMask(%q) = %d, want %d", ip, got, want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	table := NewTable(testAliases())
+	if len(table.Invalid()) != 1 || len(table.Entries()) != 6 {
+		t.Fatalf("expected 6 valid and 1 invalid entry, got %d/%d", len(table.Entries()), len(table.Invalid()))
+	}
+
+	cases := []struct {
+		ip   string
+		port int
+		want string
+	}{
+		{"10.1.2.3", 5060, "sbc-sip"},
+		{"10.1.2.3", 5080, "office"},
+		{"10.1.2.4", 0, "office"},
+		{"10.200.0.1", 0, "carrier"},
+		{"2001:db8:1::5", 0, "v6-core"},
+		{"[2001:db8::1]", 0, "v6-core"},
+		{"172.16.5.200", 0, "lab"},
+		{"192.0.2.1", 0, ""},
+		{"garbage", 0, ""},
+	}
+	for _, c := range cases {
+		if got := table.Name(c.ip, c.port); got != c.want {
+			t.Errorf("Name(%s, %d) = %q, want %q", c.ip, c.port, got, c.want)
+		}
+	}
+
+	var nilTable *Table
+	if nilTable.Name("10.0.0.1", 0) != "" {
+		t.Error("expected nil table to resolve nothing")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	table := NewTable(testAliases())
+
+	prefix, _ := Validate("10.1.0.0", 16, 0)
+	conflicts := table.Conflicts(prefix, 0, "")
+	kinds := map[string]string{}
+	for _, c := range conflicts {
+		kinds[c.UUID] = c.Kind
+	}
+	if kinds["lan"] != Duplicate || kinds["net"] != Overlap || kinds["sbc"] != Overlap || kinds["off"] != Overlap {
+		t.Errorf("unexpected conflicts: %+v", conflicts)
+	}
+	if _, ok := kinds["v6"]; ok {
+		t.Error("IPv4 prefix must not conflict with IPv6 entries")
+	}
+
+	if got := table.Conflicts(prefix, 0, "lan"); len(got) != 3 {
+		t.Errorf("expected self to be skipped on update, got %+v", got)
+	}
+
+	host, _ := Validate("10.1.2.3", 32, 5080)
+	for _, c := range table.Conflicts(host, 5080, "") {
+		if c.UUID == "sbc" {
+			t.Errorf("different ports must not conflict: %+v", c)
+		}
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	table := NewTable(testAliases())
+	got := table.Annotate("10.1.2.3:5060 -> 192.0.2.1:5060 at 10:00:01.000 via [2001:db8::1]:5060")
+	want := "10.1.2.3:5060 (sbc-sip) -> 192.0.2.1:5060 at 10:00:01.000 via [2001:db8::1]:5060 (v6-core)"
+	if got != want {
+		t.Errorf("Annotate:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestLoadCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipalias" {
+			t.Errorf("expected /ipalias, got %s", r.URL.Path)
+		}
+		requests++
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": 1, "data": testAliases()[:1]})
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.URL, "test-token")
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		table, err := Load(ctx, client, dir, time.Minute)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if table.Name("10.9.9.9", 0) != "carrier" {
+			t.Errorf("expected cached table to resolve")
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected second load to hit the cache, got %d requests", requests)
+	}
+
+	if err := Invalidate(client, dir); err != nil {
+		t.Fatalf("Invalidate failed: %v", err)
+	}
+	if _, err := Load(ctx, client, dir, time.Minute); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, err := Load(ctx, client, dir, 0); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected invalidation and zero TTL to refetch, got %d requests", requests)
+	}
+}
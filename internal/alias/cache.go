@@ -0,0 +1,96 @@
+package alias
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+)
+
+// DefaultTTL is how long a cached alias table is used before it is fetched again.
+const DefaultTTL = 5 * time.Minute
+
+// cacheFile is the on-disk representation of a cached alias table.
+type cacheFile struct {
+	BaseURL   string                      `json:"base_url"`
+	FetchedAt time.Time                   `json:"fetched_at"`
+	Aliases   []models.AliasSwaggerStruct `json:"aliases"`
+}
+
+// Fetch loads all aliases from GET /ipalias, bypassing the cache.
+func Fetch(ctx context.Context, client *api.Client) ([]models.AliasSwaggerStruct, error) {
+	var resp struct {
+		Data []models.AliasSwaggerStruct `json:"data"`
+	}
+	if err := client.Get(ctx, "/ipalias", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Load returns the alias table for client, served from the cache in dir when
+// it is younger than ttl and fetched (and cached) otherwise. A ttl of zero
+// or less always fetches. Cache write failures are not fatal.
+func Load(ctx context.Context, client *api.Client, dir string, ttl time.Duration) (*Table, error) {
+	path := cachePath(dir, client.BaseURL)
+
+	if ttl > 0 {
+		if cached, err := readCache(path); err == nil && cached.BaseURL == client.BaseURL && time.Since(cached.FetchedAt) < ttl {
+			return NewTable(cached.Aliases), nil
+		}
+	}
+
+	aliases, err := Fetch(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	writeCache(path, cacheFile{BaseURL: client.BaseURL, FetchedAt: time.Now(), Aliases: aliases})
+	return NewTable(aliases), nil
+}
+
+// Invalidate removes the cached alias table for client so the next Load
+// fetches fresh data. Call it after any change to the alias list.
+func Invalidate(client *api.Client, dir string) error {
+	err := os.Remove(cachePath(dir, client.BaseURL))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot remove alias cache: %w", err)
+	}
+	return nil
+}
+
+// cachePath keys the cache file by API base URL so several HEPIC instances
+// can share one cache directory.
+func cachePath(dir, baseURL string) string {
+	sum := sha256.Sum256([]byte(baseURL))
+	return filepath.Join(dir, "ipalias-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+func readCache(path string) (*cacheFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c cacheFile
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func writeCache(path string, c cacheFile) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,204 @@
+package alias
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"hepic-cli/internal/models"
+)
+
+// Spec is one alias in a declarative alias file.
+type Spec struct {
+	IP         string `json:"ip" yaml:"ip"`
+	Mask       *int   `json:"mask,omitempty" yaml:"mask,omitempty"`
+	Port       int    `json:"port,omitempty" yaml:"port,omitempty"`
+	Alias      string `json:"alias" yaml:"alias"`
+	Group      string `json:"group,omitempty" yaml:"group,omitempty"`
+	Servertype string `json:"servertype,omitempty" yaml:"servertype,omitempty"`
+	Status     *bool  `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// mask returns the spec's mask, defaulting to a single host.
+func (s Spec) mask(addr netip.Addr) int {
+	if s.Mask != nil {
+		return *s.Mask
+	}
+	return addr.BitLen()
+}
+
+func (s Spec) status() bool {
+	return s.Status == nil || *s.Status
+}
+
+// Body returns the request body used to create or update the alias.
+func (s Spec) Body(prefix netip.Prefix) models.AliasSwaggerStruct {
+	return models.AliasSwaggerStruct{
+		IP:         prefix.Addr().String(),
+		Mask:       uint16(prefix.Bits()),
+		Port:       uint16(s.Port),
+		Alias:      s.Alias,
+		Group:      s.Group,
+		Servertype: s.Servertype,
+		Status:     s.status(),
+	}
+}
+
+// ReadSpecs reads a YAML or JSON alias file. The file holds either a list of
+// aliases or a mapping with an "aliases" list.
+func ReadSpecs(path string) ([]Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read alias file: %w", err)
+	}
+
+	var specs []Spec
+	if err := yaml.Unmarshal(data, &specs); err != nil {
+		var doc struct {
+			Aliases []Spec `yaml:"aliases"`
+		}
+		if err2 := yaml.Unmarshal(data, &doc); err2 != nil {
+			return nil, fmt.Errorf("cannot parse alias file %s: %w", path, err)
+		}
+		specs = doc.Aliases
+	}
+	// An empty file combined with --prune would wipe every alias.
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("alias file %s contains no aliases", path)
+	}
+	return specs, nil
+}
+
+// Actions in a Plan.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change is one step of a Plan.
+type Change struct {
+	Action  string `json:"action"`
+	UUID    string `json:"uuid,omitempty"`
+	Alias   string `json:"alias"`
+	IP      string `json:"ip"`
+	Mask    int    `json:"mask"`
+	Port    int    `json:"port"`
+	Details string `json:"details,omitempty"`
+
+	// Body is the request body for create and update.
+	Body models.AliasSwaggerStruct `json:"-"`
+}
+
+// Plan computes the changes that make the server's aliases match the desired
+// specs. Aliases are matched on network prefix and port; matched entries are
+// updated when their name, group, server type or status differ. Server
+// entries missing from the specs are deleted only when prune is set, as are
+// server-side duplicates of a desired entry.
+func Plan(specs []Spec, current []models.AliasSwaggerStruct, prune bool) ([]Change, error) {
+	type key struct {
+		prefix netip.Prefix
+		port   int
+	}
+
+	byKey := make(map[key][]models.AliasSwaggerStruct)
+	var unparsed []models.AliasSwaggerStruct
+	for _, a := range current {
+		p, err := ParsePrefix(a.IP, int(a.Mask), int(a.Port))
+		if err != nil {
+			unparsed = append(unparsed, a)
+			continue
+		}
+		k := key{p.Masked(), int(a.Port)}
+		byKey[k] = append(byKey[k], a)
+	}
+
+	var changes []Change
+	seen := make(map[key]int)
+	for i, s := range specs {
+		if strings.TrimSpace(s.Alias) == "" {
+			return nil, fmt.Errorf("entry %d (%s): alias is required", i+1, s.IP)
+		}
+		addr, err := netip.ParseAddr(strings.TrimSpace(s.IP))
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: invalid IP address %q", i+1, s.IP)
+		}
+		prefix, err := Validate(s.IP, s.mask(addr), s.Port)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d (%s): %w", i+1, s.Alias, err)
+		}
+		k := key{prefix, s.Port}
+		if prev, dup := seen[k]; dup {
+			return nil, fmt.Errorf("entry %d (%s) duplicates entry %d: %s port %d", i+1, s.Alias, prev, prefix, s.Port)
+		}
+		seen[k] = i + 1
+
+		c := Change{Alias: s.Alias, IP: prefix.Addr().String(), Mask: prefix.Bits(), Port: s.Port, Body: s.Body(prefix)}
+		if err := c.Body.Validate(); err != nil {
+			return nil, fmt.Errorf("entry %d (%s): %w", i+1, s.Alias, err)
+		}
+		existing := byKey[k]
+		if len(existing) == 0 {
+			c.Action = ActionCreate
+			changes = append(changes, c)
+			continue
+		}
+
+		cur := existing[0]
+		if diff := specDiff(s, cur); diff != "" {
+			c.Action = ActionUpdate
+			c.UUID = cur.UUID
+			c.Body.UUID = cur.UUID
+			c.Details = diff
+			changes = append(changes, c)
+		}
+		if prune {
+			for _, extra := range existing[1:] {
+				changes = append(changes, deleteChange(extra, "duplicate of "+cur.UUID))
+			}
+		}
+		delete(byKey, k)
+	}
+
+	if prune {
+		var rest []models.AliasSwaggerStruct
+		for _, list := range byKey {
+			rest = append(rest, list...)
+		}
+		rest = append(rest, unparsed...)
+		sort.Slice(rest, func(i, j int) bool {
+			if rest[i].IP != rest[j].IP {
+				return rest[i].IP < rest[j].IP
+			}
+			return rest[i].UUID < rest[j].UUID
+		})
+		for _, a := range rest {
+			changes = append(changes, deleteChange(a, "not in file"))
+		}
+	}
+	return changes, nil
+}
+
+func deleteChange(a models.AliasSwaggerStruct, why string) Change {
+	return Change{Action: ActionDelete, UUID: a.UUID, Alias: a.Alias, IP: a.IP, Mask: int(a.Mask), Port: int(a.Port), Details: why}
+}
+
+// specDiff describes the fields that differ between a spec and the server
+// entry, e.g. `alias: "old" -> "new"`, or returns "".
+func specDiff(s Spec, cur models.AliasSwaggerStruct) string {
+	var diffs []string
+	add := func(field string, from, to interface{}) {
+		if from != to {
+			diffs = append(diffs, fmt.Sprintf("%s: %q -> %q", field, fmt.Sprint(from), fmt.Sprint(to)))
+		}
+	}
+	add("alias", cur.Alias, s.Alias)
+	add("group", cur.Group, s.Group)
+	add("servertype", cur.Servertype, s.Servertype)
+	add("status", cur.Status, s.status())
+	return strings.Join(diffs, "; ")
+}
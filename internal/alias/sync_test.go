@@ -0,0 +1,94 @@
+package alias
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hepic-cli/internal/models"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "aliases.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadSpecs(t *testing.T) {
+	list := writeFile(t, "- ip: 10.0.0.1\n  alias: a\n")
+	specs, err := ReadSpecs(list)
+	if err != nil || len(specs) != 1 || specs[0].Alias != "a" {
+		t.Fatalf("unexpected list result %+v %v", specs, err)
+	}
+
+	doc := writeFile(t, `{"aliases": [{"ip": "10.0.0.0", "mask": 8, "alias": "b", "status": false}]}`)
+	specs, err = ReadSpecs(doc)
+	if err != nil || len(specs) != 1 || *specs[0].Mask != 8 || specs[0].status() {
+		t.Fatalf("unexpected mapping result %+v %v", specs, err)
+	}
+
+	if _, err := ReadSpecs(writeFile(t, "other: 1\n")); err == nil {
+		t.Error("expected error for a file without aliases")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	current := []models.AliasSwaggerStruct{
+		{UUID: "same", Alias: "sbc1", IP: "10.0.0.10", Mask: 32, Port: 5060, Status: true},
+		{UUID: "renamed", Alias: "old", IP: "10.1.0.0", Mask: 16, Status: true},
+		{UUID: "dupe", Alias: "old-copy", IP: "10.1.0.0", Mask: 16, Status: true},
+		{UUID: "gone", Alias: "legacy", IP: "192.0.2.1", Mask: 32, Status: true},
+	}
+	sixteen := 16
+	specs := []Spec{
+		{IP: "10.0.0.10", Port: 5060, Alias: "sbc1"},
+		{IP: "10.1.0.0", Mask: &sixteen, Alias: "office"},
+		{IP: "2001:db8::1", Alias: "v6"},
+	}
+
+	changes, err := Plan(specs, current, false)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected update and create without prune, got %+v", changes)
+	}
+	if changes[0].Action != ActionUpdate || changes[0].UUID != "renamed" || changes[0].Details != `alias: "old" -> "office"` {
+		t.Errorf("unexpected update: %+v", changes[0])
+	}
+	if changes[1].Action != ActionCreate || changes[1].Mask != 128 || !changes[1].Body.Status {
+		t.Errorf("unexpected create: %+v", changes[1])
+	}
+
+	pruned, err := Plan(specs, current, true)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	deleted := map[string]bool{}
+	for _, c := range pruned {
+		if c.Action == ActionDelete {
+			deleted[c.UUID] = true
+		}
+	}
+	if len(deleted) != 2 || !deleted["dupe"] || !deleted["gone"] {
+		t.Errorf("expected duplicate and stale alias to be pruned, got %+v", pruned)
+	}
+}
+
+func TestPlan_InvalidSpecs(t *testing.T) {
+	eight := 8
+	bad := [][]Spec{
+		{{IP: "10.0.0.1"}},
+		{{IP: "10.0.0.300", Alias: "x"}},
+		{{IP: "10.0.0.1", Mask: &eight, Alias: "x"}},
+		{{IP: "10.0.0.1", Alias: "x"}, {IP: "10.0.0.1", Alias: "y"}},
+	}
+	for _, specs := range bad {
+		if _, err := Plan(specs, nil, false); err == nil {
+			t.Errorf("expected error for %+v", specs)
+		}
+	}
+}
@@ -29,6 +29,18 @@ type Client struct {
 	Verbose    bool
 }
 
+// transport is shared by the clients NewClient and NewClientFor create, so
+// that commands run one after another in the same process (hepic shell)
+// reuse open connections.
+var transport = newTransport()
+
+func newTransport() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = 8
+	t.IdleConnTimeout = 5 * time.Minute
+	return t
+}
+
 // NewClient creates a Client from the current viper configuration.
 func NewClient() (*Client, error) {
 	host := viper.GetString("host")
@@ -39,13 +51,18 @@ func NewClient() (*Client, error) {
 	if token == "" {
 		return nil, fmt.Errorf("token is not configured. Run 'hepic init' or set HEPIC_TOKEN")
 	}
+	return NewClientFor(host, token), nil
+}
 
+// NewClientFor creates a Client for a HEPIC host other than the configured
+// one, such as a named instance.
+func NewClientFor(host, token string) *Client {
 	return &Client{
 		BaseURL:    host + "/api/v3",
 		Token:      token,
-		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		HTTPClient: &http.Client{Timeout: 30 * time.Second, Transport: transport},
 		Verbose:    viper.GetBool("verbose"),
-	}, nil
+	}
 }
 
 // NewClientWith creates a Client with explicit parameters (useful for testing).
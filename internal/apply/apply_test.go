@@ -0,0 +1,180 @@
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/resource"
+)
+
+func writeManifests(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeManifests(t, map[string]string{
+		"mappings.yaml": `apiVersion: hepic/v1
+kind: Mapping
+spec:
+  hepid: 1
+  profile: call
+  retention: 14
+---
+kind: AdvancedSetting
+spec:
+  category: search
+  param: limit
+  data: {value: 200}
+`,
+		"scripts/scripts.yml": `kind: Script
+spec:
+  hepid: 1
+  profile: call
+  file: sip-call.lua
+`,
+		"scripts/sip-call.lua": "local x = 1\n",
+		"README.md":            "not a manifest",
+	})
+	objs, err := Load([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(objs))
+	}
+	if o := objs[0]; o.Resource.Kind != "Mapping" || o.Key != "1/call" {
+		t.Errorf("unexpected mapping %+v", o)
+	}
+	s := objs[2]
+	if s.Key != "lua/1/call" || s.Spec["data"] != "local x = 1\n" || s.Spec["status"] != true {
+		t.Errorf("unexpected script %+v", s)
+	}
+	if _, ok := s.Spec["file"]; ok {
+		t.Error("file should be replaced by data")
+	}
+
+	for name, content := range map[string]string{
+		"unknown kind":  "kind: Widget\nspec: {a: 1}\n",
+		"unknown field": "kind: Mapping\nspecs: {}\n",
+		"no key":        "kind: Mapping\nspec: {retention: 1}\n",
+		"bad syntax":    "kind: Script\nspec: {type: lua, hepid: 1, profile: call, data: 'if x then'}\n",
+		"duplicate":     "kind: Mapping\nspec: {hepid: 1, profile: call}\n---\nkind: Mapping\nspec: {hepid: 1, profile: call}\n",
+	} {
+		dir := writeManifests(t, map[string]string{"m.yaml": content})
+		if _, err := Load([]string{dir}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	dir := writeManifests(t, map[string]string{"m.yaml": `kind: Mapping
+spec: {hepid: 1, profile: call, retention: 14}
+---
+kind: Mapping
+spec: {hepid: 100, profile: default, retention: 7}
+---
+kind: Script
+spec: {type: lua, hepid: 1, profile: call, data: "return 1"}
+`})
+	objs, err := Load([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := map[string][]resource.Item{
+		"Mapping": {
+			{"guid": "m-1", "hepid": 1, "profile": "call", "retention": 7, "partid": 10},
+			{"guid": "m-2", "hepid": 100, "profile": "default", "retention": 7},
+			{"guid": "m-3", "hepid": 5, "profile": "default"},
+		},
+		"Script": {
+			{"uuid": "s-9", "type": "lua", "hepid": 5, "profile": "default", "data": "x"},
+		},
+	}
+	for _, items := range current {
+		for i, it := range items {
+			items[i] = normalize(t, it)
+		}
+	}
+
+	changes, err := Plan(objs, current, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("unexpected plan %+v", changes)
+	}
+	up := changes[0]
+	if up.Action != ActionUpdate || up.ID != "m-1" || len(up.Changes) != 1 || up.Changes[0].Path != "retention" {
+		t.Errorf("unexpected update %+v", up)
+	}
+	if up.Body["partid"] == nil {
+		t.Error("update body should keep server fields the manifest does not set")
+	}
+	if changes[1].Action != ActionCreate || changes[1].Kind != "Script" {
+		t.Errorf("unexpected create %+v", changes[1])
+	}
+
+	changes, _ = Plan(objs, current, true)
+	if n := len(changes); n != 4 || changes[2].Kind != "Script" || changes[3].Kind != "Mapping" || changes[3].ID != "m-3" {
+		t.Errorf("expected script deleted before mapping, got %+v", changes)
+	}
+
+	var buf bytes.Buffer
+	WritePlan(&buf, changes)
+	for _, want := range []string{"~ Mapping 1/call", "~ retention: 7 -> 14", "+ Script lua/1/call", "- Mapping 5/default", "Plan: 1 to add, 1 to change, 2 to destroy."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("plan is missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	// A script for a hepid/profile without a mapping is rejected.
+	dir = writeManifests(t, map[string]string{"m.yaml": "kind: Script\nspec: {type: lua, hepid: 9, profile: x, data: 'return 1'}\n"})
+	objs, _ = Load([]string{dir})
+	if _, err := Plan(objs, current, false); err == nil || !strings.Contains(err.Error(), "no mapping") {
+		t.Errorf("expected missing mapping error, got %v", err)
+	}
+
+	// One that sets neither is not checked.
+	dir = writeManifests(t, map[string]string{"m.yaml": "kind: Script\nspec: {type: lua, data: 'return 1'}\n"})
+	objs, _ = Load([]string{dir})
+	if _, err := Plan(objs, current, false); err != nil {
+		t.Errorf("unexpected error for a script without hepid and profile: %v", err)
+	}
+
+	// Specs are checked against their model.
+	dir = writeManifests(t, map[string]string{"m.yaml": "kind: Hepsub\nspec: {hepid: 1, profile: call, mapping: '{\"a\": 1}'}\n"})
+	objs, _ = Load([]string{dir})
+	if _, err := Plan(objs, current, false); err == nil || !strings.Contains(err.Error(), "Hepsub 1/call: mapping must be a JSON object or array") {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+// normalize decodes it the way server responses are decoded.
+func normalize(t *testing.T, it resource.Item) resource.Item {
+	t.Helper()
+	raw, err := json.Marshal(it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out resource.Item
+	if err := resource.Decode(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
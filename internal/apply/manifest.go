@@ -0,0 +1,220 @@
+// Package apply reads declarative manifests of several resource kinds and
+// plans the changes that make a server match them.
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/resource"
+	"hepic-cli/internal/script"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Kinds lists the manifest kinds apply manages, in dependency order.
+var Kinds = []string{"IPAlias", "Mapping", "Hepsub", "Script", "UserSetting", "AdvancedSetting", "Dashboard", "Interception"}
+
+// APIVersion is the only apiVersion manifests may declare.
+const APIVersion = "hepic/v1"
+
+// document is one YAML document in a manifest file:
+//
+//	apiVersion: hepic/v1   # optional
+//	kind: Mapping
+//	spec:
+//	  hepid: 1
+//	  profile: call
+//	  ...
+type document struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Spec       map[string]interface{} `yaml:"spec"`
+}
+
+// Object is one resource declared in a manifest.
+type Object struct {
+	Resource resource.Resource
+	Key      string
+	Spec     resource.Item
+	// Source is the file and document number, for messages.
+	Source string
+}
+
+// Load reads manifests from files and directories (recursively, *.yaml
+// and *.yml). Script specs may give "file" instead of "data" to read the
+// source from a path relative to the manifest.
+func Load(paths []string) ([]Object, error) {
+	files, err := manifestFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no manifest files found in %s", strings.Join(paths, ", "))
+	}
+
+	var objs []Object
+	seen := make(map[string]string)
+	for _, file := range files {
+		docs, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, docs...)
+	}
+	for _, o := range objs {
+		id := o.Resource.Kind + " " + o.Key
+		if prev, dup := seen[id]; dup {
+			return nil, fmt.Errorf("%s: %s is already declared in %s", o.Source, id, prev)
+		}
+		seen[id] = o.Source
+	}
+	return objs, nil
+}
+
+func manifestFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		var found []string
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ext := strings.ToLower(filepath.Ext(path)); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				found = append(found, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+func readFile(path string) ([]Object, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var objs []Object
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	for n := 1; ; n++ {
+		var doc document
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		source := fmt.Sprintf("%s#%d", path, n)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		if doc.Kind == "" && doc.Spec == nil {
+			continue // empty document, e.g. after a trailing ---
+		}
+		obj, err := newObject(doc, filepath.Dir(path), source)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func newObject(doc document, dir, source string) (Object, error) {
+	if doc.APIVersion != "" && doc.APIVersion != APIVersion {
+		return Object{}, fmt.Errorf("unsupported apiVersion %q (want %s)", doc.APIVersion, APIVersion)
+	}
+	if doc.Kind == "" {
+		return Object{}, fmt.Errorf("kind is required")
+	}
+	r, ok := resource.ByKind(doc.Kind)
+	if !ok || !managed(doc.Kind) {
+		return Object{}, fmt.Errorf("unknown kind %q (valid: %s)", doc.Kind, strings.Join(Kinds, ", "))
+	}
+	if len(doc.Spec) == 0 {
+		return Object{}, fmt.Errorf("spec is required")
+	}
+
+	if doc.Kind == "Script" {
+		if err := loadScript(doc.Spec, dir); err != nil {
+			return Object{}, err
+		}
+	}
+	// Round-trip through JSON so values compare equal to the server's.
+	data, err := json.Marshal(doc.Spec)
+	if err != nil {
+		return Object{}, fmt.Errorf("invalid spec: %w", err)
+	}
+	var spec resource.Item
+	if err := resource.Decode(data, &spec); err != nil {
+		return Object{}, fmt.Errorf("invalid spec: %w", err)
+	}
+
+	key := r.Key(spec)
+	if strings.Trim(key, "/") == "" {
+		return Object{}, fmt.Errorf("%s spec is missing its identifying fields", doc.Kind)
+	}
+	return Object{Resource: r, Key: key, Spec: spec, Source: source}, nil
+}
+
+// loadScript reads spec.file into spec.data, infers the type from the
+// extension and checks the syntax.
+func loadScript(spec map[string]interface{}, dir string) error {
+	if file, ok := spec["file"].(string); ok {
+		if _, both := spec["data"]; both {
+			return fmt.Errorf("give either file or data, not both")
+		}
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, file)
+		}
+		source, err := script.ReadSource(path)
+		if err != nil {
+			return err
+		}
+		delete(spec, "file")
+		spec["data"] = source
+		if _, ok := spec["type"]; !ok {
+			spec["type"] = string(script.TypeFromPath(file))
+		}
+	}
+	typ, _ := spec["type"].(string)
+	source, _ := spec["data"].(string)
+	if source == "" {
+		return fmt.Errorf("script needs file or data")
+	}
+	if _, ok := spec["status"]; !ok {
+		spec["status"] = true
+	}
+	return script.Check(models.ScriptType(typ), fmt.Sprint(spec["profile"]), source)
+}
+
+func managed(kind string) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
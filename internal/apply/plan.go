@@ -0,0 +1,253 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/jsondiff"
+	"hepic-cli/internal/resource"
+)
+
+// Actions in a Plan.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// volatile fields are assigned by the server and never compared.
+var volatile = map[string]bool{"create_date": true, "modify_date": true, "version": true}
+
+// Change is one step of a Plan.
+type Change struct {
+	Kind    string            `json:"kind"`
+	Action  string            `json:"action"`
+	Key     string            `json:"key"`
+	ID      string            `json:"id"`
+	Source  string            `json:"source"`
+	Changes []jsondiff.Change `json:"changes,omitempty"`
+
+	// Body is the request body for create and update.
+	Body     resource.Item     `json:"-"`
+	Resource resource.Resource `json:"-"`
+}
+
+// Fetch lists the server's items of every kind used by objs, plus
+// mappings when scripts or subscriptions refer to them.
+func Fetch(ctx context.Context, client *api.Client, objs []Object) (map[string][]resource.Item, error) {
+	kinds := make(map[string]bool)
+	for _, o := range objs {
+		kinds[o.Resource.Kind] = true
+	}
+	if kinds["Script"] || kinds["Hepsub"] {
+		kinds["Mapping"] = true
+	}
+	current := make(map[string][]resource.Item)
+	for _, k := range Kinds {
+		if !kinds[k] {
+			continue
+		}
+		r, _ := resource.ByKind(k)
+		items, err := r.List(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", r.Name, err)
+		}
+		current[k] = items
+	}
+	return current, nil
+}
+
+// Plan returns the changes that make current match objs: creates and
+// updates in dependency order, then deletes in reverse order. Only fields
+// a manifest sets are compared, so server defaults do not show up as
+// drift. With prune, server items of a kind the manifests declare but
+// that no manifest lists are deleted.
+func Plan(objs []Object, current map[string][]resource.Item, prune bool) ([]Change, error) {
+	byKind := make(map[string][]Object)
+	for _, o := range objs {
+		byKind[o.Resource.Kind] = append(byKind[o.Resource.Kind], o)
+	}
+	if err := checkMappings(objs, current); err != nil {
+		return nil, err
+	}
+
+	var changes, deletes []Change
+	for _, kind := range Kinds {
+		declared, ok := byKind[kind]
+		if !ok {
+			continue
+		}
+		r := declared[0].Resource
+		existing := make(map[string]resource.Item)
+		for _, it := range current[kind] {
+			if k := r.Key(it); k != "" {
+				if _, dup := existing[k]; !dup {
+					existing[k] = it
+				}
+			}
+		}
+
+		wanted := make(map[string]bool, len(declared))
+		for _, o := range declared {
+			wanted[o.Key] = true
+			c := Change{Kind: kind, Key: o.Key, Source: o.Source, Resource: r}
+			cur, ok := existing[o.Key]
+			if !ok {
+				if err := r.Validate(o.Spec, false); err != nil {
+					return nil, fmt.Errorf("%s: %s %s: %w", o.Source, kind, o.Key, err)
+				}
+				c.Action, c.Body = ActionCreate, o.Spec
+				changes = append(changes, c)
+				continue
+			}
+			diff := specDiff(r, o.Spec, cur)
+			if len(diff) == 0 {
+				continue
+			}
+			if !r.CanUpdate() {
+				return nil, fmt.Errorf("%s: %s %s differs but cannot be updated", o.Source, kind, o.Key)
+			}
+			body := resource.Clone(cur)
+			for k, v := range o.Spec {
+				body[k] = v
+			}
+			if err := r.Validate(body, true); err != nil {
+				return nil, fmt.Errorf("%s: %s %s: %w", o.Source, kind, o.Key, err)
+			}
+			c.Action, c.ID, c.Changes, c.Body = ActionUpdate, r.ItemID(cur), diff, body
+			changes = append(changes, c)
+		}
+
+		if !prune {
+			continue
+		}
+		var keys []string
+		for k := range existing {
+			if !wanted[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !r.CanDelete() {
+				return nil, fmt.Errorf("%s %s is not in the manifests but cannot be deleted", kind, k)
+			}
+			deletes = append(deletes, Change{Kind: kind, Action: ActionDelete, Key: k, ID: r.ItemID(existing[k]), Source: "(not in manifests)", Resource: r})
+		}
+	}
+
+	// Delete dependents (scripts) before what they depend on (mappings).
+	for i := len(deletes) - 1; i >= 0; i-- {
+		changes = append(changes, deletes[i])
+	}
+	return changes, nil
+}
+
+// specDiff compares the fields spec sets with the server's item.
+func specDiff(r resource.Resource, spec, cur resource.Item) []jsondiff.Change {
+	names := make([]string, 0, len(spec))
+	for k := range spec {
+		if !volatile[k] && k != r.ID {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	var changes []jsondiff.Change
+	for _, k := range names {
+		changes = append(changes, jsondiff.Value(k, cur[k], spec[k])...)
+	}
+	return changes
+}
+
+// checkMappings reports scripts and subscriptions whose hepid/profile has
+// no mapping on the server or in the manifests. Those that set neither
+// are not checked.
+func checkMappings(objs []Object, current map[string][]resource.Item) error {
+	mapping, _ := resource.ByKind("Mapping")
+	known := make(map[string]bool)
+	for _, it := range current["Mapping"] {
+		known[mapping.Key(it)] = true
+	}
+	for _, o := range objs {
+		if o.Resource.Kind == "Mapping" {
+			known[o.Key] = true
+		}
+	}
+	for _, o := range objs {
+		if o.Resource.Kind != "Script" && o.Resource.Kind != "Hepsub" {
+			continue
+		}
+		// Objects without a hepid and profile are not tied to a mapping.
+		if unset(o.Spec["hepid"]) && unset(o.Spec["profile"]) {
+			continue
+		}
+		if k := mapping.Key(o.Spec); !known[k] {
+			return fmt.Errorf("%s: %s %s refers to hepid/profile %s, which has no mapping", o.Source, o.Resource.Kind, o.Key, k)
+		}
+	}
+	return nil
+}
+
+// unset reports whether a spec value is missing, empty or zero.
+func unset(v interface{}) bool {
+	switch s := fmt.Sprint(v); s {
+	case "<nil>", "", "0":
+		return true
+	}
+	return false
+}
+
+// Apply performs one change.
+func Apply(ctx context.Context, client *api.Client, c Change) error {
+	switch c.Action {
+	case ActionCreate:
+		_, err := c.Resource.Create(ctx, client, c.Body)
+		return err
+	case ActionUpdate:
+		return c.Resource.Update(ctx, client, c.ID, c.Body)
+	case ActionDelete:
+		return c.Resource.Delete(ctx, client, c.ID)
+	}
+	return fmt.Errorf("unknown action %q", c.Action)
+}
+
+// WritePlan prints changes in a terraform-like form.
+func WritePlan(w io.Writer, changes []Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes. The server matches the manifests.")
+		return
+	}
+	counts := make(map[string]int)
+	for _, c := range changes {
+		counts[c.Action]++
+		switch c.Action {
+		case ActionCreate:
+			fmt.Fprintf(w, "  + %s %s  (%s)\n", c.Kind, c.Key, c.Source)
+		case ActionDelete:
+			fmt.Fprintf(w, "  - %s %s\n", c.Kind, c.Key)
+		default:
+			fmt.Fprintf(w, "  ~ %s %s  (%s)\n", c.Kind, c.Key, c.Source)
+			for _, d := range c.Changes {
+				switch d.Action {
+				case jsondiff.Added:
+					fmt.Fprintf(w, "      + %s: %s\n", d.Path, truncate(d.New))
+				case jsondiff.Removed:
+					fmt.Fprintf(w, "      - %s: %s\n", d.Path, truncate(d.Old))
+				default:
+					fmt.Fprintf(w, "      ~ %s: %s -> %s\n", d.Path, truncate(d.Old), truncate(d.New))
+				}
+			}
+		}
+	}
+	fmt.Fprintf(w, "\nPlan: %d to add, %d to change, %d to destroy.\n", counts[ActionCreate], counts[ActionUpdate], counts[ActionDelete])
+}
+
+func truncate(s string) string {
+	if len(s) <= 80 {
+		return s
+	}
+	return s[:77] + "..."
+}
@@ -0,0 +1,200 @@
+// Package backup snapshots a HEPIC instance's configuration into a
+// versioned tar.gz archive and restores it, resource by resource.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/resource"
+)
+
+// FormatVersion is the archive layout version written to the manifest.
+// Archives with a newer version are rejected.
+const FormatVersion = 1
+
+// ManifestFile is the archive member describing the backup. Every
+// resource is stored next to it as <name>.json.
+const ManifestFile = "manifest.json"
+
+// Manifest describes a backup.
+type Manifest struct {
+	Version    int            `json:"version"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Host       string         `json:"host"`
+	CLIVersion string         `json:"cli_version"`
+	Resources  []ResourceInfo `json:"resources"`
+}
+
+// ResourceInfo is one resource in a Manifest.
+type ResourceInfo struct {
+	Name  string `json:"name"`
+	File  string `json:"file"`
+	Count int    `json:"count"`
+}
+
+// Backup is a manifest and the items of each resource in it.
+type Backup struct {
+	Manifest Manifest
+	Items    map[string][]resource.Item
+}
+
+// Collect lists every resource from the server. Secret fields are removed.
+func Collect(ctx context.Context, client *api.Client, resources []resource.Resource, host, cliVersion string) (*Backup, error) {
+	b := &Backup{
+		Manifest: Manifest{
+			Version:    FormatVersion,
+			CreatedAt:  time.Now().UTC().Truncate(time.Second),
+			Host:       host,
+			CLIVersion: cliVersion,
+		},
+		Items: make(map[string][]resource.Item),
+	}
+	for _, r := range resources {
+		items, err := r.List(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		for _, it := range items {
+			for _, s := range r.Secrets {
+				delete(it, s)
+			}
+		}
+		b.Items[r.Name] = items
+		b.Manifest.Resources = append(b.Manifest.Resources, ResourceInfo{Name: r.Name, File: r.Name + ".json", Count: len(items)})
+	}
+	return b, nil
+}
+
+// Write writes b as a gzip-compressed tar archive.
+func Write(w io.Writer, b *Backup) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: b.Manifest.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	}
+
+	if err := add(ManifestFile, b.Manifest); err != nil {
+		return err
+	}
+	for _, r := range b.Manifest.Resources {
+		items := b.Items[r.Name]
+		if items == nil {
+			items = []resource.Item{}
+		}
+		if err := add(r.File, items); err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// WriteFile writes b to path, replacing it only once the archive is
+// complete.
+func WriteFile(path string, b *Backup) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, b); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Read reads an archive written by Write and checks it against its
+// manifest.
+func Read(r io.Reader) (*Backup, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("not a backup archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, tr); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", hdr.Name, err)
+		}
+		files[path.Clean(hdr.Name)] = buf.Bytes()
+	}
+
+	data, ok := files[ManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("not a backup archive: %s is missing", ManifestFile)
+	}
+	b := &Backup{Items: make(map[string][]resource.Item)}
+	if err := json.Unmarshal(data, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", ManifestFile, err)
+	}
+	if b.Manifest.Version < 1 || b.Manifest.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d (this hepic supports up to %d)", b.Manifest.Version, FormatVersion)
+	}
+	for _, ri := range b.Manifest.Resources {
+		data, ok := files[path.Clean(ri.File)]
+		if !ok {
+			return nil, fmt.Errorf("backup is incomplete: %s is missing", ri.File)
+		}
+		var items []resource.Item
+		if err := resource.Decode(data, &items); err != nil {
+			return nil, fmt.Errorf("%s: %w", ri.File, err)
+		}
+		if len(items) != ri.Count {
+			return nil, fmt.Errorf("%s: manifest lists %d items, file has %d", ri.File, ri.Count, len(items))
+		}
+		b.Items[ri.Name] = items
+	}
+	return b, nil
+}
+
+// ReadFile reads an archive from path.
+func ReadFile(path string) (*Backup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
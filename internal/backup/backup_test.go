@@ -0,0 +1,130 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/resource"
+)
+
+func lookup(t *testing.T, name string) resource.Resource {
+	t.Helper()
+	rs, err := resource.Select([]string{name}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rs[0]
+}
+
+func TestCollectWriteRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users":
+			w.Write([]byte(`{"count":1,"data":[{"guid":"u-1","username":"admin","password":"x","version":12345678901234567}]}`))
+		case "/script":
+			w.Write([]byte(`{"data":[{"uuid":"s-1","type":"lua","hepid":1,"profile":"call","data":"-- hi"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClientWith(server.URL, "test-token")
+
+	rs, _ := resource.Select([]string{"users", "scripts"}, nil)
+	b, err := Collect(context.Background(), client, rs, server.URL, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.Items["users"][0]["password"]; ok {
+		t.Error("password was not removed")
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, b); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Manifest.Version != FormatVersion || len(got.Manifest.Resources) != 2 || got.Manifest.Host != server.URL {
+		t.Errorf("unexpected manifest %+v", got.Manifest)
+	}
+	if v := got.Items["users"][0]["version"]; v != json.Number("12345678901234567") {
+		t.Errorf("version lost precision: %v", v)
+	}
+	if got.Items["scripts"][0]["data"] != "-- hi" {
+		t.Errorf("unexpected scripts %v", got.Items["scripts"])
+	}
+
+	if _, err := Read(strings.NewReader("not gzip")); err == nil {
+		t.Error("expected error for a non-archive")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	users := lookup(t, "users")
+	user := func(guid, name, email string) resource.Item {
+		return resource.Item{"guid": guid, "username": name, "email": email, "firstname": name, "lastname": "Doe",
+			"department": "ops", "usergroup": "admin", "partid": json.Number("10")}
+	}
+	backedUp := []resource.Item{
+		user("old-1", "alice", "a@example.com"),
+		user("old-2", "bob", "b@example.com"),
+		user("old-3", "carol", "c@example.com"),
+	}
+	current := []resource.Item{
+		user("new-1", "alice", "a@example.com"),
+		user("new-2", "bob", "bob@example.com"),
+		user("new-9", "bob-restored", "bob@example.com"),
+	}
+	current[0]["version"] = 3
+
+	changes, err := Plan(users, backedUp, current, PolicySkip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Action != ActionSkip || changes[1].Action != ActionCreate {
+		t.Fatalf("unexpected skip plan %+v", changes)
+	}
+	if _, ok := changes[1].Item["guid"]; ok {
+		t.Error("server ID should be dropped on create")
+	}
+
+	changes, _ = Plan(users, backedUp, current, PolicyOverwrite)
+	if c := changes[0]; c.Action != ActionUpdate || c.ID != "new-2" || c.Item["guid"] != "new-2" {
+		t.Errorf("unexpected overwrite change %+v", c)
+	}
+
+	changes, _ = Plan(users, backedUp, current, PolicyRename)
+	if c := changes[0]; c.Action != ActionCreate || c.Key != "bob-restored-2" || c.Item["username"] != "bob-restored-2" {
+		t.Errorf("unexpected rename change %+v", c)
+	}
+
+	// Mappings cannot be renamed; protocols are never restored.
+	changes, _ = Plan(lookup(t, "mappings"), []resource.Item{{"guid": "a", "hepid": 1, "profile": "call", "retention": 7}},
+		[]resource.Item{{"guid": "b", "hepid": 1, "profile": "call", "retention": 14}}, PolicyRename)
+	if len(changes) != 1 || changes[0].Action != ActionSkip || !strings.Contains(changes[0].Details, "renamed") {
+		t.Errorf("unexpected mapping plan %+v", changes)
+	}
+	changes, _ = Plan(lookup(t, "protocols"), []resource.Item{{"hepid": 1, "profile": "call"}}, nil, PolicyOverwrite)
+	if len(changes) != 1 || changes[0].Action != ActionSkip {
+		t.Errorf("unexpected protocol plan %+v", changes)
+	}
+
+	// Items are checked against their model before anything is sent.
+	invalid := user("old-4", "dave", "")
+	if _, err := Plan(users, []resource.Item{invalid}, nil, PolicySkip); err == nil || !strings.Contains(err.Error(), "users dave: email is required") {
+		t.Errorf("expected validation error, got %v", err)
+	}
+
+	if _, err := Plan(users, nil, nil, "merge"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
@@ -0,0 +1,175 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/resource"
+)
+
+// Conflict policies for items that already exist on the target server.
+const (
+	PolicySkip      = "skip"
+	PolicyOverwrite = "overwrite"
+	PolicyRename    = "rename"
+)
+
+// Policies lists the valid conflict policies.
+var Policies = []string{PolicySkip, PolicyOverwrite, PolicyRename}
+
+// Actions in a Plan.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionSkip   = "skip"
+)
+
+// Change is one step of a restore.
+type Change struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	Key      string `json:"key"`
+	ID       string `json:"id"`
+	Details  string `json:"details"`
+
+	// Item is the request body for create and update.
+	Item resource.Item `json:"-"`
+}
+
+// volatile fields are assigned by the server and ignored when comparing.
+var volatile = []string{"create_date", "modify_date", "version"}
+
+// Plan returns the changes that restore items over current. Items equal
+// to the server's are left out; existing items that differ are handled
+// according to policy.
+func Plan(r resource.Resource, items, current []resource.Item, policy string) ([]Change, error) {
+	switch policy {
+	case PolicySkip, PolicyOverwrite, PolicyRename:
+	default:
+		return nil, fmt.Errorf("invalid conflict policy %q (valid: skip, overwrite, rename)", policy)
+	}
+
+	byKey := make(map[string]resource.Item, len(current))
+	for _, c := range current {
+		if k := r.Key(c); k != "" {
+			if _, dup := byKey[k]; !dup {
+				byKey[k] = c
+			}
+		}
+	}
+	used := make(map[string]bool, len(byKey))
+	for k := range byKey {
+		used[k] = true
+	}
+
+	var changes []Change
+	for _, it := range items {
+		key := r.Key(it)
+		c := Change{Resource: r.Name, Key: key, Item: it}
+		if r.ReadOnly != "" {
+			if _, ok := byKey[key]; !ok {
+				c.Action, c.Details = ActionSkip, "missing on server; "+r.ReadOnly
+				changes = append(changes, c)
+			}
+			continue
+		}
+
+		existing, ok := byKey[key]
+		if !ok {
+			if !r.CanCreate() {
+				c.Action, c.Details = ActionSkip, "missing on server and cannot be created"
+			} else {
+				c.Action, c.Item = ActionCreate, forCreate(r, it)
+				if err := r.Validate(c.Item, false); err != nil {
+					return nil, fmt.Errorf("%s %s: %w", r.Name, key, err)
+				}
+			}
+			used[key] = true
+			changes = append(changes, c)
+			continue
+		}
+		if equal(r, it, existing) {
+			continue
+		}
+
+		c.ID = r.ItemID(existing)
+		switch {
+		case policy == PolicySkip:
+			c.Action, c.Details = ActionSkip, "exists with different content"
+		case policy == PolicyOverwrite && !r.CanUpdate():
+			c.Action, c.Details = ActionSkip, "exists and cannot be updated"
+		case policy == PolicyOverwrite:
+			body := resource.Clone(it)
+			body[r.ID] = existing[r.ID]
+			c.Action, c.Item = ActionUpdate, body
+		case r.Rename == "" || !r.CanCreate():
+			c.Action, c.Details = ActionSkip, "exists and cannot be renamed"
+		default:
+			body := forCreate(r, it)
+			name := fmt.Sprint(it[r.Rename])
+			for n := 1; ; n++ {
+				suffix := "-restored"
+				if n > 1 {
+					suffix = fmt.Sprintf("-restored-%d", n)
+				}
+				body[r.Rename] = name + suffix
+				if !used[r.Key(body)] {
+					break
+				}
+			}
+			c.ID = ""
+			c.Action, c.Key, c.Item = ActionCreate, r.Key(body), body
+			c.Details = fmt.Sprintf("renamed from %s", key)
+			used[c.Key] = true
+		}
+		if c.Action == ActionCreate || c.Action == ActionUpdate {
+			if err := r.Validate(c.Item, c.Action == ActionUpdate); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", r.Name, c.Key, err)
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// Apply performs one change and returns any note about the result.
+func Apply(ctx context.Context, client *api.Client, r resource.Resource, c Change) (string, error) {
+	switch c.Action {
+	case ActionCreate:
+		return r.Create(ctx, client, c.Item)
+	case ActionUpdate:
+		return "", r.Update(ctx, client, c.ID, c.Item)
+	}
+	return "", nil
+}
+
+// forCreate returns the body for creating it on another server: the
+// server ID is dropped unless it is also what identifies the item.
+func forCreate(r resource.Resource, it resource.Item) resource.Item {
+	body := resource.Clone(it)
+	if r.ID == "" {
+		return body
+	}
+	delete(body, r.ID)
+	if r.Key(body) != r.Key(it) {
+		body[r.ID] = it[r.ID]
+	}
+	return body
+}
+
+func equal(r resource.Resource, a, b resource.Item) bool {
+	strip := func(it resource.Item) string {
+		c := resource.Clone(it)
+		for _, f := range append(volatile, r.Secrets...) {
+			delete(c, f)
+		}
+		if r.ID != "" {
+			delete(c, r.ID)
+		}
+		data, _ := json.Marshal(c)
+		return string(data)
+	}
+	return strip(a) == strip(b)
+}
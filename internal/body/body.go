@@ -0,0 +1,110 @@
+// Package body reads request bodies given on the command line, either as
+// inline JSON or from a JSON/YAML file, and decodes them strictly into the
+// generated models so typos are caught before they reach the server.
+package body
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Parse reads a --data value: inline JSON, or "@path" to read a JSON or
+// YAML file.
+func Parse(value string) (map[string]interface{}, error) {
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		return ReadFile(path)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &m); err != nil {
+		return nil, fmt.Errorf("invalid JSON in --data flag: %w", err)
+	}
+	return m, nil
+}
+
+// ReadFile reads a JSON object from a .json file, or a YAML mapping from
+// any other file.
+func ReadFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	var m map[string]interface{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &m)
+	} else {
+		err = yaml.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	if m == nil {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+	return m, nil
+}
+
+// Merge copies the keys of src over dst, allocating dst if needed.
+func Merge(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// Decode decodes m into v, rejecting fields v does not have and values of
+// the wrong type.
+func Decode(m map[string]interface{}, v interface{}) error {
+	// Keep "->>" and friends (common in mapping expressions) unescaped.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(m); err != nil {
+		return fmt.Errorf("invalid body: %w", err)
+	}
+	dec := json.NewDecoder(&buf)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid body: %s", strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return nil
+}
+
+// CheckJSON checks that a JSON-valued field holds an object or array. A
+// string that itself contains JSON is reported as double-encoded.
+func CheckJSON(name string, raw json.RawMessage) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	switch raw[0] {
+	case '{', '[':
+		if !json.Valid(raw) {
+			return fmt.Errorf("%s is not valid JSON", name)
+		}
+		return nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil && json.Valid([]byte(s)) {
+			return fmt.Errorf("%s must be a JSON object or array, not a string containing JSON", name)
+		}
+		return fmt.Errorf("%s must be a JSON object or array: %s is not valid JSON", name, truncate(string(raw), 40))
+	}
+	return fmt.Errorf("%s must be a JSON object or array, got %s", name, truncate(string(raw), 40))
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,88 @@
+package body
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/models"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParse(t *testing.T) {
+	m, err := Parse(`{"hepid":1}`)
+	if err != nil || m["hepid"] != float64(1) {
+		t.Fatalf("unexpected inline result %v %v", m, err)
+	}
+
+	yamlFile := writeFile(t, "body.yaml", "profile: call\nmapping:\n  lookup_id: 100\n")
+	m, err = Parse("@" + yamlFile)
+	if err != nil || m["profile"] != "call" {
+		t.Fatalf("unexpected YAML result %v %v", m, err)
+	}
+
+	jsonFile := writeFile(t, "body.json", `{"profile": "registration"}`)
+	m, err = Parse("@" + jsonFile)
+	if err != nil || m["profile"] != "registration" {
+		t.Fatalf("unexpected JSON result %v %v", m, err)
+	}
+
+	if _, err := Parse(`{"hepid":`); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if _, err := Parse("@" + writeFile(t, "empty.yaml", "")); err == nil {
+		t.Error("expected error for empty file")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var sub models.HepsubSchema
+	m := map[string]interface{}{
+		"hepid":   1,
+		"profile": "call",
+		"mapping": map[string]interface{}{"lookup_id": 100},
+	}
+	if err := Decode(m, &sub); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if sub.Hepid != 1 || string(sub.Mapping) != `{"lookup_id":100}` {
+		t.Errorf("unexpected decode %+v", sub)
+	}
+
+	err := Decode(map[string]interface{}{"hep_aliass": "SIP"}, &sub)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "hep_aliass"`) {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+	if err := Decode(map[string]interface{}{"hepid": "one"}, &sub); err == nil {
+		t.Error("expected type error")
+	}
+}
+
+func TestCheckJSON(t *testing.T) {
+	good := []string{``, `null`, `{"a":1}`, `[1,2]`}
+	for _, raw := range good {
+		if err := CheckJSON("f", json.RawMessage(raw)); err != nil {
+			t.Errorf("CheckJSON(%s) = %v", raw, err)
+		}
+	}
+
+	err := CheckJSON("fields_mapping", json.RawMessage(`"[{\"id\":1}]"`))
+	if err == nil || !strings.Contains(err.Error(), "not a string containing JSON") {
+		t.Errorf("expected double-encoding error, got %v", err)
+	}
+	for _, raw := range []string{`"[{id:1}"`, `42`} {
+		if err := CheckJSON("f", json.RawMessage(raw)); err == nil {
+			t.Errorf("expected error for %s", raw)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package call
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/sip"
+)
+
+// FetchMessages searches for call messages via POST /search/call/message and
+// parses the raw SIP payload of every row. Rows without a payload are skipped.
+// The result is ordered by capture time.
+func FetchMessages(ctx context.Context, client *api.Client, params SearchParams) ([]*sip.Message, error) {
+	raw, err := SearchMessage(ctx, client, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode message search response: %w", err)
+	}
+
+	msgs := make([]*sip.Message, 0, len(resp.Data))
+	for i, row := range resp.Data {
+		payload := rowString(row, "raw", "msg", "data")
+		if payload == "" {
+			continue
+		}
+		msg, err := sip.Parse(payload)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, err)
+		}
+		msg.SrcIP = rowString(row, "srcIp", "source_ip")
+		msg.DstIP = rowString(row, "dstIp", "destination_ip")
+		msg.SrcPort = int(rowNumber(row, "srcPort", "source_port"))
+		msg.DstPort = int(rowNumber(row, "dstPort", "destination_port"))
+		msg.Timestamp = rowTime(row)
+		msgs = append(msgs, msg)
+	}
+	sip.SortByTime(msgs)
+	return msgs, nil
+}
+
+// rowString returns the first non-empty string field among keys.
+func rowString(row map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := row[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// rowNumber returns the first numeric field among keys.
+func rowNumber(row map[string]interface{}, keys ...string) float64 {
+	for _, k := range keys {
+		if n, ok := row[k].(float64); ok {
+			return n
+		}
+	}
+	return 0
+}
+
+// rowTime derives the capture time from micro_ts (microseconds) or
+// create_date (milliseconds).
+func rowTime(row map[string]interface{}) time.Time {
+	if us := rowNumber(row, "micro_ts"); us > 0 {
+		return time.UnixMicro(int64(us)).UTC()
+	}
+	if ms := rowNumber(row, "create_date"); ms > 0 {
+		return time.UnixMilli(int64(ms)).UTC()
+	}
+	return time.Time{}
+}
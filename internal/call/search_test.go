@@ -380,3 +380,49 @@ func TestSearchRemote(t *testing.T) {
 		t.Fatal("expected non-nil result")
 	}
 }
+
+func TestFetchMessages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/call/message" {
+			t.Errorf("expected path /search/call/message, got %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{
+				{
+					"raw":      "SIP/2.0 200 OK\r\nCall-ID: abc\r\nCSeq: 1 INVITE\r\n\r\n",
+					"srcIp":    "10.0.0.2",
+					"srcPort":  5060,
+					"dstIp":    "10.0.0.1",
+					"dstPort":  5062,
+					"micro_ts": 1735725601000000,
+				},
+				{
+					"raw":         "INVITE sip:bob@example.com SIP/2.0\r\nCall-ID: abc\r\nCSeq: 1 INVITE\r\n\r\n",
+					"srcIp":       "10.0.0.1",
+					"create_date": 1735725600000,
+				},
+				{"id": 3},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	client := api.NewClientWith(srv.URL, "test-token")
+	params, _ := NewSearchParams("2025-01-01", "", "", "", "abc")
+
+	msgs, err := FetchMessages(context.Background(), client, params)
+	if err != nil {
+		t.Fatalf("FetchMessages failed: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages (row without payload skipped), got %d", len(msgs))
+	}
+	if msgs[0].Method != "INVITE" {
+		t.Errorf("expected messages sorted by time, got %s first", msgs[0].Summary())
+	}
+	if msgs[1].SrcIP != "10.0.0.2" || msgs[1].DstPort != 5062 {
+		t.Errorf("unexpected addresses on response: %+v", msgs[1])
+	}
+}
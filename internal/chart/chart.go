@@ -0,0 +1,170 @@
+// Package chart draws series of numbers in the terminal with Unicode block
+// characters: one-line sparklines and multi-line bar charts.
+package chart
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// blocks are the eighth-height bars, from one eighth to full.
+var blocks = []rune("▁▂▃▄▅▆▇█")
+
+// Resample fits values into width columns, averaging the values that fall
+// into the same column; NaN values are skipped. Fewer values than columns
+// are returned unchanged.
+func Resample(values []float64, width int) []float64 {
+	if width <= 0 || len(values) <= width {
+		return values
+	}
+	out := make([]float64, width)
+	for c := range out {
+		lo, hi := c*len(values)/width, (c+1)*len(values)/width
+		sum, n := 0.0, 0
+		for _, v := range values[lo:hi] {
+			if !math.IsNaN(v) {
+				sum += v
+				n++
+			}
+		}
+		out[c] = math.NaN()
+		if n > 0 {
+			out[c] = sum / float64(n)
+		}
+	}
+	return out
+}
+
+// bounds returns the smallest and largest values, ignoring NaN.
+func bounds(values []float64) (lo, hi float64, ok bool) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		lo, hi, ok = math.Min(lo, v), math.Max(hi, v), true
+	}
+	return lo, hi, ok
+}
+
+// scale maps v between lo and hi to 0..steps.
+func scale(v, lo, hi float64, steps int) int {
+	if hi == lo {
+		return steps / 2
+	}
+	return int(math.Round((v - lo) / (hi - lo) * float64(steps)))
+}
+
+// Sparkline draws values in one line of at most width characters.
+func Sparkline(values []float64, width int) string {
+	values = Resample(values, width)
+	lo, hi, _ := bounds(values)
+	var b strings.Builder
+	for _, v := range values {
+		if math.IsNaN(v) {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(blocks[scale(v, lo, hi, len(blocks)-1)])
+	}
+	return b.String()
+}
+
+// Bars draws values as a bar chart height lines tall and at most width
+// columns wide, with the value range labelled on the left.
+func Bars(values []float64, width, height int) []string {
+	lo, hi, ok := bounds(values)
+	if !ok {
+		return nil
+	}
+	top, bottom := Format(hi), Format(lo)
+	axis := len(top)
+	if len(bottom) > axis {
+		axis = len(bottom)
+	}
+	values = Resample(values, width-axis-2)
+	if height < 1 {
+		height = 1
+	}
+	// With all values equal, draw a flat line at the bottom.
+	if hi == lo {
+		hi = lo + 1
+	}
+
+	steps := height * len(blocks)
+	lines := make([]string, height)
+	for row := range lines {
+		var b strings.Builder
+		label := ""
+		switch row {
+		case 0:
+			label = top
+		case height - 1:
+			label = bottom
+		}
+		fmt.Fprintf(&b, "%*s ┤", axis, label)
+		// Eighths filled below this row.
+		base := (height - 1 - row) * len(blocks)
+		for _, v := range values {
+			if math.IsNaN(v) {
+				b.WriteRune(' ')
+				continue
+			}
+			// At least a sliver for every value, so the lowest stays visible.
+			level := scale(v, lo, hi, steps-1) + 1
+			switch {
+			case level >= base+len(blocks):
+				b.WriteRune(blocks[len(blocks)-1])
+			case level > base:
+				b.WriteRune(blocks[level-base-1])
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		lines[row] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
+
+// Render writes a titled chart of values followed by their minimum,
+// maximum and last value. A height of 1 draws a sparkline.
+func Render(w io.Writer, title string, values []float64, width, height int) {
+	fmt.Fprintln(w, title)
+	lo, hi, ok := bounds(values)
+	if !ok {
+		fmt.Fprintln(w, "  (no data)")
+		return
+	}
+	if height <= 1 {
+		fmt.Fprintln(w, "  "+Sparkline(values, width-2))
+	} else {
+		for _, line := range Bars(values, width, height) {
+			fmt.Fprintln(w, line)
+		}
+	}
+	last := math.NaN()
+	for i := len(values) - 1; i >= 0 && math.IsNaN(last); i-- {
+		last = values[i]
+	}
+	fmt.Fprintf(w, "  min %s  max %s  last %s\n", Format(lo), Format(hi), Format(last))
+}
+
+// Format writes a value compactly: 1234567 as 1.23M, 0.5 as 0.5.
+func Format(v float64) string {
+	abs := math.Abs(v)
+	for _, u := range []struct {
+		div    float64
+		suffix string
+	}{{1e12, "T"}, {1e9, "G"}, {1e6, "M"}, {1e3, "k"}} {
+		if abs >= u.div {
+			return strconv.FormatFloat(v/u.div, 'f', 2, 64) + u.suffix
+		}
+	}
+	if v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'g', 3, 64)
+}
@@ -0,0 +1,57 @@
+package chart
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestResample(t *testing.T) {
+	got := Resample([]float64{1, 3, 5, math.NaN(), math.NaN(), math.NaN()}, 3)
+	if got[0] != 2 || got[1] != 5 || !math.IsNaN(got[2]) {
+		t.Errorf("Resample = %v", got)
+	}
+	if got := Resample([]float64{1, 2}, 10); len(got) != 2 {
+		t.Errorf("short input resampled to %v", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]float64{0, 7, math.NaN(), 14}, 10); got != "▁▅ █" {
+		t.Errorf("Sparkline = %q", got)
+	}
+	if got := Sparkline([]float64{1, 2, 3, 4}, 2); got != "▁█" {
+		t.Errorf("Sparkline resampled = %q", got)
+	}
+}
+
+func TestBars(t *testing.T) {
+	lines := Bars([]float64{0, 5, 10}, 20, 2)
+	want := []string{
+		"10 ┤ ▁█",
+		" 0 ┤▁██",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Bars =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if Bars([]float64{math.NaN()}, 20, 2) != nil {
+		t.Error("expected no bars without data")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[float64]string{
+		0:        "0",
+		42:       "42",
+		0.5:      "0.5",
+		1.23456:  "1.23",
+		1234:     "1.23k",
+		-2500000: "-2.50M",
+		3e9:      "3.00G",
+	}
+	for v, want := range tests {
+		if got := Format(v); got != want {
+			t.Errorf("Format(%v) = %q; want %q", v, got, want)
+		}
+	}
+}
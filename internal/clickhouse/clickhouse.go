@@ -0,0 +1,289 @@
+// Package clickhouse runs SQL on the ClickHouse backend through
+// /clickhouse/query/raw: binding query parameters, keeping the column
+// order of results and writing them as tables, CSV or NDJSON, and a local
+// library of saved queries.
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"hepic-cli/internal/admin"
+	"hepic-cli/internal/api"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Run executes a query with admin.ClickhouseQuery and returns the raw
+// response. A trailing semicolon is dropped.
+func Run(ctx context.Context, client *api.Client, sql string) (json.RawMessage, error) {
+	sql = strings.TrimSuffix(strings.TrimSpace(sql), ";")
+	return admin.ClickhouseQuery(ctx, client, strings.TrimSpace(sql))
+}
+
+// Result is a query result with its columns in query order.
+type Result struct {
+	Columns []string
+	Rows    []Row
+	// Total is the row count the server reports, which may exceed
+	// len(Rows).
+	Total int64
+}
+
+// Row is one result row. It marshals with its columns in query order.
+type Row struct {
+	Columns []string
+	Values  map[string]interface{}
+}
+
+// MarshalJSON writes the row as an object with the columns in order.
+func (r Row) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, c := range r.Columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(c)
+		value, err := json.Marshal(r.Values[c])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// MarshalYAML writes the row as a mapping with the columns in order.
+func (r Row) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, c := range r.Columns {
+		var value yaml.Node
+		if err := value.Encode(plain(r.Values[c])); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: c}, &value)
+	}
+	return node, nil
+}
+
+// plain turns json.Number into a number YAML writes unquoted.
+func plain(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+// ParseResult extracts the rows of a response. The rows are an array of
+// objects, bare or under "data", possibly nested as in ClickHouse's JSON
+// format, whose "meta" then gives the column order; otherwise columns are
+// ordered as they first appear in the rows.
+func ParseResult(raw json.RawMessage) (*Result, error) {
+	res := &Result{}
+	body := raw
+	for depth := 0; depth < 3; depth++ {
+		var wrapped struct {
+			Data  json.RawMessage         `json:"data"`
+			Meta  []struct{ Name string } `json:"meta"`
+			Total int64                   `json:"total"`
+			Rows  int64                   `json:"rows"`
+		}
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) == 0 || trimmed[0] != '{' {
+			break
+		}
+		if err := json.Unmarshal(trimmed, &wrapped); err != nil {
+			return nil, fmt.Errorf("unexpected response: %w", err)
+		}
+		if wrapped.Data == nil {
+			// A single object is a one-row result.
+			break
+		}
+		for _, m := range wrapped.Meta {
+			res.Columns = append(res.Columns, m.Name)
+		}
+		if res.Total == 0 {
+			res.Total = wrapped.Total
+		}
+		if res.Total == 0 {
+			res.Total = wrapped.Rows
+		}
+		body = wrapped.Data
+	}
+
+	body = bytes.TrimSpace(body)
+	var rows []json.RawMessage
+	switch {
+	case len(body) == 0 || string(body) == "null" || isEmptyObject(body):
+	case body[0] == '[':
+		if err := json.Unmarshal(body, &rows); err != nil {
+			return nil, fmt.Errorf("unexpected rows: %w", err)
+		}
+	case body[0] == '{':
+		rows = []json.RawMessage{body}
+	default:
+		return nil, fmt.Errorf("unexpected rows: %s", body)
+	}
+
+	known := make(map[string]bool)
+	for _, c := range res.Columns {
+		known[c] = true
+	}
+	for _, raw := range rows {
+		keys, values, err := decodeObject(raw)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if !known[k] {
+				known[k] = true
+				res.Columns = append(res.Columns, k)
+			}
+		}
+		res.Rows = append(res.Rows, Row{Values: values})
+	}
+	for i := range res.Rows {
+		res.Rows[i].Columns = res.Columns
+	}
+	if res.Total < int64(len(res.Rows)) {
+		res.Total = int64(len(res.Rows))
+	}
+	return res, nil
+}
+
+// isEmptyObject reports whether body is {}, which is no rows rather than
+// one row without columns.
+func isEmptyObject(body []byte) bool {
+	var m map[string]json.RawMessage
+	return json.Unmarshal(body, &m) == nil && len(m) == 0
+}
+
+// decodeObject decodes a row, returning its keys in document order. A
+// row that is an array is keyed by position.
+func decodeObject(raw json.RawMessage) ([]string, map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unexpected row: %w", err)
+	}
+	values := make(map[string]interface{})
+	var keys []string
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, nil, fmt.Errorf("unexpected row: %w", err)
+			}
+			key, _ := tok.(string)
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				return nil, nil, fmt.Errorf("unexpected row: %w", err)
+			}
+			if _, dup := values[key]; !dup {
+				keys = append(keys, key)
+			}
+			values[key] = v
+		}
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			var v interface{}
+			if err := dec.Decode(&v); err != nil {
+				return nil, nil, fmt.Errorf("unexpected row: %w", err)
+			}
+			key := fmt.Sprint(i + 1)
+			keys = append(keys, key)
+			values[key] = v
+		}
+	default:
+		return nil, nil, fmt.Errorf("unexpected row: %s", raw)
+	}
+	return keys, values, nil
+}
+
+// cell formats a value for CSV and table output: strings bare, other
+// values as JSON.
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// WriteCSV writes the result as CSV with a header row.
+func (r *Result) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(r.Columns)
+	for _, row := range r.Rows {
+		record := make([]string, len(r.Columns))
+		for i, c := range r.Columns {
+			record[i] = cell(row.Values[c])
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTable writes the result as aligned columns. Long cells are cut to
+// width characters; 0 keeps them whole. A result without columns writes
+// nothing.
+func (r *Result) WriteTable(w io.Writer, width int) error {
+	if len(r.Columns) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(r.Columns, "\t"))
+	dashes := make([]string, len(r.Columns))
+	for i, c := range r.Columns {
+		dashes[i] = strings.Repeat("-", len(c))
+	}
+	fmt.Fprintln(tw, strings.Join(dashes, "\t"))
+	for _, row := range r.Rows {
+		cells := make([]string, len(r.Columns))
+		for i, c := range r.Columns {
+			s := strings.NewReplacer("\t", " ", "\n", " ").Replace(cell(row.Values[c]))
+			if width > 0 && len([]rune(s)) > width {
+				s = string([]rune(s)[:width-1]) + "…"
+			}
+			cells[i] = s
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteNDJSON writes one JSON object per row.
+func (r *Result) WriteNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, row := range r.Rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hepic-cli/internal/api"
+
+	"go.yaml.in/yaml/v3"
+)
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/clickhouse/query/raw" {
+			t.Errorf("expected path /clickhouse/query/raw, got %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var q map[string]string
+		json.Unmarshal(body, &q)
+		if q["query"] != "SELECT 1" {
+			t.Errorf("expected query %q, got %q", "SELECT 1", q["query"])
+		}
+		w.Write([]byte(`{"data":[{"1":1}]}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.URL, "test-token")
+	raw, err := Run(context.Background(), client, "  SELECT 1;\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(raw) != `{"data":[{"1":1}]}` {
+		t.Errorf("unexpected result %s", raw)
+	}
+}
+
+func TestParseResult_Meta(t *testing.T) {
+	raw := json.RawMessage(`{"data":{"meta":[{"name":"z","type":"String"},{"name":"a","type":"UInt64"}],
+		"data":[{"a":1,"z":"x"},{"a":2,"z":"y"}],"rows":2}}`)
+	res, err := ParseResult(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(res.Columns, ",") != "z,a" {
+		t.Errorf("expected columns z,a, got %v", res.Columns)
+	}
+	if res.Total != 2 || len(res.Rows) != 2 {
+		t.Errorf("expected 2 rows, got %d (total %d)", len(res.Rows), res.Total)
+	}
+	data, _ := json.Marshal(res.Rows[0])
+	if string(data) != `{"z":"x","a":1}` {
+		t.Errorf("unexpected row JSON %s", data)
+	}
+
+	// total and rows describe the same rows; they are not added up.
+	res, err = ParseResult(json.RawMessage(`{"data":[{"a":1},{"a":2}],"total":5,"rows":2}`))
+	if err != nil || res.Total != 5 {
+		t.Errorf("expected total 5, got %+v, %v", res, err)
+	}
+}
+
+func TestParseResult_DocumentOrder(t *testing.T) {
+	res, err := ParseResult(json.RawMessage(`[{"sid":"a","n":3},{"sid":"b","extra":true,"n":1}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(res.Columns, ",") != "sid,n,extra" {
+		t.Errorf("expected columns sid,n,extra, got %v", res.Columns)
+	}
+
+	for _, empty := range []string{`{"data":[]}`, `{"data":{},"total":0}`, `null`} {
+		res, err = ParseResult(json.RawMessage(empty))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", empty, err)
+		}
+		if len(res.Rows) != 0 || len(res.Columns) != 0 || res.Total != 0 {
+			t.Errorf("%s: expected no rows, got %d", empty, len(res.Rows))
+		}
+	}
+
+	if _, err := ParseResult(json.RawMessage(`"text"`)); err == nil {
+		t.Error("expected error for a string response")
+	}
+}
+
+func TestRowMarshalYAML(t *testing.T) {
+	res, _ := ParseResult(json.RawMessage(`[{"b":"x","a":2}]`))
+	data, err := yaml.Marshal(res.Rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "- b: x\n  a: 2\n" {
+		t.Errorf("unexpected YAML %q", data)
+	}
+}
+
+func TestWriters(t *testing.T) {
+	res, _ := ParseResult(json.RawMessage(`[{"sid":"a,b","n":3,"tags":["x"]},{"sid":"long value","n":null}]`))
+
+	var buf bytes.Buffer
+	if err := res.WriteCSV(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "sid,n,tags\n\"a,b\",3,\"[\"\"x\"\"]\"\nlong value,,\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := res.WriteNDJSON(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = `{"sid":"a,b","n":3,"tags":["x"]}` + "\n" + `{"sid":"long value","n":null,"tags":null}` + "\n"
+	if buf.String() != want {
+		t.Errorf("unexpected NDJSON %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := res.WriteTable(&buf, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	want = "sid    n  tags\n---    -  ----\na,b    3  [\"x\"]\nlong…"
+	if strings.Join(lines, "\n") != want {
+		t.Errorf("unexpected table:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	params, err := ParseParams([]string{"from=now-1h", "q=a=b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params["from"] != "now-1h" || params["q"] != "a=b" {
+		t.Errorf("unexpected params %v", params)
+	}
+	if _, err := ParseParams([]string{"novalue"}); err == nil {
+		t.Error("expected error without =")
+	}
+}
+
+func TestDefaultsAndPlaceholders(t *testing.T) {
+	sql := "-- slow INVITEs\n-- param from=now-1h\nSELECT * FROM hep\nWHERE ts > {from:DateTime} AND sid = { callid : String } AND x = '{not:String}' -- {nor:String}\nAND ts < {from:DateTime}"
+	if d := Defaults(sql); len(d) != 1 || d["from"] != "now-1h" {
+		t.Errorf("unexpected defaults %v", d)
+	}
+	if p := Placeholders(sql); strings.Join(p, ",") != "from,callid" {
+		t.Errorf("unexpected placeholders %v", p)
+	}
+}
+
+func TestBind(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sql := "SELECT * FROM {table:Identifier} WHERE sid = {callid:String} AND ts > {from:DateTime} AND note = '{callid:String}'"
+	got, err := Bind(sql, map[string]string{
+		"table":  "db.hep",
+		"callid": `x' OR 1=1 --\`,
+		"from":   "now-1h",
+	}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "SELECT * FROM `db`.`hep` WHERE sid = 'x\\' OR 1=1 --\\\\' AND ts > toDateTime(1714561200) AND note = '{callid:String}'"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	if _, err := Bind(sql, map[string]string{"table": "hep"}, now); err == nil || !strings.Contains(err.Error(), "callid, from") {
+		t.Errorf("expected missing parameters error, got %v", err)
+	}
+	if _, err := Bind("SELECT 1", map[string]string{"x": "1"}, now); err == nil || !strings.Contains(err.Error(), "unknown parameters: x") {
+		t.Errorf("expected unknown parameters error, got %v", err)
+	}
+	if _, err := Bind("SELECT {n:UInt32}", map[string]string{"n": "1; DROP TABLE hep"}, now); err == nil {
+		t.Error("expected error for an invalid number")
+	}
+	// A negative value after a minus must not start a comment.
+	if got, err := Bind("SELECT 1 -{v:Int32}, 2", map[string]string{"v": "-1"}, now); err != nil || got != "SELECT 1 -(-1), 2" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		typ, value, want string
+	}{
+		{"String", "it's", `'it\'s'`},
+		{"LowCardinality(String)", "a", "'a'"},
+		{"Nullable(Int64)", "NULL", "NULL"},
+		{"Nullable(Int64)", "-5", "(-5)"},
+		{"UInt64", "18446744073709551615", "(18446744073709551615)"},
+		{"Float64", "1.5", "(1.5)"},
+		{"Bool", "true", "true"},
+		{"Date", "2024-05-01", "toDate(19844)"},
+		{"DateTime64(3)", "now", "fromUnixTimestamp64Milli(1714564800000)"},
+	}
+	for _, tt := range tests {
+		got, err := Literal(tt.typ, tt.value, now)
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %v", tt.typ, tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s %q: got %s, want %s", tt.typ, tt.value, got, tt.want)
+		}
+	}
+
+	for _, bad := range [][2]string{{"Identifier", "a`b"}, {"Int32", "1.5"}, {"Bool", "maybe"}, {"Array(String)", "x"}, {"Nullable", "1"}, {"LowCardinality", "a"}} {
+		if _, err := Literal(bad[0], bad[1], now); err == nil {
+			t.Errorf("%s %q: expected error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"SELECT 1", false},
+		{"SELECT 1;", true},
+		{"SELECT 1;  \n", true},
+		{"SELECT ';'", false},
+		{"SELECT 1 -- done;", false},
+		{"SELECT 1; -- done", true},
+		{"SELECT 1 /* ; */", false},
+	}
+	for _, tt := range tests {
+		if got := Complete(tt.sql); got != tt.want {
+			t.Errorf("Complete(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestOneLine(t *testing.T) {
+	got := OneLine("-- top calls\nSELECT sid /* id */\nFROM hep\n  WHERE x = '--';\n")
+	if got != "SELECT sid  FROM hep   WHERE x = '--';" {
+		t.Errorf("unexpected line %q", got)
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	lib := Library{Dir: t.TempDir() + "/queries"}
+
+	saved, err := lib.List()
+	if err != nil || len(saved) != 0 {
+		t.Fatalf("expected empty library, got %v, %v", saved, err)
+	}
+
+	sql := "-- param from=now-1h\n-- Slow INVITEs\nSELECT * FROM hep WHERE ts > {from:DateTime} AND sid = {callid:String}"
+	if err := lib.Save("slow-invites", sql); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := lib.Save("b", "SELECT 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := lib.Save("../x", "SELECT 1"); err == nil {
+		t.Error("expected error for an invalid name")
+	}
+
+	s, err := lib.Load("slow-invites")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Description != "Slow INVITEs" || s.SQL != sql+"\n" {
+		t.Errorf("unexpected saved query %+v", s)
+	}
+	if len(s.Params) != 2 || s.Params["from"] != "now-1h" || s.Params["callid"] != "" {
+		t.Errorf("unexpected params %v", s.Params)
+	}
+
+	saved, err = lib.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(saved) != 2 || saved[0].Name != "b" || saved[1].Name != "slow-invites" {
+		t.Errorf("unexpected list %+v", saved)
+	}
+
+	if err := lib.Delete("b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := lib.Load("b"); err == nil {
+		t.Error("expected error for a deleted query")
+	}
+	if err := lib.Delete("b"); err == nil {
+		t.Error("expected error deleting a missing query")
+	}
+}
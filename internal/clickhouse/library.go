@@ -0,0 +1,128 @@
+package clickhouse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Library is a directory of saved queries, one <name>.sql file each.
+type Library struct {
+	Dir string
+}
+
+// Saved is a saved query.
+type Saved struct {
+	Name string `json:"name"`
+	// Description is the first comment line of the query.
+	Description string `json:"description"`
+	// Params are the parameters the query takes, with their defaults.
+	Params map[string]string `json:"params,omitempty"`
+	SQL    string            `json:"-"`
+}
+
+func (l Library) path(name string) (string, error) {
+	if !namePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid query name %q: use letters, digits, '.', '-' and '_'", name)
+	}
+	return filepath.Join(l.Dir, name+".sql"), nil
+}
+
+// Load reads a saved query.
+func (l Library) Load(name string) (*Saved, error) {
+	path, err := l.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no saved query %q in %s", name, l.Dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseSaved(name, string(data)), nil
+}
+
+// Save writes a query, replacing a saved query of the same name.
+func (l Library) Save(name, sql string) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(l.Dir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.TrimRight(sql, "\n")+"\n"), 0600)
+}
+
+// Delete removes a saved query.
+func (l Library) Delete(name string) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); os.IsNotExist(err) {
+		return fmt.Errorf("no saved query %q in %s", name, l.Dir)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// List returns the saved queries by name.
+func (l Library) List() ([]Saved, error) {
+	entries, err := os.ReadDir(l.Dir)
+	if os.IsNotExist(err) {
+		return []Saved{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	saved := []Saved{}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".sql")
+		if e.IsDir() || !ok || !namePattern.MatchString(name) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(l.Dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		saved = append(saved, *parseSaved(name, string(data)))
+	}
+	sort.Slice(saved, func(i, j int) bool { return saved[i].Name < saved[j].Name })
+	return saved, nil
+}
+
+// parseSaved reads the description and parameters of a query.
+func parseSaved(name, sql string) *Saved {
+	s := &Saved{Name: name, SQL: sql, Params: map[string]string{}}
+	for _, line := range strings.Split(sql, "\n") {
+		line = strings.TrimSpace(line)
+		comment, ok := strings.CutPrefix(line, "--")
+		if !ok {
+			if line != "" {
+				break
+			}
+			continue
+		}
+		if defaultPattern.MatchString(line) {
+			continue
+		}
+		if comment = strings.TrimSpace(comment); comment != "" {
+			s.Description = comment
+			break
+		}
+	}
+	defaults := Defaults(sql)
+	for _, p := range Placeholders(sql) {
+		s.Params[p] = defaults[p]
+	}
+	return s
+}
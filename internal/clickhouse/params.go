@@ -0,0 +1,261 @@
+package clickhouse
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/statistic"
+)
+
+var (
+	placeholderPattern = regexp.MustCompile(`^\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*:\s*([A-Za-z][A-Za-z0-9_]*(?:\([^{}]*\))?)\s*\}`)
+	identifierPattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+	defaultPattern     = regexp.MustCompile(`^--\s*param\s+([A-Za-z_][A-Za-z0-9_]*)\s*=(.*)$`)
+)
+
+// ParseParams turns --param name=value flags into a map.
+func ParseParams(flags []string) (map[string]string, error) {
+	params := make(map[string]string, len(flags))
+	for _, f := range flags {
+		name, value, ok := strings.Cut(f, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid parameter %q: use name=value", f)
+		}
+		params[name] = value
+	}
+	return params, nil
+}
+
+// Defaults reads the parameter defaults declared in a query's comments,
+// one per line as in:
+//
+//	-- param from=now-1h
+func Defaults(sql string) map[string]string {
+	defaults := make(map[string]string)
+	for _, line := range strings.Split(sql, "\n") {
+		if m := defaultPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			defaults[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+	return defaults
+}
+
+// Placeholders lists the parameter names used in a query.
+func Placeholders(sql string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	scan(sql, func(i int) int {
+		m := placeholderPattern.FindStringSubmatch(sql[i:])
+		if m == nil {
+			return 0
+		}
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+		return len(m[0])
+	}, nil)
+	return names
+}
+
+// Bind replaces the ClickHouse query parameters {name:Type} in sql with
+// values as SQL literals of that type, so values cannot change the query:
+// strings are quoted and escaped, numbers checked and parenthesized (so
+// 1 -{v:Int32} with v=-1 is not a comment), and date and time
+// values (including expressions such as now-1h) converted from unix time.
+// Identifier parameters are checked and backquoted. Placeholders inside
+// string literals and comments are left alone.
+func Bind(sql string, params map[string]string, now time.Time) (string, error) {
+	var out strings.Builder
+	var missing []string
+	used := make(map[string]bool)
+	var bindErr error
+	last := 0
+	scan(sql, func(i int) int {
+		if bindErr != nil {
+			return 0
+		}
+		m := placeholderPattern.FindStringSubmatch(sql[i:])
+		if m == nil {
+			return 0
+		}
+		name, typ := m[1], m[2]
+		value, ok := params[name]
+		if !ok {
+			if !used[name] {
+				missing = append(missing, name)
+			}
+			used[name] = true
+			return len(m[0])
+		}
+		used[name] = true
+		literal, err := Literal(typ, value, now)
+		if err != nil {
+			bindErr = fmt.Errorf("parameter %s: %w", name, err)
+			return 0
+		}
+		out.WriteString(sql[last:i])
+		out.WriteString(literal)
+		last = i + len(m[0])
+		return len(m[0])
+	}, nil)
+	if bindErr != nil {
+		return "", bindErr
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing parameters: %s (set them with --param name=value)", strings.Join(missing, ", "))
+	}
+	var unused []string
+	for name := range params {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return "", fmt.Errorf("unknown parameters: %s (the query has no {name:Type} placeholder for them)", strings.Join(unused, ", "))
+	}
+	out.WriteString(sql[last:])
+	return out.String(), nil
+}
+
+// Literal formats value as a SQL literal of the ClickHouse type typ.
+func Literal(typ, value string, now time.Time) (string, error) {
+	base := typ
+	if i := strings.IndexByte(base, '('); i >= 0 {
+		base = base[:i]
+	}
+	switch {
+	case base == "Nullable" || base == "LowCardinality":
+		if len(typ) == len(base) {
+			return "", fmt.Errorf("%s needs an inner type, such as %s(String)", base, base)
+		}
+		inner := strings.TrimSuffix(typ[len(base)+1:], ")")
+		if base == "Nullable" && strings.EqualFold(value, "null") {
+			return "NULL", nil
+		}
+		return Literal(inner, value, now)
+	case base == "String" || base == "FixedString" || base == "UUID" || base == "IPv4" || base == "IPv6" || base == "Enum8" || base == "Enum16":
+		return Quote(value), nil
+	case base == "Identifier":
+		if !identifierPattern.MatchString(value) {
+			return "", fmt.Errorf("invalid identifier %q", value)
+		}
+		parts := strings.Split(value, ".")
+		for i, p := range parts {
+			parts[i] = "`" + p + "`"
+		}
+		return strings.Join(parts, "."), nil
+	case base == "Bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid Bool %q", value)
+		}
+		return strconv.FormatBool(b), nil
+	case strings.HasPrefix(base, "Int") || strings.HasPrefix(base, "UInt"):
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+				return "", fmt.Errorf("invalid %s %q", typ, value)
+			}
+		}
+		return "(" + value + ")", nil
+	case strings.HasPrefix(base, "Float") || strings.HasPrefix(base, "Decimal"):
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("invalid %s %q", typ, value)
+		}
+		return "(" + value + ")", nil
+	case base == "Date" || base == "Date32" || base == "DateTime" || base == "DateTime64":
+		t, err := statistic.ParseTime(value, now)
+		if err != nil {
+			return "", err
+		}
+		switch base {
+		case "Date", "Date32":
+			return fmt.Sprintf("toDate(%d)", t.Unix()/86400), nil
+		case "DateTime":
+			return fmt.Sprintf("toDateTime(%d)", t.Unix()), nil
+		}
+		return fmt.Sprintf("fromUnixTimestamp64Milli(%d)", t.UnixMilli()), nil
+	}
+	return "", fmt.Errorf("unsupported parameter type %s", typ)
+}
+
+// Quote quotes s as a ClickHouse string literal.
+func Quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// Complete reports whether sql ends a statement: its last token outside
+// literals and comments is a semicolon.
+func Complete(sql string) bool {
+	end := false
+	scan(sql, func(i int) int {
+		switch c := sql[i]; {
+		case c == ';':
+			end = true
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			end = false
+		}
+		return 0
+	}, nil)
+	return end
+}
+
+// OneLine joins a statement into one line for the history, dropping its
+// comments.
+func OneLine(sql string) string {
+	var b strings.Builder
+	last := 0
+	scan(sql, nil, func(start, end int) {
+		b.WriteString(sql[last:start])
+		last = end
+	})
+	b.WriteString(sql[last:])
+	return strings.TrimSpace(strings.ReplaceAll(b.String(), "\n", " "))
+}
+
+// scan calls visit for each byte of sql outside string literals, quoted
+// identifiers and comments, and comment, if not nil, for each comment.
+// visit returns how many bytes it consumed, or 0 to continue with the
+// next byte.
+func scan(sql string, visit func(i int) int, comment func(start, end int)) {
+	for i := 0; i < len(sql); {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i++
+			for i < len(sql) && sql[i] != c {
+				if sql[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+		case strings.HasPrefix(sql[i:], "--") || strings.HasPrefix(sql[i:], "/*"):
+			end := len(sql)
+			if sql[i+1] == '-' {
+				if j := strings.IndexByte(sql[i:], '\n'); j >= 0 {
+					end = i + j
+				}
+			} else if j := strings.Index(sql[i+2:], "*/"); j >= 0 {
+				end = i + j + 4
+			}
+			if comment != nil {
+				comment(i, end)
+			}
+			i = end
+		case visit == nil:
+			i++
+		default:
+			if n := visit(i); n > 0 {
+				i += n
+			} else {
+				i++
+			}
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Package compare reports configuration drift between two HEPIC
+// instances.
+package compare
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"hepic-cli/internal/jsondiff"
+	"hepic-cli/internal/resource"
+	"hepic-cli/internal/textdiff"
+)
+
+// DefaultResources are compared when no resources are given.
+var DefaultResources = []string{"ipaliases", "mappings", "hepsubs", "scripts", "advanced"}
+
+// Ignored fields are generated by each server and never compared.
+var Ignored = []string{"uuid", "guid", "create_date", "modify_date", "version"}
+
+// Item statuses, from source to target.
+const (
+	Added   = "added"   // only on the target
+	Removed = "removed" // only on the source
+	Changed = "changed"
+)
+
+// Difference is one item that is not the same on both instances.
+type Difference struct {
+	Resource string            `json:"resource"`
+	Key      string            `json:"key"`
+	Status   string            `json:"status"`
+	Changes  []jsondiff.Change `json:"changes,omitempty"`
+}
+
+// Summary counts the differences of one resource.
+type Summary struct {
+	Resource string `json:"resource"`
+	Same     int    `json:"same"`
+	Added    int    `json:"added"`
+	Removed  int    `json:"removed"`
+	Changed  int    `json:"changed"`
+}
+
+// Report is the result of a comparison.
+type Report struct {
+	Source      string       `json:"source"`
+	Target      string       `json:"target"`
+	Summary     []Summary    `json:"summary"`
+	Differences []Difference `json:"differences"`
+}
+
+// Drift reports whether any differences were found.
+func (r *Report) Drift() bool {
+	return len(r.Differences) > 0
+}
+
+// Resources resolves resource names, accepting the singular forms
+// (ipalias, mapping, ...) as well as the resource names.
+func Resources(names []string) ([]resource.Resource, error) {
+	if len(names) == 0 {
+		names = DefaultResources
+	}
+	known := make(map[string]bool)
+	for _, n := range resource.Names() {
+		known[n] = true
+	}
+	resolved := make([]string, 0, len(names))
+	for _, n := range names {
+		switch {
+		case known[n]:
+		case known[n+"s"]:
+			n += "s"
+		case known[n+"es"]:
+			n += "es"
+		}
+		resolved = append(resolved, n)
+	}
+	return resource.Select(resolved, nil)
+}
+
+// Resource compares the items of one resource and adds the result to r.
+// Items are matched on the resource's natural key.
+func (r *Report) Resource(res resource.Resource, source, target []resource.Item) {
+	src, tgt := index(res, source), index(res, target)
+	keys := make([]string, 0, len(src)+len(tgt))
+	for k := range src {
+		keys = append(keys, k)
+	}
+	for k := range tgt {
+		if _, ok := src[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	sum := Summary{Resource: res.Name}
+	for _, k := range keys {
+		s, inSource := src[k]
+		t, inTarget := tgt[k]
+		d := Difference{Resource: res.Name, Key: k}
+		switch {
+		case !inTarget:
+			d.Status = Removed
+			sum.Removed++
+		case !inSource:
+			d.Status = Added
+			sum.Added++
+		default:
+			d.Changes = Diff(res, s, t)
+			if len(d.Changes) == 0 {
+				sum.Same++
+				continue
+			}
+			d.Status = Changed
+			sum.Changed++
+		}
+		r.Differences = append(r.Differences, d)
+	}
+	r.Summary = append(r.Summary, sum)
+}
+
+// index maps items by key. Items sharing a key are numbered so none is
+// lost.
+func index(res resource.Resource, items []resource.Item) map[string]resource.Item {
+	m := make(map[string]resource.Item, len(items))
+	for _, it := range items {
+		k := res.Key(it)
+		for n := 2; ; n++ {
+			if _, dup := m[k]; !dup {
+				break
+			}
+			k = fmt.Sprintf("%s#%d", res.Key(it), n)
+		}
+		m[k] = it
+	}
+	return m
+}
+
+// Diff returns the field-level differences between two items, ignoring
+// server-generated fields and secrets.
+func Diff(res resource.Resource, source, target resource.Item) []jsondiff.Change {
+	skip := make(map[string]bool)
+	for _, f := range append(append([]string{res.ID}, Ignored...), res.Secrets...) {
+		skip[f] = true
+	}
+	fields := make(map[string]bool)
+	for k := range source {
+		fields[k] = true
+	}
+	for k := range target {
+		fields[k] = true
+	}
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		if !skip[k] {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []jsondiff.Change
+	for _, k := range names {
+		changes = append(changes, jsondiff.Value(k, source[k], target[k])...)
+	}
+	return changes
+}
+
+// Write prints r as a human-readable report. Multi-line text fields such
+// as script sources are shown as unified diffs.
+func (r *Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "Comparing %s (source) with %s (target)\n\n", r.Source, r.Target)
+	for _, s := range r.Summary {
+		state := "in sync"
+		if s.Added+s.Removed+s.Changed > 0 {
+			state = fmt.Sprintf("%d added, %d removed, %d changed", s.Added, s.Removed, s.Changed)
+		}
+		fmt.Fprintf(w, "  %-14s %4d same  %s\n", s.Resource, s.Same, state)
+	}
+	if !r.Drift() {
+		fmt.Fprintln(w, "\nNo drift found.")
+		return
+	}
+
+	resource := ""
+	for _, d := range r.Differences {
+		if d.Resource != resource {
+			resource = d.Resource
+			fmt.Fprintf(w, "\n%s\n", resource)
+		}
+		switch d.Status {
+		case Added:
+			fmt.Fprintf(w, "  + %s (only on target)\n", d.Key)
+		case Removed:
+			fmt.Fprintf(w, "  - %s (only on source)\n", d.Key)
+		default:
+			fmt.Fprintf(w, "  ~ %s\n", d.Key)
+			for _, c := range d.Changes {
+				writeChange(w, c)
+			}
+		}
+	}
+}
+
+func writeChange(w io.Writer, c jsondiff.Change) {
+	switch c.Action {
+	case jsondiff.Added:
+		fmt.Fprintf(w, "      %s: only on target: %s\n", c.Path, c.New)
+	case jsondiff.Removed:
+		fmt.Fprintf(w, "      %s: only on source: %s\n", c.Path, c.Old)
+	default:
+		old, new := unquote(c.Old), unquote(c.New)
+		if strings.Contains(old, "\n") || strings.Contains(new, "\n") {
+			fmt.Fprintf(w, "      %s:\n", c.Path)
+			diff := textdiff.Unified("source", "target", old, new, 2)
+			for _, line := range textdiff.Lines(diff) {
+				fmt.Fprintf(w, "        %s\n", line)
+			}
+			return
+		}
+		fmt.Fprintf(w, "      %s: %s -> %s\n", c.Path, c.Old, c.New)
+	}
+}
+
+// unquote returns the text of a rendered string value, or v unchanged.
+func unquote(v string) string {
+	if s, err := strconv.Unquote(v); err == nil {
+		return s
+	}
+	return v
+}
@@ -0,0 +1,67 @@
+package compare
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/resource"
+)
+
+func TestResources(t *testing.T) {
+	rs, err := Resources([]string{"ipalias", "mapping", "advanced"})
+	if err != nil || len(rs) != 3 || rs[0].Name != "ipaliases" || rs[1].Name != "mappings" {
+		t.Errorf("unexpected resources %v %v", rs, err)
+	}
+	rs, err = Resources(nil)
+	if err != nil || len(rs) != len(DefaultResources) {
+		t.Errorf("unexpected default resources %v %v", rs, err)
+	}
+	if _, err := Resources([]string{"widgets"}); err == nil {
+		t.Error("expected error for unknown resource")
+	}
+}
+
+func TestReport(t *testing.T) {
+	rs, _ := Resources([]string{"mapping", "script"})
+	mappings, scripts := rs[0], rs[1]
+
+	var r Report
+	r.Resource(mappings,
+		[]resource.Item{
+			{"guid": "s-1", "hepid": 1, "profile": "call", "retention": 7, "version": 1},
+			{"guid": "s-2", "hepid": 100, "profile": "default", "retention": 7},
+			{"guid": "s-3", "hepid": 5, "profile": "default"},
+		},
+		[]resource.Item{
+			{"guid": "t-1", "hepid": 1, "profile": "call", "retention": 7, "version": 9, "create_date": "x"},
+			{"guid": "t-2", "hepid": 100, "profile": "default", "retention": 14},
+			{"guid": "t-4", "hepid": 34, "profile": "default"},
+		})
+	r.Resource(scripts,
+		[]resource.Item{{"uuid": "a", "type": "lua", "hepid": 1, "profile": "call", "data": "a\nb\nc\n"}},
+		[]resource.Item{{"uuid": "b", "type": "lua", "hepid": 1, "profile": "call", "data": "a\nB\nc\n"}})
+
+	if s := r.Summary[0]; s.Same != 1 || s.Changed != 1 || s.Added != 1 || s.Removed != 1 {
+		t.Errorf("unexpected mapping summary %+v", s)
+	}
+	if len(r.Differences) != 4 {
+		t.Fatalf("unexpected differences %+v", r.Differences)
+	}
+	d := r.Differences[0]
+	if d.Key != "100/default" || d.Status != Changed || len(d.Changes) != 1 || d.Changes[0].Path != "retention" {
+		t.Errorf("unexpected change %+v", d)
+	}
+	if r.Differences[1].Status != Added || r.Differences[2].Status != Removed {
+		t.Errorf("unexpected statuses %+v", r.Differences[1:3])
+	}
+
+	var buf bytes.Buffer
+	r.Write(&buf)
+	out := buf.String()
+	for _, want := range []string{"~ 100/default", "retention: 7 -> 14", "+ 34/default (only on target)", "- 5/default (only on source)", "-b", "+B"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report is missing %q:\n%s", want, out)
+		}
+	}
+}
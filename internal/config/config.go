@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/viper"
 	"go.yaml.in/yaml/v3"
@@ -14,6 +16,15 @@ type Config struct {
 	Host   string `json:"host" yaml:"host"`
 	Token  string `json:"token" yaml:"token"`
 	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+	// Hosts are additional named instances, used by commands that talk to
+	// more than one server such as "hepic compare".
+	Hosts map[string]Instance `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+}
+
+// Instance is a named HEPIC server.
+type Instance struct {
+	Host  string `json:"host" yaml:"host" mapstructure:"host"`
+	Token string `json:"token" yaml:"token" mapstructure:"token"`
 }
 
 // ConfigDir returns the path to ~/.hepic.
@@ -36,11 +47,60 @@ func ConfigPath() (string, error) {
 
 // Load reads the current effective configuration from viper.
 func Load() *Config {
-	return &Config{
+	cfg := &Config{
 		Host:   viper.GetString("host"),
 		Token:  viper.GetString("token"),
 		Format: viper.GetString("format"),
 	}
+	viper.UnmarshalKey("hosts", &cfg.Hosts)
+	return cfg
+}
+
+// ReadFile reads ~/.hepic/config.yaml without environment or flag
+// overrides, for updating it in place. A missing file is an empty config.
+func ReadFile() (*Config, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config file: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("cannot parse config file: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Lookup returns the named instance from the hosts section. The name
+// "default" refers to the main host and token.
+func Lookup(name string) (Instance, error) {
+	cfg := Load()
+	if name == "default" {
+		if cfg.Host == "" || cfg.Token == "" {
+			return Instance{}, fmt.Errorf("host is not configured. Run 'hepic init' or set HEPIC_HOST")
+		}
+		return Instance{Host: cfg.Host, Token: cfg.Token}, nil
+	}
+	inst, ok := cfg.Hosts[name]
+	if !ok {
+		names := make([]string, 0, len(cfg.Hosts)+1)
+		names = append(names, "default")
+		for n := range cfg.Hosts {
+			names = append(names, n)
+		}
+		sort.Strings(names[1:])
+		return Instance{}, fmt.Errorf("unknown host %q (configured: %s); add it with 'hepic init --name %s'", name, strings.Join(names, ", "), name)
+	}
+	if inst.Host == "" || inst.Token == "" {
+		return Instance{}, fmt.Errorf("host %q needs both host and token", name)
+	}
+	return inst, nil
 }
 
 // Save writes the config to ~/.hepic/config.yaml.
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -113,3 +114,38 @@ func TestEnvOverridesConfig(t *testing.T) {
 		t.Errorf("expected token from file, got %s", loaded.Token)
 	}
 }
+
+func TestNamedHosts(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	cfg := &Config{
+		Host:  "https://prod.example.com",
+		Token: "prod-token",
+		Hosts: map[string]Instance{"staging": {Host: "https://staging.example.com", Token: "staging-token"}},
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	read, err := ReadFile()
+	if err != nil || read.Hosts["staging"].Token != "staging-token" || read.Host != cfg.Host {
+		t.Fatalf("unexpected config %+v %v", read, err)
+	}
+
+	viper.Reset()
+	viper.SetConfigFile(filepath.Join(tmpDir, ".hepic", "config.yaml"))
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	inst, err := Lookup("staging")
+	if err != nil || inst.Host != "https://staging.example.com" {
+		t.Errorf("unexpected staging instance %+v %v", inst, err)
+	}
+	inst, err = Lookup("default")
+	if err != nil || inst.Token != "prod-token" {
+		t.Errorf("unexpected default instance %+v %v", inst, err)
+	}
+	if _, err := Lookup("qa"); err == nil || !strings.Contains(err.Error(), "default, staging") {
+		t.Errorf("expected unknown host error listing hosts, got %v", err)
+	}
+}
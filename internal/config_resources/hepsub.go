@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/models"
 )
 
 // ListHepsub retrieves all HEP subscriptions.
@@ -46,3 +48,17 @@ func SearchHepsub(ctx context.Context, client *api.Client, data interface{}) (js
 	err := client.Post(ctx, "/hepsub/search", data, &result)
 	return result, err
 }
+
+// ValidateHepsub checks a HEP subscription body client-side: hepid and
+// profile are required unless partial is set (for updates), and mapping
+// must hold a JSON object or array.
+func ValidateHepsub(h models.HepsubSchema, partial bool) error {
+	validate := h.Validate
+	if partial {
+		validate = h.ValidatePartial
+	}
+	if err := validate(); err != nil {
+		return err
+	}
+	return body.CheckJSON("mapping", h.Mapping)
+}
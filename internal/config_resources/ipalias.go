@@ -3,9 +3,15 @@ package config_resources
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net/netip"
+	"strconv"
 
+	"hepic-cli/internal/alias"
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/importfile"
+	"hepic-cli/internal/models"
 )
 
 // ListAliases retrieves all IP aliases.
@@ -32,6 +38,14 @@ func UpdateAlias(ctx context.Context, client *api.Client, uuid string, data inte
 	return result, err
 }
 
+// UpdateLookupIP updates an existing alias based upon the lookup.
+// PUT /lookupip/{uuid}
+func UpdateLookupIP(ctx context.Context, client *api.Client, uuid string, data interface{}) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := client.Put(ctx, "/lookupip/"+api.PathEscape(uuid), data, &result)
+	return result, err
+}
+
 // DeleteAlias deletes an IP alias by UUID.
 // DELETE /ipalias/{uuid}
 func DeleteAlias(ctx context.Context, client *api.Client, uuid string) (json.RawMessage, error) {
@@ -57,7 +71,98 @@ func ExportAliases(ctx context.Context, client *api.Client) (io.ReadCloser, erro
 // ImportAliases imports IP aliases from a CSV file.
 // POST /ipalias/import
 func ImportAliases(ctx context.Context, client *api.Client, filePath string) (json.RawMessage, error) {
+	return ImportAliasesMode(ctx, client, filePath, importfile.ModeMerge, importfile.SourceCSV)
+}
+
+// ImportAliasesMode imports IP aliases with the given mode (merge or replace)
+// and source (csv or h2).
+// POST /ipalias/import, /ipalias/import/replace, /ipalias/import/h2, /ipalias/import/h2/replace
+func ImportAliasesMode(ctx context.Context, client *api.Client, filePath, mode, source string) (json.RawMessage, error) {
+	path, err := importfile.Endpoint("/ipalias", mode, source)
+	if err != nil {
+		return nil, err
+	}
 	var result json.RawMessage
-	err := client.PostFormFile(ctx, "/ipalias/import", "file", filePath, &result)
+	err = client.PostFormFile(ctx, path, "file", filePath, &result)
 	return result, err
 }
+
+// AliasCSVSchema validates IP alias import files. Rows are keyed by network
+// and port; mask defaults to a single host and port to any.
+var AliasCSVSchema = importfile.Schema{
+	Required: []string{"ip", "alias"},
+	Checks: map[string]func(string) error{
+		"ip": func(v string) error {
+			_, err := netip.ParseAddr(v)
+			if err != nil {
+				return fmt.Errorf("invalid IP address %q", v)
+			}
+			return nil
+		},
+		"mask":   checkInt(0, 128),
+		"port":   checkInt(0, 65535),
+		"status": checkBool,
+	},
+	Key: aliasRowKey,
+}
+
+func aliasRowKey(f *importfile.File, r importfile.Row) (string, error) {
+	ip := f.Get(r, "ip")
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", err
+	}
+	mask, port := addr.BitLen(), 0
+	if v := f.Get(r, "mask"); v != "" {
+		if mask, err = strconv.Atoi(v); err != nil {
+			return "", err
+		}
+	}
+	if v := f.Get(r, "port"); v != "" {
+		if port, err = strconv.Atoi(v); err != nil {
+			return "", err
+		}
+	}
+	prefix, err := alias.ParsePrefix(ip, mask, port)
+	if err != nil {
+		return "", err
+	}
+	a := models.AliasSwaggerStruct{IP: ip, Alias: f.Get(r, "alias"), Mask: uint16(mask), Port: uint16(port)}
+	if err := a.Validate(); err != nil {
+		return "", err
+	}
+	return alias.Key(prefix, port), nil
+}
+
+// AliasKeys returns the keys of the server's aliases in AliasCSVSchema form.
+func AliasKeys(aliases []models.AliasSwaggerStruct) []string {
+	keys := make([]string, 0, len(aliases))
+	for _, a := range aliases {
+		prefix, err := alias.ParsePrefix(a.IP, int(a.Mask), int(a.Port))
+		if err != nil {
+			continue
+		}
+		keys = append(keys, alias.Key(prefix, int(a.Port)))
+	}
+	return keys
+}
+
+func checkInt(min, max int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", v)
+		}
+		if n < min || n > max {
+			return fmt.Errorf("%d is out of range %d-%d", n, min, max)
+		}
+		return nil
+	}
+}
+
+func checkBool(v string) error {
+	if _, err := strconv.ParseBool(v); err != nil {
+		return fmt.Errorf("%q is not a boolean", v)
+	}
+	return nil
+}
@@ -6,9 +6,13 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/importfile"
+	"hepic-cli/internal/models"
 )
 
 func TestListAliases(t *testing.T) {
@@ -159,3 +163,81 @@ func TestDeleteAllAliases(t *testing.T) {
 		t.Errorf("expected 'successfully deleted all', got %s", got["message"])
 	}
 }
+
+func TestImportAliasesMode(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "aliases.csv")
+	os.WriteFile(tmpFile, []byte("ip,alias\n10.0.0.1,proxy1\n"), 0600)
+
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "ok"})
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.URL, "test-token")
+	if _, err := ImportAliasesMode(context.Background(), client, tmpFile, "replace", "h2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/ipalias/import/h2/replace" {
+		t.Errorf("expected /ipalias/import/h2/replace, got %s", gotPath)
+	}
+	if _, err := ImportAliases(context.Background(), client, tmpFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/ipalias/import" {
+		t.Errorf("expected /ipalias/import, got %s", gotPath)
+	}
+}
+
+func TestAliasCSVSchema(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "aliases.csv")
+	os.WriteFile(tmpFile, []byte("ip,mask,port,alias,status\n"+
+		"10.0.0.0,8,0,net,true\n"+
+		"10.0.0.300,32,0,bad-ip,true\n"+
+		"10.0.0.1,40,0,bad-mask,true\n"+
+		"10.0.0.2,32,70000,bad-port,yes\n"+
+		"10.0.0.9,8,0,dupe,true\n"+
+		"2001:db8::,32,0,v6,true\n"), 0600)
+
+	f, err := importfile.Read(tmpFile)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	lines := map[int]int{}
+	for _, e := range f.Validate(AliasCSVSchema) {
+		lines[e.Line]++
+	}
+	if lines[3] != 1 || lines[4] != 1 || lines[5] != 2 || lines[6] != 1 || len(lines) != 4 {
+		t.Errorf("unexpected errors per line: %v", lines)
+	}
+
+	keys := AliasKeys([]models.AliasSwaggerStruct{{IP: "10.0.0.1", Mask: 32, Port: 5060}})
+	if len(keys) != 1 || keys[0] != "10.0.0.1/32:5060" {
+		t.Errorf("unexpected keys %v", keys)
+	}
+}
+
+func TestUpdateLookupIP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		if r.URL.Path != "/lookupip/aaa" {
+			t.Errorf("expected /lookupip/aaa, got %s", r.URL.Path)
+		}
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["alias"] != "sbc1" {
+			t.Errorf("expected alias sbc1, got %v", req["alias"])
+		}
+		json.NewEncoder(w).Encode(map[string]string{"data": "aaa", "message": "successfully updated"})
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.URL, "test-token")
+	if _, err := UpdateLookupIP(context.Background(), client, "aaa", map[string]interface{}{"alias": "sbc1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -3,8 +3,14 @@ package config_resources
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/models"
 )
 
 // ListMappings retrieves all protocol mappings.
@@ -47,6 +53,96 @@ func ListAllProtocols(ctx context.Context, client *api.Client) (json.RawMessage,
 	return result, err
 }
 
+// Protocol is a hepid/profile combination the server has a mapping for.
+type Protocol struct {
+	Hepid    uint16 `json:"hepid"`
+	HEPAlias string `json:"hep_alias,omitempty"`
+	Profile  string `json:"profile"`
+}
+
+// ListProtocols retrieves the known hepid/profile combinations.
+// GET /mapping/protocols
+func ListProtocols(ctx context.Context, client *api.Client) ([]Protocol, error) {
+	var result models.SuccessResponse
+	if err := client.Get(ctx, "/mapping/protocols", &result); err != nil {
+		return nil, err
+	}
+	var protocols []Protocol
+	if len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, &protocols); err != nil {
+			return nil, fmt.Errorf("unexpected /mapping/protocols response: %w", err)
+		}
+	}
+	return protocols, nil
+}
+
+// CheckProtocol reports an error unless hepid/profile is one of protocols.
+func CheckProtocol(protocols []Protocol, hepid uint16, profile string) error {
+	var profiles []string
+	hepAlias := ""
+	for _, p := range protocols {
+		if p.Hepid != hepid {
+			continue
+		}
+		if p.Profile == profile {
+			return nil
+		}
+		profiles = append(profiles, p.Profile)
+		if p.HEPAlias != "" {
+			hepAlias = p.HEPAlias
+		}
+	}
+
+	if len(profiles) == 0 {
+		var known []string
+		seen := make(map[uint16]bool)
+		for _, p := range protocols {
+			if !seen[p.Hepid] {
+				seen[p.Hepid] = true
+				known = append(known, strings.TrimSpace(strconv.Itoa(int(p.Hepid))+" "+p.HEPAlias))
+			}
+		}
+		sort.Strings(known)
+		return fmt.Errorf("hepid %d is not a known protocol (known: %s)", hepid, strings.Join(known, ", "))
+	}
+	if hepAlias != "" {
+		hepAlias = " (" + hepAlias + ")"
+	}
+	sort.Strings(profiles)
+	return fmt.Errorf("profile %q is not defined for hepid %d%s (known: %s)", profile, hepid, hepAlias, strings.Join(profiles, ", "))
+}
+
+// ValidateMapping checks a mapping body client-side: hepid and profile are
+// required unless partial is set (for updates), and the JSON-valued fields
+// must hold JSON objects or arrays.
+func ValidateMapping(m models.MappingSchema, partial bool) error {
+	validate := m.Validate
+	if partial {
+		validate = m.ValidatePartial
+	}
+	if err := validate(); err != nil {
+		return err
+	}
+	fields := []struct {
+		name string
+		raw  json.RawMessage
+	}{
+		{"correlation_mapping", m.CorrelationMapping},
+		{"create_index", m.CreateIndex},
+		{"fields_mapping", m.FieldsMapping},
+		{"fields_settings", m.FieldsSettings},
+		{"schema_mapping", m.SchemaMapping},
+		{"schema_settings", m.SchemaSettings},
+		{"user_mapping", m.UserMapping},
+	}
+	for _, f := range fields {
+		if err := body.CheckJSON(f.name, f.raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ResetAll resets all protocol mappings to defaults.
 // GET /mapping/protocol/reset
 func ResetAll(ctx context.Context, client *api.Client) (json.RawMessage, error) {
@@ -62,3 +158,29 @@ func ResetOne(ctx context.Context, client *api.Client, uuid string) (json.RawMes
 	err := client.Get(ctx, "/mapping/protocol/reset/"+api.PathEscape(uuid), &result)
 	return result, err
 }
+
+// GetMapping retrieves a single protocol mapping by UUID.
+// GET /mapping/protocol/{uuid}
+func GetMapping(ctx context.Context, client *api.Client, uuid string) (models.MappingSchema, error) {
+	var result models.SuccessResponse
+	if err := client.Get(ctx, "/mapping/protocol/"+api.PathEscape(uuid), &result); err != nil {
+		return models.MappingSchema{}, err
+	}
+
+	// The mapping comes back either on its own or as a one-element list.
+	var mapping models.MappingSchema
+	var list []models.MappingSchema
+	if err := json.Unmarshal(result.Data, &list); err == nil {
+		if len(list) == 0 {
+			return mapping, fmt.Errorf("mapping %s not found", uuid)
+		}
+		return list[0], nil
+	}
+	if err := json.Unmarshal(result.Data, &mapping); err != nil {
+		return mapping, fmt.Errorf("unexpected /mapping/protocol response: %w", err)
+	}
+	if mapping.Guid == "" && mapping.Hepid == 0 {
+		return mapping, fmt.Errorf("mapping %s not found", uuid)
+	}
+	return mapping, nil
+}
@@ -0,0 +1,109 @@
+package config_resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+)
+
+func TestListProtocols(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mapping/protocols" {
+			t.Errorf("expected /mapping/protocols, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count":2,"data":[{"hepid":1,"hep_alias":"SIP","profile":"call"},{"hepid":1,"hep_alias":"SIP","profile":"registration"}]}`))
+	}))
+	defer server.Close()
+
+	protocols, err := ListProtocols(context.Background(), api.NewClientWith(server.URL, "test-token"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(protocols) != 2 || protocols[1].Profile != "registration" || protocols[0].HEPAlias != "SIP" {
+		t.Errorf("unexpected protocols %+v", protocols)
+	}
+}
+
+func TestCheckProtocol(t *testing.T) {
+	protocols := []Protocol{
+		{Hepid: 1, HEPAlias: "SIP", Profile: "call"},
+		{Hepid: 1, HEPAlias: "SIP", Profile: "registration"},
+		{Hepid: 100, HEPAlias: "LOG", Profile: "default"},
+	}
+	if err := CheckProtocol(protocols, 1, "call"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := CheckProtocol(protocols, 1, "cal")
+	if err == nil || !strings.Contains(err.Error(), "(SIP) (known: call, registration)") {
+		t.Errorf("unexpected error for unknown profile: %v", err)
+	}
+	err = CheckProtocol(protocols, 99, "call")
+	if err == nil || !strings.Contains(err.Error(), "known: 1 SIP, 100 LOG") {
+		t.Errorf("unexpected error for unknown hepid: %v", err)
+	}
+}
+
+func TestValidateMapping(t *testing.T) {
+	m := models.MappingSchema{Hepid: 1, Profile: "call", FieldsMapping: json.RawMessage(`[{"id":"sid"}]`)}
+	if err := ValidateMapping(m, false); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateMapping(models.MappingSchema{Profile: "call"}, false); err == nil {
+		t.Error("expected error for missing hepid")
+	}
+	if err := ValidateMapping(models.MappingSchema{Retention: 7}, true); err != nil {
+		t.Errorf("partial update should not require hepid/profile: %v", err)
+	}
+
+	m.CorrelationMapping = json.RawMessage(`"[{\"source_field\":\"callid\"}]"`)
+	err := ValidateMapping(m, false)
+	if err == nil || !strings.Contains(err.Error(), "correlation_mapping") {
+		t.Errorf("expected correlation_mapping error, got %v", err)
+	}
+}
+
+func TestValidateHepsub(t *testing.T) {
+	if err := ValidateHepsub(models.HepsubSchema{Hepid: 1, Profile: "call", Mapping: json.RawMessage(`{}`)}, false); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateHepsub(models.HepsubSchema{Hepid: 1}, false); err == nil {
+		t.Error("expected error for missing profile")
+	}
+	if err := ValidateHepsub(models.HepsubSchema{Mapping: json.RawMessage(`"x"`)}, true); err == nil {
+		t.Error("expected error for non-JSON mapping")
+	}
+}
+
+func TestGetMapping(t *testing.T) {
+	responses := map[string]string{
+		"/mapping/protocol/list":    `{"count":1,"data":[{"guid":"m-1","hepid":1,"profile":"call"}]}`,
+		"/mapping/protocol/object":  `{"count":1,"data":{"guid":"m-2","hepid":100,"profile":"default"}}`,
+		"/mapping/protocol/missing": `{"count":0,"data":[]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+	client := api.NewClientWith(server.URL, "test-token")
+
+	m, err := GetMapping(context.Background(), client, "list")
+	if err != nil || m.Guid != "m-1" || m.Profile != "call" {
+		t.Errorf("unexpected list result %+v %v", m, err)
+	}
+	m, err = GetMapping(context.Background(), client, "object")
+	if err != nil || m.Guid != "m-2" || m.Hepid != 100 {
+		t.Errorf("unexpected object result %+v %v", m, err)
+	}
+	if _, err := GetMapping(context.Background(), client, "missing"); err == nil {
+		t.Error("expected not found error")
+	}
+}
@@ -0,0 +1,114 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+)
+
+// Dashboard is a dashboard's full configuration: its element fields (id,
+// name, owner, ...) and its widgets and layout, kept as the server
+// returns them.
+type Dashboard = map[string]interface{}
+
+// Bundle is a file holding several dashboards, as written by export
+// --all.
+type Bundle struct {
+	ExportedAt time.Time   `json:"exported_at"`
+	Dashboards []Dashboard `json:"dashboards"`
+}
+
+// IDs lists the IDs of the current user's dashboards.
+func IDs(ctx context.Context, client *api.Client) ([]string, error) {
+	raw, err := List(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var list models.DashboardElementList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("unexpected dashboard list: %w", err)
+	}
+	ids := make([]string, 0, len(list.Data))
+	for _, d := range list.Data {
+		ids = append(ids, d.ID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// decode keeps numbers as json.Number, so that IDs and settings survive
+// an export and import unchanged.
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// Fetch retrieves a dashboard's full configuration. The server wraps it
+// in "data" and may leave out the ID, which is set from id.
+func Fetch(ctx context.Context, client *api.Client, id string) (Dashboard, error) {
+	raw, err := Get(ctx, client, id)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data Dashboard `json:"data"`
+	}
+	d := Dashboard{}
+	if err := decode(raw, &resp); err == nil && resp.Data != nil {
+		d = resp.Data
+	} else if err := decode(raw, &d); err != nil {
+		return nil, fmt.Errorf("unexpected response: %w", err)
+	}
+	if d == nil {
+		return nil, fmt.Errorf("empty response")
+	}
+	d["id"] = id
+	return d, nil
+}
+
+// Validate checks the element fields of a dashboard against
+// models.DashboardElements.
+func Validate(d Dashboard) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	var el models.DashboardElements
+	if err := json.Unmarshal(data, &el); err != nil {
+		return fmt.Errorf("invalid dashboard: %w", err)
+	}
+	return el.Validate()
+}
+
+// ReadFile reads the dashboards of a file written by export: a single
+// dashboard or a bundle.
+func ReadFile(path string) ([]Dashboard, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, ok := probe["dashboards"]; ok {
+		var b Bundle
+		if err := decode(data, &b); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return b.Dashboards, nil
+	}
+	var d Dashboard
+	if err := decode(data, &d); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return []Dashboard{d}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/ops"
 )
 
 // List retrieves all dashboards. GET /dashboard/info
@@ -13,7 +14,17 @@ func List(ctx context.Context, client *api.Client) (json.RawMessage, error) {
 	return result, err
 }
 
-// Store creates or updates a dashboard. PUT /dashboard/store/{dashboardId}
+// Create adds a new dashboard. POST /dashboard/store/{dashboardId}
+//
+// The spec gives the body as UserSettings, which is not what the server
+// takes, so this does not go through ops.
+func Create(ctx context.Context, client *api.Client, dashboardID string, data interface{}) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := client.Post(ctx, "/dashboard/store/"+api.PathEscape(dashboardID), data, &result)
+	return result, err
+}
+
+// Store updates an existing dashboard. PUT /dashboard/store/{dashboardId}
 func Store(ctx context.Context, client *api.Client, dashboardID string, data interface{}) (json.RawMessage, error) {
 	var result json.RawMessage
 	err := client.Put(ctx, "/dashboard/store/"+api.PathEscape(dashboardID), data, &result)
@@ -26,3 +37,16 @@ func Delete(ctx context.Context, client *api.Client, dashboardID string) (json.R
 	err := client.Delete(ctx, "/dashboard/store/"+api.PathEscape(dashboardID), &result)
 	return result, err
 }
+
+// Get retrieves a dashboard's configuration. GET /dashboard/store/{dashboardId}
+func Get(ctx context.Context, client *api.Client, dashboardID string) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := client.Get(ctx, "/dashboard/store/"+api.PathEscape(dashboardID), &result)
+	return result, err
+}
+
+// Reset restores the default dashboards of the current user. GET /dashboard/reset
+func Reset(ctx context.Context, client *api.Client) (json.RawMessage, error) {
+	_, raw, err := ops.GetDashboardReset(ctx, client)
+	return raw, err
+}
@@ -6,6 +6,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"hepic-cli/internal/api"
@@ -82,6 +85,21 @@ func TestStore_SendsPUT(t *testing.T) {
 	}
 }
 
+func TestCreate_SendsPOST(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/dashboard/store/dash1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		io.WriteString(w, `{"status":"ok"}`)
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.URL, "test-token")
+	if _, err := Create(context.Background(), client, "dash1", map[string]interface{}{"id": "dash1", "name": "New"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestDelete_SendsDELETE(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodDelete {
@@ -109,3 +127,84 @@ func TestDelete_SendsDELETE(t *testing.T) {
 		t.Errorf("expected status ok, got %s", parsed["status"])
 	}
 }
+
+func TestReset_SendsGET(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/dashboard/reset" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		io.WriteString(w, `{"count":1}`)
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.URL, "test-token")
+	if _, err := Reset(context.Background(), client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestIDsAndFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dashboard/info":
+			io.WriteString(w, `{"data":[{"id":"search","name":"Search"},{"id":"home","name":"Home"}],"total":2}`)
+		case "/dashboard/store/home":
+			io.WriteString(w, `{"data":{"name":"Home","weight":10,"widgets":[{"id":"w1"}]}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.URL, "test-token")
+	ids, err := IDs(context.Background(), client)
+	if err != nil || len(ids) != 2 || ids[0] != "home" {
+		t.Fatalf("IDs = %v, %v", ids, err)
+	}
+	d, err := Fetch(context.Background(), client, "home")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d["id"] != "home" || d["weight"] != json.Number("10") || d["widgets"] == nil {
+		t.Errorf("unexpected dashboard %v", d)
+	}
+	if err := Validate(d); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		d    Dashboard
+		want string
+	}{
+		{Dashboard{"name": "Home"}, "id is required"},
+		{Dashboard{"id": "home"}, "name is required"},
+		{Dashboard{"id": "a/b", "name": "Home"}, "invalid id"},
+		{Dashboard{"id": "home", "name": "Home", "weight": "heavy"}, "invalid dashboard"},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.d)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate(%v) = %v; want %q", tt.d, err, tt.want)
+		}
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	single := filepath.Join(dir, "home.json")
+	os.WriteFile(single, []byte(`{"id":"home","name":"Home"}`), 0644)
+	bundle := filepath.Join(dir, "all.json")
+	os.WriteFile(bundle, []byte(`{"exported_at":"2025-01-01T00:00:00Z","dashboards":[{"id":"a","name":"A"},{"id":"b","name":"B"}]}`), 0644)
+
+	if ds, err := ReadFile(single); err != nil || len(ds) != 1 || ds[0]["id"] != "home" {
+		t.Errorf("ReadFile(single) = %v, %v", ds, err)
+	}
+	if ds, err := ReadFile(bundle); err != nil || len(ds) != 2 || ds[1]["id"] != "b" {
+		t.Errorf("ReadFile(bundle) = %v, %v", ds, err)
+	}
+	if _, err := ReadFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,359 @@
+// Package hepsub reconciles HEP subscriptions on the server with a
+// templated YAML definitions file and exports them in the same format.
+package hepsub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/body"
+	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/models"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// File is a definitions file:
+//
+//	vars:                  # defaults for every environment
+//	  lookup_range: [-300, 200]
+//	environments:          # selected with --env, override vars
+//	  prod:
+//	    lookup_id: 100
+//	hepsubs:
+//	  - hepid: 1
+//	    profile: call
+//	    hep_alias: SIP
+//	    mapping:
+//	      lookup_id: "{{ .lookup_id }}"
+//	      lookup_range: "{{ .lookup_range }}"
+//
+// String values are Go templates. A value that is exactly one variable
+// reference ("{{ .name }}") takes the variable's type, so numbers and
+// lists stay numbers and lists.
+type File struct {
+	Vars         map[string]interface{}            `yaml:"vars,omitempty"`
+	Environments map[string]map[string]interface{} `yaml:"environments,omitempty"`
+	Hepsubs      []map[string]interface{}          `yaml:"hepsubs"`
+}
+
+// vars returns the variables for env: file vars, then the environment's,
+// then overrides. env is required when the file defines environments.
+func (f *File) vars(env string, overrides map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+	for k, v := range f.Vars {
+		vars[k] = v
+	}
+	if len(f.Environments) > 0 || env != "" {
+		envVars, ok := f.Environments[env]
+		if !ok {
+			names := make([]string, 0, len(f.Environments))
+			for name := range f.Environments {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if env == "" {
+				return nil, fmt.Errorf("--env is required: one of %s", strings.Join(names, ", "))
+			}
+			return nil, fmt.Errorf("unknown environment %q: must be one of %s", env, strings.Join(names, ", "))
+		}
+		for k, v := range envVars {
+			vars[k] = v
+		}
+	}
+	for k, v := range overrides {
+		vars[k] = v
+	}
+	return vars, nil
+}
+
+// Load reads a definitions file and renders it for env. Every rendered
+// subscription is decoded strictly and must have hepid and profile; a
+// hepid/profile combination may appear only once.
+func Load(path, env string, overrides map[string]interface{}) ([]models.HepsubSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read hepsub file: %w", err)
+	}
+	var f File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("cannot parse hepsub file %s: %w", path, err)
+	}
+	if len(f.Hepsubs) == 0 {
+		return nil, fmt.Errorf("hepsub file %s contains no hepsubs", path)
+	}
+
+	vars, err := f.vars(env, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]models.HepsubSchema, 0, len(f.Hepsubs))
+	seen := make(map[string]int)
+	for i, raw := range f.Hepsubs {
+		where := fmt.Sprintf("hepsubs[%d]", i)
+		rendered, err := render(where, raw, vars)
+		if err != nil {
+			return nil, err
+		}
+		var sub models.HepsubSchema
+		if err := body.Decode(rendered.(map[string]interface{}), &sub); err != nil {
+			return nil, fmt.Errorf("%s: %w", where, err)
+		}
+		if sub.Hepid == 0 || sub.Profile == "" {
+			return nil, fmt.Errorf("%s: hepid and profile are required", where)
+		}
+		k := Key(sub)
+		if prev, dup := seen[k]; dup {
+			return nil, fmt.Errorf("%s: duplicate of hepsubs[%d] (%s)", where, prev, k)
+		}
+		seen[k] = i
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// ParseSet parses --set key=value overrides. Values are YAML scalars, so
+// "lookup_id=100" sets a number.
+func ParseSet(values []string) (map[string]interface{}, error) {
+	vars := make(map[string]interface{}, len(values))
+	for _, kv := range values {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid --set %q: expected key=value", kv)
+		}
+		var val interface{}
+		if err := yaml.Unmarshal([]byte(v), &val); err != nil || val == nil {
+			val = v
+		}
+		vars[k] = val
+	}
+	return vars, nil
+}
+
+var varRef = regexp.MustCompile(`^\{\{-?\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*-?\}\}$`)
+
+// render expands templates in the string values of v.
+func render(path string, v interface{}, vars map[string]interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, val := range x {
+			r, err := render(path+"."+k, val, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, val := range x {
+			r, err := render(fmt.Sprintf("%s[%d]", path, i), val, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	case string:
+		if !strings.Contains(x, "{{") {
+			return x, nil
+		}
+		if m := varRef.FindStringSubmatch(strings.TrimSpace(x)); m != nil {
+			val, ok := vars[m[1]]
+			if !ok {
+				return nil, fmt.Errorf("%s: undefined variable %q", path, m[1])
+			}
+			return val, nil
+		}
+		tmpl, err := template.New(path).Option("missingkey=error").Parse(x)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, vars); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return buf.String(), nil
+	}
+	return v, nil
+}
+
+// Key identifies a subscription by hepid and profile.
+func Key(s models.HepsubSchema) string {
+	return fmt.Sprintf("%d/%s", s.Hepid, s.Profile)
+}
+
+// Fetch retrieves all HEP subscriptions with config_resources.ListHepsub.
+func Fetch(ctx context.Context, client *api.Client) ([]models.HepsubSchema, error) {
+	raw, err := config_resources.ListHepsub(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var result models.HepsubSchemaList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("unexpected hepsub list: %w", err)
+	}
+	return result.Data, nil
+}
+
+// Actions in a Plan.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change is one step of a Plan.
+type Change struct {
+	Action   string `json:"action"`
+	UUID     string `json:"uuid,omitempty"`
+	Hepid    uint16 `json:"hepid"`
+	Profile  string `json:"profile"`
+	HEPAlias string `json:"hep_alias"`
+	Details  string `json:"details,omitempty"`
+
+	// Body is the request body for create and update.
+	Body models.HepsubSchema `json:"-"`
+}
+
+// Plan returns the changes that make current match specs. Subscriptions
+// are matched on hepid and profile. Server entries missing from specs are
+// deleted only with prune.
+func Plan(specs, current []models.HepsubSchema, prune bool) []Change {
+	byKey := make(map[string]models.HepsubSchema, len(current))
+	for _, c := range current {
+		if _, dup := byKey[Key(c)]; !dup {
+			byKey[Key(c)] = c
+		}
+	}
+
+	var changes []Change
+	wanted := make(map[string]bool, len(specs))
+	for _, s := range specs {
+		wanted[Key(s)] = true
+		c := Change{Hepid: s.Hepid, Profile: s.Profile, HEPAlias: s.HEPAlias, Body: s}
+		existing, ok := byKey[Key(s)]
+		if !ok {
+			c.Action = ActionCreate
+			changes = append(changes, c)
+			continue
+		}
+		var details []string
+		if existing.HEPAlias != s.HEPAlias {
+			details = append(details, fmt.Sprintf("hep_alias: %q -> %q", existing.HEPAlias, s.HEPAlias))
+		}
+		if canonical(existing.Mapping) != canonical(s.Mapping) {
+			details = append(details, "mapping changed")
+		}
+		if len(details) > 0 {
+			c.Action, c.UUID, c.Details = ActionUpdate, existing.Guid, strings.Join(details, "; ")
+			c.Body.Guid = existing.Guid
+			changes = append(changes, c)
+		}
+	}
+
+	if prune {
+		seen := make(map[string]bool)
+		for _, c := range current {
+			k := Key(c)
+			if wanted[k] && !seen[k] {
+				seen[k] = true
+				continue
+			}
+			details := "not in file"
+			if wanted[k] {
+				details = "duplicate"
+			}
+			changes = append(changes, Change{Action: ActionDelete, UUID: c.Guid, Hepid: c.Hepid, Profile: c.Profile, HEPAlias: c.HEPAlias, Details: details})
+		}
+	}
+	return changes
+}
+
+// canonical re-encodes JSON so that formatting and key order don't matter.
+func canonical(raw json.RawMessage) string {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return "null"
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// escape quotes the template delimiters in the string values of v.
+func escape(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, val := range x {
+			x[k] = escape(val)
+		}
+	case []interface{}:
+		for i, val := range x {
+			x[i] = escape(val)
+		}
+	case string:
+		return strings.ReplaceAll(x, "{{", `{{"{{"}}`)
+	}
+	return v
+}
+
+// exported is one subscription in an exported file.
+type exported struct {
+	Hepid    uint16      `yaml:"hepid"`
+	Profile  string      `yaml:"profile"`
+	HEPAlias string      `yaml:"hep_alias,omitempty"`
+	Mapping  interface{} `yaml:"mapping,omitempty"`
+}
+
+// Export writes subs as a definitions file that Load reads back unchanged:
+// "{{" in mapping values is escaped so that it is not rendered as a
+// template.
+func Export(subs []models.HepsubSchema) ([]byte, error) {
+	sorted := append([]models.HepsubSchema(nil), subs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Hepid != sorted[j].Hepid {
+			return sorted[i].Hepid < sorted[j].Hepid
+		}
+		return sorted[i].Profile < sorted[j].Profile
+	})
+
+	doc := struct {
+		Hepsubs []exported `yaml:"hepsubs"`
+	}{Hepsubs: make([]exported, 0, len(sorted))}
+	for _, s := range sorted {
+		e := exported{Hepid: s.Hepid, Profile: s.Profile, HEPAlias: s.HEPAlias}
+		if len(s.Mapping) > 0 {
+			if err := json.Unmarshal(s.Mapping, &e.Mapping); err != nil {
+				return nil, fmt.Errorf("hepsub %s: invalid mapping: %w", Key(s), err)
+			}
+			e.Mapping = escape(e.Mapping)
+		}
+		doc.Hepsubs = append(doc.Hepsubs, e)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
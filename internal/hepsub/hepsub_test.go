@@ -0,0 +1,152 @@
+package hepsub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/models"
+)
+
+const definitions = `vars:
+  lookup_range: [-300, 200]
+environments:
+  prod:
+    lookup_id: 100
+  staging:
+    lookup_id: 101
+hepsubs:
+  - hepid: 1
+    profile: call
+    hep_alias: SIP
+    mapping:
+      lookup_id: "{{ .lookup_id }}"
+      lookup_range: "{{ .lookup_range }}"
+      lookup_field: "data_header->>'callid' -- id {{ .lookup_id }}"
+  - hepid: 1
+    profile: registration
+`
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hepsubs.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeFile(t, definitions)
+
+	subs, err := Load(path, "prod", nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(subs) != 2 {
+		t.Fatalf("expected 2 hepsubs, got %d", len(subs))
+	}
+	want := `{"lookup_field":"data_header->>'callid' -- id 100","lookup_id":100,"lookup_range":[-300,200]}`
+	if string(subs[0].Mapping) != want {
+		t.Errorf("unexpected mapping:\n got %s\nwant %s", subs[0].Mapping, want)
+	}
+
+	subs, err = Load(path, "staging", map[string]interface{}{"lookup_range": []interface{}{-60, 60}})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !strings.Contains(string(subs[0].Mapping), `"lookup_id":101,"lookup_range":[-60,60]`) {
+		t.Errorf("overrides not applied: %s", subs[0].Mapping)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	path := writeFile(t, definitions)
+	if _, err := Load(path, "", nil); err == nil || !strings.Contains(err.Error(), "prod, staging") {
+		t.Errorf("expected --env required error, got %v", err)
+	}
+	if _, err := Load(path, "dev", nil); err == nil {
+		t.Error("expected unknown environment error")
+	}
+
+	bad := []string{
+		"hepsubs:\n  - hepid: 1\n    profile: call\n    mapping: {id: \"{{ .missing }}\"}\n",
+		"hepsubs:\n  - hepid: 1\n    profile: call\n    hep_aliass: SIP\n",
+		"hepsubs:\n  - hepid: 1\n    profile: call\n  - hepid: 1\n    profile: call\n",
+		"hepsubs:\n  - profile: call\n",
+		"hepsubs: []\n",
+	}
+	for _, content := range bad {
+		if _, err := Load(writeFile(t, content), "", nil); err == nil {
+			t.Errorf("expected error for:\n%s", content)
+		}
+	}
+}
+
+func TestParseSet(t *testing.T) {
+	vars, err := ParseSet([]string{"lookup_id=100", "name=sip-eu", "range=[-1, 1]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["lookup_id"] != 100 || vars["name"] != "sip-eu" || len(vars["range"].([]interface{})) != 2 {
+		t.Errorf("unexpected vars %#v", vars)
+	}
+	if _, err := ParseSet([]string{"novalue"}); err == nil {
+		t.Error("expected error for missing =")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	current := []models.HepsubSchema{
+		{Guid: "same", Hepid: 1, Profile: "call", HEPAlias: "SIP", Mapping: json.RawMessage(`{"b": 2, "a": 1}`)},
+		{Guid: "changed", Hepid: 1, Profile: "registration", HEPAlias: "SIP", Mapping: json.RawMessage(`{"a":1}`)},
+		{Guid: "gone", Hepid: 100, Profile: "default"},
+	}
+	specs := []models.HepsubSchema{
+		{Hepid: 1, Profile: "call", HEPAlias: "SIP", Mapping: json.RawMessage(`{"a":1,"b":2}`)},
+		{Hepid: 1, Profile: "registration", HEPAlias: "SIP", Mapping: json.RawMessage(`{"a":2}`)},
+		{Hepid: 5, Profile: "rtcp"},
+	}
+
+	changes := Plan(specs, current, false)
+	if len(changes) != 2 {
+		t.Fatalf("expected update and create, got %+v", changes)
+	}
+	if changes[0].Action != ActionUpdate || changes[0].UUID != "changed" || changes[0].Details != "mapping changed" || changes[0].Body.Guid != "changed" {
+		t.Errorf("unexpected update %+v", changes[0])
+	}
+	if changes[1].Action != ActionCreate || changes[1].Hepid != 5 {
+		t.Errorf("unexpected create %+v", changes[1])
+	}
+
+	pruned := Plan(specs, current, true)
+	last := pruned[len(pruned)-1]
+	if len(pruned) != 3 || last.Action != ActionDelete || last.UUID != "gone" {
+		t.Errorf("expected stale subscription to be pruned, got %+v", pruned)
+	}
+}
+
+func TestExport_RoundTrip(t *testing.T) {
+	subs := []models.HepsubSchema{
+		{Guid: "b", Hepid: 100, Profile: "default", HEPAlias: "LOG"},
+		{Guid: "a", Hepid: 1, Profile: "call", HEPAlias: "SIP", Mapping: json.RawMessage(`{"lookup_id":100,"lookup_range":[-300,200]}`)},
+		{Guid: "c", Hepid: 2, Profile: "call", Mapping: json.RawMessage(`{"format":"{{ .user }}","tags":["a {{b}}"]}`)},
+	}
+	data, err := Export(subs)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "hepsubs:\n  - hepid: 1\n    profile: call\n") {
+		t.Errorf("unexpected export:\n%s", data)
+	}
+
+	loaded, err := Load(writeFile(t, string(data)), "", nil)
+	if err != nil {
+		t.Fatalf("Load of export failed: %v", err)
+	}
+	if changes := Plan(loaded, subs, true); len(changes) != 0 {
+		t.Errorf("round trip produced changes: %+v", changes)
+	}
+}
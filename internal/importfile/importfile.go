@@ -0,0 +1,277 @@
+// Package importfile validates CSV import files client-side and selects the
+// HEPIC import endpoint for a mode (merge or replace) and source (csv or h2).
+package importfile
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Import modes.
+const (
+	ModeMerge   = "merge"
+	ModeReplace = "replace"
+)
+
+// Import sources. SourceH2 is a CSV export from a legacy H2 database.
+const (
+	SourceCSV = "csv"
+	SourceH2  = "h2"
+)
+
+// Endpoint returns the import path below base (e.g. "/ipalias") for the
+// given mode and source:
+//
+//	merge,   csv -> base/import
+//	replace, csv -> base/import/replace
+//	merge,   h2  -> base/import/h2
+//	replace, h2  -> base/import/h2/replace
+func Endpoint(base, mode, source string) (string, error) {
+	path := base + "/import"
+	switch source {
+	case SourceCSV, "":
+	case SourceH2:
+		path += "/h2"
+	default:
+		return "", fmt.Errorf("invalid source %q: must be csv or h2", source)
+	}
+	switch mode {
+	case ModeMerge, "":
+	case ModeReplace:
+		path += "/replace"
+	default:
+		return "", fmt.Errorf("invalid mode %q: must be merge or replace", mode)
+	}
+	return path, nil
+}
+
+// File is a parsed CSV file with a header row.
+type File struct {
+	Header []string
+	Rows   []Row
+}
+
+// Row is one data record and the line it starts on.
+type Row struct {
+	Line   int
+	Fields []string
+}
+
+// Get returns the value of column name (case-insensitive), or "".
+func (f *File) Get(r Row, name string) string {
+	i := f.column(name)
+	if i < 0 || i >= len(r.Fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.Fields[i])
+}
+
+// Has reports whether the header contains column name.
+func (f *File) Has(name string) bool {
+	return f.column(name) >= 0
+}
+
+func (f *File) column(name string) int {
+	for i, h := range f.Header {
+		if strings.EqualFold(strings.TrimSpace(h), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Read parses a CSV file. Records with a wrong number of fields are kept
+// and reported by Validate rather than rejected here.
+func Read(path string) (*File, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer fh.Close()
+
+	r := csv.NewReader(fh)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	f := &File{}
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV in %s: %w", path, err)
+		}
+		line, _ := r.FieldPos(0)
+		if f.Header == nil {
+			// Strip a UTF-8 byte order mark left by spreadsheet exports.
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+			f.Header = record
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		f.Rows = append(f.Rows, Row{Line: line, Fields: record})
+	}
+	if f.Header == nil {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+	return f, nil
+}
+
+// Schema describes the columns an import file must or may contain.
+type Schema struct {
+	// Required columns must be present in the header and non-empty in every row.
+	Required []string
+	// Checks validate non-empty values of the named columns.
+	Checks map[string]func(string) error
+	// Key identifies a row for duplicate detection and previews; nil disables both.
+	Key func(f *File, r Row) (string, error)
+}
+
+// LineError is a validation problem at a specific line of the file.
+type LineError struct {
+	Line    int    `json:"line"`
+	Column  string `json:"column"`
+	Message string `json:"message"`
+}
+
+func (e LineError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("line %d: %s: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Validate checks f against the schema and returns every problem found,
+// ordered by line.
+func (f *File) Validate(s Schema) []LineError {
+	var errs []LineError
+	for _, col := range s.Required {
+		if !f.Has(col) {
+			errs = append(errs, LineError{Line: 1, Column: col, Message: "missing required column"})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	seen := make(map[string]int)
+	for _, r := range f.Rows {
+		if len(r.Fields) != len(f.Header) {
+			errs = append(errs, LineError{Line: r.Line, Message: fmt.Sprintf("expected %d fields, got %d", len(f.Header), len(r.Fields))})
+			continue
+		}
+		before := len(errs)
+		for _, col := range s.Required {
+			if f.Get(r, col) == "" {
+				errs = append(errs, LineError{Line: r.Line, Column: col, Message: "value is required"})
+			}
+		}
+		for col, check := range s.Checks {
+			v := f.Get(r, col)
+			if v == "" {
+				continue
+			}
+			if err := check(v); err != nil {
+				errs = append(errs, LineError{Line: r.Line, Column: col, Message: err.Error()})
+			}
+		}
+		if s.Key == nil {
+			continue
+		}
+		key, err := s.Key(f, r)
+		if err != nil {
+			// Only report what the column checks did not already catch.
+			if len(errs) == before {
+				errs = append(errs, LineError{Line: r.Line, Message: err.Error()})
+			}
+			continue
+		}
+		if prev, dup := seen[key]; dup {
+			errs = append(errs, LineError{Line: r.Line, Message: fmt.Sprintf("duplicate of line %d (%s)", prev, key)})
+			continue
+		}
+		seen[key] = r.Line
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+	return errs
+}
+
+// Keys returns the schema key of every row that has one.
+func (f *File) Keys(s Schema) []string {
+	var keys []string
+	for _, r := range f.Rows {
+		if k, err := s.Key(f, r); err == nil {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Change actions in a replace preview.
+const (
+	ActionAdd    = "add"
+	ActionRemove = "remove"
+	ActionKeep   = "keep"
+)
+
+// Change is one entry of a replace preview.
+type Change struct {
+	Action string `json:"action"`
+	Key    string `json:"key"`
+}
+
+// Preview compares the keys in an import file with those on the server and
+// reports what a replace import would add, remove and keep.
+func Preview(fileKeys, serverKeys []string) []Change {
+	inFile := make(map[string]bool, len(fileKeys))
+	for _, k := range fileKeys {
+		inFile[k] = true
+	}
+	onServer := make(map[string]bool, len(serverKeys))
+	for _, k := range serverKeys {
+		onServer[k] = true
+	}
+
+	var changes []Change
+	for k := range onServer {
+		if !inFile[k] {
+			changes = append(changes, Change{Action: ActionRemove, Key: k})
+		}
+	}
+	for k := range inFile {
+		action := ActionAdd
+		if onServer[k] {
+			action = ActionKeep
+		}
+		changes = append(changes, Change{Action: action, Key: k})
+	}
+
+	order := map[string]int{ActionRemove: 0, ActionAdd: 1, ActionKeep: 2}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Action != changes[j].Action {
+			return order[changes[i].Action] < order[changes[j].Action]
+		}
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// Count returns how many changes have the given action.
+func Count(changes []Change, action string) int {
+	n := 0
+	for _, c := range changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,111 @@
+package importfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCSV(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "import.csv")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEndpoint(t *testing.T) {
+	cases := map[[2]string]string{
+		{ModeMerge, SourceCSV}:   "/users/import",
+		{ModeReplace, SourceCSV}: "/users/import/replace",
+		{ModeMerge, SourceH2}:    "/users/import/h2",
+		{ModeReplace, SourceH2}:  "/users/import/h2/replace",
+	}
+	for in, want := range cases {
+		got, err := Endpoint("/users", in[0], in[1])
+		if err != nil || got != want {
+			t.Errorf("Endpoint(%s, %s) = %q, %v; want %q", in[0], in[1], got, err, want)
+		}
+	}
+	if _, err := Endpoint("/users", "append", SourceCSV); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+	if _, err := Endpoint("/users", ModeMerge, "xml"); err == nil {
+		t.Error("expected error for unknown source")
+	}
+}
+
+var testSchema = Schema{
+	Required: []string{"name"},
+	Checks: map[string]func(string) error{
+		"age": func(v string) error {
+			if strings.Trim(v, "0123456789") != "" {
+				return fmt.Errorf("%q is not a number", v)
+			}
+			return nil
+		},
+	},
+	Key: func(f *File, r Row) (string, error) {
+		if n := f.Get(r, "name"); n != "" {
+			return n, nil
+		}
+		return "", fmt.Errorf("no name")
+	},
+}
+
+func TestValidate(t *testing.T) {
+	path := writeCSV(t, "\ufeffName,Age\nalice,30\n,31\nbob,x\nalice,32\ncarol\n\n")
+	f, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(f.Rows) != 5 || !f.Has("name") {
+		t.Fatalf("unexpected file: %+v", f)
+	}
+
+	errs := f.Validate(testSchema)
+	want := []string{
+		"line 3: name: value is required",
+		"line 4: age: \"x\" is not a number",
+		"line 5: duplicate of line 2 (alice)",
+		"line 6: expected 2 fields, got 1",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), errs)
+	}
+	for i, e := range errs {
+		if e.Error() != want[i] {
+			t.Errorf("error %d: got %q, want %q", i, e.Error(), want[i])
+		}
+	}
+}
+
+func TestValidate_MissingColumn(t *testing.T) {
+	f, err := Read(writeCSV(t, "age\n30\n"))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	errs := f.Validate(testSchema)
+	if len(errs) != 1 || errs[0].Line != 1 || errs[0].Column != "name" {
+		t.Errorf("expected missing column error, got %v", errs)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	changes := Preview([]string{"a", "b"}, []string{"b", "c"})
+	want := []Change{{ActionRemove, "c"}, {ActionAdd, "a"}, {ActionKeep, "b"}}
+	if len(changes) != len(want) {
+		t.Fatalf("unexpected preview %+v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: got %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+	if Count(changes, ActionRemove) != 1 {
+		t.Error("expected one removal")
+	}
+}
@@ -0,0 +1,161 @@
+// Package jsondiff compares decoded JSON values field by field.
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Change actions.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is one difference between two values.
+type Change struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// Value compares two decoded JSON values and reports the differences at
+// path and below. Objects are compared key by key, and array elements
+// that carry a unique "id" are matched by id rather than position.
+func Value(path string, old, new interface{}) []Change {
+	switch {
+	case old == nil && new == nil:
+		return nil
+	case old == nil:
+		return []Change{{Path: path, Action: Added, New: render(new)}}
+	case new == nil:
+		return []Change{{Path: path, Action: Removed, Old: render(old)}}
+	}
+
+	switch o := old.(type) {
+	case map[string]interface{}:
+		if n, ok := new.(map[string]interface{}); ok {
+			return diffObject(path, o, n)
+		}
+	case []interface{}:
+		if n, ok := new.([]interface{}); ok {
+			return diffArray(path, o, n)
+		}
+	}
+	if reflect.DeepEqual(old, new) {
+		return nil
+	}
+	return []Change{{Path: path, Action: Changed, Old: render(old), New: render(new)}}
+}
+
+func diffObject(path string, old, new map[string]interface{}) []Change {
+	keys := make(map[string]bool, len(old)+len(new))
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []Change
+	for _, k := range sorted {
+		changes = append(changes, Value(path+"."+k, old[k], new[k])...)
+	}
+	return changes
+}
+
+func diffArray(path string, old, new []interface{}) []Change {
+	oldIDs, okOld := ids(old)
+	newIDs, okNew := ids(new)
+	if !okOld || !okNew {
+		var changes []Change
+		for i := 0; i < len(old) || i < len(new); i++ {
+			var o, n interface{}
+			if i < len(old) {
+				o = old[i]
+			}
+			if i < len(new) {
+				n = new[i]
+			}
+			changes = append(changes, Value(fmt.Sprintf("%s[%d]", path, i), o, n)...)
+		}
+		return changes
+	}
+
+	var changes []Change
+	for i, id := range oldIDs {
+		elem := fmt.Sprintf("%s[id=%s]", path, id)
+		j := indexOf(newIDs, id)
+		if j < 0 {
+			changes = append(changes, Value(elem, old[i], nil)...)
+			continue
+		}
+		changes = append(changes, Value(elem, old[i], new[j])...)
+	}
+	for j, id := range newIDs {
+		if indexOf(oldIDs, id) < 0 {
+			changes = append(changes, Value(fmt.Sprintf("%s[id=%s]", path, id), nil, new[j])...)
+		}
+	}
+	return changes
+}
+
+// ids returns the "id" of every element if all elements are objects with
+// a unique id.
+func ids(list []interface{}) ([]string, bool) {
+	out := make([]string, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		var id string
+		switch x := obj["id"].(type) {
+		case string:
+			id = x
+		case float64:
+			id = strconv.FormatFloat(x, 'f', -1, 64)
+		case json.Number:
+			id = x.String()
+		default:
+			return nil, false
+		}
+		if seen[id] {
+			return nil, false
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out, true
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func render(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
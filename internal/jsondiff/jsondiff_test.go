@@ -0,0 +1,34 @@
+package jsondiff
+
+import "testing"
+
+func TestValue(t *testing.T) {
+	old := map[string]interface{}{
+		"name":  "a",
+		"tags":  []interface{}{"x", "y"},
+		"items": []interface{}{map[string]interface{}{"id": "1", "v": 1.0}, map[string]interface{}{"id": "2", "v": 2.0}},
+	}
+	new := map[string]interface{}{
+		"name":  "a",
+		"tags":  []interface{}{"x"},
+		"items": []interface{}{map[string]interface{}{"id": "2", "v": 3.0}, map[string]interface{}{"id": "1", "v": 1.0}},
+		"extra": true,
+	}
+	want := []Change{
+		{Path: "spec.extra", Action: Added, New: "true"},
+		{Path: "spec.items[id=2].v", Action: Changed, Old: "2", New: "3"},
+		{Path: "spec.tags[1]", Action: Removed, Old: `"y"`},
+	}
+	got := Value("spec", old, new)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+	if got := Value("x", 1.0, 1.0); len(got) != 0 {
+		t.Errorf("expected no changes, got %+v", got)
+	}
+}
@@ -0,0 +1,173 @@
+// Package ladder draws SIP message flows as text ladder diagrams: one
+// column per endpoint and one arrow per message.
+package ladder
+
+import (
+	"fmt"
+	"strings"
+
+	"hepic-cli/internal/sip"
+)
+
+const (
+	timeWidth   = 13 // "15:04:05.000 "
+	minColWidth = 10
+	maxColWidth = 30
+)
+
+// Diagram is a rendered ladder. Lines[i] is the arrow for the i-th message.
+type Diagram struct {
+	Header string
+	Lines  []string
+}
+
+// Namer returns a display name for an endpoint, or "" to use ip:port.
+type Namer func(ip string, port int) string
+
+// Render lays msgs out in width columns. Endpoints get a column each in the
+// order they first appear. name may be nil.
+func Render(msgs []*sip.Message, width int, name Namer) Diagram {
+	var keys []string
+	index := make(map[string]int)
+	var labels []string
+	add := func(ip string, port int) int {
+		key := endpoint(ip, port)
+		if i, ok := index[key]; ok {
+			return i
+		}
+		index[key] = len(keys)
+		keys = append(keys, key)
+		label := key
+		if name != nil {
+			if n := name(ip, port); n != "" {
+				label = n
+			}
+		}
+		labels = append(labels, label)
+		return index[key]
+	}
+	type arrow struct{ from, to int }
+	arrows := make([]arrow, len(msgs))
+	for i, m := range msgs {
+		arrows[i] = arrow{add(m.SrcIP, m.SrcPort), add(m.DstIP, m.DstPort)}
+	}
+
+	col := maxColWidth
+	if len(keys) > 0 {
+		col = (width - timeWidth) / len(keys)
+	}
+	if col > maxColWidth {
+		col = maxColWidth
+	}
+	if col < minColWidth {
+		col = minColWidth
+	}
+	center := func(i int) int { return i*col + col/2 }
+	size := len(keys) * col
+
+	header := []rune(strings.Repeat(" ", size))
+	for i, l := range labels {
+		l = truncate(l, col-1)
+		put(header, center(i)-len([]rune(l))/2, l)
+	}
+
+	d := Diagram{Header: clip(strings.Repeat(" ", timeWidth)+strings.TrimRight(string(header), " "), width)}
+	for i, m := range msgs {
+		line := []rune(strings.Repeat(" ", size))
+		for j := range keys {
+			line[center(j)] = '|'
+		}
+		a, b := center(arrows[i].from), center(arrows[i].to)
+		label := Label(m)
+		switch {
+		case a == b:
+			put(line, a+1, truncate(" <> "+label, size-a-1))
+		default:
+			lo, hi := a, b
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			for x := lo + 1; x < hi; x++ {
+				line[x] = '-'
+			}
+			if a < b {
+				line[hi-1] = '>'
+			} else {
+				line[lo+1] = '<'
+			}
+			inner := hi - lo - 3
+			if inner > len([]rune(label))+2 {
+				label = " " + label + " "
+			}
+			label = truncate(label, inner)
+			put(line, lo+2+(inner-len([]rune(label)))/2, label)
+		}
+		stamp := strings.Repeat(" ", timeWidth)
+		if !m.Timestamp.IsZero() {
+			stamp = m.Timestamp.Local().Format("15:04:05.000") + " "
+		}
+		d.Lines = append(d.Lines, clip(stamp+strings.TrimRight(string(line), " "), width))
+	}
+	return d
+}
+
+// Label is the short arrow text for a message: the method or status, with
+// "(SDP)" when the message carries a session description.
+func Label(m *sip.Message) string {
+	label := m.Method
+	if !m.IsRequest() {
+		label = m.Summary()
+	}
+	if strings.Contains(strings.ToLower(m.Header("Content-Type")), "application/sdp") {
+		label += " (SDP)"
+	}
+	return label
+}
+
+func endpoint(ip string, port int) string {
+	if ip == "" {
+		ip = "?"
+	}
+	if port == 0 {
+		return ip
+	}
+	if strings.Contains(ip, ":") {
+		return fmt.Sprintf("[%s]:%d", ip, port)
+	}
+	return fmt.Sprintf("%s:%d", ip, port)
+}
+
+// put writes s into line at x, dropping what does not fit.
+func put(line []rune, x int, s string) {
+	if x < 0 {
+		x = 0
+	}
+	for _, r := range s {
+		if x >= len(line) {
+			return
+		}
+		line[x] = r
+		x++
+	}
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n <= 0 {
+		return ""
+	}
+	if len(r) <= n {
+		return s
+	}
+	if n == 1 {
+		return string(r[:1])
+	}
+	return string(r[:n-1]) + "~"
+}
+
+func clip(s string, width int) string {
+	if r := []rune(s); width > 0 && len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
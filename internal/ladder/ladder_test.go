@@ -0,0 +1,60 @@
+package ladder
+
+import (
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/sip"
+)
+
+func message(t *testing.T, raw, src string, sport int, dst string, dport int) *sip.Message {
+	t.Helper()
+	m, err := sip.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SrcIP, m.SrcPort, m.DstIP, m.DstPort = src, sport, dst, dport
+	return m
+}
+
+func TestRender(t *testing.T) {
+	invite := message(t, "INVITE sip:bob@b SIP/2.0\r\nCall-ID: x\r\nContent-Type: application/sdp\r\n\r\nv=0\r\n", "10.0.0.1", 5060, "10.0.0.2", 5060)
+	ringing := message(t, "SIP/2.0 180 Ringing\r\nCall-ID: x\r\n\r\n", "10.0.0.2", 5060, "10.0.0.1", 5060)
+	forward := message(t, "INVITE sip:bob@c SIP/2.0\r\nCall-ID: x\r\n\r\n", "10.0.0.2", 5060, "10.0.0.3", 5080)
+
+	d := Render([]*sip.Message{invite, ringing, forward}, 100, func(ip string, port int) string {
+		if ip == "10.0.0.3" {
+			return "carrier"
+		}
+		return ""
+	})
+	if len(d.Lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(d.Lines))
+	}
+	for _, want := range []string{"10.0.0.1:5060", "10.0.0.2:5060", "carrier"} {
+		if !strings.Contains(d.Header, want) {
+			t.Errorf("header %q is missing %q", d.Header, want)
+		}
+	}
+	if !strings.Contains(d.Lines[0], "INVITE (SDP)") || !strings.Contains(d.Lines[0], "->|") {
+		t.Errorf("unexpected request arrow %q", d.Lines[0])
+	}
+	if !strings.Contains(d.Lines[1], "|<-") || !strings.Contains(d.Lines[1], "180 Ringing") {
+		t.Errorf("unexpected response arrow %q", d.Lines[1])
+	}
+	// The third endpoint's lifeline runs through the first two arrows.
+	if strings.Count(d.Lines[0], "|") != 3 {
+		t.Errorf("expected three lifelines in %q", d.Lines[0])
+	}
+	// The first arrow starts at the first column, the last one does not.
+	if strings.Index(d.Lines[2], "-") < strings.Index(d.Lines[0], ">") {
+		t.Errorf("forwarded arrow should start at the second column:\n%s\n%s", d.Lines[0], d.Lines[2])
+	}
+
+	narrow := Render([]*sip.Message{invite, ringing, forward}, 40, nil)
+	for _, l := range append(narrow.Lines, narrow.Header) {
+		if len([]rune(l)) > 40 {
+			t.Errorf("line exceeds width: %q", l)
+		}
+	}
+}
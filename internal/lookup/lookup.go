@@ -0,0 +1,198 @@
+// Package lookup answers "what is this IP?" from the alias table, capture
+// agent locations and recent SIP traffic.
+package lookup
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/alias"
+	"hepic-cli/internal/call"
+	"hepic-cli/internal/models"
+)
+
+// Result describes an IP address.
+type Result struct {
+	IP      string `json:"ip"`
+	Version string `json:"version"`
+	Scope   string `json:"scope"`
+
+	Alias      string   `json:"alias,omitempty"`
+	AliasUUID  string   `json:"alias_uuid,omitempty"`
+	Network    string   `json:"network,omitempty"`
+	Port       int      `json:"port,omitempty"`
+	Group      string   `json:"group,omitempty"`
+	ServerType string   `json:"servertype,omitempty"`
+	Active     bool     `json:"active,omitempty"`
+	AlsoIn     []string `json:"also_in,omitempty"`
+
+	Agents []Agent `json:"agents,omitempty"`
+
+	Traffic *Traffic `json:"traffic,omitempty"`
+}
+
+// Agent is a capture agent related to the IP, either because it runs on the
+// IP itself ("host") or sits in the same alias network ("network").
+type Agent struct {
+	UUID  string `json:"uuid"`
+	Node  string `json:"node,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Host  string `json:"host"`
+	Port  int    `json:"port,omitempty"`
+	Match string `json:"match"`
+}
+
+// Traffic summarizes the IP's appearance in recent SIP call data.
+type Traffic struct {
+	Since         time.Time  `json:"since"`
+	Messages      int        `json:"messages"`
+	AsSource      int        `json:"as_source"`
+	AsDestination int        `json:"as_destination"`
+	Calls         int        `json:"calls"`
+	LastSeen      *time.Time `json:"last_seen,omitempty"`
+	LastMethod    string     `json:"last_method,omitempty"`
+	LastPeer      string     `json:"last_peer,omitempty"`
+}
+
+// Scope classifies an address: loopback, private, link-local, multicast,
+// unspecified or public.
+func Scope(addr netip.Addr) string {
+	switch {
+	case addr.IsLoopback():
+		return "loopback"
+	case addr.IsPrivate():
+		return "private"
+	case addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast():
+		return "link-local"
+	case addr.IsMulticast():
+		return "multicast"
+	case addr.IsUnspecified():
+		return "unspecified"
+	}
+	return "public"
+}
+
+// NewSearchParams builds a call search for traffic from or to addr since
+// the given time, so that the server filters on the address rather than
+// returning all traffic.
+func NewSearchParams(addr netip.Addr, since time.Time) (call.SearchParams, error) {
+	params, err := call.NewSearchParams(since.Format(time.RFC3339), "", "", "", "")
+	if err != nil {
+		return params, err
+	}
+	params.Param["orlogic"] = map[string]interface{}{
+		"source_ip":      addr.String(),
+		"destination_ip": addr.String(),
+	}
+	return params, nil
+}
+
+// Build combines alias, agent and (optional) call data into a Result.
+// calls may be nil when traffic was not queried.
+func Build(addr netip.Addr, table *alias.Table, agents []models.AgentsLocation, calls []models.CallElement, since time.Time) Result {
+	addr = addr.Unmap()
+	r := Result{IP: addr.String(), Version: "IPv6", Scope: Scope(addr)}
+	if addr.Is4() {
+		r.Version = "IPv4"
+	}
+
+	matches := table.Matches(r.IP)
+	var network netip.Prefix
+	if len(matches) > 0 {
+		best := matches[0]
+		// Prefer an active, port-agnostic alias as the primary answer.
+		for _, m := range matches {
+			if m.Status && m.Port == 0 {
+				best = m
+				break
+			}
+		}
+		network = best.Prefix
+		r.Alias, r.AliasUUID, r.Network = best.Alias, best.UUID, best.Prefix.String()
+		r.Port, r.Group, r.ServerType, r.Active = int(best.Port), best.Group, best.Servertype, best.Status
+		for _, m := range matches {
+			if m.UUID != best.UUID {
+				r.AlsoIn = append(r.AlsoIn, fmt.Sprintf("%s (%s)", m.Alias, alias.Key(m.Prefix, int(m.Port))))
+			}
+		}
+	}
+
+	for _, a := range agents {
+		host, err := netip.ParseAddr(strings.Trim(a.Host, "[]"))
+		if err != nil {
+			continue
+		}
+		host = host.Unmap()
+		match := ""
+		switch {
+		case host == addr:
+			match = "host"
+		case network.IsValid() && network.Bits() > 0 && network.Contains(host):
+			match = "network"
+		default:
+			continue
+		}
+		r.Agents = append(r.Agents, Agent{UUID: a.UUID, Node: a.Node, Type: a.Type, Host: a.Host, Port: int(a.Port), Match: match})
+	}
+
+	if calls != nil {
+		r.Traffic = traffic(r.IP, calls, since)
+	}
+	return r
+}
+
+func traffic(ip string, calls []models.CallElement, since time.Time) *Traffic {
+	t := &Traffic{Since: since}
+	sids := make(map[string]bool)
+	var last int64
+	for _, c := range calls {
+		src, dst := sameIP(c.SrcIP, ip), sameIP(c.DstIP, ip)
+		if !src && !dst {
+			continue
+		}
+		t.Messages++
+		if src {
+			t.AsSource++
+		}
+		if dst {
+			t.AsDestination++
+		}
+		if c.Sid != "" {
+			sids[c.Sid] = true
+		}
+
+		ts := c.CreateDate
+		if ts == 0 && c.MicroTs > 0 {
+			ts = c.MicroTs / 1000
+		}
+		if ts >= last {
+			last = ts
+			t.LastMethod = c.Method
+			if src {
+				t.LastPeer = c.DstIP
+			} else {
+				t.LastPeer = c.SrcIP
+			}
+		}
+	}
+	t.Calls = len(sids)
+	if last > 0 {
+		seen := time.UnixMilli(last).UTC()
+		t.LastSeen = &seen
+	}
+	return t
+}
+
+func sameIP(a, b string) bool {
+	x, err := netip.ParseAddr(a)
+	if err != nil {
+		return false
+	}
+	y, err := netip.ParseAddr(b)
+	if err != nil {
+		return false
+	}
+	return x.Unmap() == y.Unmap()
+}
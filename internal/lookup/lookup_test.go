@@ -0,0 +1,73 @@
+package lookup
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"hepic-cli/internal/alias"
+	"hepic-cli/internal/models"
+)
+
+func TestBuild(t *testing.T) {
+	table := alias.NewTable([]models.AliasSwaggerStruct{
+		{UUID: "lan", Alias: "office", IP: "10.1.0.0", Mask: 16, Group: "branches", Servertype: "lan", Status: true},
+		{UUID: "sbc", Alias: "sbc-sip", IP: "10.1.2.3", Mask: 32, Port: 5060, Status: true},
+	})
+	agents := []models.AgentsLocation{
+		{UUID: "a1", Host: "10.1.2.3", Node: "probe1", Type: "hep"},
+		{UUID: "a2", Host: "10.1.9.9", Node: "probe2"},
+		{UUID: "a3", Host: "192.0.2.1"},
+		{UUID: "a4", Host: "probe.example.com"},
+	}
+	calls := []models.CallElement{
+		{Sid: "c1", SrcIP: "10.1.2.3", DstIP: "192.0.2.1", Method: "INVITE", CreateDate: 1000},
+		{Sid: "c1", SrcIP: "192.0.2.1", DstIP: "10.1.2.3", Method: "200", CreateDate: 2000},
+		{Sid: "c2", SrcIP: "10.1.2.3", DstIP: "192.0.2.7", Method: "OPTIONS", CreateDate: 1500},
+		{Sid: "c3", SrcIP: "192.0.2.1", DstIP: "192.0.2.7", Method: "INVITE", CreateDate: 3000},
+	}
+
+	r := Build(netip.MustParseAddr("10.1.2.3"), table, agents, calls, time.Unix(0, 0))
+	if r.Version != "IPv4" || r.Scope != "private" {
+		t.Errorf("unexpected version/scope: %+v", r)
+	}
+	if r.Alias != "office" || r.Group != "branches" || r.Network != "10.1.0.0/16" {
+		t.Errorf("expected port-agnostic alias as primary answer, got %+v", r)
+	}
+	if len(r.AlsoIn) != 1 || r.AlsoIn[0] != "sbc-sip (10.1.2.3/32:5060)" {
+		t.Errorf("unexpected also_in %v", r.AlsoIn)
+	}
+	if len(r.Agents) != 2 || r.Agents[0].Match != "host" || r.Agents[1].Match != "network" {
+		t.Errorf("unexpected agents %+v", r.Agents)
+	}
+
+	tr := r.Traffic
+	if tr == nil || tr.Messages != 3 || tr.AsSource != 2 || tr.AsDestination != 1 || tr.Calls != 2 {
+		t.Fatalf("unexpected traffic %+v", tr)
+	}
+	if tr.LastSeen == nil || tr.LastSeen.UnixMilli() != 2000 || tr.LastMethod != "200" || tr.LastPeer != "192.0.2.1" {
+		t.Errorf("unexpected last seen: %+v", tr)
+	}
+}
+
+func TestBuild_Unknown(t *testing.T) {
+	r := Build(netip.MustParseAddr("2001:db8::1"), nil, nil, nil, time.Time{})
+	if r.Version != "IPv6" || r.Scope != "public" || r.Alias != "" || r.Traffic != nil {
+		t.Errorf("unexpected result %+v", r)
+	}
+}
+
+func TestNewSearchParams(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	params, err := NewSearchParams(netip.MustParseAddr("10.0.0.1"), since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Timestamp["from"] != since.UnixMilli() {
+		t.Errorf("unexpected from %v", params.Timestamp["from"])
+	}
+	orlogic, _ := params.Param["orlogic"].(map[string]interface{})
+	if orlogic["source_ip"] != "10.0.0.1" || orlogic["destination_ip"] != "10.0.0.1" {
+		t.Errorf("unexpected orlogic %v", params.Param["orlogic"])
+	}
+}
@@ -0,0 +1,47 @@
+package mappingdir
+
+import (
+	"encoding/json"
+
+	"hepic-cli/internal/jsondiff"
+	"hepic-cli/internal/models"
+)
+
+// Diff compares two mappings field by field. JSON sub-documents are
+// compared by value, not text, and array elements that carry a unique "id"
+// (as fields_mapping entries do) are matched by id rather than position.
+// guid, version and create_date are not compared.
+func Diff(old, new models.MappingSchema) []jsondiff.Change {
+	var changes []jsondiff.Change
+	om, nm := metaOf(old), metaOf(new)
+	scalars := []struct {
+		path     string
+		old, new interface{}
+	}{
+		{"hepid", om.Hepid, nm.Hepid},
+		{"hep_alias", om.HEPAlias, nm.HEPAlias},
+		{"profile", om.Profile, nm.Profile},
+		{"partid", om.Partid, nm.Partid},
+		{"retention", om.Retention, nm.Retention},
+		{"partition_step", om.PartitionStep, nm.PartitionStep},
+		{"apply_ttl_all", om.ApplyTTLAll, nm.ApplyTTLAll},
+		{"table_name", om.TableName, nm.TableName},
+		{"create_table", old.CreateTable, new.CreateTable},
+	}
+	for _, s := range scalars {
+		changes = append(changes, jsondiff.Value(s.path, s.old, s.new)...)
+	}
+
+	for _, d := range documents {
+		changes = append(changes, jsondiff.Value(d.name, decode(*d.field(&old)), decode(*d.field(&new)))...)
+	}
+	return changes
+}
+
+func decode(raw json.RawMessage) interface{} {
+	var v interface{}
+	if len(raw) > 0 {
+		json.Unmarshal(raw, &v)
+	}
+	return v
+}
@@ -0,0 +1,227 @@
+// Package mappingdir stores a protocol mapping as a directory of readable
+// files, one per JSON sub-document, so it can be edited and reviewed
+// locally and pushed back:
+//
+//	mapping.yaml              scalar fields (guid, hepid, profile, retention, ...)
+//	create_table.sql          create_table, if set
+//	fields_mapping.json       one pretty-printed file per JSON-valued field
+//	correlation_mapping.json
+//	...
+package mappingdir
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hepic-cli/internal/models"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// MetaFile holds the scalar fields of a mapping.
+const MetaFile = "mapping.yaml"
+
+// TableFile holds the create_table statement.
+const TableFile = "create_table.sql"
+
+// Meta is the content of mapping.yaml.
+type Meta struct {
+	Guid          string `yaml:"guid"`
+	Hepid         uint16 `yaml:"hepid"`
+	HEPAlias      string `yaml:"hep_alias,omitempty"`
+	Profile       string `yaml:"profile"`
+	Partid        uint16 `yaml:"partid,omitempty"`
+	Retention     uint16 `yaml:"retention,omitempty"`
+	PartitionStep uint16 `yaml:"partition_step,omitempty"`
+	ApplyTTLAll   bool   `yaml:"apply_ttl_all"`
+	TableName     string `yaml:"table_name,omitempty"`
+	Version       uint64 `yaml:"version,omitempty"`
+}
+
+// document is a JSON-valued field of MappingSchema.
+type document struct {
+	name  string
+	field func(m *models.MappingSchema) *json.RawMessage
+}
+
+var documents = []document{
+	{"fields_mapping", func(m *models.MappingSchema) *json.RawMessage { return &m.FieldsMapping }},
+	{"correlation_mapping", func(m *models.MappingSchema) *json.RawMessage { return &m.CorrelationMapping }},
+	{"fields_settings", func(m *models.MappingSchema) *json.RawMessage { return &m.FieldsSettings }},
+	{"schema_mapping", func(m *models.MappingSchema) *json.RawMessage { return &m.SchemaMapping }},
+	{"schema_settings", func(m *models.MappingSchema) *json.RawMessage { return &m.SchemaSettings }},
+	{"user_mapping", func(m *models.MappingSchema) *json.RawMessage { return &m.UserMapping }},
+	{"create_index", func(m *models.MappingSchema) *json.RawMessage { return &m.CreateIndex }},
+}
+
+func metaOf(m models.MappingSchema) Meta {
+	return Meta{
+		Guid: m.Guid, Hepid: m.Hepid, HEPAlias: m.HEPAlias, Profile: m.Profile,
+		Partid: m.Partid, Retention: m.Retention, PartitionStep: m.PartitionStep,
+		ApplyTTLAll: m.ApplyTTLAll, TableName: m.TableName, Version: m.Version,
+	}
+}
+
+// Write stores m in dir, creating it if needed, and returns the files
+// written. Files for empty fields are removed so the directory mirrors m.
+func Write(dir string, m models.MappingSchema) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	meta, err := yaml.Marshal(metaOf(m))
+	if err != nil {
+		return nil, err
+	}
+	files := []string{filepath.Join(dir, MetaFile)}
+	if err := os.WriteFile(files[0], meta, 0644); err != nil {
+		return nil, err
+	}
+
+	write := func(name string, data []byte) error {
+		path := filepath.Join(dir, name)
+		if len(data) == 0 {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			return nil
+		}
+		files = append(files, path)
+		return os.WriteFile(path, data, 0644)
+	}
+
+	table := []byte(nil)
+	if m.CreateTable != "" {
+		table = []byte(strings.TrimRight(m.CreateTable, "\n") + "\n")
+	}
+	if err := write(TableFile, table); err != nil {
+		return nil, err
+	}
+	for _, d := range documents {
+		raw := *d.field(&m)
+		var pretty []byte
+		if len(raw) > 0 && !bytes.Equal(raw, []byte("null")) {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, raw, "", "  "); err != nil {
+				return nil, fmt.Errorf("%s: %w", d.name, err)
+			}
+			buf.WriteByte('\n')
+			pretty = buf.Bytes()
+		}
+		if err := write(d.name+".json", pretty); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// Read loads a mapping from dir. JSON syntax errors are reported with the
+// file, line and column.
+func Read(dir string) (models.MappingSchema, error) {
+	var m models.MappingSchema
+
+	data, err := os.ReadFile(filepath.Join(dir, MetaFile))
+	if err != nil {
+		return m, fmt.Errorf("cannot read mapping directory: %w", err)
+	}
+	var meta Meta
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&meta); err != nil {
+		return m, fmt.Errorf("%s: %w", MetaFile, err)
+	}
+	m.Guid, m.Hepid, m.HEPAlias, m.Profile = meta.Guid, meta.Hepid, meta.HEPAlias, meta.Profile
+	m.Partid, m.Retention, m.PartitionStep = meta.Partid, meta.Retention, meta.PartitionStep
+	m.ApplyTTLAll, m.TableName, m.Version = meta.ApplyTTLAll, meta.TableName, meta.Version
+
+	if table, err := os.ReadFile(filepath.Join(dir, TableFile)); err == nil {
+		m.CreateTable = strings.TrimRight(string(table), "\n")
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return m, err
+	}
+
+	for _, d := range documents {
+		name := d.name + ".json"
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return m, err
+		}
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			var syntax *json.SyntaxError
+			if errors.As(err, &syntax) {
+				line, col := position(raw, syntax.Offset)
+				return m, fmt.Errorf("%s:%d:%d: %s", name, line, col, syntax)
+			}
+			return m, fmt.Errorf("%s: %w", name, err)
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, raw); err != nil {
+			return m, fmt.Errorf("%s: %w", name, err)
+		}
+		*d.field(&m) = json.RawMessage(buf.Bytes())
+	}
+	return m, nil
+}
+
+// Files returns the files of a mapping directory that exist, mapping.yaml
+// first.
+func Files(dir string) []string {
+	files := []string{filepath.Join(dir, MetaFile)}
+	names := []string{TableFile}
+	for _, d := range documents {
+		names = append(names, d.name+".json")
+	}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// Body returns m as an update request body. apply_ttl_all, create_table
+// and the JSON documents are always sent, empty ones as false, "" and null,
+// so that deleting a file or switching a flag off clears the field on the
+// server rather than keeping its old value.
+func Body(m models.MappingSchema) (map[string]interface{}, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	body["apply_ttl_all"] = m.ApplyTTLAll
+	body["create_table"] = m.CreateTable
+	for _, d := range documents {
+		if _, ok := body[d.name]; !ok {
+			body[d.name] = nil
+		}
+	}
+	return body, nil
+}
+
+// position converts a byte offset into a 1-based line and column.
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col - 1
+}
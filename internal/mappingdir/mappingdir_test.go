@@ -0,0 +1,120 @@
+package mappingdir
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/jsondiff"
+	"hepic-cli/internal/models"
+)
+
+func sample() models.MappingSchema {
+	return models.MappingSchema{
+		Guid:               "m-1",
+		Hepid:              1,
+		HEPAlias:           "SIP",
+		Profile:            "call",
+		Retention:          10,
+		PartitionStep:      10,
+		CreateTable:        "CREATE TABLE hep_proto_1_call (id bigint);",
+		FieldsMapping:      json.RawMessage(`[{"id":"data_header.callid","name":"Call-ID","type":"string"},{"id":"data_header.from_user","name":"From","type":"string"}]`),
+		CorrelationMapping: json.RawMessage(`[{"source_field":"data_header.callid","lookup_id":100}]`),
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+	// A stale file from an earlier pull must not survive.
+	if err := os.WriteFile(filepath.Join(dir, "user_mapping.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Write(dir, sample())
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if len(files) != 4 {
+		t.Errorf("expected mapping.yaml, create_table.sql and two JSON files, got %v", files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "user_mapping.json")); !os.IsNotExist(err) {
+		t.Error("stale user_mapping.json was not removed")
+	}
+	pretty, _ := os.ReadFile(filepath.Join(dir, "fields_mapping.json"))
+	if !strings.Contains(string(pretty), "\n    \"id\": \"data_header.callid\"") {
+		t.Errorf("fields_mapping.json is not pretty-printed:\n%s", pretty)
+	}
+
+	got, err := Read(dir)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if changes := Diff(sample(), got); len(changes) != 0 {
+		t.Errorf("round trip changed the mapping: %+v", changes)
+	}
+	if got.Guid != "m-1" || got.CreateTable != sample().CreateTable {
+		t.Errorf("unexpected mapping %+v", got)
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Write(dir, sample()); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "fields_mapping.json"), []byte("[\n  {\"id\": \"x\",}\n]\n"), 0644)
+	_, err := Read(dir)
+	if err == nil || !strings.HasPrefix(err.Error(), "fields_mapping.json:2:") {
+		t.Errorf("expected positioned syntax error, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dir, MetaFile), []byte("hepid: 1\nprofle: call\n"), 0644)
+	if _, err := Read(dir); err == nil {
+		t.Error("expected error for unknown mapping.yaml field")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := sample()
+	new := sample()
+	new.Retention = 14
+	// Reordered, one renamed, one added.
+	new.FieldsMapping = json.RawMessage(`[{"id":"data_header.from_user","name":"From","type":"string"},{"id":"data_header.callid","name":"Call ID","type":"string"},{"id":"data_header.to_user","name":"To"}]`)
+	new.CorrelationMapping = nil
+
+	changes := Diff(old, new)
+	want := []jsondiff.Change{
+		{Path: "retention", Action: jsondiff.Changed, Old: "10", New: "14"},
+		{Path: "fields_mapping[id=data_header.callid].name", Action: jsondiff.Changed, Old: `"Call-ID"`, New: `"Call ID"`},
+		{Path: "fields_mapping[id=data_header.to_user]", Action: jsondiff.Added, New: `{"id":"data_header.to_user","name":"To"}`},
+		{Path: "correlation_mapping", Action: jsondiff.Removed, Old: `[{"lookup_id":100,"source_field":"data_header.callid"}]`},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: expected %+v, got %+v", i, want[i], changes[i])
+		}
+	}
+}
+
+func TestBody(t *testing.T) {
+	body, err := Body(models.MappingSchema{Guid: "m-1", Hepid: 1, Profile: "call"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := body["apply_ttl_all"]; !ok || v != false {
+		t.Errorf("apply_ttl_all must always be sent, got %v", body)
+	}
+	if v, ok := body["create_table"]; !ok || v != "" {
+		t.Errorf("an empty create_table must be sent to clear it, got %v", body)
+	}
+	for _, name := range []string{"fields_mapping", "user_mapping", "create_index"} {
+		if v, ok := body[name]; !ok || v != nil {
+			t.Errorf("an empty %s must be sent as null to clear it, got %v", name, body)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package mocksrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Row is one captured message, as returned by /search/call/message: sid
+// (or callid), create_date in milliseconds or micro_ts in microseconds,
+// srcIp, srcPort, dstIp, dstPort, method, from_user, ruri_user, raw, ...
+type Row = map[string]interface{}
+
+// payloadKeys hold the SIP payload, which /search/call/data leaves out.
+var payloadKeys = []string{"raw", "msg"}
+
+// LoadCalls reads every *.json file in dir, each holding a list of rows
+// or an object with a "data" list, and returns the rows in file order.
+func LoadCalls(dir string) ([]Row, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("fixtures: %w", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	var rows []Row
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("fixtures: %w", err)
+		}
+		var list []Row
+		if err := json.Unmarshal(data, &list); err != nil {
+			var obj struct {
+				Data []Row `json:"data"`
+			}
+			if err := json.Unmarshal(data, &obj); err != nil || obj.Data == nil {
+				return nil, fmt.Errorf("fixtures: %s: expected a list of messages or an object with a data list", filepath.Base(f))
+			}
+			list = obj.Data
+		}
+		rows = append(rows, list...)
+	}
+	return rows, nil
+}
+
+// serveCalls answers the call searches from the fixtures. Without
+// fixtures they return samples like every other path.
+func (s *Server) serveCalls(w http.ResponseWriter, template string, body interface{}) bool {
+	if len(s.calls) == 0 {
+		return false
+	}
+	var dataKey string
+	strip := false
+	switch template {
+	case "/search/call/data":
+		dataKey, strip = "data", true
+	case "/search/call/message":
+		dataKey = "data"
+	case "/call/transaction":
+		dataKey = "Data"
+	default:
+		return false
+	}
+
+	q := parseQuery(body)
+	rows := []Row{}
+	keys := make(map[string]bool)
+	for _, row := range s.calls {
+		if !q.match(row) {
+			continue
+		}
+		if strip {
+			out := make(Row, len(row))
+			for k, v := range row {
+				out[k] = v
+			}
+			for _, k := range payloadKeys {
+				delete(out, k)
+			}
+			row = out
+		}
+		for k := range row {
+			keys[k] = true
+		}
+		rows = append(rows, row)
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	writeJSON(w, http.StatusOK, map[string]interface{}{dataKey: rows, "keys": names, "total": len(rows)})
+	return true
+}
+
+// query is the part of a search request the fixtures are filtered by.
+type query struct {
+	from, to float64
+	search   map[string]interface{}
+	orlogic  map[string]interface{}
+}
+
+func parseQuery(body interface{}) query {
+	var q query
+	m, _ := body.(map[string]interface{})
+	if ts, ok := m["timestamp"].(map[string]interface{}); ok {
+		q.from, _ = ts["from"].(float64)
+		q.to, _ = ts["to"].(float64)
+	}
+	if param, ok := m["param"].(map[string]interface{}); ok {
+		q.search, _ = param["search"].(map[string]interface{})
+		q.orlogic, _ = param["orlogic"].(map[string]interface{})
+	}
+	return q
+}
+
+// match reports whether row is in the time range, equals every search
+// field and equals at least one orlogic field. Call-IDs are matched
+// against sid and callid, source_ip and destination_ip against srcIp and
+// dstIp; a list of values matches any of them.
+func (q query) match(row Row) bool {
+	if ms := rowTime(row); ms > 0 && ((q.from > 0 && ms < q.from) || (q.to > 0 && ms > q.to)) {
+		return false
+	}
+	for k, want := range q.search {
+		if !fieldMatches(row, k, want) {
+			return false
+		}
+	}
+	if len(q.orlogic) == 0 {
+		return true
+	}
+	for k, want := range q.orlogic {
+		if fieldMatches(row, k, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldMatches(row Row, key string, want interface{}) bool {
+	fields := []string{key}
+	switch key {
+	case "callid", "sid":
+		fields = []string{"sid", "callid"}
+	case "source_ip":
+		fields = []string{"source_ip", "srcIp"}
+	case "destination_ip":
+		fields = []string{"destination_ip", "dstIp"}
+	}
+	wants, ok := want.([]interface{})
+	if !ok {
+		wants = []interface{}{want}
+	}
+	for _, f := range fields {
+		v, ok := row[f]
+		if !ok {
+			continue
+		}
+		for _, w := range wants {
+			if fmt.Sprint(v) == fmt.Sprint(w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rowTime returns the capture time in milliseconds, or 0.
+func rowTime(row Row) float64 {
+	if us, ok := row["micro_ts"].(float64); ok && us > 0 {
+		return us / 1000
+	}
+	if ms, ok := row["create_date"].(float64); ok {
+		return ms
+	}
+	return 0
+}
@@ -0,0 +1,148 @@
+package mocksrv
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// collections are the resources with in-memory state. Items are listed at
+// Path and updated and deleted at Path/{id}.
+var collections = []collection{
+	{Path: "/ipalias", ID: "uuid", Clear: "/ipalias/all"},
+	{Path: "/users", ID: "guid", Secrets: []string{"password"}},
+	{Path: "/script", ID: "uuid"},
+	{Path: "/hepsub/protocol", ID: "guid"},
+	{Path: "/mapping/protocol", ID: "guid"},
+}
+
+type collection struct {
+	Path string
+	// ID is the field holding the item's identifier; it is assigned on
+	// create if the body has none.
+	ID string
+	// Clear is a path deleting every item, if the API has one.
+	Clear string
+	// Secrets are fields accepted on create and update but never returned.
+	Secrets []string
+
+	items []map[string]interface{}
+}
+
+// serveCollection handles requests for the stateful resources. It reports
+// whether the request was handled.
+func (s *Server) serveCollection(w http.ResponseWriter, method, template, path string, body interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.collections {
+		switch {
+		case template == c.Path && method == http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": c.list(), "count": len(c.items)})
+		case template == c.Path && method == http.MethodPost:
+			item, ok := body.(map[string]interface{})
+			if !ok {
+				writeError(w, http.StatusBadRequest, "expected a JSON object")
+				return true
+			}
+			id, err := c.create(item)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return true
+			}
+			writeJSON(w, http.StatusCreated, map[string]interface{}{"data": id, "message": "successfully created"})
+		case template == c.Clear && method == http.MethodDelete:
+			c.items = nil
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": nil, "message": "successfully deleted"})
+		case c.isItem(template):
+			id, err := url.PathUnescape(path[strings.LastIndex(path, "/")+1:])
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return true
+			}
+			i := c.find(id)
+			if i < 0 {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", c.ID, id))
+				return true
+			}
+			switch method {
+			case http.MethodGet:
+				writeJSON(w, http.StatusOK, map[string]interface{}{"data": c.public(c.items[i]), "count": 1})
+			case http.MethodPut:
+				item, ok := body.(map[string]interface{})
+				if !ok {
+					writeError(w, http.StatusBadRequest, "expected a JSON object")
+					return true
+				}
+				for k, v := range item {
+					c.items[i][k] = v
+				}
+				c.items[i][c.ID] = id
+				writeJSON(w, http.StatusOK, map[string]interface{}{"data": id, "message": "successfully updated"})
+			case http.MethodDelete:
+				c.items = append(c.items[:i], c.items[i+1:]...)
+				writeJSON(w, http.StatusOK, map[string]interface{}{"data": id, "message": "successfully deleted"})
+			default:
+				return false
+			}
+		default:
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// isItem reports whether template is Path/{id}.
+func (c *collection) isItem(template string) bool {
+	rest := strings.TrimPrefix(template, c.Path+"/")
+	return rest != template && !strings.Contains(rest, "/") && isParam(rest)
+}
+
+func (c *collection) create(item map[string]interface{}) (string, error) {
+	stored := make(map[string]interface{}, len(item)+1)
+	for k, v := range item {
+		stored[k] = v
+	}
+	id, _ := stored[c.ID].(string)
+	if id == "" {
+		id = newID()
+		stored[c.ID] = id
+	} else if c.find(id) >= 0 {
+		return "", fmt.Errorf("%s %s already exists", c.ID, id)
+	}
+	c.items = append(c.items, stored)
+	return id, nil
+}
+
+func (c *collection) find(id string) int {
+	for i, item := range c.items {
+		if fmt.Sprint(item[c.ID]) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *collection) list() []map[string]interface{} {
+	list := make([]map[string]interface{}, len(c.items))
+	for i, item := range c.items {
+		list[i] = c.public(item)
+	}
+	return list
+}
+
+// public returns item without its secrets.
+func (c *collection) public(item map[string]interface{}) map[string]interface{} {
+	if len(c.Secrets) == 0 {
+		return item
+	}
+	out := make(map[string]interface{}, len(item))
+	for k, v := range item {
+		out[k] = v
+	}
+	for _, k := range c.Secrets {
+		delete(out, k)
+	}
+	return out
+}
@@ -0,0 +1,199 @@
+// Package mocksrv is a mock HEPIC API generated from swagger.json. Every
+// documented path answers with a sample of its response schema; the
+// configuration resources (IP aliases, users, scripts, hepsub, mappings)
+// keep in-memory state, and call searches answer from fixture files.
+//
+// In tests:
+//
+//	srv, _ := mocksrv.New(spec, mocksrv.Options{Token: "t"})
+//	ts := httptest.NewServer(srv)
+//	client := api.NewClientWith(ts.URL, "t")
+package mocksrv
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Token is the Auth-Token requests must carry. If empty, any token is
+	// accepted, but one is still required.
+	Token string
+	// Fixtures is a directory of call fixtures; see LoadCalls.
+	Fixtures string
+	// Log receives one line per request; nil disables logging.
+	Log io.Writer
+}
+
+// Server is an http.Handler serving the HEPIC API. Paths may be given with
+// or without the spec's base path (/api/v3).
+type Server struct {
+	spec *Spec
+	opts Options
+
+	mu          sync.Mutex
+	collections map[string]*collection
+	calls       []Row
+}
+
+// New creates a Server from a swagger 2.0 document.
+func New(spec []byte, opts Options) (*Server, error) {
+	s, err := ParseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	srv := &Server{spec: s, opts: opts, collections: make(map[string]*collection)}
+	for _, c := range collections {
+		srv.collections[c.Path] = &collection{Path: c.Path, ID: c.ID, Clear: c.Clear, Secrets: c.Secrets}
+	}
+	if opts.Fixtures != "" {
+		calls, err := LoadCalls(opts.Fixtures)
+		if err != nil {
+			return nil, err
+		}
+		srv.calls = calls
+	}
+	return srv, nil
+}
+
+// Seed adds items to the collection at path, e.g. "/ipalias", assigning
+// IDs to items without one.
+func (s *Server) Seed(path string, items ...map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.collections[path]
+	if !ok {
+		return fmt.Errorf("no collection at %s", path)
+	}
+	for _, item := range items {
+		if _, err := c.create(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	start := time.Now()
+	s.serve(rec, r)
+	if s.opts.Log != nil {
+		fmt.Fprintf(s.opts.Log, "%s %s %s → %d (%s)\n", start.Format("15:04:05"), r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Microsecond))
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	// Match on the escaped path, so that an ID holding a "/" stays one
+	// segment.
+	path := r.URL.EscapedPath()
+	if s.spec.BasePath != "" && strings.HasPrefix(path, s.spec.BasePath+"/") {
+		path = strings.TrimPrefix(path, s.spec.BasePath)
+	}
+	template, op, found := s.spec.Find(r.Method, path)
+	if !found {
+		writeError(w, http.StatusNotFound, "no such path in the API")
+		return
+	}
+	// The stateful resources need a token too, including the methods
+	// the spec leaves out (such as PUT /ipalias/{uuid}).
+	if (op == nil || op.Security != nil) && !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "missing or invalid Auth-Token")
+		return
+	}
+
+	var body interface{}
+	if r.Body != nil && (r.Method == http.MethodPost || r.Method == http.MethodPut) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(data) > 0 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			if err := json.Unmarshal(data, &body); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+				return
+			}
+		}
+	}
+
+	if s.serveCollection(w, r.Method, template, path, body) || s.serveCalls(w, template, body) {
+		return
+	}
+	if op == nil {
+		writeError(w, http.StatusMethodNotAllowed, r.Method+" is not allowed on this path")
+		return
+	}
+
+	code, schema := op.Success()
+	if op.Binary() {
+		w.Header().Set("Content-Type", op.Produces[0])
+		w.WriteHeader(code)
+		return
+	}
+	sample := s.spec.Sample(schema)
+	if template == "/auth" && r.Method == http.MethodPost {
+		// A login returns the token the server accepts.
+		if m, ok := sample.(map[string]interface{}); ok {
+			m["token"] = s.token()
+		}
+	}
+	writeJSON(w, code, sample)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token := r.Header.Get("Auth-Token")
+	if token == "" {
+		token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	return token != "" && (s.opts.Token == "" || token == s.opts.Token)
+}
+
+func (s *Server) token() string {
+	if s.opts.Token != "" {
+		return s.opts.Token
+	}
+	return "mock-token"
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the FailureResponse shape the API uses for errors.
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]interface{}{
+		"statuscode": code,
+		"error":      http.StatusText(code),
+		"message":    message,
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// newID returns a random UUID.
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b)
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
@@ -0,0 +1,228 @@
+package mocksrv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/call"
+	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/resource"
+	"hepic-cli/internal/user"
+)
+
+func newTestServer(t *testing.T, opts Options) (*Server, *api.Client) {
+	t.Helper()
+	spec, err := os.ReadFile("../../swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Token == "" {
+		opts.Token = "test-token"
+	}
+	srv, err := New(spec, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return srv, api.NewClientWith(ts.URL+"/api/v3", opts.Token)
+}
+
+func TestSpec(t *testing.T) {
+	spec, err := ParseSpec([]byte(`{
+		"basePath": "/api/v3",
+		"paths": {
+			"/ipalias/{uuid}": {"delete": {"responses": {}}},
+			"/ipalias/all": {"delete": {"responses": {}}},
+			"/log/:type/:action": {"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/Node"}}}}}
+		},
+		"definitions": {
+			"Node": {"type": "object", "properties": {
+				"name": {"type": "string", "example": "root"},
+				"at": {"type": "string", "format": "date-time"},
+				"kind": {"type": "string", "enum": ["a", "b"]},
+				"children": {"type": "array", "items": {"$ref": "#/definitions/Node"}}
+			}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{
+		"/ipalias/all": "/ipalias/all",
+		"/ipalias/x":   "/ipalias/{uuid}",
+	} {
+		if got, _, _ := spec.Find("DELETE", path); got != want {
+			t.Errorf("%s: matched %q, want %q", path, got, want)
+		}
+	}
+	if _, op, ok := spec.Find("POST", "/ipalias/x"); !ok || op != nil {
+		t.Error("expected a path match without an operation")
+	}
+	if _, _, ok := spec.Find("GET", "/nope"); ok {
+		t.Error("unexpected match")
+	}
+
+	_, op, _ := spec.Find("GET", "/log/sip/tail")
+	code, schema := op.Success()
+	got, _ := json.Marshal(spec.Sample(schema))
+	want := `{"at":"2024-01-01T00:00:00Z","children":[null],"kind":"a","name":"root"}`
+	if code != 200 || string(got) != want {
+		t.Errorf("got %d %s, want 200 %s", code, got, want)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	_, client := newTestServer(t, Options{})
+	ctx := context.Background()
+
+	client.Token = "wrong"
+	var apiErr *api.APIError
+	if _, err := config_resources.ListAliases(ctx, client); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %v", err)
+	}
+	// Login needs no token and returns the one the server accepts.
+	var login map[string]interface{}
+	if err := client.Post(ctx, "/auth", map[string]string{"username": "admin", "password": "x"}, &login); err != nil {
+		t.Fatal(err)
+	}
+	if login["token"] != "test-token" {
+		t.Errorf("unexpected login response %v", login)
+	}
+	client.Token = "test-token"
+	if _, err := config_resources.ListAliases(ctx, client); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSamples(t *testing.T) {
+	_, client := newTestServer(t, Options{})
+	ctx := context.Background()
+
+	var data struct {
+		Keys  []string `json:"keys"`
+		Total int      `json:"total"`
+	}
+	if err := client.Post(ctx, "/search/call/data", map[string]interface{}{}, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Total != 45 || len(data.Keys) == 0 {
+		t.Errorf("expected the schema's examples, got %+v", data)
+	}
+	var apiErr *api.APIError
+	if err := client.Get(ctx, "/no/such/path", nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %v", err)
+	}
+	if err := client.Delete(ctx, "/search/call/data", nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %v", err)
+	}
+}
+
+func TestCollections(t *testing.T) {
+	srv, client := newTestServer(t, Options{})
+	ctx := context.Background()
+	list := func(r string) []resource.Item {
+		t.Helper()
+		res, _ := resource.ByKind(r)
+		items, err := res.List(ctx, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return items
+	}
+
+	if err := srv.Seed("/ipalias", map[string]interface{}{"uuid": "a-1", "alias": "proxy", "ip": "10.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config_resources.CreateAlias(ctx, client, map[string]interface{}{"alias": "media", "ip": "10.0.0.2"}); err != nil {
+		t.Fatal(err)
+	}
+	items := list("IPAlias")
+	if len(items) != 2 || items[1]["uuid"] == "" {
+		t.Fatalf("unexpected aliases %v", items)
+	}
+	if _, err := config_resources.UpdateAlias(ctx, client, "a-1", map[string]interface{}{"alias": "edge"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config_resources.DeleteAlias(ctx, client, items[1]["uuid"].(string)); err != nil {
+		t.Fatal(err)
+	}
+	items = list("IPAlias")
+	if len(items) != 1 || items[0]["alias"] != "edge" || items[0]["ip"] != "10.0.0.1" {
+		t.Errorf("unexpected aliases after update and delete %v", items)
+	}
+	var apiErr *api.APIError
+	if _, err := config_resources.DeleteAlias(ctx, client, "nope"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %v", err)
+	}
+	if _, err := config_resources.DeleteAllAliases(ctx, client); err != nil || len(list("IPAlias")) != 0 {
+		t.Errorf("expected no aliases after delete all (%v)", err)
+	}
+
+	if _, err := user.Create(ctx, client, map[string]interface{}{"username": "bob", "password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	users := list("User")
+	if len(users) != 1 || users[0]["guid"] == "" || users[0]["password"] != nil {
+		t.Errorf("unexpected users %v", users)
+	}
+
+	if _, err := config_resources.CreateMapping(ctx, client, map[string]interface{}{"guid": "m-1", "hepid": 1, "profile": "call"}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := config_resources.GetMapping(ctx, client, "m-1")
+	if err != nil || m.Profile != "call" {
+		t.Errorf("unexpected mapping %+v (%v)", m, err)
+	}
+}
+
+func TestCalls(t *testing.T) {
+	dir := t.TempDir()
+	rows := []map[string]interface{}{
+		{"sid": "abc", "create_date": 1735725600000, "method": "INVITE", "srcIp": "10.0.0.1", "dstIp": "10.0.0.2",
+			"raw": "INVITE sip:bob@example.com SIP/2.0\r\nCall-ID: abc\r\nCSeq: 1 INVITE\r\n\r\n"},
+		{"sid": "abc", "create_date": 1735725601000, "method": "200", "srcIp": "10.0.0.2", "dstIp": "10.0.0.1",
+			"raw": "SIP/2.0 200 OK\r\nCall-ID: abc\r\nCSeq: 1 INVITE\r\n\r\n"},
+		{"sid": "other", "create_date": 1735725602000, "method": "OPTIONS"},
+	}
+	data, _ := json.Marshal(map[string]interface{}{"data": rows})
+	os.WriteFile(filepath.Join(dir, "calls.json"), data, 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644)
+
+	_, client := newTestServer(t, Options{Fixtures: dir})
+	ctx := context.Background()
+
+	params, _ := call.NewSearchParams("2025-01-01", "2025-01-02", "", "", "abc")
+	result, err := call.SearchData(ctx, client, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || len(result.Data) != 2 || result.Data[0].Method != "INVITE" {
+		t.Errorf("unexpected search result %+v", result)
+	}
+	msgs, err := call.FetchMessages(ctx, client, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || msgs[1].StatusCode != 200 {
+		t.Errorf("unexpected messages %v", msgs)
+	}
+
+	params, _ = call.NewSearchParams("2020-01-01", "2020-01-02", "", "", "")
+	if result, err := call.SearchData(ctx, client, params); err != nil || result.Total != 0 {
+		t.Errorf("expected no calls outside the time range, got %+v (%v)", result, err)
+	}
+
+	bad := t.TempDir()
+	os.WriteFile(filepath.Join(bad, "x.json"), []byte(`{"rows": []}`), 0644)
+	if _, err := LoadCalls(bad); err == nil {
+		t.Error("expected error for a fixture without data")
+	}
+}
@@ -0,0 +1,219 @@
+package mocksrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sampleTime is the value of every date-time string in sample responses,
+// so that responses are the same on every run.
+const sampleTime = "2024-01-01T00:00:00Z"
+
+// Schema is the subset of a swagger 2.0 schema the server needs to build
+// sample values.
+type Schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Example    json.RawMessage    `json:"example"`
+	Enum       []interface{}      `json:"enum"`
+	Properties map[string]*Schema `json:"properties"`
+	Items      *Schema            `json:"items"`
+}
+
+// Operation is one method of a path.
+type Operation struct {
+	// Security is nil for operations that need no token, such as login.
+	Security  []json.RawMessage   `json:"security"`
+	Produces  []string            `json:"produces"`
+	Responses map[string]Response `json:"responses"`
+}
+
+// Response is a documented response of an operation.
+type Response struct {
+	Description string  `json:"description"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Spec is a parsed swagger.json.
+type Spec struct {
+	BasePath    string                                `json:"basePath"`
+	Definitions map[string]*Schema                    `json:"definitions"`
+	Paths       map[string]map[string]json.RawMessage `json:"paths"`
+
+	routes []*route
+}
+
+// route is a path template with its operations by upper-case method.
+type route struct {
+	template string
+	segments []string
+	ops      map[string]*Operation
+}
+
+// ParseSpec parses a swagger 2.0 document.
+func ParseSpec(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid swagger spec: %w", err)
+	}
+	if len(spec.Paths) == 0 {
+		return nil, fmt.Errorf("invalid swagger spec: no paths")
+	}
+	for template, methods := range spec.Paths {
+		r := &route{template: template, segments: split(template), ops: make(map[string]*Operation)}
+		for method, raw := range methods {
+			if method == "parameters" {
+				continue
+			}
+			var op Operation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("invalid swagger spec: %s %s: %w", method, template, err)
+			}
+			r.ops[strings.ToUpper(method)] = &op
+		}
+		spec.routes = append(spec.routes, r)
+	}
+	// Literal segments win over parameters, so /ipalias/all is matched
+	// before /ipalias/{uuid}.
+	sort.Slice(spec.routes, func(i, j int) bool {
+		a, b := spec.routes[i].segments, spec.routes[j].segments
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if pa, pb := isParam(a[k]), isParam(b[k]); pa != pb {
+				return pb
+			}
+		}
+		return spec.routes[i].template < spec.routes[j].template
+	})
+	return &spec, nil
+}
+
+// Find returns the route template and operation for a request path
+// relative to the base path. ok is false if no template matches; op is nil
+// if templates match but none has the method, and template is then the
+// most specific match.
+func (s *Spec) Find(method, path string) (template string, op *Operation, ok bool) {
+	segments := split(path)
+	for _, r := range s.routes {
+		if !r.match(segments) {
+			continue
+		}
+		if !ok {
+			template, ok = r.template, true
+		}
+		if op := r.ops[method]; op != nil {
+			return r.template, op, true
+		}
+	}
+	return template, nil, ok
+}
+
+func (r *route) match(segments []string) bool {
+	if len(segments) != len(r.segments) {
+		return false
+	}
+	for i, s := range r.segments {
+		if !isParam(s) && s != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func split(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// isParam reports whether a template segment is a parameter: {uuid} or,
+// in a few paths of the spec, :type.
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") || strings.HasPrefix(segment, ":")
+}
+
+// Success returns the status code and schema of the operation's first 2xx
+// response.
+func (op *Operation) Success() (int, *Schema) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if n, err := strconv.Atoi(code); err == nil && n >= 200 && n < 300 {
+			return n, op.Responses[code].Schema
+		}
+	}
+	return 200, nil
+}
+
+// Binary reports whether the operation returns a file rather than JSON.
+func (op *Operation) Binary() bool {
+	for _, p := range op.Produces {
+		if p == "application/json" {
+			return false
+		}
+	}
+	return len(op.Produces) > 0
+}
+
+// Sample returns a value conforming to schema: the schema's example if it
+// has one, otherwise a value built from its type and properties. Arrays
+// have one element; recursive definitions end in null.
+func (s *Spec) Sample(schema *Schema) interface{} {
+	return s.sample(schema, make(map[string]bool))
+}
+
+func (s *Spec) sample(schema *Schema, seen map[string]bool) interface{} {
+	if schema == nil {
+		return map[string]interface{}{}
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/definitions/")
+		def, ok := s.Definitions[name]
+		if !ok || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return s.sample(def, seen)
+	}
+	if len(schema.Example) > 0 {
+		var v interface{}
+		if err := json.Unmarshal(schema.Example, &v); err == nil {
+			return v
+		}
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			return sampleTime
+		case "date":
+			return sampleTime[:10]
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		}
+		return "string"
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		return []interface{}{s.sample(schema.Items, seen)}
+	}
+	obj := make(map[string]interface{}, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		obj[name] = s.sample(prop, seen)
+	}
+	return obj
+}
@@ -3,7 +3,12 @@
 
 package models
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"math/big"
+	"net/netip"
+	"time"
+)
 
 // Ensure json import is used.
 var _ json.RawMessage
@@ -35,8 +40,8 @@ type AgentLocationUpdateSuccessResponse struct {
 // AgentsLocation represents the AgentsLocation schema.
 type AgentsLocation struct {
 	Active bool `json:"active,omitempty"`
-	CreateDate string `json:"create_date,omitempty"`
-	ExpireDate string `json:"expire_date,omitempty"`
+	CreateDate *Time `json:"create_date,omitempty"`
+	ExpireDate *Time `json:"expire_date,omitempty"`
 	GID uint32 `json:"gid,omitempty"`
 	Host string `json:"host,omitempty"`
 	Node string `json:"node,omitempty"`
@@ -57,16 +62,16 @@ type AgentsLocationList struct {
 // AliasMapStruct represents the AliasMapStruct schema.
 type AliasMapStruct struct {
 	Alias string `json:"alias"`
-	Firstipv4 string `json:"firstipv4"`
-	Firstipv6 string `json:"firstipv6"`
+	Firstipv4 netip.Addr `json:"firstipv4"`
+	Firstipv6 netip.Addr `json:"firstipv6"`
 	Group string `json:"group,omitempty"`
 	IP string `json:"ip"`
 	Ipobject json.RawMessage `json:"ipobject"`
 	Ipv6 bool `json:"ipv6,omitempty"`
 	Lastbinaryipv4 uint32 `json:"lastbinaryipv4"`
-	Lastbinaryipv6 int64 `json:"lastbinaryipv6"`
-	Lastipv4 string `json:"lastipv4"`
-	Lastipv6 string `json:"lastipv6"`
+	Lastbinaryipv6 *big.Int `json:"lastbinaryipv6"`
+	Lastipv4 netip.Addr `json:"lastipv4"`
+	Lastipv6 netip.Addr `json:"lastipv6"`
 	Mask uint16 `json:"mask"`
 	Port uint16 `json:"port"`
 	Servertype string `json:"servertype,omitempty"`
@@ -175,7 +180,7 @@ type CreateUserStruct struct {
 type DbstatLog struct {
 	Critical bool `json:"critical,omitempty"`
 	Error string `json:"error,omitempty"`
-	Time string `json:"time,omitempty"`
+	Time *Time `json:"time,omitempty"`
 }
 
 // Dbstats represents the DBStats schema.
@@ -188,7 +193,7 @@ type Dbstats struct {
 	MaxOpenConnections int64 `json:"MaxOpenConnections,omitempty"`
 	OpenConnections int64 `json:"OpenConnections,omitempty"`
 	WaitCount int64 `json:"WaitCount,omitempty"`
-	WaitDuration int64 `json:"WaitDuration,omitempty"`
+	WaitDuration time.Duration `json:"WaitDuration,omitempty"`
 }
 
 // DashboardElementList represents the DashboardElementList schema.
@@ -219,7 +224,7 @@ type DatabaseStatistic struct {
 	DBErrorCount int64 `json:"db_error_count,omitempty"`
 	DBErrorLog []DbstatLog `json:"db_error_log,omitempty"`
 	DBStats Dbstats `json:"db_stats,omitempty"`
-	LastCheck string `json:"last_check,omitempty"`
+	LastCheck *Time `json:"last_check,omitempty"`
 	LastError string `json:"last_error,omitempty"`
 	LatencyAvg int64 `json:"latency_avg,omitempty"`
 	LatencyMax int64 `json:"latency_max,omitempty"`
@@ -267,7 +272,7 @@ type GlobalSettingsDeleteSuccessfulResponse struct {
 // GlobalSettingsStruct represents the GlobalSettingsStruct schema.
 type GlobalSettingsStruct struct {
 	Category string `json:"category,omitempty"`
-	CreateDate string `json:"create_date,omitempty"`
+	CreateDate *Time `json:"create_date,omitempty"`
 	Data json.RawMessage `json:"data,omitempty"`
 	Guid string `json:"guid,omitempty"`
 	Param string `json:"param,omitempty"`
@@ -302,7 +307,7 @@ type HepsubDeleteSuccessResponse struct {
 
 // HepsubSchema represents the HepsubSchema schema.
 type HepsubSchema struct {
-	CreateDate string `json:"create_date,omitempty"`
+	CreateDate *Time `json:"create_date,omitempty"`
 	Guid string `json:"guid,omitempty"`
 	HEPAlias string `json:"hep_alias,omitempty"`
 	Hepid uint16 `json:"hepid,omitempty"`
@@ -355,18 +360,18 @@ type InterceptionAgentRequest struct {
 
 // InterceptionsStruct represents the InterceptionsStruct schema.
 type InterceptionsStruct struct {
-	CreateDate string `json:"create_date"`
+	CreateDate Time `json:"create_date"`
 	Delivery json.RawMessage `json:"delivery"`
 	Description string `json:"description,omitempty"`
 	GID uint32 `json:"gid,omitempty"`
 	Liid uint32 `json:"liid,omitempty"`
-	ModifyDate string `json:"modify_date,omitempty"`
+	ModifyDate *Time `json:"modify_date,omitempty"`
 	SearchCallee string `json:"search_callee,omitempty"`
 	SearchCaller string `json:"search_caller,omitempty"`
 	SearchIP string `json:"search_ip,omitempty"`
-	StartDate string `json:"start_date,omitempty"`
+	StartDate *Time `json:"start_date,omitempty"`
 	Status bool `json:"status,omitempty"`
-	StopDate string `json:"stop_date,omitempty"`
+	StopDate *Time `json:"stop_date,omitempty"`
 	UUID string `json:"uuid"`
 	Version uint64 `json:"version,omitempty"`
 }
@@ -404,7 +409,7 @@ type ListUsers struct {
 type MappingSchema struct {
 	ApplyTTLAll bool `json:"apply_ttl_all,omitempty"`
 	CorrelationMapping json.RawMessage `json:"correlation_mapping,omitempty"`
-	CreateDate string `json:"create_date,omitempty"`
+	CreateDate *Time `json:"create_date,omitempty"`
 	CreateIndex json.RawMessage `json:"create_index,omitempty"`
 	CreateTable string `json:"create_table,omitempty"`
 	FieldsMapping json.RawMessage `json:"fields_mapping,omitempty"`
@@ -451,7 +456,7 @@ type NodeList struct {
 // OldaliasStruct represents the OLDAliasStruct schema.
 type OldaliasStruct struct {
 	Arguments []map[string]interface{} `json:"Arguments,omitempty"`
-	CreateDate string `json:"CreateDate,omitempty"`
+	CreateDate *Time `json:"CreateDate,omitempty"`
 	DataMaps [][]map[string]interface{} `json:"DataMaps,omitempty"`
 	DB string `json:"Db,omitempty"`
 	ManualResync bool `json:"ManualResync,omitempty"`
@@ -486,7 +491,7 @@ type RecordingTransactionRTP struct {
 	Liid uint32 `json:"liid,omitempty"`
 	Node string `json:"node,omitempty"`
 	Proto uint8 `json:"proto,omitempty"`
-	RecordDatetime string `json:"record_datetime"`
+	RecordDatetime Time `json:"record_datetime"`
 	Sid uint32 `json:"sid,omitempty"`
 	SrcIP string `json:"src_ip,omitempty"`
 	SrcPort uint16 `json:"src_port,omitempty"`
@@ -510,7 +515,7 @@ type RecordingTransactionSIP struct {
 	Liid uint32 `json:"liid,omitempty"`
 	Node string `json:"node,omitempty"`
 	Proto uint8 `json:"proto,omitempty"`
-	RecordDatetime string `json:"record_datetime"`
+	RecordDatetime Time `json:"record_datetime"`
 	Sid uint32 `json:"sid,omitempty"`
 	SrcIP string `json:"src_ip,omitempty"`
 	SrcPort uint16 `json:"src_port,omitempty"`
@@ -545,7 +550,7 @@ type ScriptDataStruct struct {
 	Partid uint16 `json:"partid,omitempty"`
 	Profile string `json:"profile,omitempty"`
 	Status bool `json:"status"`
-	Type string `json:"type"`
+	Type ScriptType `json:"type"`
 	UUID string `json:"uuid,omitempty"`
 	Version uint64 `json:"version,omitempty"`
 }
@@ -674,12 +679,12 @@ type SuccessResponse struct {
 // TableAuthToken represents the TableAuthToken schema.
 type TableAuthToken struct {
 	Active bool `json:"active,omitempty"`
-	CreateDate string `json:"create_date,omitempty"`
+	CreateDate *Time `json:"create_date,omitempty"`
 	CreatorGuid string `json:"creator_guid,omitempty"`
-	ExpireDate string `json:"expire_date,omitempty"`
+	ExpireDate *Time `json:"expire_date,omitempty"`
 	Guid string `json:"guid,omitempty"`
 	IPAddress string `json:"ip_address,omitempty"`
-	LastusageDate string `json:"lastusage_date,omitempty"`
+	LastusageDate *Time `json:"lastusage_date,omitempty"`
 	LimitCalls uint32 `json:"limit_calls,omitempty"`
 	Name string `json:"name,omitempty"`
 	UsageCalls uint32 `json:"usage_calls,omitempty"`
@@ -902,7 +907,7 @@ type UserLegacyStruct struct {
 // UserLogin represents the UserLogin schema.
 type UserLogin struct {
 	Password string `json:"password"`
-	Type string `json:"type,omitempty"`
+	Type UserLoginType `json:"type,omitempty"`
 	Username string `json:"username"`
 }
 
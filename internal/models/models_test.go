@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeJSON(t *testing.T) {
+	for _, in := range []string{`"2024-03-01T10:00:00Z"`, `1709287200000`} {
+		var got Time
+		if err := json.Unmarshal([]byte(in), &got); err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if !got.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s decoded as %v", in, got)
+		}
+	}
+	for _, in := range []string{`""`, `null`} {
+		got := Time{time.Now()}
+		if err := json.Unmarshal([]byte(in), &got); err != nil || !got.IsZero() {
+			t.Errorf("%s decoded as %v (%v)", in, got, err)
+		}
+	}
+
+	// Unset optional dates are left out, unset required ones are null.
+	data, _ := json.Marshal(InterceptionsStruct{UUID: "i1"})
+	if s := string(data); !strings.Contains(s, `"create_date":null`) || strings.Contains(s, "start_date") {
+		t.Errorf("unexpected body %s", s)
+	}
+	start, err := ParseTime("2024-03-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = json.Marshal(InterceptionsStruct{StartDate: &start})
+	if !strings.Contains(string(data), `"start_date":"2024-03-01T00:00:00Z"`) {
+		t.Errorf("unexpected body %s", data)
+	}
+	if _, err := ParseTime("yesterday"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestAddrJSON(t *testing.T) {
+	var m AliasMapStruct
+	if err := json.Unmarshal([]byte(`{"firstipv4":"10.0.0.1","firstipv6":"2001:db8::1","lastbinaryipv6":340282366920938463463374607431768211455}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Firstipv4 != netip.MustParseAddr("10.0.0.1") || !m.Firstipv6.Is6() || m.Lastbinaryipv6.BitLen() != 128 {
+		t.Errorf("unexpected decode %+v", m)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := AliasSwaggerStruct{Alias: "sbc", IP: "10.0.0.1", Mask: 32}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		alias AliasSwaggerStruct
+		want  string
+	}{
+		{AliasSwaggerStruct{IP: "10.0.0.1"}, "alias is required"},
+		{AliasSwaggerStruct{Alias: "sbc", IP: "10.0.0"}, `invalid ip "10.0.0"`},
+		{AliasSwaggerStruct{Alias: "sbc", IP: "2001:db8::", Mask: 129}, "invalid mask 129"},
+	}
+	for _, tt := range tests {
+		if err := tt.alias.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%+v: got %v, want %q", tt.alias, err, tt.want)
+		}
+	}
+	// Updates only check what is set.
+	if err := (AliasSwaggerStruct{Mask: 16}).ValidatePartial(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := (ScriptDataStruct{Data: "x", Type: "python"}).Validate(); err == nil || !strings.Contains(err.Error(), "lua, javascript") {
+		t.Errorf("expected the valid types in %v", err)
+	}
+	if !ScriptTypeLua.Valid() || ScriptType("python").Valid() {
+		t.Error("unexpected ScriptType.Valid")
+	}
+	if err := (UserLogin{Username: "admin", Password: "x", Type: "radius"}).Validate(); err == nil || !strings.Contains(err.Error(), "internal, ldap") {
+		t.Errorf("expected the valid login types in %v", err)
+	}
+
+	// constraints.json drops the version new users do not have.
+	u := CreateUserStruct{Department: "ops", Email: "a@b", Firstname: "A", Lastname: "B", Partid: 10, Password: "x", Usergroup: "admin"}
+	if err := u.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (GlobalSettingsStruct{Category: "system"}).Validate(); err == nil || !strings.Contains(err.Error(), "param is required") {
+		t.Errorf("expected missing param error, got %v", err)
+	}
+}
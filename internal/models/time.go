@@ -0,0 +1,60 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Time is a date-time field. Besides RFC 3339 strings it accepts "", null
+// and Unix milliseconds, and it marshals the zero Time as null so an unset
+// date is not sent as 0001-01-01.
+type Time struct {
+	time.Time
+}
+
+// NewTime returns t as a Time.
+func NewTime(t time.Time) *Time {
+	return &Time{t}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return t.Time.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case string(data) == "null" || string(data) == `""`:
+		*t = Time{}
+		return nil
+	case len(data) > 0 && data[0] != '"':
+		ms, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid time %s", data)
+		}
+		*t = Time{time.UnixMilli(ms)}
+		return nil
+	}
+	return t.Time.UnmarshalJSON(data)
+}
+
+// ParseTime parses a time given on the command line: RFC 3339, a date and
+// time without a zone, a date, or Unix milliseconds.
+func ParseTime(s string) (Time, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return Time{time.UnixMilli(ms)}, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Time{t}, nil
+		}
+	}
+	return Time{}, fmt.Errorf("unsupported time format %q (use RFC3339, YYYY-MM-DD, or unix ms)", s)
+}
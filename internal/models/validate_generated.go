@@ -0,0 +1,653 @@
+// Code generated from swagger.json — DO NOT EDIT.
+// Regenerate with: go run ./tools/generate
+
+package models
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+var (
+	dashboardElementsIDPattern = regexp.MustCompile("^[A-Za-z0-9_.-]+$")
+)
+
+// ScriptType is the type of ScriptDataStruct.type.
+type ScriptType string
+
+const (
+	ScriptTypeLua        ScriptType = "lua"
+	ScriptTypeJavascript ScriptType = "javascript"
+)
+
+// ScriptTypeValues lists the valid ScriptType values.
+var ScriptTypeValues = []ScriptType{ScriptTypeLua, ScriptTypeJavascript}
+
+// Valid reports whether v is one of the ScriptType values.
+func (v ScriptType) Valid() bool {
+	for _, x := range ScriptTypeValues {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
+
+func (v ScriptType) choices() string {
+	names := make([]string, len(ScriptTypeValues))
+	for i, x := range ScriptTypeValues {
+		names[i] = string(x)
+	}
+	return strings.Join(names, ", ")
+}
+
+// UserLoginType is the type of UserLogin.type.
+type UserLoginType string
+
+const (
+	UserLoginTypeInternal UserLoginType = "internal"
+	UserLoginTypeLDAP     UserLoginType = "ldap"
+)
+
+// UserLoginTypeValues lists the valid UserLoginType values.
+var UserLoginTypeValues = []UserLoginType{UserLoginTypeInternal, UserLoginTypeLDAP}
+
+// Valid reports whether v is one of the UserLoginType values.
+func (v UserLoginType) Valid() bool {
+	for _, x := range UserLoginTypeValues {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
+
+func (v UserLoginType) choices() string {
+	names := make([]string, len(UserLoginTypeValues))
+	for i, x := range UserLoginTypeValues {
+		names[i] = string(x)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Validate checks m against the AliasMapStruct constraints.
+func (m AliasMapStruct) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m AliasMapStruct) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m AliasMapStruct) validate(partial bool) error {
+	if !partial && m.Alias == "" {
+		return fmt.Errorf("alias is required")
+	}
+	if !partial && !m.Firstipv4.IsValid() {
+		return fmt.Errorf("firstipv4 is required")
+	}
+	if !partial && !m.Firstipv6.IsValid() {
+		return fmt.Errorf("firstipv6 is required")
+	}
+	if !partial && m.IP == "" {
+		return fmt.Errorf("ip is required")
+	}
+	if !partial && len(m.Ipobject) == 0 {
+		return fmt.Errorf("ipobject is required")
+	}
+	if !partial && m.Lastbinaryipv4 == 0 {
+		return fmt.Errorf("lastbinaryipv4 is required")
+	}
+	if !partial && m.Lastbinaryipv6 == nil {
+		return fmt.Errorf("lastbinaryipv6 is required")
+	}
+	if !partial && !m.Lastipv4.IsValid() {
+		return fmt.Errorf("lastipv4 is required")
+	}
+	if !partial && !m.Lastipv6.IsValid() {
+		return fmt.Errorf("lastipv6 is required")
+	}
+	if !partial && m.Mask == 0 {
+		return fmt.Errorf("mask is required")
+	}
+	if !partial && m.Port == 0 {
+		return fmt.Errorf("port is required")
+	}
+	return nil
+}
+
+// Validate checks m against the AliasStruct constraints.
+func (m AliasStruct) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m AliasStruct) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m AliasStruct) validate(partial bool) error {
+	if !partial && m.Alias == "" {
+		return fmt.Errorf("alias is required")
+	}
+	if !partial && m.Group == "" {
+		return fmt.Errorf("group is required")
+	}
+	if !partial && m.IP == "" {
+		return fmt.Errorf("ip is required")
+	}
+	if !partial && m.Mask == 0 {
+		return fmt.Errorf("mask is required")
+	}
+	if !partial && m.Port == 0 {
+		return fmt.Errorf("port is required")
+	}
+	if !partial && m.Servertype == "" {
+		return fmt.Errorf("servertype is required")
+	}
+	if !partial && m.Type == 0 {
+		return fmt.Errorf("type is required")
+	}
+	if !partial && m.UUID == "" {
+		return fmt.Errorf("uuid is required")
+	}
+	return nil
+}
+
+// Validate checks m against the AliasSwaggerStruct constraints.
+func (m AliasSwaggerStruct) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m AliasSwaggerStruct) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m AliasSwaggerStruct) validate(partial bool) error {
+	if !partial && m.Alias == "" {
+		return fmt.Errorf("alias is required")
+	}
+	if !partial && m.IP == "" {
+		return fmt.Errorf("ip is required")
+	}
+	if m.IP != "" {
+		if _, err := netip.ParseAddr(m.IP); err != nil {
+			return fmt.Errorf("invalid ip %q: not an IP address", m.IP)
+		}
+	}
+	if m.Mask > 128 {
+		return fmt.Errorf("invalid mask %v: must be at most 128", m.Mask)
+	}
+	return nil
+}
+
+// Validate checks m against the ClickhouseObject constraints.
+func (m ClickhouseObject) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m ClickhouseObject) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m ClickhouseObject) validate(partial bool) error {
+	if !partial && m.Query == "" {
+		return fmt.Errorf("query is required")
+	}
+	return nil
+}
+
+// Validate checks m against the CreateUserStruct constraints.
+func (m CreateUserStruct) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m CreateUserStruct) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m CreateUserStruct) validate(partial bool) error {
+	if !partial && m.Department == "" {
+		return fmt.Errorf("department is required")
+	}
+	if !partial && m.Email == "" {
+		return fmt.Errorf("email is required")
+	}
+	if !partial && m.Firstname == "" {
+		return fmt.Errorf("firstname is required")
+	}
+	if !partial && m.Lastname == "" {
+		return fmt.Errorf("lastname is required")
+	}
+	if !partial && m.Partid == 0 {
+		return fmt.Errorf("partid is required")
+	}
+	if !partial && m.Password == "" {
+		return fmt.Errorf("password is required")
+	}
+	if !partial && m.Usergroup == "" {
+		return fmt.Errorf("usergroup is required")
+	}
+	return nil
+}
+
+// Validate checks m against the DashboardElements constraints.
+func (m DashboardElements) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m DashboardElements) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m DashboardElements) validate(partial bool) error {
+	if !partial && m.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if m.ID != "" && !dashboardElementsIDPattern.MatchString(m.ID) {
+		return fmt.Errorf("invalid id %q: must match %s", m.ID, dashboardElementsIDPattern)
+	}
+	if !partial && m.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	return nil
+}
+
+// Validate checks m against the ExportCallData constraints.
+func (m ExportCallData) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m ExportCallData) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m ExportCallData) validate(partial bool) error {
+	if !partial && len(m.Param) == 0 {
+		return fmt.Errorf("param is required")
+	}
+	return nil
+}
+
+// Validate checks m against the GlobalSettingsStruct constraints.
+func (m GlobalSettingsStruct) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m GlobalSettingsStruct) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m GlobalSettingsStruct) validate(partial bool) error {
+	if !partial && m.Category == "" {
+		return fmt.Errorf("category is required")
+	}
+	if !partial && m.Param == "" {
+		return fmt.Errorf("param is required")
+	}
+	return nil
+}
+
+// Validate checks m against the HepsubSchema constraints.
+func (m HepsubSchema) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m HepsubSchema) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m HepsubSchema) validate(partial bool) error {
+	if !partial && m.Hepid == 0 {
+		return fmt.Errorf("hepid is required")
+	}
+	if !partial && m.Profile == "" {
+		return fmt.Errorf("profile is required")
+	}
+	return nil
+}
+
+// Validate checks m against the InterceptionAgentRequest constraints.
+func (m InterceptionAgentRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m InterceptionAgentRequest) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m InterceptionAgentRequest) validate(partial bool) error {
+	if !partial && m.TsCreate == 0 {
+		return fmt.Errorf("ts_create is required")
+	}
+	if !partial && m.TsModify == 0 {
+		return fmt.Errorf("ts_modify is required")
+	}
+	if !partial && m.TsStart == 0 {
+		return fmt.Errorf("ts_start is required")
+	}
+	if !partial && m.TsStop == 0 {
+		return fmt.Errorf("ts_stop is required")
+	}
+	if !partial && m.UUID == "" {
+		return fmt.Errorf("uuid is required")
+	}
+	return nil
+}
+
+// Validate checks m against the InterceptionsStruct constraints.
+func (m InterceptionsStruct) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m InterceptionsStruct) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m InterceptionsStruct) validate(partial bool) error {
+	if m.SearchIP != "" {
+		if _, err := netip.ParseAddr(m.SearchIP); err != nil {
+			return fmt.Errorf("invalid search_ip %q: not an IP address", m.SearchIP)
+		}
+	}
+	return nil
+}
+
+// Validate checks m against the LegacyAlias constraints.
+func (m LegacyAlias) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m LegacyAlias) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m LegacyAlias) validate(partial bool) error {
+	if !partial && m.IP == "" {
+		return fmt.Errorf("IP is required")
+	}
+	if !partial && m.Ipbits == 0 {
+		return fmt.Errorf("IPBits is required")
+	}
+	if !partial && m.Port == 0 {
+		return fmt.Errorf("Port is required")
+	}
+	if !partial && m.Type == 0 {
+		return fmt.Errorf("Type is required")
+	}
+	return nil
+}
+
+// Validate checks m against the MappingSchema constraints.
+func (m MappingSchema) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m MappingSchema) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m MappingSchema) validate(partial bool) error {
+	if !partial && m.Hepid == 0 {
+		return fmt.Errorf("hepid is required")
+	}
+	if !partial && m.Profile == "" {
+		return fmt.Errorf("profile is required")
+	}
+	return nil
+}
+
+// Validate checks m against the RecordingTransactionRTP constraints.
+func (m RecordingTransactionRTP) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m RecordingTransactionRTP) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m RecordingTransactionRTP) validate(partial bool) error {
+	if !partial && m.CreateDate == 0 {
+		return fmt.Errorf("create_date is required")
+	}
+	if !partial && m.Date == "" {
+		return fmt.Errorf("date is required")
+	}
+	if !partial && m.RecordDatetime.IsZero() {
+		return fmt.Errorf("record_datetime is required")
+	}
+	if !partial && m.UUID == "" {
+		return fmt.Errorf("uuid is required")
+	}
+	return nil
+}
+
+// Validate checks m against the RecordingTransactionSIP constraints.
+func (m RecordingTransactionSIP) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m RecordingTransactionSIP) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m RecordingTransactionSIP) validate(partial bool) error {
+	if !partial && m.CreateDate == 0 {
+		return fmt.Errorf("create_date is required")
+	}
+	if !partial && m.Date == "" {
+		return fmt.Errorf("date is required")
+	}
+	if !partial && m.RecordDatetime.IsZero() {
+		return fmt.Errorf("record_datetime is required")
+	}
+	if !partial && m.UUID == "" {
+		return fmt.Errorf("uuid is required")
+	}
+	return nil
+}
+
+// Validate checks m against the ScriptDataStruct constraints.
+func (m ScriptDataStruct) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m ScriptDataStruct) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m ScriptDataStruct) validate(partial bool) error {
+	if !partial && m.Data == "" {
+		return fmt.Errorf("data is required")
+	}
+	if !partial && m.Type == "" {
+		return fmt.Errorf("type is required")
+	}
+	if m.Type != "" && !m.Type.Valid() {
+		return fmt.Errorf("invalid type %q: must be one of %s", m.Type, m.Type.choices())
+	}
+	return nil
+}
+
+// Validate checks m against the SearchCallExportPCAP constraints.
+func (m SearchCallExportPCAP) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m SearchCallExportPCAP) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m SearchCallExportPCAP) validate(partial bool) error {
+	if !partial && len(m.Param) == 0 {
+		return fmt.Errorf("param is required")
+	}
+	return nil
+}
+
+// Validate checks m against the SearchObject constraints.
+func (m SearchObject) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m SearchObject) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m SearchObject) validate(partial bool) error {
+	if !partial && len(m.Param) == 0 {
+		return fmt.Errorf("param is required")
+	}
+	return nil
+}
+
+// Validate checks m against the SearchObjectExportPCAP constraints.
+func (m SearchObjectExportPCAP) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m SearchObjectExportPCAP) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m SearchObjectExportPCAP) validate(partial bool) error {
+	if !partial && len(m.Param) == 0 {
+		return fmt.Errorf("param is required")
+	}
+	return nil
+}
+
+// Validate checks m against the SearchTransactionData constraints.
+func (m SearchTransactionData) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m SearchTransactionData) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m SearchTransactionData) validate(partial bool) error {
+	if !partial && len(m.Param) == 0 {
+		return fmt.Errorf("param is required")
+	}
+	return nil
+}
+
+// Validate checks m against the SuccessResponse constraints.
+func (m SuccessResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m SuccessResponse) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m SuccessResponse) validate(partial bool) error {
+	if !partial && m.Count == 0 {
+		return fmt.Errorf("count is required")
+	}
+	if !partial && len(m.Data) == 0 {
+		return fmt.Errorf("data is required")
+	}
+	if !partial && m.Message == "" {
+		return fmt.Errorf("message is required")
+	}
+	return nil
+}
+
+// Validate checks m against the UserLegacyStruct constraints.
+func (m UserLegacyStruct) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m UserLegacyStruct) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m UserLegacyStruct) validate(partial bool) error {
+	if !partial && m.Department == "" {
+		return fmt.Errorf("Department is required")
+	}
+	if !partial && m.Email == "" {
+		return fmt.Errorf("Email is required")
+	}
+	if !partial && m.FirstName == "" {
+		return fmt.Errorf("FirstName is required")
+	}
+	if !partial && m.LastName == "" {
+		return fmt.Errorf("LastName is required")
+	}
+	if !partial && m.PartID == 0 {
+		return fmt.Errorf("PartID is required")
+	}
+	if !partial && m.Password == "" {
+		return fmt.Errorf("Password is required")
+	}
+	return nil
+}
+
+// Validate checks m against the UserLogin constraints.
+func (m UserLogin) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m UserLogin) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m UserLogin) validate(partial bool) error {
+	if !partial && m.Password == "" {
+		return fmt.Errorf("password is required")
+	}
+	if m.Type != "" && !m.Type.Valid() {
+		return fmt.Errorf("invalid type %q: must be one of %s", m.Type, m.Type.choices())
+	}
+	if !partial && m.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	return nil
+}
+
+// Validate checks m against the UserSuccessResponse constraints.
+func (m UserSuccessResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidatePartial is Validate without the required fields, for updates.
+func (m UserSuccessResponse) ValidatePartial() error {
+	return m.validate(true)
+}
+
+func (m UserSuccessResponse) validate(partial bool) error {
+	if !partial && m.Data == "" {
+		return fmt.Errorf("data is required")
+	}
+	if !partial && m.Message == "" {
+		return fmt.Errorf("message is required")
+	}
+	return nil
+}
@@ -0,0 +1,2422 @@
+// Code generated from swagger.json — DO NOT EDIT.
+// Regenerate with: go run ./tools/generate
+
+// Package ops has a function for every operation in swagger.json, named
+// after the method and path: GET /search/remote/label is
+// GetSearchRemoteLabel, DELETE /ipalias/{uuid} is DeleteIpaliasByUUID.
+// Path parameters are escaped; bodies use the documented models type.
+//
+// The response schemas in the spec do not always match what the server
+// sends, so functions with a documented model return it together with the
+// raw body; the model is nil if the body does not decode into it.
+//
+// Packages written since these functions were generated send a request
+// through them whenever the spec describes it completely, and call
+// api.Client directly only for what it leaves out: undocumented query
+// parameters, a body model the server does not accept, or a raw proxied
+// path. Older packages keep their own requests.
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+)
+
+// GetAdminProfiles calls GET /admin/profiles (ListProfiles).
+//
+// Returns data from server.
+//
+// Documented response: HepsubSchema.
+func GetAdminProfiles(ctx context.Context, client *api.Client) (*models.HepsubSchema, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/admin/profiles", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.HepsubSchema
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetAdvanced calls GET /advanced (ListAdvancedSettings).
+//
+// Returns advanced setting of user.
+//
+// Documented response: GlobalSettingsStructList.
+func GetAdvanced(ctx context.Context, client *api.Client) (*models.GlobalSettingsStructList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/advanced", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.GlobalSettingsStructList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostAdvanced calls POST /advanced (AddAdvanced).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: GlobalSettingsCreateSuccessfulResponse.
+func PostAdvanced(ctx context.Context, client *api.Client, body models.GlobalSettingsStruct) (*models.GlobalSettingsCreateSuccessfulResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/advanced", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.GlobalSettingsCreateSuccessfulResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetAdvancedByUUID calls GET /advanced/{uuid} (AdvancedAgainstUUID).
+//
+// Get mapping against id and profile.
+//
+// Documented response: GlobalSettingsStruct.
+func GetAdvancedByUUID(ctx context.Context, client *api.Client, uuid string) (*models.GlobalSettingsStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/advanced/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.GlobalSettingsStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutAdvancedByUUID calls PUT /advanced/{uuid} (updateAdvanced).
+//
+// Get mapping against id and profile.
+//
+// Documented response: GlobalSettingsUpdateSuccessfulResponse.
+func PutAdvancedByUUID(ctx context.Context, client *api.Client, uuid string, body models.GlobalSettingsStruct) (*models.GlobalSettingsUpdateSuccessfulResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/advanced/"+api.PathEscape(uuid), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.GlobalSettingsUpdateSuccessfulResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteAdvancedByUUID calls DELETE /advanced/{uuid} (DeleteSettings).
+//
+// Get mapping against id and profile.
+//
+// Documented response: GlobalSettingsDeleteSuccessfulResponse.
+func DeleteAdvancedByUUID(ctx context.Context, client *api.Client, uuid string) (*models.GlobalSettingsDeleteSuccessfulResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/advanced/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.GlobalSettingsDeleteSuccessfulResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostAgentSearchByGuidByType calls POST /agent/search/{guid}/{type} (GetAgentSearchByTypeAndUUID).
+//
+// Get agent by guid and type.
+//
+// Documented response: AgentsLocationList.
+func PostAgentSearchByGuidByType(ctx context.Context, client *api.Client, guid string, typ string) (*models.AgentsLocationList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/agent/search/"+api.PathEscape(guid)+"/"+api.PathEscape(typ), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AgentsLocationList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetAgentSubscribe calls GET /agent/subscribe (ListAgents).
+//
+// Get agent.
+//
+// Documented response: AgentsLocationList.
+func GetAgentSubscribe(ctx context.Context, client *api.Client) (*models.AgentsLocationList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/agent/subscribe", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AgentsLocationList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetAgentSubscribeByUUID calls GET /agent/subscribe/{uuid} (UpdateAgent).
+//
+// Returns data from server.
+//
+// Documented response: AgentsLocationList.
+func GetAgentSubscribeByUUID(ctx context.Context, client *api.Client, uuid string) (*models.AgentsLocationList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/agent/subscribe/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AgentsLocationList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutAgentSubscribeByUUID calls PUT /agent/subscribe/{uuid} (agentsSubUpdateAgentsubAgainstGUID).
+//
+// Update Agent by uuid.
+//
+// Documented response: AgentLocationUpdateSuccessResponse.
+func PutAgentSubscribeByUUID(ctx context.Context, client *api.Client, uuid string) (*models.AgentLocationUpdateSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/agent/subscribe/"+api.PathEscape(uuid), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AgentLocationUpdateSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteAgentSubscribeByUUID calls DELETE /agent/subscribe/{uuid} (DeleteAgent).
+//
+// Returns data from server.
+//
+// Documented response: AgentLocationDeleteSuccessResponse.
+func DeleteAgentSubscribeByUUID(ctx context.Context, client *api.Client, uuid string) (*models.AgentLocationDeleteSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/agent/subscribe/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AgentLocationDeleteSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetAgentTypeByType calls GET /agent/type/{type} (GetAgentByType).
+//
+// Get agent.
+//
+// Documented response: AgentsLocationList.
+func GetAgentTypeByType(ctx context.Context, client *api.Client, typ string) (*models.AgentsLocationList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/agent/type/"+api.PathEscape(typ), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AgentsLocationList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostAgentsubProtocol calls POST /agentsub/protocol (AddAgentsub).
+//
+// Returns data from server.
+//
+// Documented response: AgentLocationCreateSuccessResponse.
+func PostAgentsubProtocol(ctx context.Context, client *api.Client, body models.AgentsLocation) (*models.AgentLocationCreateSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/agentsub/protocol", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AgentLocationCreateSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetAuth calls GET /auth (userLogin).
+//
+// Returns a JWT Token and UUID attached to user.
+//
+// Documented response: UserLoginSuccessResponse.
+func GetAuth(ctx context.Context, client *api.Client) (*models.UserLoginSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/auth", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserLoginSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostAuth calls POST /auth (userLogin).
+//
+// Returns a JWT Token and UUID attached to user.
+//
+// Documented response: UserLoginSuccessResponse.
+func PostAuth(ctx context.Context, client *api.Client, body models.UserLogin) (*models.UserLoginSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/auth", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserLoginSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetAuthTypeList calls GET /auth/type/list (SuccessResponse).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetAuthTypeList(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/auth/type/list", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostCallRecordingData calls POST /call/recording/data (GetRecordingData).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostCallRecordingData(ctx context.Context, client *api.Client, body models.SearchCallData) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/call/recording/data", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetCallRecordingDownloadByTypeByUUID calls GET /call/recording/download/{type}/{uuid} (GetRecordingPlayDataByType).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetCallRecordingDownloadByTypeByUUID(ctx context.Context, client *api.Client, typ string, uuid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/call/recording/download/"+api.PathEscape(typ)+"/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetCallRecordingInfoByUUID calls GET /call/recording/info/{uuid} (GetRecordingInfoDataByType).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetCallRecordingInfoByUUID(ctx context.Context, client *api.Client, uuid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/call/recording/info/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetCallRecordingPlayByUUID calls GET /call/recording/play/{uuid} (GetRecordingPlayDataByType).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetCallRecordingPlayByUUID(ctx context.Context, client *api.Client, uuid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/call/recording/play/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostCallReportDTMF calls POST /call/report/dtmf (GetTransactionDTMF).
+//
+// Returns log data based upon filtered json.
+func PostCallReportDTMF(ctx context.Context, client *api.Client, body models.SearchObject) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Post(ctx, "/call/report/dtmf", body, &raw)
+	return raw, err
+}
+
+// PostCallReportLog calls POST /call/report/log (GetTransactionLog).
+//
+// Returns log data based upon filtered json.
+func PostCallReportLog(ctx context.Context, client *api.Client, body models.SearchObject) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Post(ctx, "/call/report/log", body, &raw)
+	return raw, err
+}
+
+// PostCallReportQOS calls POST /call/report/qos (GetTransactionQos).
+//
+// Returns qos data based upon filtered json.
+//
+// Documented response: SearchTransactionQOS.
+func PostCallReportQOS(ctx context.Context, client *api.Client, body models.SearchObject) (*models.SearchTransactionQOS, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/call/report/qos", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SearchTransactionQOS
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostCallTransaction calls POST /call/transaction (GetTransaction).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: SearchTransactionResponse.
+func PostCallTransaction(ctx context.Context, client *api.Client, body models.SearchObject) (*models.SearchTransactionResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/call/transaction", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SearchTransactionResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostClickhouseQueryRaw calls POST /clickhouse/query/raw (rawSearchData).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ClickhouseRawQuery.
+func PostClickhouseQueryRaw(ctx context.Context, client *api.Client, body models.ClickhouseObject) (*models.ClickhouseRawQuery, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/clickhouse/query/raw", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ClickhouseRawQuery
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetConfigdbTablesList calls GET /configdb/tables/list (tablesList).
+//
+// Returns the list of tables.
+//
+// Documented response: AdminTables.
+func GetConfigdbTablesList(ctx context.Context, client *api.Client) (*models.AdminTables, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/configdb/tables/list", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AdminTables
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostConfigdbTablesResync calls POST /configdb/tables/resync (tablesResync).
+//
+// Returns the list of tables.
+//
+// Documented response: SuccessResponse.
+func PostConfigdbTablesResync(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/configdb/tables/resync", nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetDashboardInfo calls GET /dashboard/info (ListDashboard).
+//
+// Get Dashbroad list.
+//
+// Documented response: DashboardElementList.
+func GetDashboardInfo(ctx context.Context, client *api.Client) (*models.DashboardElementList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/dashboard/info", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.DashboardElementList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetDashboardReset calls GET /dashboard/reset (ListDashboard).
+//
+// Get Dashbroad list.
+//
+// Documented response: SuccessResponse.
+func GetDashboardReset(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/dashboard/reset", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetDashboardStoreByDashboardID calls GET /dashboard/store/{dashboardId} (GetDashboard).
+//
+// Get Dashboard Against a GUID.
+func GetDashboardStoreByDashboardID(ctx context.Context, client *api.Client, dashboardID string) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Get(ctx, "/dashboard/store/"+api.PathEscape(dashboardID), &raw)
+	return raw, err
+}
+
+// PostDashboardStoreByDashboardID calls POST /dashboard/store/{dashboardId} (CreateDashboard).
+//
+// Add new dashboard.
+//
+// Documented response: SuccessResponse.
+func PostDashboardStoreByDashboardID(ctx context.Context, client *api.Client, dashboardID string, body models.UserSettings) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/dashboard/store/"+api.PathEscape(dashboardID), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutDashboardStoreByDashboardID calls PUT /dashboard/store/{dashboardId} (UpdateDashboard).
+//
+// Add new dashboard.
+//
+// Documented response: SuccessResponse.
+func PutDashboardStoreByDashboardID(ctx context.Context, client *api.Client, dashboardID string, body models.UserSettings) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/dashboard/store/"+api.PathEscape(dashboardID), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteDashboardStoreByDashboardID calls DELETE /dashboard/store/{dashboardId} (DeleteDashboard).
+//
+// Delete a dashboard.
+//
+// Documented response: SuccessResponse.
+func DeleteDashboardStoreByDashboardID(ctx context.Context, client *api.Client, dashboardID string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/dashboard/store/"+api.PathEscape(dashboardID), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetDatabaseGroupList calls GET /database/group/list (GroupListMapping).
+//
+// Returns group of nodes from server.
+//
+// Documented response: NodeList.
+func GetDatabaseGroupList(ctx context.Context, client *api.Client) (*models.NodeList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/database/group/list", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.NodeList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetDatabaseNodeList calls GET /database/node/list (NodeListMapping).
+//
+// Returns data from server.
+//
+// Documented response: NodeList.
+func GetDatabaseNodeList(ctx context.Context, client *api.Client) (*models.NodeList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/database/node/list", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.NodeList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetDatabaseWebnodeList calls GET /database/webnode/list (ListMapping).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetDatabaseWebnodeList(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/database/webnode/list", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetExportActionActive calls GET /export/action/active (actionActive).
+//
+// Returns log data based upon filtered json.
+func GetExportActionActive(ctx context.Context, client *api.Client) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Get(ctx, "/export/action/active", &raw)
+	return raw, err
+}
+
+// GetExportActionHepicapp calls GET /export/action/hepicapp (hepicapp).
+//
+// Returns log data based upon filtered json.
+//
+// Documented response: ExportActionRtpagent.
+func GetExportActionHepicapp(ctx context.Context, client *api.Client) (*models.ExportActionRtpagent, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/export/action/hepicapp", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ExportActionRtpagent
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetExportActionLogs calls GET /export/action/logs (hepicapplog).
+//
+// Returns log data based upon filtered json.
+//
+// Documented response: ExportActionRtpagent.
+func GetExportActionLogs(ctx context.Context, client *api.Client) (*models.ExportActionRtpagent, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/export/action/logs", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ExportActionRtpagent
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetExportActionPicserver calls GET /export/action/picserver (picserver).
+//
+// Returns log data based upon filtered json.
+//
+// Documented response: ExportActionRtpagent.
+func GetExportActionPicserver(ctx context.Context, client *api.Client) (*models.ExportActionRtpagent, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/export/action/picserver", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ExportActionRtpagent
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetExportActionRtpagent calls GET /export/action/rtpagent (rtpagetn).
+//
+// Returns log data based upon filtered json.
+//
+// Documented response: ExportActionRtpagent.
+func GetExportActionRtpagent(ctx context.Context, client *api.Client) (*models.ExportActionRtpagent, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/export/action/rtpagent", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ExportActionRtpagent
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostExportCallDataPCAP calls POST /export/call/data/pcap (GetSearchDataAsPcap).
+//
+// Returns pcap data based upon filtered json.
+//
+// Documented response: Pcapresponse.
+func PostExportCallDataPCAP(ctx context.Context, client *api.Client, body models.SearchCallExportPCAP) (*models.Pcapresponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/export/call/data/pcap", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.Pcapresponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostExportCallMessagesPCAP calls POST /export/call/messages/pcap (GetMessagesAsPCap).
+//
+// Returns pcap data based upon filtered json.
+//
+// Documented response: Pcapresponse.
+func PostExportCallMessagesPCAP(ctx context.Context, client *api.Client, body models.SearchObjectExportPCAP) (*models.Pcapresponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/export/call/messages/pcap", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.Pcapresponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostExportCallMessagesSipp calls POST /export/call/messages/sipp (GetMessagesAsSIPP).
+//
+// Returns text data based upon filtered json.
+//
+// Documented response: Sippresponse.
+func PostExportCallMessagesSipp(ctx context.Context, client *api.Client, body models.SearchObject) (*models.Sippresponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/export/call/messages/sipp", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.Sippresponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostExportCallMessagesText calls POST /export/call/messages/text (GetMessagesAsText).
+//
+// Returns text data based upon filtered json.
+//
+// Documented response: TextResponse.
+func PostExportCallMessagesText(ctx context.Context, client *api.Client, body models.SearchObject) (*models.TextResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/export/call/messages/text", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.TextResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostExportCallStenographer calls POST /export/call/stenographer (GetDataFromStenographer).
+//
+// Returns pcap data based upon filtered json.
+//
+// Documented response: StenographerResponse.
+func PostExportCallStenographer(ctx context.Context, client *api.Client, body models.SearchObject) (*models.StenographerResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/export/call/stenographer", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.StenographerResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostExportCallTransactionArchive calls POST /export/call/transaction/archive (saveToArchive).
+//
+// Save transaction data to Archive.
+//
+// Documented response: ListUsers.
+func PostExportCallTransactionArchive(ctx context.Context, client *api.Client, body models.SearchObject) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/export/call/transaction/archive", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostExportCallTransactionLink calls POST /export/call/transaction/link (GetTransactionLink).
+//
+// Returns text data based upon filtered json.
+//
+// Documented response: LinkResponse.
+func PostExportCallTransactionLink(ctx context.Context, client *api.Client, body models.SearchObject) (*models.LinkResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/export/call/transaction/link", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.LinkResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostExportCallTransactionReport calls POST /export/call/transaction/report (exportCallTransactionReport).
+//
+// Returns transaction data based upon filtered json.
+func PostExportCallTransactionReport(ctx context.Context, client *api.Client, body models.SearchObject) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Post(ctx, "/export/call/transaction/report", body, &raw)
+	return raw, err
+}
+
+// GetHepsubProtocol calls GET /hepsub/protocol (GetHepSubAgainstUUID).
+//
+// Add new Hepsub information.
+//
+// Documented response: HepsubSchema.
+func GetHepsubProtocol(ctx context.Context, client *api.Client) (*models.HepsubSchema, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/hepsub/protocol", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.HepsubSchema
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostHepsubProtocol calls POST /hepsub/protocol (AddHepSub).
+//
+// Add new Hepsub information.
+//
+// Documented response: HepsubCreateSuccessResponse.
+func PostHepsubProtocol(ctx context.Context, client *api.Client, body models.HepsubSchema) (*models.HepsubCreateSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/hepsub/protocol", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.HepsubCreateSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutHepsubProtocolByUUID calls PUT /hepsub/protocol/{uuid} (UpdateHepSubAgainstUUID).
+//
+// Delete hepsub by guid.
+//
+// Documented response: HepsubUpdateSuccessResponse.
+func PutHepsubProtocolByUUID(ctx context.Context, client *api.Client, uuid string) (*models.HepsubUpdateSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/hepsub/protocol/"+api.PathEscape(uuid), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.HepsubUpdateSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteHepsubProtocolByUUID calls DELETE /hepsub/protocol/{uuid} (hepSubDeleteHepSubAgainstUUID).
+//
+// Delete hepsub by guid.
+//
+// Documented response: HepsubDeleteSuccessResponse.
+func DeleteHepsubProtocolByUUID(ctx context.Context, client *api.Client, uuid string) (*models.HepsubDeleteSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/hepsub/protocol/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.HepsubDeleteSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostHepsubSearch calls POST /hepsub/search (AddHepsubsearch).
+//
+// Returns data from server.
+//
+// Documented response: HepsubCreateSuccessResponse.
+func PostHepsubSearch(ctx context.Context, client *api.Client, body models.HepsubSchema) (*models.HepsubCreateSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/hepsub/search", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.HepsubCreateSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostImportDataPCAP calls POST /import/data/pcap (GetMessagesAsPCap).
+//
+// Returns pcap data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostImportDataPCAP(ctx context.Context, client *api.Client, body interface{}) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/import/data/pcap", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostImportDataPCAPNow calls POST /import/data/pcap/now (GetMessagesAsPCapNow).
+//
+// Returns pcap data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostImportDataPCAPNow(ctx context.Context, client *api.Client, body interface{}) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/import/data/pcap/now", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostInterceptionAddRtprecord calls POST /interception/add/rtprecord (AddRtpRecordFromAgent).
+//
+// Returns data from server.
+//
+// Documented response: InterceptionsStruct.
+func PostInterceptionAddRtprecord(ctx context.Context, client *api.Client) (*models.InterceptionsStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/interception/add/rtprecord", nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.InterceptionsStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetInterceptions calls GET /interceptions (ListInterceptions).
+//
+// Returns data from server.
+//
+// Documented response: InterceptionsStruct.
+func GetInterceptions(ctx context.Context, client *api.Client) (*models.InterceptionsStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/interceptions", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.InterceptionsStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostInterceptions calls POST /interceptions (AddInterceptions).
+//
+// Adds interceptions to system.
+//
+// Documented response: UserLoginSuccessResponse.
+func PostInterceptions(ctx context.Context, client *api.Client, body models.InterceptionsStruct) (*models.UserLoginSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/interceptions", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserLoginSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutInterceptionsByUUID calls PUT /interceptions/{uuid} (UpdateInterceptions).
+//
+// Update an existing user.
+//
+// Documented response: InterceptionsStruct.
+func PutInterceptionsByUUID(ctx context.Context, client *api.Client, uuid string, body interface{}) (*models.InterceptionsStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/interceptions/"+api.PathEscape(uuid), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.InterceptionsStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteInterceptionsByUUID calls DELETE /interceptions/{uuid} (DeleteInterceptions).
+//
+// Update an existing user.
+//
+// Documented response: InterceptionsStruct.
+func DeleteInterceptionsByUUID(ctx context.Context, client *api.Client, uuid string) (*models.InterceptionsStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/interceptions/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.InterceptionsStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetIpalias calls GET /ipalias (GetAllLookupIP).
+//
+// Returns the list of aliases.
+//
+// Documented response: SuccessResponse.
+func GetIpalias(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/ipalias", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostIpalias calls POST /ipalias (ipAliasLookup).
+//
+// Adds LookupIP to system.
+//
+// Documented response: AliasSwaggerStruct.
+func PostIpalias(ctx context.Context, client *api.Client, body models.AliasSwaggerStruct) (*models.AliasSwaggerStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/ipalias", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AliasSwaggerStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteIpaliasAll calls DELETE /ipalias/all (DeleteLookupIP).
+//
+// Deletes all the aliases from the system.
+//
+// Documented response: SuccessResponse.
+func DeleteIpaliasAll(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/ipalias/all", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetIpaliasExport calls GET /ipalias/export (FileDownload).
+//
+// Returns a csv file of the aliases.
+//
+// Documented response: SuccessResponse.
+func GetIpaliasExport(ctx context.Context, client *api.Client) (io.ReadCloser, error) {
+	return client.GetRaw(ctx, "/ipalias/export")
+}
+
+// PostIpaliasImport calls POST /ipalias/import (IPAliasFileResponse).
+//
+// Import a csv file of aliases into system.
+//
+// Documented response: SuccessResponse.
+func PostIpaliasImport(ctx context.Context, client *api.Client, filePath string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.PostFormFile(ctx, "/ipalias/import", "file", filePath, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostIpaliasImportH2 calls POST /ipalias/import/h2 (IPAliasFileResponse).
+//
+// Import a csv file of aliases into system.
+//
+// Documented response: SuccessResponse.
+func PostIpaliasImportH2(ctx context.Context, client *api.Client, filePath string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.PostFormFile(ctx, "/ipalias/import/h2", "file", filePath, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostIpaliasImportH2Replace calls POST /ipalias/import/h2/replace (IPAliasFileResponse).
+//
+// Import a csv file of aliases into system.
+//
+// Documented response: SuccessResponse.
+func PostIpaliasImportH2Replace(ctx context.Context, client *api.Client, filePath string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.PostFormFile(ctx, "/ipalias/import/h2/replace", "file", filePath, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostIpaliasImportReplace calls POST /ipalias/import/replace (IPAliasFileResponse).
+//
+// Import a csv file of aliases into system.
+//
+// Documented response: SuccessResponse.
+func PostIpaliasImportReplace(ctx context.Context, client *api.Client, filePath string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.PostFormFile(ctx, "/ipalias/import/replace", "file", filePath, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteIpaliasByUUID calls DELETE /ipalias/{uuid} (DeleteLookupIP).
+//
+// delete an alias based upon uuid.
+func DeleteIpaliasByUUID(ctx context.Context, client *api.Client, uuid string) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Delete(ctx, "/ipalias/"+api.PathEscape(uuid), &raw)
+	return raw, err
+}
+
+// PutLookupipByUUID calls PUT /lookupip/{uuid} (UpdateLookupIP).
+//
+// Update an existing alias based upon the lookup.
+//
+// Documented response: SuccessResponse.
+func PutLookupipByUUID(ctx context.Context, client *api.Client, uuid string, body interface{}) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/lookupip/"+api.PathEscape(uuid), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetMappingProtocol calls GET /mapping/protocol (ListProfiles).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetMappingProtocol(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/mapping/protocol", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostMappingProtocol calls POST /mapping/protocol (AddMapping).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func PostMappingProtocol(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/mapping/protocol", nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetMappingProtocolReset calls GET /mapping/protocol/reset (resetProtocols).
+//
+// Get mapping against id and profile.
+//
+// Documented response: SuccessResponse.
+func GetMappingProtocolReset(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/mapping/protocol/reset", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetMappingProtocolResetByUUID calls GET /mapping/protocol/reset/{uuid} (ResetMapping).
+//
+// Get mapping against id and profile.
+//
+// Documented response: SuccessResponse.
+func GetMappingProtocolResetByUUID(ctx context.Context, client *api.Client, uuid string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/mapping/protocol/reset/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetMappingProtocolByIDByTransaction calls GET /mapping/protocol/{id}/{transaction} (GetProtocolMapping).
+//
+// Get mapping against id and profile.
+//
+// Documented response: SuccessResponse.
+func GetMappingProtocolByIDByTransaction(ctx context.Context, client *api.Client, id string, transaction string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/mapping/protocol/"+api.PathEscape(id)+"/"+api.PathEscape(transaction), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetMappingProtocolByUUID calls GET /mapping/protocol/{uuid} (getMapping).
+//
+// Get mapping against id and profile.
+//
+// Documented response: SuccessResponse.
+func GetMappingProtocolByUUID(ctx context.Context, client *api.Client, uuid string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/mapping/protocol/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteMappingProtocolByUUID calls DELETE /mapping/protocol/{uuid} (DeleteMapping).
+//
+// Get mapping against id and profile.
+//
+// Documented response: SuccessResponse.
+func DeleteMappingProtocolByUUID(ctx context.Context, client *api.Client, uuid string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/mapping/protocol/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetMappingProtocols calls GET /mapping/protocols (ListMapping).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetMappingProtocols(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/mapping/protocols", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostPrometheusData calls POST /prometheus/data (prometheusData).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostPrometheusData(ctx context.Context, client *api.Client, body models.PrometheusObject) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/prometheus/data", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetPrometheusLabelByUserlabel calls GET /prometheus/label/{userlabel} (prometheusUserLabel).
+//
+// Returns data based upon filtered json.
+func GetPrometheusLabelByUserlabel(ctx context.Context, client *api.Client, userlabel string) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Get(ctx, "/prometheus/label/"+api.PathEscape(userlabel), &raw)
+	return raw, err
+}
+
+// GetPrometheusLabels calls GET /prometheus/labels (prometheusLabels).
+//
+// Returns data based upon filtered json.
+func GetPrometheusLabels(ctx context.Context, client *api.Client) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Get(ctx, "/prometheus/labels", &raw)
+	return raw, err
+}
+
+// PostPrometheusValue calls POST /prometheus/value (prometheusValue).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostPrometheusValue(ctx context.Context, client *api.Client, body models.PrometheusObject) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/prometheus/value", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetProtocolSearchByID calls GET /protocol/search/{id} (ListSettings).
+//
+// Returns the list of settings.
+//
+// Documented response: ListUsers.
+func GetProtocolSearchByID(ctx context.Context, client *api.Client, id string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/protocol/search/"+api.PathEscape(id), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostProtocolByID calls POST /protocol/{id} (AddProtocol).
+//
+// Adds alias to system.
+//
+// Documented response: UserLoginSuccessResponse.
+func PostProtocolByID(ctx context.Context, client *api.Client, id string, body interface{}) (*models.UserLoginSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/protocol/"+api.PathEscape(id), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserLoginSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutProtocolByUUID calls PUT /protocol/{uuid} (UpdateAlias).
+//
+// Update an existing user.
+//
+// Documented response: AliasStruct.
+func PutProtocolByUUID(ctx context.Context, client *api.Client, uuid string, body interface{}) (*models.AliasStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/protocol/"+api.PathEscape(uuid), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AliasStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteProtocolByUUID calls DELETE /protocol/{uuid} (DeleteProtocol).
+//
+// Update an existing user.
+//
+// Documented response: AliasStruct.
+func DeleteProtocolByUUID(ctx context.Context, client *api.Client, uuid string) (*models.AliasStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/protocol/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AliasStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetProxyGrafanaDashboardsUidByUid calls GET /proxy/grafana/dashboards/uid/{uid} (grafanaDashboard).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetProxyGrafanaDashboardsUidByUid(ctx context.Context, client *api.Client, uid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/proxy/grafana/dashboards/uid/"+api.PathEscape(uid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetProxyGrafanaFolders calls GET /proxy/grafana/folders (grafanaFolders).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetProxyGrafanaFolders(ctx context.Context, client *api.Client) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/proxy/grafana/folders", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetProxyGrafanaOrg calls GET /proxy/grafana/org (grafanaOrg).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetProxyGrafanaOrg(ctx context.Context, client *api.Client) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/proxy/grafana/org", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetProxyGrafanaRequestDByUidByParam calls GET /proxy/grafana/request/d/{uid}/{param} (grafanaRequest).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetProxyGrafanaRequestDByUidByParam(ctx context.Context, client *api.Client, uid string, param string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/proxy/grafana/request/d/"+api.PathEscape(uid)+"/"+api.PathEscape(param), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetProxyGrafanaSearchByUid calls GET /proxy/grafana/search/{uid} (grafanaSearch).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetProxyGrafanaSearchByUid(ctx context.Context, client *api.Client, uid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/proxy/grafana/search/"+api.PathEscape(uid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetProxyGrafanaStatus calls GET /proxy/grafana/status (grafana).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetProxyGrafanaStatus(ctx context.Context, client *api.Client) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/proxy/grafana/status", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetProxyGrafanaURL calls GET /proxy/grafana/url (grafanaUrl).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetProxyGrafanaURL(ctx context.Context, client *api.Client) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/proxy/grafana/url", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetScript calls GET /script (ListScriptData).
+//
+// Returns data from server.
+//
+// Documented response: ScriptDataStruct.
+func GetScript(ctx context.Context, client *api.Client) (*models.ScriptDataStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/script", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ScriptDataStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostScript calls POST /script (AddScriptData).
+//
+// Adds Scripts to system.
+//
+// Documented response: UserLoginSuccessResponse.
+func PostScript(ctx context.Context, client *api.Client, body models.ScriptDataStruct) (*models.UserLoginSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/script", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserLoginSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutScriptByUUID calls PUT /script/{uuid} (UpdateScriptData).
+//
+// Update an existing user.
+//
+// Documented response: ScriptDataStruct.
+func PutScriptByUUID(ctx context.Context, client *api.Client, uuid string, body interface{}) (*models.ScriptDataStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/script/"+api.PathEscape(uuid), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ScriptDataStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteScriptByUUID calls DELETE /script/{uuid} (DeleteScriptData).
+//
+// Update an existing user.
+//
+// Documented response: ScriptDataStruct.
+func DeleteScriptByUUID(ctx context.Context, client *api.Client, uuid string) (*models.ScriptDataStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/script/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ScriptDataStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostSearchCallData calls POST /search/call/data (SearchData).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: SearchCallData.
+func PostSearchCallData(ctx context.Context, client *api.Client, body models.SearchObject) (*models.SearchCallData, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/search/call/data", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SearchCallData
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostSearchCallDecodeMessage calls POST /search/call/decode/message (callDecodeMessage).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: MessageDecoded.
+func PostSearchCallDecodeMessage(ctx context.Context, client *api.Client, body models.SearchObject) (*models.MessageDecoded, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/search/call/decode/message", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.MessageDecoded
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostSearchCallMessage calls POST /search/call/message (callMessage).
+//
+// Returns message data based upon filtered json.
+//
+// Documented response: SearchCallData.
+func PostSearchCallMessage(ctx context.Context, client *api.Client, body models.SearchObject) (*models.SearchCallData, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/search/call/message", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SearchCallData
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostSearchRemoteData calls POST /search/remote/data (remoteData).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: RemoteResponseData.
+func PostSearchRemoteData(ctx context.Context, client *api.Client, body models.RemoteObject) (*models.RemoteResponseData, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/search/remote/data", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.RemoteResponseData
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetSearchRemoteLabel calls GET /search/remote/label (remoteLabel).
+//
+// Returns data based upon filtered json.
+func GetSearchRemoteLabel(ctx context.Context, client *api.Client) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Get(ctx, "/search/remote/label", &raw)
+	return raw, err
+}
+
+// GetSearchRemoteStatus calls GET /search/remote/status (remoteStatus).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetSearchRemoteStatus(ctx context.Context, client *api.Client) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/search/remote/status", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetSearchRemoteValues calls GET /search/remote/values (remoteValues).
+//
+// Returns data based upon filtered json.
+func GetSearchRemoteValues(ctx context.Context, client *api.Client) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Get(ctx, "/search/remote/values", &raw)
+	return raw, err
+}
+
+// PostSearchTransactionType calls POST /search/transaction/type (GetTypeTransaction).
+//
+// Returns data based upon filtered json.
+func PostSearchTransactionType(ctx context.Context, client *api.Client, body models.SearchObject) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Post(ctx, "/search/transaction/type", body, &raw)
+	return raw, err
+}
+
+// PostShareCallReportDTMFByUUID calls POST /share/call/report/dtmf/{uuid} (searchCallReportDtmf).
+//
+// Returns log data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostShareCallReportDTMFByUUID(ctx context.Context, client *api.Client, uuid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/share/call/report/dtmf/"+api.PathEscape(uuid), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostShareCallReportLogByUUID calls POST /share/call/report/log/{uuid} (searchCallReportLogs).
+//
+// Returns log data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostShareCallReportLogByUUID(ctx context.Context, client *api.Client, uuid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/share/call/report/log/"+api.PathEscape(uuid), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostShareCallTransactionByUUID calls POST /share/call/transaction/{uuid} (searchCallTransaction).
+//
+// Returns transaction data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostShareCallTransactionByUUID(ctx context.Context, client *api.Client, uuid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/share/call/transaction/"+api.PathEscape(uuid), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostShareExportCallMessagesPCAPByUUID calls POST /share/export/call/messages/pcap/{uuid} (searchCallMessagesPcap).
+//
+// Returns pcap data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostShareExportCallMessagesPCAPByUUID(ctx context.Context, client *api.Client, uuid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/share/export/call/messages/pcap/"+api.PathEscape(uuid), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostShareExportCallMessagesTextByUUID calls POST /share/export/call/messages/text/{uuid} (searchMessagesText).
+//
+// Returns text data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostShareExportCallMessagesTextByUUID(ctx context.Context, client *api.Client, uuid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/share/export/call/messages/text/"+api.PathEscape(uuid), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetShareIpaliasByUUID calls GET /share/ipalias/{uuid} (ipAliasLookup).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetShareIpaliasByUUID(ctx context.Context, client *api.Client, uuid string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/share/ipalias/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetShareMappingProtocolByUUID calls GET /share/mapping/protocol/{uuid} (searchMappingProtocol).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetShareMappingProtocolByUUID(ctx context.Context, client *api.Client, uuid string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/share/mapping/protocol/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostShareSearchCallMessageByUUID calls POST /share/search/call/message/{uuid} (searchCalMessageByUuuid).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostShareSearchCallMessageByUUID(ctx context.Context, client *api.Client, uuid string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/share/search/call/message/"+api.PathEscape(uuid), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetStatisticDB calls GET /statistic/_db (statsDB).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetStatisticDB(ctx context.Context, client *api.Client) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/statistic/_db", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostStatisticMeasurementsByDbid calls POST /statistic/_measurements/{dbid} (statsMeasurement).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostStatisticMeasurementsByDbid(ctx context.Context, client *api.Client, dbid string, body models.StatisticSearchObject) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/statistic/_measurements/"+api.PathEscape(dbid), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostStatisticMetrics calls POST /statistic/_metrics (statsMetrics).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostStatisticMetrics(ctx context.Context, client *api.Client, body models.StatisticObject) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/statistic/_metrics", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostStatisticRetentions calls POST /statistic/_retentions (statsRetention).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostStatisticRetentions(ctx context.Context, client *api.Client, body models.StatisticSearchObject) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/statistic/_retentions", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostStatisticTags calls POST /statistic/_tags (statsTags).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostStatisticTags(ctx context.Context, client *api.Client, body models.StatisticObject) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/statistic/_tags", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostStatisticConfigdbInfo calls POST /statistic/configdb/info (infoDatabase).
+//
+// Returns log data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostStatisticConfigdbInfo(ctx context.Context, client *api.Client) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/statistic/configdb/info", nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostStatisticData calls POST /statistic/data (dataSearch).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostStatisticData(ctx context.Context, client *api.Client, body models.StatisticObject) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/statistic/data", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostStatisticDatabaseInfo calls POST /statistic/database/info (infoDatabase).
+//
+// Returns log data based upon filtered json.
+//
+// Documented response: ListUsers.
+func PostStatisticDatabaseInfo(ctx context.Context, client *api.Client) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/statistic/database/info", nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetTokenAuth calls GET /token/auth (GetToken).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetTokenAuth(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/token/auth", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostTokenAuth calls POST /token/auth (AddAuthToken).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func PostTokenAuth(ctx context.Context, client *api.Client, body models.TableAuthToken) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/token/auth", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetTokenAuthByUUID calls GET /token/auth/{uuid} (GetTokenAgainstUUID).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetTokenAuthByUUID(ctx context.Context, client *api.Client, uuid string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/token/auth/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutTokenAuthByUUID calls PUT /token/auth/{uuid} (UpdateToken).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func PutTokenAuthByUUID(ctx context.Context, client *api.Client, uuid string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/token/auth/"+api.PathEscape(uuid), nil, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteTokenAuthByUUID calls DELETE /token/auth/{uuid} (DeleteToken).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func DeleteTokenAuthByUUID(ctx context.Context, client *api.Client, uuid string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/token/auth/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetTroubleshootingLogByTypeByAction calls GET /troubleshooting/log/:type/:action (DoActionForLog).
+//
+// Returns data based upon filtered json.
+//
+// Documented response: ListUsers.
+func GetTroubleshootingLogByTypeByAction(ctx context.Context, client *api.Client, typ string, action string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/troubleshooting/log/"+api.PathEscape(typ)+"/"+api.PathEscape(action), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetUserSettings calls GET /user/settings (ListSettings).
+//
+// Returns the list of settings.
+//
+// Documented response: ListUsers.
+func GetUserSettings(ctx context.Context, client *api.Client) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/user/settings", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostUserSettings calls POST /user/settings (AddUserSettings).
+//
+// Adds user settings to system.
+//
+// Documented response: UserLoginSuccessResponse.
+func PostUserSettings(ctx context.Context, client *api.Client, body interface{}) (*models.UserLoginSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/user/settings", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserLoginSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetUserSettingsByCategory calls GET /user/settings/{category} (ListSettings).
+//
+// Returns the list of settings.
+//
+// Documented response: ListUsers.
+func GetUserSettingsByCategory(ctx context.Context, client *api.Client, category string) (*models.ListUsers, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/user/settings/"+api.PathEscape(category), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.ListUsers
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutUserSettingsByUUID calls PUT /user/settings/{uuid} (UpdateSettings).
+//
+// Update an existing user.
+//
+// Documented response: AliasStruct.
+func PutUserSettingsByUUID(ctx context.Context, client *api.Client, uuid string, body interface{}) (*models.AliasStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/user/settings/"+api.PathEscape(uuid), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AliasStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteUserSettingsByUUID calls DELETE /user/settings/{uuid} (DeleteSettings).
+//
+// Update an existing user.
+//
+// Documented response: AliasStruct.
+func DeleteUserSettingsByUUID(ctx context.Context, client *api.Client, uuid string) (*models.AliasStruct, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/user/settings/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.AliasStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetUsers calls GET /users (CreateUserStruct).
+//
+// Returns data from server in array.
+//
+// Documented response: TableUserList.
+func GetUsers(ctx context.Context, client *api.Client) (*models.TableUserList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/users", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.TableUserList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostUsers calls POST /users (CreateUser).
+//
+// Create a New user.
+//
+// Documented response: UserSuccessResponse.
+func PostUsers(ctx context.Context, client *api.Client, body models.CreateUserStruct) (*models.UserSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Post(ctx, "/users", body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetUsersExport calls GET /users/export (ListUsers).
+//
+// Returns data from server.
+func GetUsersExport(ctx context.Context, client *api.Client) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := client.Get(ctx, "/users/export", &raw)
+	return raw, err
+}
+
+// GetUsersGroups calls GET /users/groups (groups).
+//
+// Returns the list of groups.
+//
+// Documented response: UserGroupList.
+func GetUsersGroups(ctx context.Context, client *api.Client) (*models.UserGroupList, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/users/groups", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserGroupList
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostUsersImport calls POST /users/import (UserFileResponse).
+//
+// Upload.
+//
+// Documented response: SuccessResponse.
+func PostUsersImport(ctx context.Context, client *api.Client, filePath string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.PostFormFile(ctx, "/users/import", "file", filePath, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostUsersImportH2 calls POST /users/import/h2 (UserFileResponse).
+//
+// Upload.
+//
+// Documented response: SuccessResponse.
+func PostUsersImportH2(ctx context.Context, client *api.Client, filePath string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.PostFormFile(ctx, "/users/import/h2", "file", filePath, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostUsersImportH2Replace calls POST /users/import/h2/replace (UserFileResponse).
+//
+// Upload.
+//
+// Documented response: SuccessResponse.
+func PostUsersImportH2Replace(ctx context.Context, client *api.Client, filePath string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.PostFormFile(ctx, "/users/import/h2/replace", "file", filePath, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PostUsersImportReplace calls POST /users/import/replace (UserFileResponse).
+//
+// Upload.
+//
+// Documented response: SuccessResponse.
+func PostUsersImportReplace(ctx context.Context, client *api.Client, filePath string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.PostFormFile(ctx, "/users/import/replace", "file", filePath, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutUsersUpdatePasswordByUUID calls PUT /users/update/password/{uuid} (UpdateUserPassword).
+//
+// Update a user's password.
+//
+// Documented response: SuccessResponse.
+func PutUsersUpdatePasswordByUUID(ctx context.Context, client *api.Client, uuid string, body interface{}) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/users/update/password/"+api.PathEscape(uuid), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// PutUsersByUUID calls PUT /users/{uuid} (UpdateUser).
+//
+// Update an existing user.
+//
+// Documented response: UserUpdateSuccessResponse.
+func PutUsersByUUID(ctx context.Context, client *api.Client, uuid string, body interface{}) (*models.UserUpdateSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Put(ctx, "/users/"+api.PathEscape(uuid), body, &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserUpdateSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// DeleteUsersByUUID calls DELETE /users/{uuid} (DeleteUser).
+//
+// Delete an existing User.
+//
+// Documented response: UserDeleteSuccessResponse.
+func DeleteUsersByUUID(ctx context.Context, client *api.Client, uuid string) (*models.UserDeleteSuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Delete(ctx, "/users/"+api.PathEscape(uuid), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.UserDeleteSuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetVersionAPIInfo calls GET /version/api/info (ListMapping).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetVersionAPIInfo(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/version/api/info", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetVersionUICheckByUI calls GET /version/ui/check/{ui} (ListMapping).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetVersionUICheckByUI(ctx context.Context, client *api.Client, ui string) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/version/ui/check/"+api.PathEscape(ui), &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
+
+// GetVersionUIInfo calls GET /version/ui/info (ListMapping).
+//
+// Returns data from server.
+//
+// Documented response: SuccessResponse.
+func GetVersionUIInfo(ctx context.Context, client *api.Client) (*models.SuccessResponse, json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := client.Get(ctx, "/version/ui/info", &raw); err != nil {
+		return nil, nil, err
+	}
+	var result models.SuccessResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, raw, nil
+	}
+	return &result, raw, nil
+}
@@ -0,0 +1,74 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/mocksrv"
+	"hepic-cli/internal/models"
+)
+
+func TestOperations(t *testing.T) {
+	spec, err := os.ReadFile("../../swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := mocksrv.New(spec, mocksrv.Options{Token: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	client := api.NewClientWith(ts.URL, "t")
+	ctx := context.Background()
+
+	if _, _, err := PostIpalias(ctx, client, models.AliasSwaggerStruct{UUID: "a/1", Alias: "proxy", IP: "10.0.0.1", Mask: 32}); err != nil {
+		t.Fatal(err)
+	}
+	list, raw, err := GetIpalias(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var aliases []models.AliasSwaggerStruct
+	if list == nil || list.Count != 1 || json.Unmarshal(list.Data, &aliases) != nil || aliases[0].Alias != "proxy" {
+		t.Errorf("unexpected list %s", raw)
+	}
+	// The UUID is escaped into one path segment.
+	if _, err := DeleteIpaliasByUUID(ctx, client, "a/1"); err != nil {
+		t.Errorf("delete failed: %v", err)
+	}
+
+	labels, err := GetSearchRemoteLabel(ctx, client)
+	if err != nil || len(labels) == 0 {
+		t.Errorf("unexpected labels %s (%v)", labels, err)
+	}
+	body, err := GetIpaliasExport(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Close()
+}
+
+func TestUndocumentedResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/statistic/_tags" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		io.WriteString(w, `["tag1","tag2"]`)
+	}))
+	defer ts.Close()
+
+	result, raw, err := PostStatisticTags(context.Background(), api.NewClientWith(ts.URL, "t"), models.StatisticObject{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != nil || string(raw) != `["tag1","tag2"]` {
+		t.Errorf("expected only the raw body, got %+v %s", result, raw)
+	}
+}
@@ -1,12 +1,19 @@
 package output
 
-import "io"
+import (
+	"fmt"
+	"io"
+	"strings"
+)
 
 // Formatter formats data for output to a writer.
 type Formatter interface {
 	Format(w io.Writer, data interface{}) error
 }
 
+// Formats lists the output formats every command supports.
+var Formats = []string{"json", "table", "yaml"}
+
 // formatters maps format names to their Formatter implementation.
 var formatters = map[string]Formatter{
 	"json":  &JSONFormatter{},
@@ -14,11 +21,15 @@ var formatters = map[string]Formatter{
 	"yaml":  &YAMLFormatter{},
 }
 
-// GetFormatter returns the Formatter for the given format name.
-// Falls back to JSON if the format is unknown.
-func GetFormatter(format string) Formatter {
-	if f, ok := formatters[format]; ok {
-		return f
+// GetFormatter returns the Formatter for the given format name. An empty
+// name selects JSON; unknown names are an error.
+func GetFormatter(format string) (Formatter, error) {
+	if format == "" {
+		format = "json"
+	}
+	f, ok := formatters[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q (valid: %s)", format, strings.Join(Formats, ", "))
 	}
-	return formatters["json"]
+	return f, nil
 }
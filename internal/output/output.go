@@ -9,14 +9,33 @@ import (
 	"github.com/spf13/viper"
 )
 
+// last is the data most recently passed to Print.
+var last interface{}
+
 // Print writes data to stdout in the format specified by the --format flag.
 func Print(data interface{}) error {
+	last = data
 	return Fprint(os.Stdout, viper.GetString("format"), data)
 }
 
+// Last returns the data most recently passed to Print, so that hepic shell
+// can refer to the previous result. ResetLast clears it.
+func Last() interface{} {
+	return last
+}
+
+// ResetLast forgets the data recorded by Print.
+func ResetLast() {
+	last = nil
+}
+
 // Fprint writes data to the given writer in the specified format.
 func Fprint(w io.Writer, format string, data interface{}) error {
-	return GetFormatter(format).Format(w, data)
+	f, err := GetFormatter(format)
+	if err != nil {
+		return err
+	}
+	return f.Format(w, data)
 }
 
 // PrintError writes a structured error to stderr as JSON.
@@ -308,3 +308,14 @@ func TestDefaultFormatIsJSON(t *testing.T) {
 		t.Fatalf("default format should be JSON: %v", err)
 	}
 }
+
+func TestUnknownFormat(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Fprint(buf, "csv", map[string]string{"key": "val"})
+	if err == nil || !strings.Contains(err.Error(), `unknown format "csv"`) {
+		t.Errorf("expected unknown format error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
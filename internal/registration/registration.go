@@ -0,0 +1,469 @@
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/call"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/sip"
+)
+
+// NewSearchParams builds a message search restricted to registration
+// transactions. user filters on the From/To user of the REGISTER.
+func NewSearchParams(from, to, user, callID string) (call.SearchParams, error) {
+	params, err := call.NewSearchParams(from, to, "", "", callID)
+	if err != nil {
+		return params, err
+	}
+	params.Param["transaction"] = map[string]interface{}{
+		"call":         false,
+		"registration": true,
+		"rest":         false,
+	}
+	if user != "" {
+		params.Param["orlogic"] = map[string]interface{}{
+			"from_user": user,
+			"to_user":   user,
+		}
+	}
+	return params, nil
+}
+
+// Search retrieves registration messages via POST /search/call/message.
+func Search(ctx context.Context, client *api.Client, params call.SearchParams) ([]models.TableSIPRegistrationAllV2, error) {
+	raw, err := call.SearchMessage(ctx, client, params)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data []models.TableSIPRegistrationAllV2 `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode registration search response: %w", err)
+	}
+	return resp.Data, nil
+}
+
+// Result classifies the outcome of a REGISTER transaction.
+const (
+	ResultSuccess      = "success"
+	ResultUnregistered = "unregistered"
+	ResultChallenged   = "challenged"
+	ResultFailed       = "failed"
+	ResultUnanswered   = "unanswered"
+)
+
+// Results lists the supported --result values.
+var Results = []string{ResultSuccess, ResultUnregistered, ResultChallenged, ResultFailed, ResultUnanswered}
+
+// Registration is one REGISTER transaction (a Call-ID and CSeq pair) with its
+// final outcome.
+type Registration struct {
+	Time             time.Time `json:"time"`
+	User             string    `json:"user"`
+	Domain           string    `json:"domain"`
+	CallID           string    `json:"call_id"`
+	CSeq             int       `json:"cseq"`
+	Status           int       `json:"status"`
+	Result           string    `json:"result"`
+	Contact          string    `json:"contact"`
+	RequestedExpires int       `json:"requested_expires"`
+	GrantedExpires   int       `json:"granted_expires"`
+	SourceIP         string    `json:"source_ip"`
+	SourcePort       int       `json:"source_port"`
+	SourceAlias      string    `json:"source_alias,omitempty"`
+	UserAgent        string    `json:"user_agent"`
+	Authenticated    bool      `json:"authenticated"`
+	NAT              bool      `json:"nat"`
+}
+
+// AOR returns the address of record as user@domain.
+func (r Registration) AOR() string {
+	return r.User + "@" + r.Domain
+}
+
+// Messages parses the SIP payload of registration rows. Rows without a
+// parsable payload are skipped.
+func Messages(rows []models.TableSIPRegistrationAllV2) []*sip.Message {
+	msgs := make([]*sip.Message, 0, len(rows))
+	for _, row := range rows {
+		payload := row.Message
+		if payload == "" {
+			payload = row.Data
+		}
+		m, err := sip.Parse(payload)
+		if err != nil {
+			continue
+		}
+		m.SrcIP, m.SrcPort = row.SourceIP, int(row.SourcePort)
+		m.DstIP, m.DstPort = row.DestinationIP, int(row.DestinationPort)
+		if row.CreateTs > 0 {
+			m.Timestamp = tsToTime(row.CreateTs)
+		}
+		msgs = append(msgs, m)
+	}
+	sip.SortByTime(msgs)
+	return msgs
+}
+
+// tsToTime converts create_ts, which is stored in micro- or milliseconds.
+func tsToTime(ts uint64) time.Time {
+	if ts > 1e15 {
+		return time.UnixMicro(int64(ts)).UTC()
+	}
+	return time.UnixMilli(int64(ts)).UTC()
+}
+
+// Build pairs REGISTER requests with their final responses. aliases resolves
+// a source IP and port to an alias name and may be nil.
+func Build(msgs []*sip.Message, aliases func(ip string, port int) string) []Registration {
+	type tx struct {
+		req   *sip.Message
+		final *sip.Message
+	}
+	var order []string
+	txs := make(map[string]*tx)
+
+	for _, m := range msgs {
+		seq, method, ok := m.CSeq()
+		if !ok || method != "REGISTER" {
+			continue
+		}
+		key := m.CallID() + "|" + strconv.Itoa(seq)
+		t, exists := txs[key]
+		if !exists {
+			t = &tx{}
+			txs[key] = t
+			order = append(order, key)
+		}
+		switch {
+		case m.IsRequest():
+			if t.req == nil {
+				t.req = m
+			}
+		case m.StatusCode >= 200:
+			t.final = m
+		}
+	}
+
+	regs := make([]Registration, 0, len(order))
+	for _, key := range order {
+		t := txs[key]
+		if t.req == nil {
+			continue
+		}
+		regs = append(regs, newRegistration(t.req, t.final, aliases))
+	}
+	return regs
+}
+
+func newRegistration(req, final *sip.Message, aliases func(ip string, port int) string) Registration {
+	seq, _, _ := req.CSeq()
+	aor := sip.URI(req.Header("To"))
+	contact := ""
+	if contacts := req.Values("Contact"); len(contacts) > 0 {
+		contact = contacts[0]
+	}
+
+	r := Registration{
+		Time:             req.Timestamp,
+		User:             uriUser(aor),
+		Domain:           sip.URIHost(aor),
+		CallID:           req.CallID(),
+		CSeq:             seq,
+		Contact:          sip.URI(contact),
+		RequestedExpires: expires(req, contact),
+		SourceIP:         req.SrcIP,
+		SourcePort:       req.SrcPort,
+		UserAgent:        req.Header("User-Agent"),
+		Authenticated:    req.Has("Authorization") || req.Has("Proxy-Authorization"),
+		NAT:              behindNAT(req.SrcIP, sip.URIHost(sip.URI(contact))),
+	}
+
+	if aliases != nil {
+		r.SourceAlias = aliases(req.SrcIP, req.SrcPort)
+	}
+
+	if final == nil {
+		r.Result = ResultUnanswered
+		return r
+	}
+	r.Status = final.StatusCode
+	switch {
+	case final.StatusCode >= 200 && final.StatusCode < 300:
+		r.GrantedExpires = grantedExpires(final, r.Contact, r.RequestedExpires)
+		r.Result = ResultSuccess
+		if r.GrantedExpires == 0 {
+			r.Result = ResultUnregistered
+		}
+	case final.StatusCode == 401 || final.StatusCode == 407:
+		r.Result = ResultChallenged
+	default:
+		r.Result = ResultFailed
+	}
+	return r
+}
+
+// expires returns the expiry requested for a contact: its expires parameter,
+// else the Expires header, else the RFC 3261 default of 3600 seconds.
+func expires(m *sip.Message, contact string) int {
+	if v := sip.Param(contact, "expires"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(m.Header("Expires"))); err == nil {
+		return n
+	}
+	return 3600
+}
+
+// grantedExpires finds the expiry the registrar granted for the registered
+// contact in a 2xx response.
+func grantedExpires(resp *sip.Message, contactURI string, requested int) int {
+	for _, c := range resp.Values("Contact") {
+		if sip.URI(c) == contactURI {
+			return expires(resp, c)
+		}
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(resp.Header("Expires"))); err == nil {
+		return n
+	}
+	return requested
+}
+
+func uriUser(uri string) string {
+	rest := uri
+	if i := strings.Index(rest, ":"); i >= 0 {
+		rest = rest[i+1:]
+	}
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		return rest[:i]
+	}
+	return ""
+}
+
+// behindNAT reports whether the Contact host is an IP address that differs
+// from the packet source and is private.
+func behindNAT(srcIP, contactHost string) bool {
+	host, err := netip.ParseAddr(contactHost)
+	if err != nil {
+		return false
+	}
+	src, err := netip.ParseAddr(srcIP)
+	if err != nil {
+		return host.IsPrivate()
+	}
+	return host != src && host.IsPrivate()
+}
+
+// Stats aggregates registrations for one group.
+type Stats struct {
+	UserAgent      string  `json:"user_agent,omitempty"`
+	SourceAlias    string  `json:"source_alias,omitempty"`
+	SourceIP       string  `json:"source_ip,omitempty"`
+	User           string  `json:"user,omitempty"`
+	Registers      int     `json:"registers"`
+	Success        int     `json:"success"`
+	Unregisters    int     `json:"unregisters"`
+	Challenged     int     `json:"challenged"`
+	ChallengeLoops int     `json:"challenge_loops"`
+	Failed         int     `json:"failed"`
+	Unanswered     int     `json:"unanswered"`
+	SuccessRate    float64 `json:"success_rate"`
+	ExpiresMin     int     `json:"expires_min"`
+	ExpiresAvg     int     `json:"expires_avg"`
+	ExpiresMax     int     `json:"expires_max"`
+	Users          int     `json:"users"`
+	ContactChurn   int     `json:"contact_churn"`
+	NATContacts    int     `json:"nat_contacts"`
+	StaleDevices   int     `json:"stale_devices"`
+}
+
+// GroupKeys lists the supported --group-by values.
+var GroupKeys = []string{"user-agent", "alias", "source-ip", "user"}
+
+func groupValue(r Registration, key string) string {
+	switch key {
+	case "user-agent":
+		return r.UserAgent
+	case "alias":
+		if r.SourceAlias != "" {
+			return r.SourceAlias
+		}
+		return r.SourceIP
+	case "source-ip":
+		return r.SourceIP
+	case "user":
+		return r.AOR()
+	}
+	return ""
+}
+
+// Aggregate computes statistics per group. A device is stale when its last
+// successful registration expired before end without being renewed.
+func Aggregate(regs []Registration, groupBy []string, end time.Time) ([]Stats, error) {
+	for _, key := range groupBy {
+		valid := false
+		for _, k := range GroupKeys {
+			valid = valid || k == key
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown group %q (valid: %s)", key, strings.Join(GroupKeys, ", "))
+		}
+	}
+
+	type acc struct {
+		stats    Stats
+		expSum   int
+		expN     int
+		contacts map[string]map[string]bool
+		lastOK   map[string]Registration
+		loops    map[string]int
+	}
+	var order []string
+	groups := make(map[string]*acc)
+
+	for _, r := range regs {
+		parts := make([]string, len(groupBy))
+		for i, key := range groupBy {
+			parts[i] = groupValue(r, key)
+		}
+		gk := strings.Join(parts, "\x00")
+		a, ok := groups[gk]
+		if !ok {
+			a = &acc{
+				contacts: make(map[string]map[string]bool),
+				lastOK:   make(map[string]Registration),
+				loops:    make(map[string]int),
+			}
+			for i, key := range groupBy {
+				switch key {
+				case "user-agent":
+					a.stats.UserAgent = parts[i]
+				case "alias":
+					a.stats.SourceAlias = parts[i]
+				case "source-ip":
+					a.stats.SourceIP = parts[i]
+				case "user":
+					a.stats.User = parts[i]
+				}
+			}
+			groups[gk] = a
+			order = append(order, gk)
+		}
+
+		s := &a.stats
+		s.Registers++
+		aor := r.AOR()
+		switch r.Result {
+		case ResultSuccess:
+			s.Success++
+			a.loops[aor] = 0
+			if a.contacts[aor] == nil {
+				a.contacts[aor] = make(map[string]bool)
+			}
+			a.contacts[aor][r.Contact] = true
+			a.lastOK[aor] = r
+			a.expSum += r.GrantedExpires
+			a.expN++
+			if s.ExpiresMin == 0 || r.GrantedExpires < s.ExpiresMin {
+				s.ExpiresMin = r.GrantedExpires
+			}
+			if r.GrantedExpires > s.ExpiresMax {
+				s.ExpiresMax = r.GrantedExpires
+			}
+		case ResultUnregistered:
+			s.Unregisters++
+			delete(a.lastOK, aor)
+		case ResultChallenged:
+			s.Challenged++
+			// A challenge to a request that already carried credentials
+			// means the credentials were rejected.
+			if r.Authenticated {
+				a.loops[aor]++
+				if a.loops[aor] == 1 {
+					s.ChallengeLoops++
+				}
+			}
+		case ResultFailed:
+			s.Failed++
+		case ResultUnanswered:
+			s.Unanswered++
+		}
+		if r.NAT {
+			s.NATContacts++
+		}
+		if _, seen := a.contacts[aor]; !seen {
+			a.contacts[aor] = make(map[string]bool)
+		}
+	}
+
+	out := make([]Stats, 0, len(order))
+	for _, gk := range order {
+		a := groups[gk]
+		s := a.stats
+		if s.Registers > 0 {
+			s.SuccessRate = float64(s.Success+s.Unregisters) / float64(s.Registers)
+		}
+		if a.expN > 0 {
+			s.ExpiresAvg = a.expSum / a.expN
+		}
+		s.Users = len(a.contacts)
+		for _, contacts := range a.contacts {
+			if len(contacts) > 1 {
+				s.ContactChurn += len(contacts) - 1
+			}
+		}
+		for _, r := range a.lastOK {
+			if r.GrantedExpires > 0 && r.Time.Add(time.Duration(r.GrantedExpires)*time.Second).Before(end) {
+				s.StaleDevices++
+			}
+		}
+		out = append(out, s)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Registers > out[j].Registers })
+	return out, nil
+}
+
+// Stale returns the last successful registration of every AOR whose binding
+// expired before end without being renewed or removed.
+func Stale(regs []Registration, end time.Time) []Registration {
+	last := make(map[string]Registration)
+	seen := make(map[string]bool)
+	var order []string
+	for _, r := range regs {
+		aor := r.AOR()
+		switch r.Result {
+		case ResultSuccess:
+			if !seen[aor] {
+				seen[aor] = true
+				order = append(order, aor)
+			}
+			last[aor] = r
+		case ResultUnregistered:
+			delete(last, aor)
+		}
+	}
+
+	stale := []Registration{}
+	for _, aor := range order {
+		r, ok := last[aor]
+		if !ok || r.GrantedExpires <= 0 {
+			continue
+		}
+		if r.Time.Add(time.Duration(r.GrantedExpires) * time.Second).Before(end) {
+			stale = append(stale, r)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,195 @@
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/sip"
+)
+
+var t0 = time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+func register(t *testing.T, offset time.Duration, callID string, cseq, expires int, contact string, auth bool) *sip.Message {
+	t.Helper()
+	lines := []string{
+		"REGISTER sip:example.com SIP/2.0",
+		"Via: SIP/2.0/UDP 203.0.113.5;branch=z9hG4bK" + callID,
+		"From: <sip:1001@example.com>;tag=x",
+		"To: <sip:1001@example.com>",
+		"Call-ID: " + callID,
+		"CSeq: " + itoa(cseq) + " REGISTER",
+		"Contact: <" + contact + ">",
+		"Expires: " + itoa(expires),
+		"User-Agent: Phone/1.0",
+	}
+	if auth {
+		lines = append(lines, `Authorization: Digest username="1001"`)
+	}
+	return parse(t, offset, "203.0.113.5", lines)
+}
+
+func response(t *testing.T, offset time.Duration, callID string, cseq, code int, extra ...string) *sip.Message {
+	t.Helper()
+	lines := append([]string{
+		"SIP/2.0 " + itoa(code) + " Reason",
+		"Call-ID: " + callID,
+		"CSeq: " + itoa(cseq) + " REGISTER",
+		"To: <sip:1001@example.com>;tag=r",
+	}, extra...)
+	return parse(t, offset, "198.51.100.1", lines)
+}
+
+func parse(t *testing.T, offset time.Duration, src string, lines []string) *sip.Message {
+	m, err := sip.Parse(strings.Join(lines, "\r\n") + "\r\n\r\n")
+	if err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	m.Timestamp = t0.Add(offset)
+	m.SrcIP = src
+	return m
+}
+
+func itoa(n int) string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
+
+func TestBuild(t *testing.T) {
+	msgs := []*sip.Message{
+		register(t, 0, "r1", 1, 3600, "sip:1001@192.168.1.10:5060", false),
+		response(t, 10*time.Millisecond, "r1", 1, 401),
+		register(t, 20*time.Millisecond, "r1", 2, 3600, "sip:1001@192.168.1.10:5060", true),
+		response(t, 30*time.Millisecond, "r1", 2, 200, "Contact: <sip:1001@192.168.1.10:5060>;expires=600"),
+	}
+
+	regs := Build(msgs, func(ip string, port int) string {
+		if ip == "203.0.113.5" {
+			return "office"
+		}
+		return ""
+	})
+	if len(regs) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(regs))
+	}
+	if regs[0].Result != ResultChallenged || regs[0].Status != 401 {
+		t.Errorf("expected first transaction challenged, got %+v", regs[0])
+	}
+	r := regs[1]
+	if r.Result != ResultSuccess || r.GrantedExpires != 600 || r.RequestedExpires != 3600 {
+		t.Errorf("unexpected second transaction: %+v", r)
+	}
+	if r.User != "1001" || r.Domain != "example.com" || r.SourceAlias != "office" || r.UserAgent != "Phone/1.0" {
+		t.Errorf("unexpected identity fields: %+v", r)
+	}
+	if !r.NAT || !r.Authenticated {
+		t.Errorf("expected NAT'd and authenticated registration: %+v", r)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	msgs := []*sip.Message{
+		register(t, 0, "a", 1, 60, "sip:1001@192.168.1.10", true),
+		response(t, time.Millisecond, "a", 1, 401),
+		register(t, time.Second, "a", 2, 60, "sip:1001@192.168.1.10", true),
+		response(t, time.Second+time.Millisecond, "a", 2, 200),
+		register(t, 2*time.Second, "a", 3, 60, "sip:1001@192.168.1.11", true),
+		response(t, 2*time.Second+time.Millisecond, "a", 3, 200),
+		register(t, 3*time.Second, "b", 1, 60, "sip:1001@192.168.1.11", true),
+		response(t, 3*time.Second+time.Millisecond, "b", 1, 403),
+	}
+	regs := Build(msgs, nil)
+
+	stats, err := Aggregate(regs, []string{"user-agent"}, t0.Add(10*time.Minute))
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(stats))
+	}
+	s := stats[0]
+	if s.UserAgent != "Phone/1.0" || s.Registers != 4 || s.Success != 2 || s.Challenged != 1 || s.Failed != 1 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.ChallengeLoops != 1 {
+		t.Errorf("expected a challenge loop for rejected credentials, got %d", s.ChallengeLoops)
+	}
+	if s.ContactChurn != 1 || s.NATContacts != 4 || s.StaleDevices != 1 || s.Users != 1 {
+		t.Errorf("unexpected churn/NAT/stale: %+v", s)
+	}
+	if s.ExpiresMin != 60 || s.ExpiresAvg != 60 || s.ExpiresMax != 60 {
+		t.Errorf("unexpected expires: %+v", s)
+	}
+
+	if _, err := Aggregate(regs, []string{"bogus"}, t0); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
+
+func TestStale(t *testing.T) {
+	msgs := []*sip.Message{
+		register(t, 0, "a", 1, 60, "sip:1001@192.168.1.10", false),
+		response(t, time.Millisecond, "a", 1, 200),
+		register(t, time.Second, "a", 2, 0, "sip:1001@192.168.1.10", false),
+		response(t, time.Second+time.Millisecond, "a", 2, 200),
+	}
+	regs := Build(msgs, nil)
+	if regs[1].Result != ResultUnregistered {
+		t.Fatalf("expected de-registration, got %+v", regs[1])
+	}
+	if stale := Stale(regs, t0.Add(time.Hour)); len(stale) != 0 {
+		t.Errorf("de-registered device must not be stale, got %+v", stale)
+	}
+	if stale := Stale(regs[:1], t0.Add(time.Hour)); len(stale) != 1 {
+		t.Errorf("expected expired binding to be stale, got %+v", stale)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/call/message" {
+			t.Errorf("expected path /search/call/message, got %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		param := body["param"].(map[string]interface{})
+		tx, _ := param["transaction"].(map[string]interface{})
+		if tx["registration"] != true {
+			t.Errorf("expected registration transaction filter, got %v", param["transaction"])
+		}
+		orlogic, _ := param["orlogic"].(map[string]interface{})
+		if orlogic["to_user"] != "1001" {
+			t.Errorf("expected user filter, got %v", param["orlogic"])
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []models.TableSIPRegistrationAllV2{{
+				Callid:   "a",
+				CreateTs: uint64(t0.UnixMicro()),
+				SourceIP: "203.0.113.5",
+				Message:  "REGISTER sip:example.com SIP/2.0\r\nCall-ID: a\r\nCSeq: 1 REGISTER\r\n\r\n",
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	client := api.NewClientWith(srv.URL, "test-token")
+	params, err := NewSearchParams("2025-01-01", "", "1001", "")
+	if err != nil {
+		t.Fatalf("NewSearchParams failed: %v", err)
+	}
+	rows, err := Search(context.Background(), client, params)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	msgs := Messages(rows)
+	if len(msgs) != 1 || msgs[0].Method != "REGISTER" || !msgs[0].Timestamp.Equal(t0) {
+		t.Errorf("unexpected messages: %+v", msgs)
+	}
+}
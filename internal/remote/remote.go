@@ -0,0 +1,187 @@
+// Package remote queries the remote log servers (Loki) HEPIC proxies
+// under /search/remote: label names, label values and log lines.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/ops"
+)
+
+// Labels lists the label names known to the remote server. server selects
+// one of the configured remote servers; "" is the default one.
+func Labels(ctx context.Context, client *api.Client, server string) ([]string, error) {
+	var labels []string
+	if err := client.Get(ctx, "/search/remote/label"+query("server", server), &labels); err != nil {
+		return nil, err
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// Values lists the values of a label.
+func Values(ctx context.Context, client *api.Client, label, server string) ([]string, error) {
+	var values []string
+	if err := client.Get(ctx, "/search/remote/values"+query("label", label, "server", server), &values); err != nil {
+		return nil, err
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+// Status reports whether the remote server is reachable.
+func Status(ctx context.Context, client *api.Client, server string) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := client.Get(ctx, "/search/remote/status"+query("server", server), &result)
+	return result, err
+}
+
+// query encodes the non-empty key/value pairs as a query string. The spec
+// documents no parameters for label, values and status, so those requests
+// do not go through ops.
+func query(pairs ...string) string {
+	v := url.Values{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			v.Set(pairs[i], pairs[i+1])
+		}
+	}
+	if len(v) == 0 {
+		return ""
+	}
+	return "?" + v.Encode()
+}
+
+// NewQuery builds the body of a log query: a LogQL expression such as
+// {job="kamailio"} |= "error", a time range and a line limit.
+func NewQuery(expr string, from, to time.Time, limit int, server string) models.RemoteObject {
+	param := map[string]interface{}{"search": expr, "limit": limit}
+	if server != "" {
+		param["server"] = server
+	}
+	return models.RemoteObject{
+		Param:     param,
+		Timestamp: map[string]interface{}{"from": from.UnixMilli(), "to": to.UnixMilli()},
+	}
+}
+
+// Query runs a log query via POST /search/remote/data.
+func Query(ctx context.Context, client *api.Client, q models.RemoteObject) (*models.RemoteResponseData, error) {
+	result, raw, err := ops.PostSearchRemoteData(ctx, client, q)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("unexpected response: %.100s", raw)
+	}
+	return result, nil
+}
+
+// Entry is one log line.
+type Entry struct {
+	ID     int64             `json:"id"`
+	Time   time.Time         `json:"time"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Line   string            `json:"line"`
+}
+
+// String formats an entry for streaming: time, labels and line.
+func (e Entry) String() string {
+	var labels []string
+	for k, v := range e.Labels {
+		labels = append(labels, fmt.Sprintf("%s=%q", k, v))
+	}
+	ts := e.Time.Format("2006-01-02T15:04:05.000Z07:00")
+	if len(labels) == 0 {
+		return ts + " " + e.Line
+	}
+	sort.Strings(labels)
+	return fmt.Sprintf("%s {%s} %s", ts, strings.Join(labels, ", "), e.Line)
+}
+
+// Entries converts a query response into log lines, oldest first. The
+// server puts the line in custom_1 and the stream labels, as a JSON
+// object, in custom_2.
+func Entries(resp *models.RemoteResponseData) []Entry {
+	entries := make([]Entry, 0, len(resp.Data))
+	for _, row := range resp.Data {
+		e := Entry{ID: toInt(row["id"]), Time: toTime(toInt(row["micro_ts"]))}
+		e.Line, _ = row["custom_1"].(string)
+		if s, ok := row["custom_2"].(string); ok && s != "" {
+			var labels map[string]interface{}
+			if json.Unmarshal([]byte(s), &labels) == nil {
+				e.Labels = make(map[string]string, len(labels))
+				for k, v := range labels {
+					e.Labels[k] = fmt.Sprint(v)
+				}
+			}
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries
+}
+
+func toInt(v interface{}) int64 {
+	switch v := v.(type) {
+	case float64:
+		return int64(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	}
+	return 0
+}
+
+// toTime reads micro_ts, which despite its name is in milliseconds;
+// microseconds are accepted too.
+func toTime(ts int64) time.Time {
+	if ts > 1e14 {
+		return time.UnixMicro(ts)
+	}
+	return time.UnixMilli(ts)
+}
+
+// Follower remembers which lines were printed, so that polling again from
+// the newest timestamp seen does not repeat lines.
+type Follower struct {
+	// Since is the start of the next query.
+	Since time.Time
+	seen  map[string]bool
+}
+
+// NewFollower starts following at since.
+func NewFollower(since time.Time) *Follower {
+	return &Follower{Since: since, seen: make(map[string]bool)}
+}
+
+// Next returns the entries not returned before and moves Since to the
+// newest of them. Lines sharing that timestamp are remembered, as the
+// next query includes it.
+func (f *Follower) Next(entries []Entry) []Entry {
+	var fresh []Entry
+	for _, e := range entries {
+		if e.Time.Before(f.Since) || f.seen[key(e)] {
+			continue
+		}
+		if e.Time.After(f.Since) {
+			f.Since = e.Time
+			f.seen = make(map[string]bool)
+		}
+		f.seen[key(e)] = true
+		fresh = append(fresh, e)
+	}
+	return fresh
+}
+
+func key(e Entry) string {
+	return fmt.Sprintf("%d\x00%d\x00%s", e.ID, e.Time.UnixNano(), e.Line)
+}
@@ -0,0 +1,98 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+)
+
+func TestLabelsAndValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/remote/label":
+			if got := r.URL.Query().Get("server"); got != "loki2" {
+				t.Errorf("expected server loki2, got %q", got)
+			}
+			io.WriteString(w, `["job","host"]`)
+		case "/search/remote/values":
+			if got := r.URL.Query().Get("label"); got != "job" {
+				t.Errorf("expected label job, got %q", got)
+			}
+			if r.URL.Query().Has("server") {
+				t.Error("expected no server parameter")
+			}
+			io.WriteString(w, `["kamailio","asterisk"]`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClientWith(server.URL, "test-token")
+
+	labels, err := Labels(context.Background(), client, "loki2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 2 || labels[0] != "host" {
+		t.Errorf("expected sorted labels, got %v", labels)
+	}
+	values, err := Values(context.Background(), client, "job", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || values[0] != "asterisk" {
+		t.Errorf("expected sorted values, got %v", values)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var q models.RemoteObject
+		if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+			t.Fatal(err)
+		}
+		if q.Param["search"] != `{job="x"} |= "error"` || q.Timestamp["from"] != float64(1000) {
+			t.Errorf("unexpected query %+v", q)
+		}
+		io.WriteString(w, `{"Data":[
+			{"id":2,"micro_ts":3000,"custom_1":"second","custom_2":"{\"job\":\"x\"}"},
+			{"id":1,"micro_ts":2000,"custom_1":"first","custom_2":""}
+		]}`)
+	}))
+	defer server.Close()
+
+	q := NewQuery(`{job="x"} |= "error"`, time.UnixMilli(1000), time.UnixMilli(5000), 10, "")
+	resp, err := Query(context.Background(), api.NewClientWith(server.URL, "test-token"), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := Entries(resp)
+	if len(entries) != 2 || entries[0].Line != "first" || entries[1].Labels["job"] != "x" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if !entries[1].Time.Equal(time.UnixMilli(3000)) {
+		t.Errorf("unexpected time %v", entries[1].Time)
+	}
+}
+
+func TestFollower(t *testing.T) {
+	at := func(ms int64, line string) Entry { return Entry{Time: time.UnixMilli(ms), Line: line} }
+	f := NewFollower(time.UnixMilli(1000))
+
+	got := f.Next([]Entry{at(500, "too old"), at(1000, "a"), at(2000, "b"), at(2000, "c")})
+	if len(got) != 3 || !f.Since.Equal(time.UnixMilli(2000)) {
+		t.Fatalf("unexpected first batch %v (since %v)", got, f.Since)
+	}
+	// The next poll starts at 2000 and returns b and c again.
+	got = f.Next([]Entry{at(2000, "b"), at(2000, "c"), at(2000, "d"), at(3000, "e")})
+	if len(got) != 2 || got[0].Line != "d" || got[1].Line != "e" {
+		t.Errorf("expected only d and e, got %v", got)
+	}
+}
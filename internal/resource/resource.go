@@ -0,0 +1,513 @@
+// Package resource describes the HEPIC configuration resources (IP
+// aliases, mappings, scripts, ...) in one table, so commands that work on
+// many kinds at once (backup, compare, apply) list, match, create, update
+// and delete them the same way.
+package resource
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"hepic-cli/internal/admin"
+	"hepic-cli/internal/agent"
+	"hepic-cli/internal/alias"
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/config_resources"
+	"hepic-cli/internal/dashboard"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/recording"
+	"hepic-cli/internal/script"
+	"hepic-cli/internal/user"
+)
+
+// Item is one configuration object as the server returns it.
+type Item = map[string]interface{}
+
+// Resource describes how one kind of configuration is listed, matched
+// and restored.
+type Resource struct {
+	Name string
+	// Kind is the name used in manifests, e.g. "IPAlias".
+	Kind string
+	// ID is the field holding the server-assigned identifier, used as the
+	// path parameter for updates.
+	ID string
+	// Key identifies an item across instances, so a restore onto a fresh
+	// server can find conflicts without relying on server IDs.
+	Key func(Item) string
+	// Rename is the field changed under the rename conflict policy; empty
+	// if items of this kind cannot be renamed.
+	Rename string
+	// Secrets are fields never written to a backup.
+	Secrets []string
+	// ReadOnly explains why items are backed up but never restored.
+	ReadOnly string
+
+	list     func(ctx context.Context, client *api.Client) ([]Item, error)
+	validate func(item Item, partial bool) error
+	create   func(ctx context.Context, client *api.Client, item Item) (string, error)
+	update   func(ctx context.Context, client *api.Client, id string, item Item) error
+	delete   func(ctx context.Context, client *api.Client, id string) error
+}
+
+// Resources lists every resource in dependency order: mappings before the
+// scripts and subscriptions that refer to them.
+var Resources = []Resource{
+	{
+		Name: "ipaliases", Kind: "IPAlias", ID: "uuid", Key: aliasKey,
+		list:     listOf(config_resources.ListAliases),
+		validate: model[models.AliasSwaggerStruct],
+		create: func(ctx context.Context, c *api.Client, it Item) (string, error) {
+			return "", discard(config_resources.CreateAlias(ctx, c, it))
+		},
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			return discard(config_resources.UpdateAlias(ctx, c, id, it))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(config_resources.DeleteAlias(ctx, c, id))
+		},
+	},
+	{
+		Name: "mappings", Kind: "Mapping", ID: "guid", Key: fields("hepid", "profile"),
+		list: listOf(config_resources.ListMappings),
+		validate: func(it Item, partial bool) error {
+			var m models.MappingSchema
+			if err := convert(it, &m); err != nil {
+				return err
+			}
+			return config_resources.ValidateMapping(m, partial)
+		},
+		create: func(ctx context.Context, c *api.Client, it Item) (string, error) {
+			return "", discard(config_resources.CreateMapping(ctx, c, it))
+		},
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			return discard(config_resources.UpdateMapping(ctx, c, id, it))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(config_resources.DeleteMapping(ctx, c, id))
+		},
+	},
+	{
+		Name: "protocols", Kind: "Protocol", Key: fields("hepid", "profile"),
+		ReadOnly: "protocols are derived from mappings",
+		list:     listOf(config_resources.ListAllProtocols),
+	},
+	{
+		Name: "hepsubs", Kind: "Hepsub", ID: "guid", Key: fields("hepid", "profile"),
+		list: listOf(config_resources.ListHepsub),
+		validate: func(it Item, partial bool) error {
+			var h models.HepsubSchema
+			if err := convert(it, &h); err != nil {
+				return err
+			}
+			return config_resources.ValidateHepsub(h, partial)
+		},
+		create: func(ctx context.Context, c *api.Client, it Item) (string, error) {
+			return "", discard(config_resources.CreateHepsub(ctx, c, it))
+		},
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			return discard(config_resources.UpdateHepsub(ctx, c, id, it))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(config_resources.DeleteHepsub(ctx, c, id))
+		},
+	},
+	{
+		Name: "scripts", Kind: "Script", ID: "uuid", Key: fields("type", "hepid", "profile"),
+		list: listOf(script.List),
+		validate: func(it Item, partial bool) error {
+			var sc models.ScriptDataStruct
+			if err := convert(it, &sc); err != nil {
+				return err
+			}
+			if partial {
+				return sc.ValidatePartial()
+			}
+			return script.Validate(sc)
+		},
+		create: func(ctx context.Context, c *api.Client, it Item) (string, error) {
+			return "", discard(script.Create(ctx, c, it))
+		},
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			return discard(script.Update(ctx, c, id, it))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(script.Delete(ctx, c, id))
+		},
+	},
+	{
+		Name: "settings", Kind: "UserSetting", ID: "guid", Key: fields("username", "category", "param"),
+		list: listOf(admin.ListSettings),
+		create: func(ctx context.Context, c *api.Client, it Item) (string, error) {
+			return "", discard(admin.CreateSetting(ctx, c, it))
+		},
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			return discard(admin.UpdateSetting(ctx, c, id, it))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(admin.DeleteSetting(ctx, c, id))
+		},
+	},
+	{
+		Name: "advanced", Kind: "AdvancedSetting", ID: "guid", Key: fields("category", "param"),
+		list:     listOf(admin.ListAdvanced),
+		validate: model[models.GlobalSettingsStruct],
+		create: func(ctx context.Context, c *api.Client, it Item) (string, error) {
+			return "", discard(admin.CreateAdvanced(ctx, c, it))
+		},
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			return discard(admin.UpdateAdvanced(ctx, c, id, it))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(admin.DeleteAdvanced(ctx, c, id))
+		},
+	},
+	{
+		Name: "dashboards", Kind: "Dashboard", ID: "id", Key: fields("id"), Rename: "id",
+		list: listDashboards,
+		validate: func(it Item, partial bool) error {
+			return dashboard.Validate(it)
+		},
+		create: func(ctx context.Context, c *api.Client, it Item) (string, error) {
+			return "", discard(dashboard.Create(ctx, c, fmt.Sprint(it["id"]), it))
+		},
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			return discard(dashboard.Store(ctx, c, id, it))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(dashboard.Delete(ctx, c, id))
+		},
+	},
+	{
+		Name: "agents", Kind: "Agent", ID: "uuid", Key: fields("type", "node", "host", "port"),
+		list: listOf(agent.List),
+		// Agents register themselves; a restore can only update them.
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			return discard(agent.Update(ctx, c, id, it))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(agent.Delete(ctx, c, id))
+		},
+	},
+	{
+		Name: "interceptions", Kind: "Interception", ID: "uuid", Key: interceptionKey,
+		list:     listOf(recording.ListInterceptions),
+		validate: model[models.InterceptionsStruct],
+		create: func(ctx context.Context, c *api.Client, it Item) (string, error) {
+			var in models.InterceptionsStruct
+			if err := convert(it, &in); err != nil {
+				return "", err
+			}
+			return "", discard(recording.CreateInterception(ctx, c, in))
+		},
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			var in models.InterceptionsStruct
+			if err := convert(it, &in); err != nil {
+				return err
+			}
+			return discard(recording.UpdateInterception(ctx, c, id, in))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(recording.DeleteInterception(ctx, c, id))
+		},
+	},
+	{
+		Name: "users", Kind: "User", ID: "guid", Key: fields("username"), Rename: "username",
+		Secrets: []string{"password", "hash", "token", "secret"},
+		list:    listOf(user.List),
+		validate: func(it Item, partial bool) error {
+			if !partial && it["password"] == nil {
+				// create sets a random one.
+				it = Clone(it)
+				it["password"] = "-"
+			}
+			return model[models.CreateUserStruct](it, partial)
+		},
+		// Passwords are not backed up; new users get a random one that an
+		// admin has to reset.
+		create: func(ctx context.Context, c *api.Client, it Item) (string, error) {
+			it = Clone(it)
+			pw, err := randomPassword()
+			if err != nil {
+				return "", err
+			}
+			it["password"] = pw
+			return "password must be reset", discard(user.Create(ctx, c, it))
+		},
+		update: func(ctx context.Context, c *api.Client, id string, it Item) error {
+			return discard(user.Update(ctx, c, id, it))
+		},
+		delete: func(ctx context.Context, c *api.Client, id string) error {
+			return discard(user.Delete(ctx, c, id))
+		},
+	},
+}
+
+// List retrieves the resource's items from the server.
+func (r Resource) List(ctx context.Context, client *api.Client) ([]Item, error) {
+	return r.list(ctx, client)
+}
+
+// Validate decodes item into the resource's model and checks it: fully
+// for a create, without the required fields if partial (for an update).
+// Fields the model does not have, such as those the server adds, are
+// allowed. Resources without a model accept any item.
+func (r Resource) Validate(item Item, partial bool) error {
+	if r.validate == nil {
+		return nil
+	}
+	return r.validate(item, partial)
+}
+
+// Create validates and creates an item and returns any note about the
+// result.
+func (r Resource) Create(ctx context.Context, client *api.Client, item Item) (string, error) {
+	if r.create == nil {
+		return "", fmt.Errorf("%s cannot be created", r.Name)
+	}
+	if err := r.Validate(item, false); err != nil {
+		return "", err
+	}
+	return r.create(ctx, client, item)
+}
+
+// Update validates item and replaces the item with the given server ID.
+func (r Resource) Update(ctx context.Context, client *api.Client, id string, item Item) error {
+	if r.update == nil {
+		return fmt.Errorf("%s cannot be updated", r.Name)
+	}
+	if err := r.Validate(item, true); err != nil {
+		return err
+	}
+	return r.update(ctx, client, id, item)
+}
+
+// Delete removes the item with the given server ID.
+func (r Resource) Delete(ctx context.Context, client *api.Client, id string) error {
+	if r.delete == nil {
+		return fmt.Errorf("%s cannot be deleted", r.Name)
+	}
+	return r.delete(ctx, client, id)
+}
+
+// CanCreate, CanUpdate and CanDelete report which operations the server
+// supports for the resource.
+func (r Resource) CanCreate() bool { return r.create != nil }
+func (r Resource) CanUpdate() bool { return r.update != nil }
+func (r Resource) CanDelete() bool { return r.delete != nil }
+
+// ItemID returns the server ID of it, or "" if it has none.
+func (r Resource) ItemID(it Item) string {
+	if r.ID == "" || it[r.ID] == nil {
+		return ""
+	}
+	return fmt.Sprint(it[r.ID])
+}
+
+// ByKind returns the resource with the given manifest kind.
+func ByKind(kind string) (Resource, bool) {
+	for _, r := range Resources {
+		if r.Kind == kind {
+			return r, true
+		}
+	}
+	return Resource{}, false
+}
+
+// Names returns the names of all resources.
+func Names() []string {
+	names := make([]string, len(Resources))
+	for i, r := range Resources {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// Select returns the resources named in only (all if empty) minus those
+// in exclude, in restore order.
+func Select(only, exclude []string) ([]Resource, error) {
+	known := make(map[string]bool, len(Resources))
+	for _, r := range Resources {
+		known[r.Name] = true
+	}
+	want := make(map[string]bool)
+	for _, list := range [][]string{only, exclude} {
+		for _, n := range list {
+			if !known[n] {
+				return nil, fmt.Errorf("unknown resource %q (valid: %s)", n, strings.Join(Names(), ", "))
+			}
+		}
+	}
+	for _, n := range only {
+		want[n] = true
+	}
+	skip := make(map[string]bool)
+	for _, n := range exclude {
+		skip[n] = true
+	}
+
+	var selected []Resource
+	for _, r := range Resources {
+		if (len(only) == 0 || want[r.Name]) && !skip[r.Name] {
+			selected = append(selected, r)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no resources selected")
+	}
+	return selected, nil
+}
+
+// listOf adapts a List function returning the raw response.
+func listOf(list func(context.Context, *api.Client) (json.RawMessage, error)) func(context.Context, *api.Client) ([]Item, error) {
+	return func(ctx context.Context, client *api.Client) ([]Item, error) {
+		raw, err := list(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		return Items(raw)
+	}
+}
+
+// listDashboards fetches every dashboard's full configuration; the info
+// endpoint only returns names.
+func listDashboards(ctx context.Context, client *api.Client) ([]Item, error) {
+	infos, err := listOf(dashboard.List)(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(infos))
+	for _, info := range infos {
+		id := fmt.Sprint(info["id"])
+		item, err := dashboard.Fetch(ctx, client, id)
+		if err != nil {
+			return nil, fmt.Errorf("dashboard %s: %w", id, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Items extracts the list of objects from a list response, which is
+// either an array or an object with a data (or Data) array.
+func Items(raw json.RawMessage) ([]Item, error) {
+	var items []Item
+	if err := Decode(raw, &items); err == nil {
+		return items, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := Decode(raw, &obj); err != nil {
+		return nil, fmt.Errorf("unexpected response: %w", err)
+	}
+	for _, k := range []string{"data", "Data"} {
+		data, ok := obj[k]
+		if !ok {
+			continue
+		}
+		if string(data) == "null" {
+			return []Item{}, nil
+		}
+		if err := Decode(data, &items); err != nil {
+			return nil, fmt.Errorf("unexpected response: %s is not a list", k)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected response: no data list")
+}
+
+// fields returns a Key function joining the given fields.
+func fields(names ...string) func(Item) string {
+	return func(it Item) string {
+		parts := make([]string, len(names))
+		for i, n := range names {
+			if v, ok := it[n]; ok && v != nil {
+				parts[i] = fmt.Sprint(v)
+			}
+		}
+		return strings.Join(parts, "/")
+	}
+}
+
+// aliasKey matches aliases on the normalized prefix and port, like the
+// CSV import does.
+func aliasKey(it Item) string {
+	ip := fmt.Sprint(it["ip"])
+	mask, port := toInt(it["mask"]), toInt(it["port"])
+	prefix, err := alias.ParsePrefix(ip, mask, port)
+	if err != nil {
+		return fields("ip", "mask", "port")(it)
+	}
+	return alias.Key(prefix, port)
+}
+
+// interceptionKey matches interceptions on their LIID, or on what they
+// capture when they have none.
+func interceptionKey(it Item) string {
+	if liid := toInt(it["liid"]); liid != 0 {
+		return fmt.Sprint(liid)
+	}
+	return fields("search_ip", "search_caller", "search_callee")(it)
+}
+
+func toInt(v interface{}) int {
+	var n int
+	fmt.Sscan(fmt.Sprint(v), &n)
+	return n
+}
+
+// model validates an item decoded into the models type T.
+func model[T interface {
+	Validate() error
+	ValidatePartial() error
+}](it Item, partial bool) error {
+	var m T
+	if err := convert(it, &m); err != nil {
+		return err
+	}
+	if partial {
+		return m.ValidatePartial()
+	}
+	return m.Validate()
+}
+
+func convert(it Item, v interface{}) error {
+	data, err := json.Marshal(it)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Clone returns a shallow copy of it.
+func Clone(it Item) Item {
+	c := make(Item, len(it))
+	for k, v := range it {
+		c[k] = v
+	}
+	return c
+}
+
+func discard(_ json.RawMessage, err error) error {
+	return err
+}
+
+// Decode unmarshals JSON keeping numbers exact, so IDs and versions
+// survive a round trip.
+func Decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
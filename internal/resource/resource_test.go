@@ -0,0 +1,85 @@
+package resource
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestItems(t *testing.T) {
+	for _, raw := range []string{
+		`[{"uuid":"a"}]`,
+		`{"count":1,"data":[{"uuid":"a"}]}`,
+		`{"Data":[{"uuid":"a"}]}`,
+	} {
+		items, err := Items(json.RawMessage(raw))
+		if err != nil || len(items) != 1 || items[0]["uuid"] != "a" {
+			t.Errorf("Items(%s) = %v, %v", raw, items, err)
+		}
+	}
+	if items, err := Items(json.RawMessage(`{"data":null}`)); err != nil || len(items) != 0 {
+		t.Errorf("expected empty list, got %v %v", items, err)
+	}
+	if _, err := Items(json.RawMessage(`{"message":"ok"}`)); err == nil {
+		t.Error("expected error without a data list")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	rs, err := Select(nil, []string{"users", "agents"})
+	if err != nil || len(rs) != len(Resources)-2 {
+		t.Errorf("unexpected selection %d %v", len(rs), err)
+	}
+	rs, err = Select([]string{"scripts", "mappings"}, nil)
+	if err != nil || len(rs) != 2 || rs[0].Name != "mappings" {
+		t.Errorf("expected restore order, got %v %v", rs, err)
+	}
+	if _, err := Select([]string{"nope"}, nil); err == nil {
+		t.Error("expected error for unknown resource")
+	}
+	if _, err := Select([]string{"users"}, []string{"users"}); err == nil {
+		t.Error("expected error for empty selection")
+	}
+}
+
+func TestNaturalKeys(t *testing.T) {
+	agents, _ := ByKind("Agent")
+	if k := agents.Key(Item{"uuid": "a-1", "type": "hepic", "node": "n1", "host": "10.0.0.1", "port": 9060}); k != "hepic/n1/10.0.0.1/9060" {
+		t.Errorf("unexpected agent key %q", k)
+	}
+	interceptions, _ := ByKind("Interception")
+	if k := interceptions.Key(Item{"uuid": "i-1", "liid": json.Number("42"), "search_ip": "10.0.0.1"}); k != "42" {
+		t.Errorf("unexpected interception key %q", k)
+	}
+	if k := interceptions.Key(Item{"uuid": "i-1", "search_caller": "alice"}); k != "/alice/" {
+		t.Errorf("unexpected interception key %q", k)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		kind    string
+		item    Item
+		partial bool
+		want    string
+	}{
+		{"IPAlias", Item{"uuid": "a-1", "alias": "pbx", "ip": "10.0.0.1", "mask": 32}, false, ""},
+		{"IPAlias", Item{"alias": "pbx", "ip": "10.0.0.1", "mask": 200}, false, "mask"},
+		{"Mapping", Item{"guid": "m-1", "retention": 7}, true, ""},
+		{"Mapping", Item{"retention": 7}, false, "hepid is required"},
+		{"Script", Item{"type": "python", "data": "x"}, false, "lua, javascript"},
+		{"AdvancedSetting", Item{"category": "system"}, false, "param is required"},
+		// The password of a new user is generated on create.
+		{"User", Item{"username": "alice", "email": "a@example.com", "firstname": "Alice", "lastname": "Doe",
+			"department": "ops", "usergroup": "admin", "partid": json.Number("10")}, false, ""},
+		{"User", Item{"username": "alice", "partid": "ten"}, true, "partid"},
+		{"Agent", Item{"anything": true}, false, ""},
+	}
+	for _, tt := range tests {
+		r, _ := ByKind(tt.kind)
+		err := r.Validate(tt.item, tt.partial)
+		if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("%s Validate(%v) = %v; want %q", tt.kind, tt.item, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package script
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/sip"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// API lists the HEPIC scripting functions the sandbox provides. Getters
+// read the current message; setters are recorded as modifications and
+// applied to a copy of the raw message.
+var API = []string{
+	"GetHEPStruct", "GetSIPStruct", "GetRawMessage",
+	"GetHEPProtoType", "GetHEPSrcIP", "GetHEPSrcPort", "GetHEPDstIP", "GetHEPDstPort",
+	"GetHEPTimeSeconds", "GetHEPTimeUseconds", "GetHEPNodeID",
+	"SetRawMessage", "SetSIPHeader", "SetCustomSIPHeader", "SetHEPField",
+	"HashTable", "HashString", "Logp", "Print",
+}
+
+// Modification is a change a script made to a message.
+type Modification struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// Result is the outcome of running a script against one message.
+type Result struct {
+	Message       int            `json:"message"`
+	Summary       string         `json:"summary"`
+	DurationMs    float64        `json:"duration_ms"`
+	Modifications []Modification `json:"modifications"`
+	Logs          []string       `json:"logs"`
+	Error         string         `json:"error,omitempty"`
+	Raw           string         `json:"raw,omitempty"`
+}
+
+// Sandbox runs a Lua script in an embedded VM with stubs for the HEPIC
+// scripting API. Only the base, string, table and math libraries are
+// available; there is no file, OS or network access. Globals, including
+// HashTable entries, persist across messages as they do on the server.
+type Sandbox struct {
+	L         *lua.LState
+	functions []string
+	timeout   time.Duration
+	nodeID    int
+
+	// Per-message state.
+	msg    *sip.Message
+	raw    string
+	result *Result
+	hash   map[string]string
+}
+
+// Functions returns the global functions a Lua script defines at the top
+// level.
+func Functions(source string) ([]string, error) {
+	chunk, err := parse.Parse(strings.NewReader(source), "script")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, stmt := range chunk {
+		def, ok := stmt.(*ast.FuncDefStmt)
+		if !ok {
+			continue
+		}
+		if id, ok := def.Name.Func.(*ast.IdentExpr); ok {
+			names = append(names, id.Value)
+		}
+	}
+	return names, nil
+}
+
+// EntryPoints are the functions the server calls for every message, in
+// order. Names are matched case-insensitively.
+var EntryPoints = []string{"checkRAW", "checkSIP"}
+
+// NewSandbox loads source into a fresh VM. functions are the entry points
+// to call per message; if empty, the EntryPoints the script defines are
+// called. timeout limits each message's run (0 for none).
+func NewSandbox(source string, functions []string, timeout time.Duration, nodeID int) (*Sandbox, error) {
+	defined, err := Functions(source)
+	if err != nil {
+		return nil, fmt.Errorf("lua syntax error: %s", strings.Join(strings.Fields(err.Error()), " "))
+	}
+	if len(functions) == 0 {
+		for _, entry := range EntryPoints {
+			for _, name := range defined {
+				if strings.EqualFold(name, entry) {
+					functions = append(functions, name)
+				}
+			}
+		}
+	}
+	if len(functions) == 0 {
+		return nil, fmt.Errorf("the script defines none of the entry points %s; choose the functions to call with --func", strings.Join(EntryPoints, ", "))
+	}
+
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		fn   lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.fn))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	// The base library can still read files.
+	for _, name := range []string{"dofile", "loadfile", "require", "module"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	s := &Sandbox{L: L, functions: functions, timeout: timeout, nodeID: nodeID, hash: make(map[string]string)}
+	s.register()
+
+	if err := L.DoString(source); err != nil {
+		L.Close()
+		return nil, fmt.Errorf("loading script: %w", err)
+	}
+	for _, name := range functions {
+		if L.GetGlobal(name).Type() != lua.LTFunction {
+			L.Close()
+			return nil, fmt.Errorf("function %q is not defined by the script", name)
+		}
+	}
+	return s, nil
+}
+
+// Close releases the VM.
+func (s *Sandbox) Close() {
+	s.L.Close()
+}
+
+// Run calls the script's entry points for one message.
+func (s *Sandbox) Run(n int, msg *sip.Message) Result {
+	s.msg, s.raw = msg, msg.Raw
+	r := Result{Message: n, Summary: msg.Summary(), Modifications: []Modification{}, Logs: []string{}}
+	s.result = &r
+
+	ctx := context.Background()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	s.L.SetContext(ctx)
+
+	start := time.Now()
+	for _, name := range s.functions {
+		err := s.L.CallByParam(lua.P{Fn: s.L.GetGlobal(name), NRet: 0, Protect: true})
+		if err != nil {
+			if ctx.Err() != nil {
+				r.Error = fmt.Sprintf("%s: timed out after %s", name, s.timeout)
+			} else {
+				r.Error = fmt.Sprintf("%s: %v", name, err)
+			}
+			break
+		}
+	}
+	r.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	s.L.RemoveContext()
+
+	if s.raw != msg.Raw {
+		r.Raw = s.raw
+	}
+	s.result = nil
+	return r
+}
+
+func (s *Sandbox) register() {
+	L := s.L
+	str := func(f func() string) lua.LGFunction {
+		return func(L *lua.LState) int { L.Push(lua.LString(f())); return 1 }
+	}
+	num := func(f func() float64) lua.LGFunction {
+		return func(L *lua.LState) int { L.Push(lua.LNumber(f())); return 1 }
+	}
+
+	fns := map[string]lua.LGFunction{
+		"GetHEPStruct":       s.getHEPStruct,
+		"GetSIPStruct":       s.getSIPStruct,
+		"GetRawMessage":      str(func() string { return s.raw }),
+		"GetHEPProtoType":    num(func() float64 { return 1 }),
+		"GetHEPSrcIP":        str(func() string { return s.msg.SrcIP }),
+		"GetHEPSrcPort":      num(func() float64 { return float64(s.msg.SrcPort) }),
+		"GetHEPDstIP":        str(func() string { return s.msg.DstIP }),
+		"GetHEPDstPort":      num(func() float64 { return float64(s.msg.DstPort) }),
+		"GetHEPTimeSeconds":  num(func() float64 { return float64(s.msg.Timestamp.Unix()) }),
+		"GetHEPTimeUseconds": num(func() float64 { return float64(s.msg.Timestamp.Nanosecond() / 1000) }),
+		"GetHEPNodeID":       num(func() float64 { return float64(s.nodeID) }),
+		"SetRawMessage":      s.setRawMessage,
+		"SetSIPHeader":       s.setSIPHeader,
+		"SetCustomSIPHeader": s.setCustomSIPHeader,
+		"SetHEPField":        s.setHEPField,
+		"HashTable":          s.hashTable,
+		"HashString":         s.hashString,
+		"Logp":               s.logp,
+		"Print":              s.print,
+	}
+	for name, fn := range fns {
+		L.SetGlobal(name, L.NewFunction(fn))
+	}
+	// print() is commonly used for debugging; capture it too.
+	L.SetGlobal("print", L.NewFunction(s.print))
+}
+
+func (s *Sandbox) modify(kind, name, old, new string) {
+	if s.result != nil {
+		s.result.Modifications = append(s.result.Modifications, Modification{Kind: kind, Name: name, Old: old, New: new})
+	}
+}
+
+func (s *Sandbox) log(line string) {
+	if s.result != nil {
+		s.result.Logs = append(s.result.Logs, line)
+	}
+}
+
+func (s *Sandbox) getHEPStruct(L *lua.LState) int {
+	m := s.msg
+	t := L.NewTable()
+	t.RawSetString("Version", lua.LNumber(3))
+	t.RawSetString("Protocol", lua.LNumber(17))
+	t.RawSetString("SrcIP", lua.LString(m.SrcIP))
+	t.RawSetString("DstIP", lua.LString(m.DstIP))
+	t.RawSetString("SrcPort", lua.LNumber(m.SrcPort))
+	t.RawSetString("DstPort", lua.LNumber(m.DstPort))
+	t.RawSetString("Tsec", lua.LNumber(m.Timestamp.Unix()))
+	t.RawSetString("Tmsec", lua.LNumber(m.Timestamp.Nanosecond()/1000))
+	t.RawSetString("ProtoType", lua.LNumber(1))
+	t.RawSetString("NodeID", lua.LNumber(s.nodeID))
+	t.RawSetString("CID", lua.LString(m.CallID()))
+	t.RawSetString("Payload", lua.LString(s.raw))
+	L.Push(t)
+	return 1
+}
+
+func (s *Sandbox) getSIPStruct(L *lua.LState) int {
+	m := s.msg
+	t := L.NewTable()
+	set := func(k, v string) { t.RawSetString(k, lua.LString(v)) }
+
+	if m.IsRequest() {
+		set("FirstMethod", m.Method)
+		set("URIRaw", m.RequestURI)
+		set("URIUser", uriUser(m.RequestURI))
+		set("URIHost", sip.URIHost(m.RequestURI))
+	} else {
+		set("FirstMethod", fmt.Sprint(m.StatusCode))
+		set("FirstRespText", m.Reason)
+	}
+	from, to := sip.URI(m.Header("From")), sip.URI(m.Header("To"))
+	set("CallID", m.CallID())
+	set("FromUser", uriUser(from))
+	set("FromHost", sip.URIHost(from))
+	set("FromTag", m.FromTag())
+	set("ToUser", uriUser(to))
+	set("ToHost", sip.URIHost(to))
+	set("ToTag", m.ToTag())
+	if seq, method, ok := m.CSeq(); ok {
+		set("CseqMethod", method)
+		set("CseqVal", fmt.Sprint(seq))
+	}
+	set("ViaOneBranch", m.Branch())
+	set("UserAgent", m.Header("User-Agent"))
+	set("Server", m.Header("Server"))
+	set("ContactUser", uriUser(sip.URI(m.Header("Contact"))))
+	set("Body", m.Body)
+
+	headers := L.NewTable()
+	for _, h := range m.Headers {
+		headers.RawSetString(h.Name, lua.LString(h.Value))
+	}
+	t.RawSetString("Headers", headers)
+	L.Push(t)
+	return 1
+}
+
+func (s *Sandbox) setRawMessage(L *lua.LState) int {
+	value := L.CheckString(1)
+	s.modify("raw", "", summarize(s.raw), summarize(value))
+	s.raw = value
+	return 0
+}
+
+func (s *Sandbox) setSIPHeader(L *lua.LState) int {
+	name, value := L.CheckString(1), L.CheckString(2)
+	// Earlier calls may have changed the header already.
+	old := header(s.raw, name)
+	s.modify("sip_header", name, old, value)
+	s.raw = setHeader(s.raw, name, value)
+	return 0
+}
+
+func (s *Sandbox) setCustomSIPHeader(L *lua.LState) int {
+	name, value := L.CheckString(1), L.CheckString(2)
+	s.modify("custom_header", name, "", value)
+	return 0
+}
+
+func (s *Sandbox) setHEPField(L *lua.LState) int {
+	name, value := L.CheckString(1), L.CheckString(2)
+	s.modify("hep_field", name, "", value)
+	return 0
+}
+
+// hashTable implements HashTable(op, key[, value]) with op "set", "get" or
+// "delete", backed by a map that lives as long as the sandbox.
+func (s *Sandbox) hashTable(L *lua.LState) int {
+	op, key := L.CheckString(1), L.CheckString(2)
+	switch strings.ToLower(op) {
+	case "set":
+		s.hash[key] = L.CheckString(3)
+		L.Push(lua.LTrue)
+	case "get":
+		v, ok := s.hash[key]
+		if !ok {
+			L.Push(lua.LNil)
+		} else {
+			L.Push(lua.LString(v))
+		}
+	case "delete":
+		delete(s.hash, key)
+		L.Push(lua.LTrue)
+	default:
+		L.ArgError(1, "must be set, get or delete")
+	}
+	return 1
+}
+
+func (s *Sandbox) hashString(L *lua.LState) int {
+	typ, value := L.CheckString(1), L.CheckString(2)
+	var sum []byte
+	switch strings.ToLower(typ) {
+	case "md5":
+		h := md5.Sum([]byte(value))
+		sum = h[:]
+	case "sha1":
+		h := sha1.Sum([]byte(value))
+		sum = h[:]
+	case "sha256":
+		h := sha256.Sum256([]byte(value))
+		sum = h[:]
+	case "fnv", "fnv32":
+		h := fnv.New32a()
+		h.Write([]byte(value))
+		sum = h.Sum(nil)
+	default:
+		L.ArgError(1, "must be md5, sha1, sha256 or fnv")
+	}
+	L.Push(lua.LString(hex.EncodeToString(sum)))
+	return 1
+}
+
+func (s *Sandbox) logp(L *lua.LState) int {
+	level := L.OptString(1, "INFO")
+	parts := []string{strings.ToUpper(level)}
+	for i := 2; i <= L.GetTop(); i++ {
+		parts = append(parts, describe(L.Get(i)))
+	}
+	s.log(strings.Join(parts, " "))
+	return 0
+}
+
+func (s *Sandbox) print(L *lua.LState) int {
+	var parts []string
+	for i := 1; i <= L.GetTop(); i++ {
+		parts = append(parts, describe(L.Get(i)))
+	}
+	s.log(strings.Join(parts, " "))
+	return 0
+}
+
+// describe renders a Lua value for logs; tables are shown as {k=v, ...}.
+func describe(v lua.LValue) string {
+	t, ok := v.(*lua.LTable)
+	if !ok {
+		return v.String()
+	}
+	var parts []string
+	t.ForEach(func(k, v lua.LValue) {
+		if _, nested := v.(*lua.LTable); nested {
+			parts = append(parts, k.String()+"={...}")
+		} else {
+			parts = append(parts, k.String()+"="+v.String())
+		}
+	})
+	sort.Strings(parts)
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// headerLines splits raw into lines and returns the index of the first
+// line of header name, or -1.
+func headerLines(raw, name string) (lines []string, eol string, index int) {
+	eol = "\n"
+	if strings.Contains(raw, "\r\n") {
+		eol = "\r\n"
+	}
+	lines = strings.Split(raw, eol)
+	for i := 1; i < len(lines) && lines[i] != ""; i++ {
+		n, _, ok := strings.Cut(lines[i], ":")
+		if ok && strings.EqualFold(strings.TrimSpace(n), name) {
+			return lines, eol, i
+		}
+	}
+	return lines, eol, -1
+}
+
+// header returns the value of header name in raw, or "".
+func header(raw, name string) string {
+	lines, _, i := headerLines(raw, name)
+	if i < 0 {
+		return ""
+	}
+	_, value, _ := strings.Cut(lines[i], ":")
+	return strings.TrimSpace(value)
+}
+
+// setHeader replaces the first header called name in a raw SIP message,
+// or adds it after the start line if there is none.
+func setHeader(raw, name, value string) string {
+	lines, eol, i := headerLines(raw, name)
+	if i >= 0 {
+		lines[i] = name + ": " + value
+		return strings.Join(lines, eol)
+	}
+	if len(lines) == 0 {
+		return name + ": " + value
+	}
+	lines = append(lines[:1], append([]string{name + ": " + value}, lines[1:]...)...)
+	return strings.Join(lines, eol)
+}
+
+func uriUser(uri string) string {
+	_, rest, ok := strings.Cut(uri, ":")
+	if !ok {
+		return ""
+	}
+	user, _, ok := strings.Cut(rest, "@")
+	if !ok {
+		return ""
+	}
+	user, _, _ = strings.Cut(user, ";")
+	return user
+}
+
+// summarize shortens a raw message to its first line and size.
+func summarize(raw string) string {
+	first, _, _ := strings.Cut(raw, "\n")
+	return fmt.Sprintf("%s (%d bytes)", strings.TrimSpace(first), len(raw))
+}
@@ -0,0 +1,165 @@
+package script
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"hepic-cli/internal/sip"
+)
+
+const testInvite = "INVITE sip:bob@example.com SIP/2.0\r\n" +
+	"Via: SIP/2.0/UDP 10.0.0.1:5060;branch=z9hG4bK1\r\n" +
+	"From: <sip:alice@example.com>;tag=a1\r\n" +
+	"To: <sip:bob@example.com>\r\n" +
+	"Call-ID: call-1@example.com\r\n" +
+	"CSeq: 1 INVITE\r\n" +
+	"User-Agent: test-ua\r\n" +
+	"Content-Length: 0\r\n\r\n"
+
+func testMessage(t *testing.T) *sip.Message {
+	t.Helper()
+	m, err := sip.Parse(testInvite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SrcIP, m.SrcPort = "10.0.0.1", 5060
+	return m
+}
+
+func TestFunctions(t *testing.T) {
+	names, err := Functions("local function helper() end\nfunction a() end\nfunction t.b() end\nfunction c() end\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "a,c" {
+		t.Errorf("got %v, want [a c]", names)
+	}
+}
+
+func TestSandboxRun(t *testing.T) {
+	source := `
+count = 0
+function checkRaw()
+  count = count + 1
+  local sip = GetSIPStruct()
+  local hep = GetHEPStruct()
+  Logp("DEBUG", sip.FromUser, sip.CseqMethod, hep.SrcIP, count)
+  if sip.UserAgent == "test-ua" then
+    SetSIPHeader("User-Agent", "rewritten")
+    SetCustomSIPHeader("X-Hash", HashString("md5", sip.CallID))
+  end
+  HashTable("set", sip.CallID, "seen")
+end
+`
+	sb, err := NewSandbox(source, nil, time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sb.Close()
+
+	r := sb.Run(1, testMessage(t))
+	if r.Error != "" {
+		t.Fatalf("unexpected error: %s", r.Error)
+	}
+	if len(r.Logs) != 1 || r.Logs[0] != "DEBUG alice INVITE 10.0.0.1 1" {
+		t.Errorf("logs = %q", r.Logs)
+	}
+	if len(r.Modifications) != 2 {
+		t.Fatalf("modifications = %+v", r.Modifications)
+	}
+	if m := r.Modifications[0]; m.Kind != "sip_header" || m.Old != "test-ua" || m.New != "rewritten" {
+		t.Errorf("unexpected modification %+v", m)
+	}
+	if !strings.Contains(r.Raw, "User-Agent: rewritten\r\n") {
+		t.Errorf("raw message not rewritten:\n%s", r.Raw)
+	}
+
+	// Globals and the hash table persist across messages.
+	r = sb.Run(2, testMessage(t))
+	if len(r.Logs) != 1 || !strings.HasSuffix(r.Logs[0], " 2") {
+		t.Errorf("logs = %q", r.Logs)
+	}
+	if sb.hash["call-1@example.com"] != "seen" {
+		t.Errorf("hash table = %v", sb.hash)
+	}
+}
+
+func TestSandboxErrors(t *testing.T) {
+	sb, err := NewSandbox("function f() error('boom') end\nfunction g() while true do end end\n", []string{"f"}, 50*time.Millisecond, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := sb.Run(1, testMessage(t))
+	if !strings.Contains(r.Error, "boom") {
+		t.Errorf("error = %q", r.Error)
+	}
+	sb.Close()
+
+	sb, err = NewSandbox("function g() while true do end end\n", []string{"g"}, 50*time.Millisecond, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = sb.Run(1, testMessage(t))
+	if !strings.Contains(r.Error, "timed out") {
+		t.Errorf("error = %q", r.Error)
+	}
+	sb.Close()
+
+	// The sandbox has no io or os library.
+	sb, err = NewSandbox("function f() io.open('/etc/passwd') end\n", []string{"f"}, time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := sb.Run(1, testMessage(t)); r.Error == "" {
+		t.Error("expected io to be unavailable")
+	}
+	sb.Close()
+
+	if _, err := NewSandbox("function f() end\n", []string{"missing"}, time.Second, 0); err == nil {
+		t.Error("expected error for an undefined function")
+	}
+	if _, err := NewSandbox("local x = 1\n", nil, time.Second, 0); err == nil {
+		t.Error("expected error for a script without functions")
+	}
+	if _, err := NewSandbox("function helper(x) end\n", nil, time.Second, 0); err == nil {
+		t.Error("expected error for a script without entry points")
+	}
+}
+
+func TestSandboxEntryPoints(t *testing.T) {
+	source := `
+function helper(name) Logp("INFO", "helper", name) end
+function checkSIP() Logp("INFO", "sip") end
+function checkRaw() Logp("INFO", "raw") end
+`
+	sb, err := NewSandbox(source, nil, time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sb.Close()
+	if r := sb.Run(1, testMessage(t)); strings.Join(r.Logs, ",") != "INFO raw,INFO sip" {
+		t.Errorf("logs = %q", r.Logs)
+	}
+}
+
+func TestSandboxChainedHeaders(t *testing.T) {
+	source := `
+function checkRAW()
+  SetSIPHeader("User-Agent", "first")
+  SetSIPHeader("User-Agent", "second")
+end
+`
+	sb, err := NewSandbox(source, nil, time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sb.Close()
+	r := sb.Run(1, testMessage(t))
+	if len(r.Modifications) != 2 {
+		t.Fatalf("modifications = %+v", r.Modifications)
+	}
+	if m := r.Modifications[1]; m.Old != "first" || m.New != "second" {
+		t.Errorf("unexpected modification %+v", m)
+	}
+}
@@ -3,8 +3,16 @@ package script
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+)
+
+// Script types accepted by the server.
+const (
+	TypeLua        = models.ScriptTypeLua
+	TypeJavaScript = models.ScriptTypeJavascript
 )
 
 // List retrieves all scripts. GET /script
@@ -34,3 +42,21 @@ func Delete(ctx context.Context, client *api.Client, uuid string) (json.RawMessa
 	err := client.Delete(ctx, "/script/"+api.PathEscape(uuid), &result)
 	return result, err
 }
+
+// Validate checks a script body client-side: data and type are required,
+// type must be lua or javascript, and hepid and profile go together.
+func Validate(s models.ScriptDataStruct) error {
+	if s.Data == "" {
+		return fmt.Errorf("data (the script source) is required")
+	}
+	if s.Type == "" {
+		return fmt.Errorf("type is required: %s or %s", TypeLua, TypeJavaScript)
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if (s.Hepid == 0) != (s.Profile == "") {
+		return fmt.Errorf("hepid and profile must be set together")
+	}
+	return nil
+}
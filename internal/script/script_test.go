@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
 )
 
 func TestList(t *testing.T) {
@@ -186,3 +187,22 @@ func TestList_Error(t *testing.T) {
 		t.Fatal("expected error for 500 response")
 	}
 }
+
+func TestValidate(t *testing.T) {
+	valid := models.ScriptDataStruct{Data: "return true", Type: TypeLua, Hepid: 1, Profile: "call"}
+	if err := Validate(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	invalid := []models.ScriptDataStruct{
+		{Type: TypeLua},
+		{Data: "x"},
+		{Data: "x", Type: "python"},
+		{Data: "x", Type: TypeJavaScript, Hepid: 1},
+	}
+	for _, s := range invalid {
+		if err := Validate(s); err == nil {
+			t.Errorf("expected error for %+v", s)
+		}
+	}
+}
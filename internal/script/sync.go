@@ -0,0 +1,324 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/textdiff"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Fetch retrieves all scripts.
+func Fetch(ctx context.Context, client *api.Client) ([]models.ScriptDataStruct, error) {
+	var result struct {
+		Data []models.ScriptDataStruct `json:"data"`
+	}
+	if err := client.Get(ctx, "/script", &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// Find returns the script with the given UUID.
+func Find(scripts []models.ScriptDataStruct, uuid string) (models.ScriptDataStruct, bool) {
+	for _, s := range scripts {
+		if s.UUID == uuid {
+			return s, true
+		}
+	}
+	return models.ScriptDataStruct{}, false
+}
+
+// Match finds the server script a local one corresponds to: by UUID when
+// set, otherwise the only script with the same type, hepid and profile.
+// It reports false when there is none.
+func Match(s models.ScriptDataStruct, current []models.ScriptDataStruct) (models.ScriptDataStruct, bool, error) {
+	if s.UUID != "" {
+		found, ok := Find(current, s.UUID)
+		if !ok {
+			return found, false, fmt.Errorf("script %s not found on the server", s.UUID)
+		}
+		return found, true, nil
+	}
+	var matches []models.ScriptDataStruct
+	for _, c := range current {
+		if c.Type == s.Type && c.Hepid == s.Hepid && c.Profile == s.Profile {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return models.ScriptDataStruct{}, false, nil
+	case 1:
+		return matches[0], true, nil
+	}
+	return models.ScriptDataStruct{}, false, fmt.Errorf("%d %s scripts exist for hepid %d profile %q: set the uuid", len(matches), s.Type, s.Hepid, s.Profile)
+}
+
+// ManifestFile describes the scripts in a directory.
+const ManifestFile = "scripts.yaml"
+
+// Entry is one script in a manifest. type defaults to the file
+// extension and status to true.
+type Entry struct {
+	File     string            `yaml:"file"`
+	UUID     string            `yaml:"uuid,omitempty"`
+	Type     models.ScriptType `yaml:"type,omitempty"`
+	Hepid    uint16            `yaml:"hepid,omitempty"`
+	Profile  string            `yaml:"profile,omitempty"`
+	HEPAlias string            `yaml:"hep_alias,omitempty"`
+	Partid   uint16            `yaml:"partid,omitempty"`
+	Status   *bool             `yaml:"status,omitempty"`
+}
+
+// Manifest is the content of scripts.yaml:
+//
+//	scripts:
+//	  - file: sip-call.lua
+//	    uuid: 5a3b...
+//	    hepid: 1
+//	    profile: call
+//	  - file: log-default.js
+//	    hepid: 100
+//	    profile: default
+//	    status: false
+type Manifest struct {
+	Scripts []Entry `yaml:"scripts"`
+}
+
+// Local is a script read from a directory.
+type Local struct {
+	File   string
+	Script models.ScriptDataStruct
+}
+
+// ReadDir reads the manifest in dir and the scripts it lists.
+func ReadDir(dir string) ([]Local, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read script manifest: %w", err)
+	}
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("%s: %w", ManifestFile, err)
+	}
+	if len(m.Scripts) == 0 {
+		return nil, fmt.Errorf("%s lists no scripts", ManifestFile)
+	}
+
+	locals := make([]Local, 0, len(m.Scripts))
+	files := make(map[string]bool)
+	for i, e := range m.Scripts {
+		if e.File == "" {
+			return nil, fmt.Errorf("%s: scripts[%d]: file is required", ManifestFile, i)
+		}
+		if files[e.File] {
+			return nil, fmt.Errorf("%s: %s is listed twice", ManifestFile, e.File)
+		}
+		files[e.File] = true
+
+		source, err := ReadSource(filepath.Join(dir, e.File))
+		if err != nil {
+			return nil, err
+		}
+		s := models.ScriptDataStruct{
+			Data: source, UUID: e.UUID, Type: e.Type, Hepid: e.Hepid,
+			Profile: e.Profile, HEPAlias: e.HEPAlias, Partid: e.Partid, Status: true,
+		}
+		if s.Type == "" {
+			s.Type = TypeFromPath(e.File)
+		}
+		if e.Status != nil {
+			s.Status = *e.Status
+		}
+		if err := Validate(s); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.File, err)
+		}
+		locals = append(locals, Local{File: e.File, Script: s})
+	}
+	return locals, nil
+}
+
+// FileName returns a file name for a script: <profile>-<hepid> when set,
+// otherwise the UUID, with the type's extension.
+func FileName(s models.ScriptDataStruct) string {
+	name := s.UUID
+	if s.Profile != "" {
+		name = fmt.Sprintf("%s-%d", s.Profile, s.Hepid)
+	}
+	if name == "" {
+		name = "script"
+	}
+	return strings.NewReplacer("/", "_", string(os.PathSeparator), "_").Replace(name) + Ext(s.Type)
+}
+
+// WriteDir writes scripts and a manifest listing them to dir and returns
+// the files written.
+func WriteDir(dir string, scripts []models.ScriptDataStruct) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var m Manifest
+	var files []string
+	used := make(map[string]bool)
+	for _, s := range scripts {
+		name := FileName(s)
+		if used[name] {
+			name = strings.TrimSuffix(name, Ext(s.Type)) + "-" + s.UUID + Ext(s.Type)
+		}
+		used[name] = true
+
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(s.Data), 0644); err != nil {
+			return nil, err
+		}
+		files = append(files, path)
+		status := s.Status
+		m.Scripts = append(m.Scripts, Entry{
+			File: name, UUID: s.UUID, Type: s.Type, Hepid: s.Hepid,
+			Profile: s.Profile, HEPAlias: s.HEPAlias, Partid: s.Partid, Status: &status,
+		})
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, ManifestFile)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return nil, err
+	}
+	return append(files, path), nil
+}
+
+// Actions in a Plan.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change is one step of a Plan.
+type Change struct {
+	Action  string            `json:"action"`
+	UUID    string            `json:"uuid,omitempty"`
+	File    string            `json:"file"`
+	Type    models.ScriptType `json:"type"`
+	Hepid   uint16            `json:"hepid"`
+	Profile string            `json:"profile"`
+	Details string            `json:"details,omitempty"`
+
+	// Old is the server version of the script, Body the request body for
+	// create and update.
+	Old  models.ScriptDataStruct `json:"-"`
+	Body models.ScriptDataStruct `json:"-"`
+}
+
+// Diff returns a unified diff of the script source from Old to Body.
+func (c Change) Diff() string {
+	from, to := "server/"+c.File, "local/"+c.File
+	if c.Action == ActionCreate {
+		from = "/dev/null"
+	}
+	if c.Action == ActionDelete {
+		to = "/dev/null"
+	}
+	return textdiff.Unified(from, to, c.Old.Data, c.Body.Data, 3)
+}
+
+// Compare describes how local differs from the server version, or returns
+// "" if they are the same.
+func Compare(old, local models.ScriptDataStruct) string {
+	var details []string
+	if added, removed := textdiff.Stat(old.Data, local.Data); added+removed > 0 {
+		details = append(details, fmt.Sprintf("+%d -%d lines", added, removed))
+	}
+	if old.Status != local.Status {
+		details = append(details, fmt.Sprintf("status: %t -> %t", old.Status, local.Status))
+	}
+	if old.Hepid != local.Hepid || old.Profile != local.Profile {
+		details = append(details, fmt.Sprintf("protocol: %d/%s -> %d/%s", old.Hepid, old.Profile, local.Hepid, local.Profile))
+	}
+	if old.HEPAlias != local.HEPAlias {
+		details = append(details, fmt.Sprintf("hep_alias: %q -> %q", old.HEPAlias, local.HEPAlias))
+	}
+	if old.Partid != local.Partid {
+		details = append(details, fmt.Sprintf("partid: %d -> %d", old.Partid, local.Partid))
+	}
+	if old.Type != local.Type {
+		details = append(details, fmt.Sprintf("type: %s -> %s", old.Type, local.Type))
+	}
+	return strings.Join(details, "; ")
+}
+
+// Plan returns the changes that make the server's scripts match locals.
+// Server scripts not matched by any local script are deleted only with
+// prune.
+func Plan(locals []Local, current []models.ScriptDataStruct, prune bool) ([]Change, error) {
+	var changes []Change
+	matched := make(map[string]string)
+	for _, l := range locals {
+		s := l.Script
+		c := Change{File: l.File, Type: s.Type, Hepid: s.Hepid, Profile: s.Profile, Body: s}
+		old, ok, err := Match(s, current)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", l.File, err)
+		}
+		if !ok {
+			c.Action, c.Details = ActionCreate, fmt.Sprintf("+%d lines", len(textdiff.Lines(s.Data)))
+			changes = append(changes, c)
+			continue
+		}
+		if prev, dup := matched[old.UUID]; dup {
+			return nil, fmt.Errorf("%s and %s both match script %s", prev, l.File, old.UUID)
+		}
+		matched[old.UUID] = l.File
+
+		c.UUID, c.Old = old.UUID, old
+		c.Body.UUID = old.UUID
+		if details := Compare(old, s); details != "" {
+			c.Action, c.Details = ActionUpdate, details
+			changes = append(changes, c)
+		}
+	}
+
+	if prune {
+		for _, s := range current {
+			if _, ok := matched[s.UUID]; ok {
+				continue
+			}
+			changes = append(changes, Change{
+				Action: ActionDelete, UUID: s.UUID, File: FileName(s), Type: s.Type,
+				Hepid: s.Hepid, Profile: s.Profile, Details: "not in manifest", Old: s,
+			})
+		}
+	}
+	return changes, nil
+}
+
+// ReadSource reads a script file, rejecting files that are not text.
+func ReadSource(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return "", errors.New(path + " is not a text file")
+	}
+	return string(data), nil
+}
@@ -0,0 +1,110 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/models"
+)
+
+func TestCheck(t *testing.T) {
+	if err := Check(TypeLua, "ok.lua", "local x = 1\nif x then return x end\n"); err != nil {
+		t.Errorf("unexpected lua error: %v", err)
+	}
+	err := Check(TypeLua, "bad.lua", "local x = 1\nif x then\n  print(x\nend\n")
+	if err == nil || !strings.Contains(err.Error(), "bad.lua line:4") {
+		t.Errorf("expected lua syntax error with line, got %v", err)
+	}
+
+	if err := Check(TypeJavaScript, "ok.js", "function f(m) { return m; }\n"); err != nil {
+		t.Errorf("unexpected javascript error: %v", err)
+	}
+	err = Check(TypeJavaScript, "bad.js", "var x = 1;\nfunction f( {\n")
+	if err == nil || !strings.Contains(err.Error(), "bad.js: Line") {
+		t.Errorf("expected javascript syntax error with line, got %v", err)
+	}
+
+	if err := Check("python", "x.py", ""); err == nil {
+		t.Error("expected error for unknown type")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	current := []models.ScriptDataStruct{
+		{UUID: "a", Type: TypeLua, Hepid: 1, Profile: "call"},
+		{UUID: "b", Type: TypeLua, Hepid: 100, Profile: "default"},
+		{UUID: "c", Type: TypeLua, Hepid: 100, Profile: "default"},
+	}
+	if s, ok, err := Match(models.ScriptDataStruct{Type: TypeLua, Hepid: 1, Profile: "call"}, current); err != nil || !ok || s.UUID != "a" {
+		t.Errorf("expected match on protocol, got %+v %v %v", s, ok, err)
+	}
+	if _, ok, err := Match(models.ScriptDataStruct{Type: TypeJavaScript, Hepid: 1, Profile: "call"}, current); err != nil || ok {
+		t.Errorf("expected no match for another type, got %v %v", ok, err)
+	}
+	if _, _, err := Match(models.ScriptDataStruct{Type: TypeLua, Hepid: 100, Profile: "default"}, current); err == nil {
+		t.Error("expected ambiguity error")
+	}
+	if _, _, err := Match(models.ScriptDataStruct{UUID: "zzz"}, current); err == nil {
+		t.Error("expected not found error for unknown uuid")
+	}
+}
+
+func TestWriteReadDir(t *testing.T) {
+	dir := t.TempDir()
+	scripts := []models.ScriptDataStruct{
+		{UUID: "a", Type: TypeLua, Hepid: 1, Profile: "call", Data: "return 1\n", Status: true},
+		{UUID: "b", Type: TypeJavaScript, Data: "var x = 1;\n", Status: false},
+	}
+	files, err := WriteDir(dir, scripts)
+	if err != nil {
+		t.Fatalf("WriteDir failed: %v", err)
+	}
+	if len(files) != 3 || filepath.Base(files[0]) != "call-1.lua" || filepath.Base(files[1]) != "b.js" {
+		t.Errorf("unexpected files %v", files)
+	}
+
+	locals, err := ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	changes, err := Plan(locals, scripts, true)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("round trip produced changes: %+v %v", changes, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, ManifestFile), []byte("scripts:\n  - file: call-1.lua\n    typo: x\n"), 0644)
+	if _, err := ReadDir(dir); err == nil {
+		t.Error("expected error for unknown manifest field")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	current := []models.ScriptDataStruct{
+		{UUID: "a", Type: TypeLua, Hepid: 1, Profile: "call", Data: "return 1\n", Status: true},
+		{UUID: "gone", Type: TypeLua, Hepid: 1, Profile: "registration", Data: "return 2\n", Status: true},
+	}
+	locals := []Local{
+		{File: "call.lua", Script: models.ScriptDataStruct{Type: TypeLua, Hepid: 1, Profile: "call", Data: "-- v2\nreturn 1\n", Status: false}},
+		{File: "new.js", Script: models.ScriptDataStruct{Type: TypeJavaScript, Data: "var x;\n", Status: true}},
+	}
+
+	changes, err := Plan(locals, current, true)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected update, create and delete, got %+v", changes)
+	}
+	up := changes[0]
+	if up.Action != ActionUpdate || up.UUID != "a" || up.Details != "+1 -0 lines; status: true -> false" || up.Body.UUID != "a" {
+		t.Errorf("unexpected update %+v", up)
+	}
+	if !strings.Contains(up.Diff(), "--- server/call.lua\n+++ local/call.lua\n@@ -1 +1,2 @@\n+-- v2\n return 1\n") {
+		t.Errorf("unexpected diff:\n%s", up.Diff())
+	}
+	if changes[1].Action != ActionCreate || changes[2].Action != ActionDelete || changes[2].UUID != "gone" {
+		t.Errorf("unexpected create/delete %+v", changes[1:])
+	}
+}
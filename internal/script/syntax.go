@@ -0,0 +1,49 @@
+package script
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"hepic-cli/internal/models"
+
+	"github.com/dop251/goja"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// TypeFromPath infers the script type from a file extension: .lua or .js.
+func TypeFromPath(path string) models.ScriptType {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".lua":
+		return TypeLua
+	case ".js":
+		return TypeJavaScript
+	}
+	return ""
+}
+
+// Ext returns the file extension for a script type.
+func Ext(typ models.ScriptType) string {
+	if typ == TypeJavaScript {
+		return ".js"
+	}
+	return ".lua"
+}
+
+// Check parses source without running it and returns the first syntax
+// error, which includes the line number. name is used in the message.
+func Check(typ models.ScriptType, name, source string) error {
+	switch typ {
+	case TypeLua:
+		if _, err := parse.Parse(strings.NewReader(source), name); err != nil {
+			return fmt.Errorf("lua syntax error: %s", strings.Join(strings.Fields(err.Error()), " "))
+		}
+	case TypeJavaScript:
+		if _, err := goja.Compile(name, source, false); err != nil {
+			return fmt.Errorf("javascript syntax error: %s", strings.TrimPrefix(err.Error(), "SyntaxError: "))
+		}
+	default:
+		return fmt.Errorf("invalid type %q: must be %s or %s", typ, TypeLua, TypeJavaScript)
+	}
+	return nil
+}
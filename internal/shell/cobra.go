@@ -0,0 +1,136 @@
+package shell
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Reset prepares the tree for running another command in the same
+// process: every flag goes back to its default, and every command gets ctx
+// because cobra keeps the context of a command's first run.
+func Reset(root *cobra.Command, ctx context.Context) {
+	reset := func(f *pflag.Flag) {
+		switch f.Value.Type() {
+		case "stringSlice", "stringArray":
+			// Replace keeps appending to the old value on the next Set,
+			// so start from a fresh value instead.
+			fs := pflag.NewFlagSet("reset", pflag.ContinueOnError)
+			if f.Value.Type() == "stringSlice" {
+				fs.StringSlice(f.Name, sliceDefault(f.DefValue), "")
+			} else {
+				fs.StringArray(f.Name, sliceDefault(f.DefValue), "")
+			}
+			f.Value = fs.Lookup(f.Name).Value
+		default:
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				sv.Replace(sliceDefault(f.DefValue))
+			} else {
+				f.Value.Set(f.DefValue)
+			}
+		}
+		f.Changed = false
+	}
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		c.SetContext(ctx)
+		c.Flags().VisitAll(reset)
+		c.PersistentFlags().VisitAll(reset)
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+// sliceDefault parses the "[a,b]" form pflag uses for slice defaults.
+func sliceDefault(def string) []string {
+	def = strings.TrimSuffix(strings.TrimPrefix(def, "["), "]")
+	if def == "" {
+		return []string{}
+	}
+	return strings.Split(def, ",")
+}
+
+// Complete returns the candidates for the last word of line: builtins and
+// commands for the first word, subcommands and --flags after a command,
+// and variables after "$". start is where the word begins in line.
+func Complete(root *cobra.Command, builtins []string, s *Session, line string) (start int, candidates []string) {
+	start = strings.LastIndexAny(line, " \t") + 1
+	word := line[start:]
+	words := strings.Fields(line[:start])
+
+	var all []string
+	switch {
+	case strings.HasPrefix(word, "$"):
+		for _, name := range append(s.names(), Last) {
+			all = append(all, "$"+name)
+		}
+	case len(words) == 0:
+		all = append(all, builtins...)
+		all = append(all, commandNames(root)...)
+	case words[0] == "set" || words[0] == "unset":
+		if len(words) == 1 {
+			all = s.names()
+			if words[0] == "set" {
+				all = append(all, flagNames(root)...)
+			}
+		}
+	default:
+		c, _, err := root.Find(words)
+		if err != nil {
+			return start, nil
+		}
+		if strings.HasPrefix(word, "-") {
+			add := func(f *pflag.Flag) {
+				if !f.Hidden {
+					all = append(all, "--"+f.Name)
+				}
+			}
+			c.Flags().VisitAll(add)
+			c.InheritedFlags().VisitAll(add)
+		} else {
+			all = append(all, commandNames(c)...)
+			all = append(all, c.ValidArgs...)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range all {
+		if strings.HasPrefix(c, word) && !seen[c] {
+			seen[c] = true
+			candidates = append(candidates, c)
+		}
+	}
+	sort.Strings(candidates)
+	return start, candidates
+}
+
+func commandNames(c *cobra.Command) []string {
+	var names []string
+	for _, sub := range c.Commands() {
+		if sub.IsAvailableCommand() {
+			names = append(names, sub.Name())
+		}
+	}
+	return names
+}
+
+// flagNames lists every flag name in the tree, the names "set" can give
+// defaults for.
+func flagNames(root *cobra.Command) []string {
+	var names []string
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		c.Flags().VisitAll(func(f *pflag.Flag) { names = append(names, f.Name) })
+		c.PersistentFlags().VisitAll(func(f *pflag.Flag) { names = append(names, f.Name) })
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+	return names
+}
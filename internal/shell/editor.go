@@ -0,0 +1,143 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"hepic-cli/internal/tui"
+)
+
+// ErrInterrupted is returned by ReadLine when Ctrl-C discards the line.
+var ErrInterrupted = errors.New("interrupted")
+
+// Editor reads lines from a terminal in raw mode with cursor movement,
+// history (Up/Down) and tab completion.
+type Editor struct {
+	Prompt  string
+	History []string
+	// Complete returns where the word under completion starts in line
+	// and the candidates for it.
+	Complete func(line string) (start int, candidates []string)
+}
+
+// ReadLine reads one line. It returns io.EOF on Ctrl-D at an empty line.
+// Lines are not added to History; the caller decides what to keep.
+func (e *Editor) ReadLine(r *bufio.Reader, w io.Writer) (string, error) {
+	var line []rune
+	pos := 0
+	hist := len(e.History)
+	var draft []rune
+	lastTab := false
+
+	draw := func() {
+		fmt.Fprintf(w, "\r%s%s\x1b[K", e.Prompt, string(line))
+		if back := len(line) - pos; back > 0 {
+			fmt.Fprintf(w, "\x1b[%dD", back)
+		}
+	}
+	draw()
+	for {
+		k, err := tui.ReadKey(r)
+		if err != nil {
+			return "", err
+		}
+		tab := false
+		switch k.Code {
+		case tui.KeyEnter:
+			fmt.Fprint(w, "\r\n")
+			return string(line), nil
+		case tui.KeyCtrlC:
+			fmt.Fprint(w, "^C\r\n")
+			return "", ErrInterrupted
+		case tui.KeyCtrlD:
+			if len(line) == 0 {
+				fmt.Fprint(w, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(line) {
+				line = append(line[:pos], line[pos+1:]...)
+			}
+		case tui.KeyCtrlU:
+			line, pos = line[pos:], 0
+		case tui.KeyBackspace:
+			if pos > 0 {
+				line = append(line[:pos-1], line[pos:]...)
+				pos--
+			}
+		case tui.KeyDelete:
+			if pos < len(line) {
+				line = append(line[:pos], line[pos+1:]...)
+			}
+		case tui.KeyLeft:
+			if pos > 0 {
+				pos--
+			}
+		case tui.KeyRight:
+			if pos < len(line) {
+				pos++
+			}
+		case tui.KeyHome:
+			pos = 0
+		case tui.KeyEnd:
+			pos = len(line)
+		case tui.KeyUp:
+			if hist > 0 {
+				if hist == len(e.History) {
+					draft = line
+				}
+				hist--
+				line = []rune(e.History[hist])
+				pos = len(line)
+			}
+		case tui.KeyDown:
+			if hist < len(e.History) {
+				hist++
+				if hist == len(e.History) {
+					line = draft
+				} else {
+					line = []rune(e.History[hist])
+				}
+				pos = len(line)
+			}
+		case tui.KeyTab:
+			tab = true
+			if e.Complete == nil {
+				break
+			}
+			before := string(line[:pos])
+			start, candidates := e.Complete(before)
+			word := before[start:]
+			insert := ""
+			switch {
+			case len(candidates) == 1:
+				insert = strings.TrimPrefix(candidates[0], word) + " "
+			case len(candidates) > 1:
+				insert = strings.TrimPrefix(commonPrefix(candidates), word)
+				if insert == "" && lastTab {
+					fmt.Fprintf(w, "\r\n%s\r\n", strings.Join(candidates, "  "))
+				}
+			}
+			ins := []rune(insert)
+			line = append(line[:pos], append(ins, line[pos:]...)...)
+			pos += len(ins)
+		case tui.KeyRune:
+			line = append(line[:pos], append([]rune{k.Rune}, line[pos:]...)...)
+			pos++
+		}
+		lastTab = tab
+		draw()
+	}
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,82 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Split breaks a command line into arguments like a POSIX shell: words
+// are separated by spaces, quotes group words, and backslash escapes the
+// next character outside single quotes. $name references outside single
+// quotes are replaced with resolve(name).
+func Split(line string, resolve func(ref string) (string, error)) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\\' && i+1 < len(runes):
+			i++
+			cur.WriteRune(runes[i])
+			inWord = true
+		case r == '$' && i+1 < len(runes) && isRefStart(runes[i+1]):
+			j := i + 1
+			for j < len(runes) && isRefChar(runes[j]) {
+				j++
+			}
+			// A trailing dot or dash ends the sentence, not the reference.
+			for runes[j-1] == '.' || runes[j-1] == '-' {
+				j--
+			}
+			v, err := resolve(string(runes[i+1 : j]))
+			if err != nil {
+				return nil, err
+			}
+			cur.WriteString(v)
+			inWord = true
+			i = j - 1
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				args = append(args, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+func isRefStart(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isRefChar(r rune) bool {
+	return isRefStart(r) || r >= '0' && r <= '9' || r == '-' || r == '.'
+}
@@ -0,0 +1,265 @@
+// Package shell implements the parts of "hepic shell" that do not need a
+// terminal: session variables and $references, splitting command lines,
+// injecting variables as flags and completing from the cobra tree.
+package shell
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Last is the reference to the previous result.
+const Last = "last"
+
+// callIDKeys are the fields a bare $last looks for, in order.
+var callIDKeys = []string{"sid", "callid", "call_id", "callId", "Call-ID"}
+
+var (
+	namePattern     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	relativePattern = regexp.MustCompile(`^now(?:([+-])(\d+)([smhdw]))?$`)
+)
+
+// Session holds the variables set with "set" and the previous result.
+type Session struct {
+	vars map[string]string
+	// last is the previous result as plain JSON values; result is the
+	// result as the command printed it.
+	last   interface{}
+	result interface{}
+	now    func() time.Time
+}
+
+// Var is a session variable as listed by "set".
+type Var struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Current is Value evaluated now, for relative times such as now-1h.
+	Current string `json:"current"`
+}
+
+// NewSession returns an empty session.
+func NewSession() *Session {
+	return &Session{vars: make(map[string]string), now: time.Now}
+}
+
+// Set defines a variable. Variables named after a flag are passed to
+// every command that has the flag.
+func (s *Session) Set(name, value string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid variable name %q", name)
+	}
+	if name == Last {
+		return fmt.Errorf("$%s is set by the shell", Last)
+	}
+	s.vars[name] = value
+	return nil
+}
+
+// Unset removes a variable.
+func (s *Session) Unset(name string) error {
+	if _, ok := s.vars[name]; !ok {
+		return fmt.Errorf("variable %q is not set", name)
+	}
+	delete(s.vars, name)
+	return nil
+}
+
+// Vars lists the variables by name.
+func (s *Session) Vars() []Var {
+	vars := make([]Var, 0, len(s.vars))
+	for _, name := range s.names() {
+		current, _ := s.Value(name)
+		vars = append(vars, Var{Name: name, Value: s.vars[name], Current: current})
+	}
+	return vars
+}
+
+func (s *Session) names() []string {
+	names := make([]string, 0, len(s.vars))
+	for name := range s.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Value returns a variable, with relative times ("now", "now-1h",
+// "now+30m"; units s, m, h, d, w) evaluated to RFC3339.
+func (s *Session) Value(name string) (string, bool) {
+	v, ok := s.vars[name]
+	if !ok {
+		return "", false
+	}
+	if t, ok := RelativeTime(v, s.now()); ok {
+		return t.Format(time.RFC3339), true
+	}
+	return v, true
+}
+
+// RelativeTime parses "now" optionally followed by a signed offset such as
+// "-1h" or "+2d".
+func RelativeTime(s string, now time.Time) (time.Time, bool) {
+	m := relativePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return time.Time{}, false
+	}
+	if m[1] == "" {
+		return now, true
+	}
+	n, _ := strconv.Atoi(m[2])
+	unit := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[3]]
+	d := time.Duration(n) * unit
+	if m[1] == "-" {
+		d = -d
+	}
+	return now.Add(d), true
+}
+
+// SetLast records the result of a command for $last. The result is
+// normalised to plain JSON values.
+func (s *Session) SetLast(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var last interface{}
+	if err := dec.Decode(&last); err != nil {
+		return err
+	}
+	s.last, s.result = last, v
+	return nil
+}
+
+// LastResult returns the result recorded by SetLast, or nil.
+func (s *Session) LastResult() interface{} {
+	return s.result
+}
+
+// Resolve expands a reference without its "$": a variable name, "last"
+// for the previous result's Call-ID, or "last.<path>" for a field of it.
+// Paths are dot-separated keys and indexes; they are looked up in the
+// result and then in its first row.
+func (s *Session) Resolve(ref string) (string, error) {
+	if ref != Last && !strings.HasPrefix(ref, Last+".") {
+		v, ok := s.Value(ref)
+		if !ok {
+			return "", fmt.Errorf("$%s is not set", ref)
+		}
+		return v, nil
+	}
+	if s.last == nil {
+		return "", fmt.Errorf("$%s: no previous result", Last)
+	}
+	if ref == Last {
+		for _, obj := range []interface{}{s.last, firstRow(s.last)} {
+			for _, key := range callIDKeys {
+				if v, ok := lookup(obj, []string{key}); ok && scalar(v) != "" {
+					return scalar(v), nil
+				}
+			}
+		}
+		return "", fmt.Errorf("$%s: the previous result has no Call-ID; use $%s.<field>", Last, Last)
+	}
+	path := strings.Split(strings.TrimPrefix(ref, Last+"."), ".")
+	for _, obj := range []interface{}{s.last, firstRow(s.last)} {
+		if v, ok := lookup(obj, path); ok {
+			return scalar(v), nil
+		}
+	}
+	return "", fmt.Errorf("$%s: the previous result has no field %q", ref, strings.Join(path, "."))
+}
+
+// firstRow returns the first element of a list result, or of its "data"
+// list.
+func firstRow(v interface{}) interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		v = m["data"]
+	}
+	if list, ok := v.([]interface{}); ok && len(list) > 0 {
+		return list[0]
+	}
+	return nil
+}
+
+func lookup(v interface{}, path []string) (interface{}, bool) {
+	for _, p := range path {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[p]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+func scalar(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case bool:
+		return strconv.FormatBool(x)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// Inject appends a --name=value flag for every variable that names a flag
+// of the command args run, unless args already set it.
+func (s *Session) Inject(root *cobra.Command, args []string) []string {
+	c, _, err := root.Find(args)
+	if err != nil || c == root {
+		return args
+	}
+	end := len(args)
+	for i, a := range args {
+		if a == "--" {
+			end = i
+			break
+		}
+	}
+	var extra []string
+	for _, name := range s.names() {
+		f := c.Flag(name)
+		if f == nil || given(args[:end], f.Name, f.Shorthand) {
+			continue
+		}
+		v, _ := s.Value(name)
+		extra = append(extra, "--"+f.Name+"="+v)
+	}
+	return append(append(append([]string{}, args[:end]...), extra...), args[end:]...)
+}
+
+func given(args []string, name, shorthand string) bool {
+	for _, a := range args {
+		if a == "--"+name || strings.HasPrefix(a, "--"+name+"=") {
+			return true
+		}
+		if shorthand != "" && !strings.HasPrefix(a, "--") && strings.HasPrefix(a, "-"+shorthand) {
+			return true
+		}
+	}
+	return false
+}
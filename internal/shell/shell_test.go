@@ -0,0 +1,212 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSplit(t *testing.T) {
+	vars := map[string]string{"from": "2025-01-01", "last": "abc@host", "last.data.0.srcIp": "10.0.0.1"}
+	resolve := func(ref string) (string, error) {
+		if v, ok := vars[ref]; ok {
+			return v, nil
+		}
+		return "", io.EOF
+	}
+	for line, want := range map[string][]string{
+		`call search --from $from`:              {"call", "search", "--from", "2025-01-01"},
+		`x --call-id=$last  "a b" 'c $last' ""`: {"x", "--call-id=abc@host", "a b", "c $last", ""},
+		`x "$last.data.0.srcIp" \$last`:         {"x", "10.0.0.1", "$last"},
+		`x $last.`:                              {"x", "abc@host."},
+		`x 100$ a\ b`:                           {"x", "100$", "a b"},
+	} {
+		got, err := Split(line, resolve)
+		if err != nil {
+			t.Errorf("%s: %v", line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %q, want %q", line, got, want)
+		}
+	}
+	if _, err := Split(`x "open`, resolve); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+	if _, err := Split(`x $nope`, resolve); err == nil {
+		t.Error("expected error for unknown reference")
+	}
+}
+
+func TestSession(t *testing.T) {
+	s := NewSession()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if err := s.Set("from", "now-1h"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.Resolve("from"); v != "2025-01-01T11:00:00Z" {
+		t.Errorf("unexpected relative time %q", v)
+	}
+	if err := s.Set("last", "x"); err == nil {
+		t.Error("last should be reserved")
+	}
+	if _, err := s.Resolve("last"); err == nil {
+		t.Error("expected error without a previous result")
+	}
+
+	s.SetLast(json.RawMessage(`{"data":[{"sid":"abc@host","srcIp":"10.0.0.1","id":12345678901234567}],"total":1}`))
+	for ref, want := range map[string]string{
+		"last":              "abc@host",
+		"last.total":        "1",
+		"last.data.0.srcIp": "10.0.0.1",
+		"last.srcIp":        "10.0.0.1",
+		"last.id":           "12345678901234567",
+	} {
+		if got, err := s.Resolve(ref); err != nil || got != want {
+			t.Errorf("$%s: got %q (%v), want %q", ref, got, err, want)
+		}
+	}
+	if _, err := s.Resolve("last.nope"); err == nil {
+		t.Error("expected error for a missing field")
+	}
+	s.SetLast([]map[string]string{{"uuid": "u-1"}})
+	if _, err := s.Resolve("last"); err == nil || !strings.Contains(err.Error(), "no Call-ID") {
+		t.Errorf("expected missing Call-ID error, got %v", err)
+	}
+
+	for in, ok := range map[string]bool{"now": true, "now+2d": true, "now-1w": true, "now-1y": false, "yesterday": false} {
+		if _, got := RelativeTime(in, now); got != ok {
+			t.Errorf("RelativeTime(%q) = %v", in, got)
+		}
+	}
+}
+
+func testTree() *cobra.Command {
+	root := &cobra.Command{Use: "hepic"}
+	root.PersistentFlags().String("format", "json", "")
+	call := &cobra.Command{Use: "call"}
+	search := &cobra.Command{Use: "search", Run: func(*cobra.Command, []string) {}}
+	search.Flags().String("from", "", "")
+	search.Flags().StringP("call-id", "c", "", "")
+	search.Flags().StringSlice("group-by", []string{"a", "b"}, "")
+	call.AddCommand(search)
+	root.AddCommand(call, &cobra.Command{Use: "version", Run: func(*cobra.Command, []string) {}})
+	return root
+}
+
+func TestInject(t *testing.T) {
+	root := testTree()
+	s := NewSession()
+	s.Set("from", "2025-01-01")
+	s.Set("format", "table")
+	s.Set("call-id", "abc")
+	s.Set("unrelated", "x")
+
+	got := s.Inject(root, []string{"call", "search", "-c", "given", "--", "arg"})
+	want := []string{"call", "search", "-c", "given", "--format=table", "--from=2025-01-01", "--", "arg"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := s.Inject(root, []string{"version", "--format", "yaml"}); len(got) != 3 {
+		t.Errorf("explicit flags should win, got %q", got)
+	}
+}
+
+func TestReset(t *testing.T) {
+	root := testTree()
+	run := func(args ...string) *cobra.Command {
+		t.Helper()
+		Reset(root, context.Background())
+		root.SetArgs(args)
+		c, err := root.ExecuteC()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	c := run("call", "search", "--from", "x", "--group-by", "c", "--format", "yaml")
+	if v, _ := c.Flags().GetStringSlice("group-by"); !reflect.DeepEqual(v, []string{"c"}) {
+		t.Errorf("unexpected group-by %q", v)
+	}
+	c = run("call", "search", "--group-by", "d")
+	from, _ := c.Flags().GetString("from")
+	format, _ := c.Flags().GetString("format")
+	groupBy, _ := c.Flags().GetStringSlice("group-by")
+	if from != "" || format != "json" || !reflect.DeepEqual(groupBy, []string{"d"}) || c.Flags().Changed("from") {
+		t.Errorf("flags were not reset: from=%q format=%q group-by=%q", from, format, groupBy)
+	}
+	c = run("call", "search")
+	if v, _ := c.Flags().GetStringSlice("group-by"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("expected the default group-by, got %q", v)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	root := testTree()
+	s := NewSession()
+	s.Set("from", "now")
+	builtins := []string{"set", "exit"}
+	for line, want := range map[string][]string{
+		"":                   {"call", "exit", "set", "version"},
+		"ca":                 {"call"},
+		"call ":              {"search"},
+		"call search --f":    {"--format", "--from"},
+		"call search x $":    {"$from", "$last"},
+		"unset ":             {"from"},
+		"nope --":            nil,
+		"call search --from": {"--from"},
+	} {
+		_, got := Complete(root, builtins, s, line)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %q, want %q", line, got, want)
+		}
+	}
+	if start, _ := Complete(root, builtins, s, "call sea"); start != 5 {
+		t.Errorf("unexpected start %d", start)
+	}
+}
+
+func TestEditor(t *testing.T) {
+	e := &Editor{
+		Prompt:  "> ",
+		History: []string{"version"},
+		Complete: func(line string) (int, []string) {
+			return Complete(testTree(), nil, NewSession(), line)
+		},
+	}
+	read := func(input string) (string, error) {
+		t.Helper()
+		var out bytes.Buffer
+		return e.ReadLine(bufio.NewReader(strings.NewReader(input)), &out)
+	}
+
+	// Tab completion, cursor movement and editing.
+	if got, _ := read("ca\tse\t\x7f\r"); got != "call search" {
+		t.Errorf("unexpected line %q", got)
+	}
+	if got, _ := read("abc\x1b[D\x1b[D\x7f\r"); got != "bc" {
+		t.Errorf("unexpected line %q", got)
+	}
+	if got, _ := read("x\x1b[A\r"); got != "version" {
+		t.Errorf("expected the history entry, got %q", got)
+	}
+	if got, _ := read("abc\x01X\x05Y\r"); got != "XabcY" {
+		t.Errorf("unexpected line %q", got)
+	}
+	if _, err := read("abc\x03"); err != ErrInterrupted {
+		t.Errorf("expected interrupt, got %v", err)
+	}
+	if _, err := read("\x04"); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
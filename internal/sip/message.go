@@ -0,0 +1,347 @@
+package sip
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is a parsed SIP request or response together with the capture
+// metadata (time and addresses) it was recorded with.
+type Message struct {
+	Timestamp time.Time
+	SrcIP     string
+	SrcPort   int
+	DstIP     string
+	DstPort   int
+
+	// Method and RequestURI are set for requests.
+	Method     string
+	RequestURI string
+
+	// StatusCode and Reason are set for responses.
+	StatusCode int
+	Reason     string
+
+	Headers []Header
+	Body    string
+	Raw     string
+}
+
+// Header is a single SIP header line. Folded lines are joined.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// compactForms maps RFC 3261 §7.3.3 compact header names to their long form.
+var compactForms = map[string]string{
+	"i": "call-id",
+	"m": "contact",
+	"e": "content-encoding",
+	"l": "content-length",
+	"c": "content-type",
+	"f": "from",
+	"s": "subject",
+	"k": "supported",
+	"t": "to",
+	"v": "via",
+	"o": "event",
+	"u": "allow-events",
+	"r": "refer-to",
+	"b": "referred-by",
+	"x": "session-expires",
+}
+
+// canonicalName lower-cases a header name and expands compact forms.
+func canonicalName(name string) string {
+	n := strings.ToLower(strings.TrimSpace(name))
+	if long, ok := compactForms[n]; ok {
+		return long
+	}
+	return n
+}
+
+// Parse parses a raw SIP message. Both CRLF and bare LF line endings are accepted.
+func Parse(raw string) (*Message, error) {
+	text := strings.ReplaceAll(raw, "\r\n", "\n")
+	text = strings.TrimLeft(text, "\n")
+
+	head, body, _ := strings.Cut(text, "\n\n")
+	lines := strings.Split(head, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) == "" {
+		return nil, fmt.Errorf("empty SIP message")
+	}
+
+	msg := &Message{Raw: raw}
+	if err := msg.parseStartLine(strings.TrimSpace(lines[0])); err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines[1:] {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(msg.Headers) > 0 {
+			last := &msg.Headers[len(msg.Headers)-1]
+			last.Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed header line %q", line)
+		}
+		msg.Headers = append(msg.Headers, Header{
+			Name:  strings.TrimSpace(name),
+			Value: strings.TrimSpace(value),
+		})
+	}
+
+	body = strings.TrimRight(body, "\n")
+	if cl := msg.Header("Content-Length"); cl != "" {
+		if n, err := strconv.Atoi(cl); err == nil && n >= 0 {
+			// Content-Length counts CRLF line endings; the body here uses LF.
+			crlf := strings.ReplaceAll(body, "\n", "\r\n")
+			if n < len(crlf) {
+				body = strings.ReplaceAll(crlf[:n], "\r\n", "\n")
+			}
+		}
+	}
+	msg.Body = body
+	return msg, nil
+}
+
+func (m *Message) parseStartLine(line string) error {
+	if strings.HasPrefix(line, "SIP/2.0 ") {
+		rest := strings.TrimPrefix(line, "SIP/2.0 ")
+		codeStr, reason, _ := strings.Cut(rest, " ")
+		code, err := strconv.Atoi(codeStr)
+		if err != nil || code < 100 || code > 699 {
+			return fmt.Errorf("invalid status line %q", line)
+		}
+		m.StatusCode = code
+		m.Reason = reason
+		return nil
+	}
+
+	parts := strings.Fields(line)
+	if len(parts) != 3 || parts[2] != "SIP/2.0" {
+		return fmt.Errorf("invalid request line %q", line)
+	}
+	m.Method = strings.ToUpper(parts[0])
+	m.RequestURI = parts[1]
+	return nil
+}
+
+// IsStartLine reports whether line looks like a SIP request or status line.
+func IsStartLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "SIP/2.0 ") {
+		return true
+	}
+	parts := strings.Fields(line)
+	if len(parts) != 3 || parts[2] != "SIP/2.0" {
+		return false
+	}
+	for _, r := range parts[0] {
+		if (r < 'A' || r > 'Z') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsRequest reports whether the message is a request.
+func (m *Message) IsRequest() bool {
+	return m.Method != ""
+}
+
+// Summary returns the start line of the message without the SIP version,
+// e.g. "INVITE sip:bob@example.com" or "200 OK".
+func (m *Message) Summary() string {
+	if m.IsRequest() {
+		return m.Method + " " + m.RequestURI
+	}
+	return strings.TrimSpace(fmt.Sprintf("%d %s", m.StatusCode, m.Reason))
+}
+
+// Has reports whether at least one header with the given name is present.
+func (m *Message) Has(name string) bool {
+	want := canonicalName(name)
+	for _, h := range m.Headers {
+		if canonicalName(h.Name) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Header returns the value of the first header with the given name, or "".
+// Names are matched case-insensitively and compact forms are recognised.
+func (m *Message) Header(name string) string {
+	want := canonicalName(name)
+	for _, h := range m.Headers {
+		if canonicalName(h.Name) == want {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// Values returns all values of a header, splitting comma-separated lists.
+// Commas inside quoted strings and angle brackets are not treated as separators.
+func (m *Message) Values(name string) []string {
+	want := canonicalName(name)
+	var values []string
+	for _, h := range m.Headers {
+		if canonicalName(h.Name) == want {
+			values = append(values, splitList(h.Value)...)
+		}
+	}
+	return values
+}
+
+// HasOption reports whether an option tag (e.g. "100rel", "timer") is listed
+// in the given header, such as Require or Supported.
+func (m *Message) HasOption(header, option string) bool {
+	for _, v := range m.Values(header) {
+		if strings.EqualFold(strings.TrimSpace(v), option) {
+			return true
+		}
+	}
+	return false
+}
+
+// CallID returns the Call-ID header value.
+func (m *Message) CallID() string {
+	return m.Header("Call-ID")
+}
+
+// CSeq returns the sequence number and method of the CSeq header.
+// ok is false when the header is missing or malformed.
+func (m *Message) CSeq() (seq int, method string, ok bool) {
+	fields := strings.Fields(m.Header("CSeq"))
+	if len(fields) != 2 {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, "", false
+	}
+	return n, strings.ToUpper(fields[1]), true
+}
+
+// FromTag returns the tag parameter of the From header.
+func (m *Message) FromTag() string {
+	return Param(m.Header("From"), "tag")
+}
+
+// ToTag returns the tag parameter of the To header.
+func (m *Message) ToTag() string {
+	return Param(m.Header("To"), "tag")
+}
+
+// Branch returns the branch parameter of the topmost Via header.
+func (m *Message) Branch() string {
+	vias := m.Values("Via")
+	if len(vias) == 0 {
+		return ""
+	}
+	return Param(vias[0], "branch")
+}
+
+// Param returns the value of a header parameter such as "tag" or "branch".
+// Parameters inside an angle-bracketed URI belong to the URI and are ignored.
+// A parameter without a value returns its name so presence can be tested.
+func Param(value, name string) string {
+	if i := strings.LastIndex(value, ">"); i >= 0 {
+		value = value[i+1:]
+	}
+	parts := strings.Split(value, ";")
+	for _, p := range parts[1:] {
+		k, v, hasValue := strings.Cut(strings.TrimSpace(p), "=")
+		if strings.EqualFold(strings.TrimSpace(k), name) {
+			if !hasValue {
+				return k
+			}
+			return strings.Trim(strings.TrimSpace(v), `"`)
+		}
+	}
+	return ""
+}
+
+// URI extracts the URI from a name-addr or addr-spec header value,
+// e.g. `"Bob" <sip:bob@example.com>;tag=1` yields "sip:bob@example.com".
+func URI(value string) string {
+	value = strings.TrimSpace(value)
+	if start := strings.Index(value, "<"); start >= 0 {
+		if end := strings.Index(value[start:], ">"); end >= 0 {
+			return value[start+1 : start+end]
+		}
+	}
+	uri, _, _ := strings.Cut(value, ";")
+	return strings.TrimSpace(uri)
+}
+
+// URIHost returns the host part of a SIP URI, without port or parameters.
+func URIHost(uri string) string {
+	rest := uri
+	if i := strings.Index(rest, ":"); i >= 0 {
+		rest = rest[i+1:]
+	}
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		rest = rest[i+1:]
+	}
+	rest, _, _ = strings.Cut(rest, ";")
+	rest, _, _ = strings.Cut(rest, "?")
+	if strings.HasPrefix(rest, "[") {
+		if end := strings.Index(rest, "]"); end > 0 {
+			return rest[1:end]
+		}
+	}
+	if host, _, err := net.SplitHostPort(rest); err == nil {
+		return host
+	}
+	return rest
+}
+
+// splitList splits a header value on top-level commas.
+func splitList(value string) []string {
+	var (
+		out     []string
+		inQuote bool
+		inAngle bool
+		start   int
+	)
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if inQuote {
+				i++
+			}
+		case '"':
+			inQuote = !inQuote
+		case '<':
+			if !inQuote {
+				inAngle = true
+			}
+		case '>':
+			if !inQuote {
+				inAngle = false
+			}
+		case ',':
+			if !inQuote && !inAngle {
+				if s := strings.TrimSpace(value[start:i]); s != "" {
+					out = append(out, s)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if s := strings.TrimSpace(value[start:]); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
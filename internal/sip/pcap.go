@@ -0,0 +1,216 @@
+package sip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Link-layer header types supported by ReadPCAP.
+const (
+	linkTypeNull     = 0
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLinuxSLL = 113
+	linkTypeIPv4     = 228
+	linkTypeIPv6     = 229
+	linkTypeSLL2     = 276
+)
+
+const (
+	pcapMagicMicros = 0xa1b2c3d4
+	pcapMagicNanos  = 0xa1b23c4d
+	pcapngMagic     = 0x0a0d0d0a
+)
+
+func isPCAP(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		switch order.Uint32(data) {
+		case pcapMagicMicros, pcapMagicNanos, pcapngMagic:
+			return true
+		}
+	}
+	return false
+}
+
+// ReadPCAP extracts SIP messages carried over UDP or TCP from a classic
+// libpcap capture. IP fragments and TCP streams are not reassembled; each
+// packet payload is parsed on its own.
+func ReadPCAP(r io.Reader) ([]*Message, error) {
+	var hdr [24]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, fmt.Errorf("failed to read pcap header: %w", err)
+	}
+
+	var (
+		order   binary.ByteOrder
+		nanoRes bool
+	)
+	switch {
+	case binary.LittleEndian.Uint32(hdr[:]) == pcapMagicMicros:
+		order = binary.LittleEndian
+	case binary.BigEndian.Uint32(hdr[:]) == pcapMagicMicros:
+		order = binary.BigEndian
+	case binary.LittleEndian.Uint32(hdr[:]) == pcapMagicNanos:
+		order, nanoRes = binary.LittleEndian, true
+	case binary.BigEndian.Uint32(hdr[:]) == pcapMagicNanos:
+		order, nanoRes = binary.BigEndian, true
+	case binary.LittleEndian.Uint32(hdr[:]) == pcapngMagic:
+		return nil, fmt.Errorf("pcapng files are not supported; convert with: editcap -F pcap in.pcapng out.pcap")
+	default:
+		return nil, fmt.Errorf("not a pcap file")
+	}
+	linkType := order.Uint32(hdr[20:]) & 0x0fffffff
+
+	var msgs []*Message
+	var rec [16]byte
+	for {
+		if _, err := io.ReadFull(r, rec[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("truncated pcap record header: %w", err)
+		}
+		sec := int64(order.Uint32(rec[0:]))
+		frac := int64(order.Uint32(rec[4:]))
+		capLen := order.Uint32(rec[8:])
+		if capLen > 256*1024 {
+			return nil, fmt.Errorf("pcap record too large (%d bytes)", capLen)
+		}
+		packet := make([]byte, capLen)
+		if _, err := io.ReadFull(r, packet); err != nil {
+			return nil, fmt.Errorf("truncated pcap record: %w", err)
+		}
+
+		if !nanoRes {
+			frac *= 1000
+		}
+		ts := time.Unix(sec, frac).UTC()
+
+		src, dst, sport, dport, payload := decodePacket(linkType, packet)
+		if len(payload) == 0 || !IsStartLine(firstLine(payload)) {
+			continue
+		}
+		parsed, err := ReadText(bytes.NewReader(payload))
+		if err != nil {
+			continue
+		}
+		for _, m := range parsed {
+			m.Timestamp = ts
+			m.SrcIP, m.SrcPort = src, sport
+			m.DstIP, m.DstPort = dst, dport
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, nil
+}
+
+func firstLine(b []byte) string {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		b = b[:i]
+	}
+	return string(bytes.TrimRight(b, "\r"))
+}
+
+// decodePacket strips link, network and transport headers and returns the
+// addresses and application payload. Unsupported packets yield a nil payload.
+func decodePacket(linkType uint32, b []byte) (src, dst string, sport, dport int, payload []byte) {
+	var etherType uint16
+	switch linkType {
+	case linkTypeEthernet:
+		if len(b) < 14 {
+			return
+		}
+		etherType = binary.BigEndian.Uint16(b[12:])
+		b = b[14:]
+		for etherType == 0x8100 || etherType == 0x88a8 {
+			if len(b) < 4 {
+				return
+			}
+			etherType = binary.BigEndian.Uint16(b[2:])
+			b = b[4:]
+		}
+	case linkTypeLinuxSLL:
+		if len(b) < 16 {
+			return
+		}
+		etherType = binary.BigEndian.Uint16(b[14:])
+		b = b[16:]
+	case linkTypeSLL2:
+		if len(b) < 20 {
+			return
+		}
+		etherType = binary.BigEndian.Uint16(b[0:])
+		b = b[20:]
+	case linkTypeNull:
+		if len(b) < 4 {
+			return
+		}
+		b = b[4:]
+	case linkTypeRaw, linkTypeIPv4, linkTypeIPv6:
+	default:
+		return
+	}
+	if etherType == 0 && len(b) > 0 {
+		switch b[0] >> 4 {
+		case 4:
+			etherType = 0x0800
+		case 6:
+			etherType = 0x86dd
+		}
+	}
+
+	var proto byte
+	switch etherType {
+	case 0x0800:
+		if len(b) < 20 {
+			return
+		}
+		ihl := int(b[0]&0x0f) * 4
+		if ihl < 20 || len(b) < ihl {
+			return
+		}
+		// Skip non-first fragments; their payload has no transport header.
+		if binary.BigEndian.Uint16(b[6:])&0x1fff != 0 {
+			return
+		}
+		proto = b[9]
+		src, dst = net.IP(b[12:16]).String(), net.IP(b[16:20]).String()
+		b = b[ihl:]
+	case 0x86dd:
+		if len(b) < 40 {
+			return
+		}
+		proto = b[6]
+		src, dst = net.IP(b[8:24]).String(), net.IP(b[24:40]).String()
+		b = b[40:]
+	default:
+		return
+	}
+
+	switch proto {
+	case 17:
+		if len(b) < 8 {
+			return
+		}
+		sport, dport = int(binary.BigEndian.Uint16(b[0:])), int(binary.BigEndian.Uint16(b[2:]))
+		payload = b[8:]
+	case 6:
+		if len(b) < 20 {
+			return
+		}
+		off := int(b[12]>>4) * 4
+		if off < 20 || len(b) < off {
+			return
+		}
+		sport, dport = int(binary.BigEndian.Uint16(b[0:])), int(binary.BigEndian.Uint16(b[2:]))
+		payload = b[off:]
+	}
+	return
+}
@@ -0,0 +1,145 @@
+package sip
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metaLine matches the per-message header line written by HEPIC text exports
+// and ngrep-style dumps, e.g. "2025-01-01T10:00:00.123Z 10.0.0.1:5060 -> 10.0.0.2:5060".
+var metaLine = regexp.MustCompile(`^(?:[TU]\s+)?(.*?)\s*\[?([0-9A-Fa-f:.]+?)\]?:(\d+)\s*->\s*\[?([0-9A-Fa-f:.]+?)\]?:(\d+)\s*$`)
+
+// metaTimeLayouts are tried in order when parsing the timestamp of a meta line.
+var metaTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006/01/02 15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+}
+
+// ReadFile reads SIP messages from a text dump or a classic libpcap file.
+// The format is detected from the file's magic number.
+func ReadFile(path string) ([]*Message, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if isPCAP(data) {
+		return ReadPCAP(bytes.NewReader(data))
+	}
+	return ReadText(bytes.NewReader(data))
+}
+
+// ReadText splits a plain-text dump into SIP messages. Each message starts at
+// its request or status line and may be preceded by a meta line carrying the
+// capture time and addresses.
+func ReadText(r io.Reader) ([]*Message, error) {
+	var (
+		msgs    []*Message
+		current []string
+		meta    *Message
+		pending *Message
+	)
+
+	flush := func() error {
+		if len(current) == 0 {
+			return nil
+		}
+		msg, err := Parse(strings.Join(current, "\n"))
+		if err != nil {
+			return fmt.Errorf("message %d: %w", len(msgs)+1, err)
+		}
+		if pending != nil {
+			msg.Timestamp = pending.Timestamp
+			msg.SrcIP, msg.SrcPort = pending.SrcIP, pending.SrcPort
+			msg.DstIP, msg.DstPort = pending.DstIP, pending.DstPort
+		}
+		msgs = append(msgs, msg)
+		current = nil
+		pending = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if m := parseMetaLine(line); m != nil {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			meta = m
+			continue
+		}
+		if IsStartLine(line) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			pending, meta = meta, nil
+			current = []string{strings.TrimSpace(line)}
+			continue
+		}
+		if current != nil {
+			current = append(current, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+func parseMetaLine(line string) *Message {
+	m := metaLine.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return nil
+	}
+	srcPort, _ := strconv.Atoi(m[3])
+	dstPort, _ := strconv.Atoi(m[5])
+	msg := &Message{SrcIP: m[2], SrcPort: srcPort, DstIP: m[4], DstPort: dstPort}
+	ts := strings.TrimSpace(m[1])
+	for _, layout := range metaTimeLayouts {
+		if t, err := time.Parse(layout, ts); err == nil {
+			msg.Timestamp = t
+			break
+		}
+	}
+	return msg
+}
+
+// SortByTime orders messages by capture time, keeping the original order for
+// messages with equal or missing timestamps.
+func SortByTime(msgs []*Message) {
+	sort.SliceStable(msgs, func(i, j int) bool {
+		if msgs[i].Timestamp.IsZero() || msgs[j].Timestamp.IsZero() {
+			return false
+		}
+		return msgs[i].Timestamp.Before(msgs[j].Timestamp)
+	})
+}
+
+// GroupByCallID groups messages by Call-ID, preserving first-seen order of
+// the calls and the order of messages within each call.
+func GroupByCallID(msgs []*Message) (order []string, calls map[string][]*Message) {
+	calls = make(map[string][]*Message)
+	for _, m := range msgs {
+		id := m.CallID()
+		if _, seen := calls[id]; !seen {
+			order = append(order, id)
+		}
+		calls[id] = append(calls[id], m)
+	}
+	return order, calls
+}
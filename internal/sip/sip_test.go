@@ -0,0 +1,192 @@
+package sip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleInvite = "INVITE sip:bob@example.com SIP/2.0\r\n" +
+	"Via: SIP/2.0/UDP 10.0.0.1:5060;branch=z9hG4bK776asdhds, SIP/2.0/UDP 10.0.0.9;branch=z9hG4bKold\r\n" +
+	"Max-Forwards: 70\r\n" +
+	"To: Bob <sip:bob@example.com>\r\n" +
+	"f: \"Alice, A.\" <sip:alice@example.com;transport=udp>;tag=1928301774\r\n" +
+	"i: a84b4c76e66710\r\n" +
+	"CSeq: 314159 INVITE\r\n" +
+	"Contact: <sip:alice@10.0.0.1>\r\n" +
+	"Subject: folded\r\n" +
+	" continuation\r\n" +
+	"Content-Type: application/sdp\r\n" +
+	"Content-Length: 4\r\n" +
+	"\r\n" +
+	"v=0\r\n"
+
+func TestParse_Request(t *testing.T) {
+	m, err := Parse(sampleInvite)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !m.IsRequest() || m.Method != "INVITE" || m.RequestURI != "sip:bob@example.com" {
+		t.Errorf("unexpected start line: %q %q", m.Method, m.RequestURI)
+	}
+	if m.CallID() != "a84b4c76e66710" {
+		t.Errorf("expected compact Call-ID to resolve, got %q", m.CallID())
+	}
+	if m.FromTag() != "1928301774" {
+		t.Errorf("expected from tag, got %q", m.FromTag())
+	}
+	if m.ToTag() != "" {
+		t.Errorf("expected empty to tag, got %q", m.ToTag())
+	}
+	if m.Branch() != "z9hG4bK776asdhds" {
+		t.Errorf("expected top Via branch, got %q", m.Branch())
+	}
+	if got := len(m.Values("Via")); got != 2 {
+		t.Errorf("expected 2 Via values, got %d", got)
+	}
+	seq, method, ok := m.CSeq()
+	if !ok || seq != 314159 || method != "INVITE" {
+		t.Errorf("unexpected CSeq: %d %s %v", seq, method, ok)
+	}
+	if m.Header("Subject") != "folded continuation" {
+		t.Errorf("expected folded header to be joined, got %q", m.Header("Subject"))
+	}
+	if m.Body != "v=0" {
+		t.Errorf("expected body truncated to Content-Length, got %q", m.Body)
+	}
+}
+
+func TestParse_Response(t *testing.T) {
+	m, err := Parse("SIP/2.0 180 Ringing\nTo: <sip:bob@example.com>;tag=abc\n\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.IsRequest() || m.StatusCode != 180 || m.Reason != "Ringing" {
+		t.Errorf("unexpected status: %d %q", m.StatusCode, m.Reason)
+	}
+	if m.Summary() != "180 Ringing" {
+		t.Errorf("unexpected summary %q", m.Summary())
+	}
+	if m.ToTag() != "abc" {
+		t.Errorf("expected to tag abc, got %q", m.ToTag())
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "HELLO WORLD", "SIP/2.0 abc Foo", "INVITE sip:x SIP/2.0\nbroken header"} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestURIHelpers(t *testing.T) {
+	if got := URI(`"Bob" <sip:bob@192.168.1.5:5062;transport=tcp>;tag=1`); got != "sip:bob@192.168.1.5:5062;transport=tcp" {
+		t.Errorf("unexpected URI %q", got)
+	}
+	if got := URIHost("sip:bob@192.168.1.5:5062;transport=tcp"); got != "192.168.1.5" {
+		t.Errorf("unexpected host %q", got)
+	}
+	if got := URIHost("sip:[2001:db8::1]:5060"); got != "2001:db8::1" {
+		t.Errorf("unexpected IPv6 host %q", got)
+	}
+	if got := Param("<sip:a@b;lr>;tag=x", "lr"); got != "" {
+		t.Errorf("URI parameters must not be returned as header parameters, got %q", got)
+	}
+}
+
+func TestReadText(t *testing.T) {
+	dump := strings.Join([]string{
+		"2025-01-01T10:00:00.5Z 10.0.0.1:5060 -> 10.0.0.2:5060",
+		"",
+		strings.ReplaceAll(sampleInvite, "\r\n", "\n"),
+		"U 2025/01/01 10:00:01.000000 10.0.0.2:5060 -> 10.0.0.1:5060",
+		"SIP/2.0 100 Trying",
+		"i: a84b4c76e66710",
+		"",
+	}, "\n")
+
+	msgs, err := ReadText(strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	if msgs[0].SrcIP != "10.0.0.1" || msgs[0].DstPort != 5060 {
+		t.Errorf("unexpected addresses: %s:%d -> %s:%d", msgs[0].SrcIP, msgs[0].SrcPort, msgs[0].DstIP, msgs[0].DstPort)
+	}
+	want := time.Date(2025, 1, 1, 10, 0, 0, 500000000, time.UTC)
+	if !msgs[0].Timestamp.Equal(want) {
+		t.Errorf("expected timestamp %v, got %v", want, msgs[0].Timestamp)
+	}
+	if msgs[1].StatusCode != 100 || msgs[1].SrcIP != "10.0.0.2" || msgs[1].Timestamp.IsZero() {
+		t.Errorf("unexpected second message: %+v", msgs[1])
+	}
+}
+
+func TestReadPCAP(t *testing.T) {
+	payload := []byte("OPTIONS sip:x@example.com SIP/2.0\r\nCall-ID: p1\r\n\r\n")
+
+	var buf bytes.Buffer
+	hdr := make([]byte, 24)
+	binary.LittleEndian.PutUint32(hdr[0:], pcapMagicMicros)
+	binary.LittleEndian.PutUint16(hdr[4:], 2)
+	binary.LittleEndian.PutUint16(hdr[6:], 4)
+	binary.LittleEndian.PutUint32(hdr[16:], 65535)
+	binary.LittleEndian.PutUint32(hdr[20:], linkTypeEthernet)
+	buf.Write(hdr)
+
+	pkt := make([]byte, 14+20+8)
+	binary.BigEndian.PutUint16(pkt[12:], 0x0800)
+	ip := pkt[14:]
+	ip[0] = 0x45
+	ip[9] = 17
+	copy(ip[12:], []byte{192, 0, 2, 1})
+	copy(ip[16:], []byte{192, 0, 2, 2})
+	udp := ip[20:]
+	binary.BigEndian.PutUint16(udp[0:], 5060)
+	binary.BigEndian.PutUint16(udp[2:], 5080)
+	pkt = append(pkt, payload...)
+
+	rec := make([]byte, 16)
+	binary.LittleEndian.PutUint32(rec[0:], 1735725600)
+	binary.LittleEndian.PutUint32(rec[4:], 250000)
+	binary.LittleEndian.PutUint32(rec[8:], uint32(len(pkt)))
+	binary.LittleEndian.PutUint32(rec[12:], uint32(len(pkt)))
+	buf.Write(rec)
+	buf.Write(pkt)
+
+	if !isPCAP(buf.Bytes()) {
+		t.Fatal("expected pcap magic to be detected")
+	}
+	msgs, err := ReadPCAP(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	m := msgs[0]
+	if m.Method != "OPTIONS" || m.SrcIP != "192.0.2.1" || m.DstPort != 5080 {
+		t.Errorf("unexpected message: %s %s:%d -> %s:%d", m.Method, m.SrcIP, m.SrcPort, m.DstIP, m.DstPort)
+	}
+	if m.Timestamp.Nanosecond() != 250000000 {
+		t.Errorf("expected microsecond timestamp to be converted, got %v", m.Timestamp)
+	}
+}
+
+func TestGroupByCallID(t *testing.T) {
+	mk := func(id string) *Message {
+		return &Message{Headers: []Header{{Name: "Call-ID", Value: id}}}
+	}
+	order, calls := GroupByCallID([]*Message{mk("b"), mk("a"), mk("b")})
+	if len(order) != 2 || order[0] != "b" || order[1] != "a" {
+		t.Errorf("unexpected order %v", order)
+	}
+	if len(calls["b"]) != 2 {
+		t.Errorf("expected 2 messages for call b, got %d", len(calls["b"]))
+	}
+}
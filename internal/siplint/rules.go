@@ -0,0 +1,546 @@
+package siplint
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/sip"
+)
+
+var ruleMandatoryHeaders = Rule{
+	ID:          "mandatory-headers",
+	Severity:    Error,
+	Description: "Requests and responses carry Via, To, From, Call-ID, CSeq (and Max-Forwards for requests); bodies have a Content-Type",
+	Reference:   "RFC 3261 §8.1.1, §8.2.6.2, §20.15",
+	check:       checkMandatoryHeaders,
+}
+
+var ruleCSeqOrder = Rule{
+	ID:          "cseq-order",
+	Severity:    Error,
+	Description: "CSeq numbers increase per UA, the CSeq method matches the request, ACK/CANCEL reuse the INVITE's number",
+	Reference:   "RFC 3261 §8.1.1.5, §12.2.1.1",
+	check:       checkCSeqOrder,
+}
+
+var ruleViaBranch = Rule{
+	ID:          "via-branch",
+	Severity:    Error,
+	Description: "The top Via branch starts with the z9hG4bK magic cookie; CANCEL reuses the INVITE branch",
+	Reference:   "RFC 3261 §8.1.1.7, §9.1",
+	check:       checkViaBranch,
+}
+
+var ruleToTag = Rule{
+	ID:          "to-tag",
+	Severity:    Error,
+	Description: "Responses other than 100 carry a To tag that stays stable per transaction; in-dialog requests use established tags",
+	Reference:   "RFC 3261 §8.2.6.2, §12.2.1.1",
+	check:       checkToTag,
+}
+
+var ruleReinviteGlare = Rule{
+	ID:          "reinvite-glare",
+	Severity:    Warning,
+	Description: "Overlapping re-INVITEs from both parties, 491 responses and retry timing after 491",
+	Reference:   "RFC 3261 §14.1, §14.2",
+	check:       checkReinviteGlare,
+}
+
+var ruleMissingACK = Rule{
+	ID:          "missing-ack",
+	Severity:    Error,
+	Description: "Every final response to an INVITE is acknowledged with an ACK",
+	Reference:   "RFC 3261 §13.2.2.4, §17.1.1.3",
+	check:       checkMissingACK,
+}
+
+var ruleUnansweredPRACK = Rule{
+	ID:          "unanswered-prack",
+	Severity:    Warning,
+	Description: "Reliable provisional responses carry RSeq and are PRACKed; every PRACK gets a final response",
+	Reference:   "RFC 3262 §3, §4, §7",
+	check:       checkUnansweredPRACK,
+}
+
+var ruleSessionTimer = Rule{
+	ID:          "session-timer",
+	Severity:    Warning,
+	Description: "Session-Expires respects Min-SE, 2xx responses name a refresher and require timer, sessions are refreshed in time",
+	Reference:   "RFC 4028 §4, §6, §9, §10",
+	check:       checkSessionTimer,
+}
+
+var ruleContactRoute = Rule{
+	ID:          "contact-route",
+	Severity:    Warning,
+	Description: "Dialog-creating messages carry exactly one SIP Contact, Record-Route uses loose routing and is mirrored in 2xx, Contact is not a private address behind NAT",
+	Reference:   "RFC 3261 §8.1.1.8, §12.1.1, §16.6, §20.30",
+	check:       checkContactRoute,
+}
+
+// txKey identifies a transaction by requester tag, CSeq number and method.
+func txKey(tag string, seq int, method string) string {
+	return tag + "|" + strconv.Itoa(seq) + "|" + method
+}
+
+func checkMandatoryHeaders(c *Call) []hit {
+	var hits []hit
+	for i, m := range c.Messages {
+		required := []string{"Via", "To", "From", "Call-ID", "CSeq"}
+		if m.IsRequest() {
+			required = append(required, "Max-Forwards")
+		}
+		for _, name := range required {
+			if !m.Has(name) {
+				hits = append(hits, hitf(i, "missing mandatory %s header", name))
+			}
+		}
+		if m.Has("CSeq") {
+			if _, _, ok := m.CSeq(); !ok {
+				hits = append(hits, hitf(i, "malformed CSeq header %q", m.Header("CSeq")))
+			}
+		}
+		if strings.TrimSpace(m.Body) != "" && !m.Has("Content-Type") {
+			hits = append(hits, hitf(i, "message has a body but no Content-Type header"))
+		}
+	}
+	return hits
+}
+
+func checkCSeqOrder(c *Call) []hit {
+	var hits []hit
+	last := make(map[string]int)
+	seen := make(map[string]bool)
+	invites := make(map[string]map[int]bool)
+
+	for i, m := range c.Messages {
+		if !m.IsRequest() {
+			continue
+		}
+		seq, method, ok := m.CSeq()
+		if !ok {
+			continue
+		}
+		if method != m.Method {
+			hits = append(hits, hitf(i, "CSeq method %s does not match request method %s", method, m.Method))
+		}
+
+		tag := m.FromTag()
+		if m.Method == "ACK" || m.Method == "CANCEL" {
+			if len(invites[tag]) > 0 && !invites[tag][seq] {
+				hits = append(hits, hitf(i, "%s CSeq %d does not match any INVITE from the same UA", m.Method, seq))
+			}
+			continue
+		}
+
+		// The same request seen again is a retransmission or another hop.
+		key := txKey(tag, seq, m.Method)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if prev, ok := last[tag]; ok && seq <= prev {
+			hits = append(hits, hitf(i, "CSeq %d is not higher than the previous request's CSeq %d from the same UA", seq, prev))
+		}
+		if seq > last[tag] {
+			last[tag] = seq
+		}
+		if m.Method == "INVITE" {
+			if invites[tag] == nil {
+				invites[tag] = make(map[int]bool)
+			}
+			invites[tag][seq] = true
+		}
+	}
+	return hits
+}
+
+func checkViaBranch(c *Call) []hit {
+	var hits []hit
+	for i, m := range c.Messages {
+		if !m.IsRequest() || !m.Has("Via") {
+			continue
+		}
+		branch := m.Branch()
+		switch {
+		case branch == "":
+			hits = append(hits, hitf(i, "top Via has no branch parameter"))
+		case !strings.HasPrefix(branch, "z9hG4bK"):
+			hits = append(hits, hitf(i, "top Via branch %q lacks the z9hG4bK magic cookie", branch))
+		}
+
+		if m.Method != "CANCEL" {
+			continue
+		}
+		seq, _, _ := m.CSeq()
+		var branches []string
+		for _, inv := range c.Messages[:i] {
+			invSeq, _, _ := inv.CSeq()
+			if inv.Method != "INVITE" || invSeq != seq || inv.FromTag() != m.FromTag() {
+				continue
+			}
+			if m.SrcIP != "" && inv.SrcIP != "" && (inv.SrcIP != m.SrcIP || inv.DstIP != m.DstIP) {
+				continue
+			}
+			branches = append(branches, inv.Branch())
+		}
+		if len(branches) > 0 && !contains(branches, branch) {
+			hits = append(hits, hitf(i, "CANCEL branch %q differs from the INVITE branch %q", branch, branches[0]))
+		}
+	}
+	return hits
+}
+
+func checkToTag(c *Call) []hit {
+	var hits []hit
+	dialogs := make(map[string]bool)
+	txTags := make(map[string]string)
+
+	for i, m := range c.Messages {
+		if !m.IsRequest() {
+			if m.StatusCode <= 100 || !m.Has("To") {
+				continue
+			}
+			to := m.ToTag()
+			if to == "" {
+				hits = append(hits, hitf(i, "%d response has no To tag", m.StatusCode))
+				continue
+			}
+			dialogs[m.FromTag()+"|"+to] = true
+
+			if m.SrcIP == "" {
+				continue
+			}
+			seq, method, _ := m.CSeq()
+			key := txKey(m.FromTag(), seq, method) + "|" + m.SrcIP + ":" + strconv.Itoa(m.SrcPort)
+			if prev, ok := txTags[key]; ok && prev != to {
+				hits = append(hits, hitf(i, "response changes the To tag from %q to %q within the same transaction", prev, to))
+			}
+			txTags[key] = to
+			continue
+		}
+
+		to := m.ToTag()
+		if to == "" || len(dialogs) == 0 {
+			continue
+		}
+		from := m.FromTag()
+		if !dialogs[from+"|"+to] && !dialogs[to+"|"+from] {
+			hits = append(hits, hitf(i, "in-dialog %s uses To tag %q which matches no dialog established by earlier responses", m.Method, to))
+		}
+	}
+	return hits
+}
+
+func checkReinviteGlare(c *Call) []hit {
+	var hits []hit
+	var ownerTag string
+	pending := make(map[string]int)
+	last491 := make(map[string]time.Time)
+
+	for i, m := range c.Messages {
+		seq, method, _ := m.CSeq()
+		tag := m.FromTag()
+
+		if m.IsRequest() {
+			if m.Method != "INVITE" {
+				continue
+			}
+			if m.ToTag() == "" {
+				if ownerTag == "" {
+					ownerTag = tag
+				}
+				continue
+			}
+			if p, ok := pending[tag]; ok && p == seq {
+				continue
+			}
+			for other, otherSeq := range pending {
+				if other != tag {
+					hits = append(hits, hitf(i, "re-INVITE sent while a re-INVITE (CSeq %d) from the other party is still pending", otherSeq))
+				}
+			}
+			pending[tag] = seq
+
+			if t, ok := last491[tag]; ok && !t.IsZero() && !m.Timestamp.IsZero() {
+				wait := m.Timestamp.Sub(t)
+				owner := tag == ownerTag
+				switch {
+				case owner && (wait < 2100*time.Millisecond || wait > 4*time.Second):
+					hits = append(hits, hitf(i, "re-INVITE retried %.2fs after 491; the Call-ID owner must wait 2.1-4s", wait.Seconds()))
+				case !owner && wait > 2*time.Second:
+					hits = append(hits, hitf(i, "re-INVITE retried %.2fs after 491; the non-owner must wait 0-2s", wait.Seconds()))
+				}
+			}
+			delete(last491, tag)
+			continue
+		}
+
+		if method != "INVITE" || m.StatusCode < 200 {
+			continue
+		}
+		if pending[tag] == seq {
+			delete(pending, tag)
+		}
+		if m.StatusCode == 491 {
+			hits = append(hits, hitf(i, "491 Request Pending: re-INVITE glare on CSeq %d", seq))
+			last491[tag] = m.Timestamp
+		}
+	}
+	return hits
+}
+
+func checkMissingACK(c *Call) []hit {
+	var hits []hit
+	acked := make(map[string]bool)
+	for _, m := range c.Messages {
+		if m.Method == "ACK" {
+			seq, _, _ := m.CSeq()
+			acked[txKey(m.FromTag(), seq, "INVITE")] = true
+		}
+	}
+
+	reported := make(map[string]bool)
+	for i, m := range c.Messages {
+		if m.IsRequest() || m.StatusCode < 200 {
+			continue
+		}
+		seq, method, _ := m.CSeq()
+		if method != "INVITE" {
+			continue
+		}
+		key := txKey(m.FromTag(), seq, method)
+		if acked[key] || reported[key] {
+			continue
+		}
+		reported[key] = true
+		hits = append(hits, hitf(i, "%d response to INVITE CSeq %d was never acknowledged", m.StatusCode, seq))
+	}
+	return hits
+}
+
+func checkUnansweredPRACK(c *Call) []hit {
+	var hits []hit
+
+	pracked := make(map[string]bool)
+	answered := make(map[string]bool)
+	for _, m := range c.Messages {
+		if m.Method == "PRACK" {
+			pracked[m.FromTag()+"|"+strings.Join(strings.Fields(m.Header("RAck")), " ")] = true
+		}
+		if !m.IsRequest() && m.StatusCode >= 200 {
+			seq, method, _ := m.CSeq()
+			if method == "PRACK" {
+				answered[txKey(m.FromTag(), seq, method)] = true
+			}
+		}
+	}
+
+	reliable := make(map[string]bool)
+	for i, m := range c.Messages {
+		switch {
+		case !m.IsRequest() && m.StatusCode > 100 && m.StatusCode < 200 && m.HasOption("Require", "100rel"):
+			rseq := strings.TrimSpace(m.Header("RSeq"))
+			if rseq == "" {
+				hits = append(hits, hitf(i, "reliable provisional response has no RSeq header"))
+				continue
+			}
+			seq, method, _ := m.CSeq()
+			rack := fmt.Sprintf("%s %d %s", rseq, seq, method)
+			key := m.FromTag() + "|" + rack
+			if reliable[key] {
+				continue
+			}
+			reliable[key] = true
+			if !pracked[key] {
+				hits = append(hits, hitf(i, "reliable provisional response RSeq %s was not acknowledged by PRACK", rseq))
+			}
+
+		case m.Method == "PRACK":
+			seq, _, _ := m.CSeq()
+			if !answered[txKey(m.FromTag(), seq, "PRACK")] {
+				hits = append(hits, hitf(i, "PRACK CSeq %d received no final response", seq))
+			}
+		}
+	}
+	return hits
+}
+
+// sessionExpires parses a Session-Expires or Min-SE header value.
+func sessionExpires(value string) (int, bool) {
+	v, _, _ := strings.Cut(value, ";")
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	return n, err == nil
+}
+
+func checkSessionTimer(c *Call) []hit {
+	var hits []hit
+	for i, m := range c.Messages {
+		if !m.IsRequest() && m.StatusCode == 422 && !m.Has("Min-SE") {
+			hits = append(hits, hitf(i, "422 response lacks a Min-SE header"))
+		}
+
+		raw := m.Header("Session-Expires")
+		if raw == "" {
+			continue
+		}
+		se, ok := sessionExpires(raw)
+		if !ok {
+			hits = append(hits, hitf(i, "malformed Session-Expires %q", raw))
+			continue
+		}
+		minSE := 90
+		if v, ok := sessionExpires(m.Header("Min-SE")); ok {
+			minSE = v
+		}
+		if se < minSE {
+			hits = append(hits, hitf(i, "Session-Expires %d is below Min-SE %d", se, minSE))
+		}
+
+		_, method, _ := m.CSeq()
+		if m.IsRequest() || m.StatusCode < 200 || m.StatusCode > 299 || (method != "INVITE" && method != "UPDATE") {
+			continue
+		}
+		if sip.Param(raw, "refresher") == "" {
+			hits = append(hits, hitf(i, "2xx response with Session-Expires does not name a refresher"))
+		}
+		if !m.HasOption("Require", "timer") {
+			hits = append(hits, hitf(i, "2xx response with Session-Expires lacks Require: timer"))
+		}
+		if h, ok := checkRefresh(c, i, se); ok {
+			hits = append(hits, h)
+		}
+	}
+	return hits
+}
+
+// checkRefresh verifies that the session negotiated by the 2xx at index i is
+// refreshed or torn down before it expires.
+func checkRefresh(c *Call, i, se int) (hit, bool) {
+	start := c.Messages[i].Timestamp
+	if start.IsZero() {
+		return hit{}, false
+	}
+	deadline := start.Add(time.Duration(se) * time.Second)
+
+	for _, m := range c.Messages[i+1:] {
+		if m.Timestamp.IsZero() {
+			continue
+		}
+		_, method, _ := m.CSeq()
+		refresh := !m.IsRequest() && m.StatusCode >= 200 && m.StatusCode < 300 && (method == "INVITE" || method == "UPDATE")
+		if refresh || m.Method == "BYE" {
+			if m.Timestamp.After(deadline) {
+				return hitf(i, "session not refreshed within Session-Expires of %ds (next refresh or BYE after %s)", se, m.Timestamp.Sub(start).Round(time.Second)), true
+			}
+			return hit{}, false
+		}
+	}
+
+	last := c.Messages[len(c.Messages)-1].Timestamp
+	if last.After(deadline) {
+		return hitf(i, "session not refreshed within Session-Expires of %ds", se), true
+	}
+	return hit{}, false
+}
+
+func checkContactRoute(c *Call) []hit {
+	var hits []hit
+	for i, m := range c.Messages {
+		seq, method, _ := m.CSeq()
+
+		dialogCreating := (m.IsRequest() && (m.Method == "INVITE" || m.Method == "SUBSCRIBE" || m.Method == "REFER") && m.ToTag() == "") ||
+			(!m.IsRequest() && m.StatusCode >= 200 && m.StatusCode < 300 && (method == "INVITE" || method == "SUBSCRIBE"))
+
+		contacts := m.Values("Contact")
+		if dialogCreating {
+			switch {
+			case len(contacts) == 0:
+				hits = append(hits, hitf(i, "dialog-creating message lacks a Contact header"))
+			case len(contacts) > 1:
+				hits = append(hits, hitf(i, "dialog-creating message carries %d Contact URIs; exactly one is allowed", len(contacts)))
+			}
+		}
+		for _, ct := range contacts {
+			if strings.TrimSpace(ct) == "*" {
+				continue
+			}
+			uri := sip.URI(ct)
+			lower := strings.ToLower(uri)
+			if dialogCreating && !strings.HasPrefix(lower, "sip:") && !strings.HasPrefix(lower, "sips:") {
+				hits = append(hits, hitf(i, "Contact %q is not a SIP URI", uri))
+				continue
+			}
+			if h := natContact(m, uri); h != "" {
+				hits = append(hits, hitf(i, "%s", h))
+			}
+		}
+
+		for _, rr := range m.Values("Record-Route") {
+			uri := sip.URI(rr)
+			if !strings.Contains(strings.ToLower(uri), ";lr") {
+				hits = append(hits, hitf(i, "Record-Route %s lacks the lr parameter (strict routing)", uri))
+			}
+		}
+
+		if !m.IsRequest() && m.StatusCode >= 200 && m.StatusCode < 300 && method == "INVITE" && m.SrcIP != "" {
+			if req := matchingInvite(c, i, seq); req != nil {
+				want := strings.Join(req.Values("Record-Route"), ",")
+				got := strings.Join(m.Values("Record-Route"), ",")
+				if want != got {
+					hits = append(hits, hitf(i, "Record-Route set in 2xx (%s) differs from the INVITE's (%s)", orNone(got), orNone(want)))
+				}
+			}
+		}
+	}
+	return hits
+}
+
+// natContact reports a Contact that points at a private address while the
+// message itself was sent from a public one.
+func natContact(m *sip.Message, uri string) string {
+	host, err := netip.ParseAddr(sip.URIHost(uri))
+	if err != nil || !host.IsPrivate() {
+		return ""
+	}
+	src, err := netip.ParseAddr(m.SrcIP)
+	if err != nil || src.IsPrivate() || src.IsLoopback() {
+		return ""
+	}
+	return fmt.Sprintf("Contact host %s is a private address but the message was sent from %s (NAT?)", host, src)
+}
+
+// matchingInvite finds the INVITE answered by the 2xx at index i, captured on
+// the same hop (its destination is the response's source).
+func matchingInvite(c *Call, i, seq int) *sip.Message {
+	resp := c.Messages[i]
+	for j := i - 1; j >= 0; j-- {
+		m := c.Messages[j]
+		s, _, _ := m.CSeq()
+		if m.Method == "INVITE" && s == seq && m.FromTag() == resp.FromTag() && m.DstIP == resp.SrcIP && m.SrcIP == resp.DstIP {
+			return m
+		}
+	}
+	return nil
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package siplint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/sip"
+)
+
+// Severity ranks how serious a finding is.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+var severityNames = []string{"info", "warning", "error"}
+
+func (s Severity) String() string {
+	if int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// MarshalText renders the severity by name in JSON and YAML output.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseSeverity parses "info", "warning" or "error".
+func ParseSeverity(s string) (Severity, error) {
+	for i, name := range severityNames {
+		if strings.EqualFold(s, name) {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q (valid: %s)", s, strings.Join(severityNames, ", "))
+}
+
+// Finding is a single rule violation in a captured call.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	CallID   string   `json:"call_id"`
+	// Message is the 1-based position of the offending message within its call.
+	Message   int    `json:"message"`
+	Time      string `json:"time,omitempty"`
+	StartLine string `json:"start_line"`
+	Text      string `json:"text"`
+	Reference string `json:"reference"`
+}
+
+// Rule is a named protocol check that can be enabled or disabled individually.
+type Rule struct {
+	ID          string   `json:"id"`
+	Severity    Severity `json:"severity"`
+	Description string   `json:"description"`
+	Reference   string   `json:"reference"`
+
+	check func(c *Call) []hit
+}
+
+// hit is a violation reported by a rule check before it is turned into a Finding.
+type hit struct {
+	index int
+	text  string
+}
+
+func hitf(index int, format string, args ...interface{}) hit {
+	return hit{index: index, text: fmt.Sprintf(format, args...)}
+}
+
+// Call is the ordered set of messages sharing one Call-ID.
+type Call struct {
+	ID       string
+	Messages []*sip.Message
+}
+
+// Rules returns all available rules in evaluation order.
+func Rules() []Rule {
+	return []Rule{
+		ruleMandatoryHeaders,
+		ruleCSeqOrder,
+		ruleViaBranch,
+		ruleToTag,
+		ruleReinviteGlare,
+		ruleMissingACK,
+		ruleUnansweredPRACK,
+		ruleSessionTimer,
+		ruleContactRoute,
+	}
+}
+
+// Select returns the rules to run. If only is non-empty, just those rules are
+// enabled; rules listed in disable are then removed. Unknown IDs are an error.
+func Select(only, disable []string) ([]Rule, error) {
+	all := Rules()
+	known := make(map[string]bool, len(all))
+	for _, r := range all {
+		known[r.ID] = true
+	}
+	for _, id := range append(append([]string{}, only...), disable...) {
+		if !known[id] {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+	}
+
+	enabled := make(map[string]bool, len(all))
+	for _, r := range all {
+		enabled[r.ID] = len(only) == 0
+	}
+	for _, id := range only {
+		enabled[id] = true
+	}
+	for _, id := range disable {
+		enabled[id] = false
+	}
+
+	var rules []Rule
+	for _, r := range all {
+		if enabled[r.ID] {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+// Lint groups messages by Call-ID and runs the given rules against each call.
+// Findings are ordered by call, then message position, then rule order.
+func Lint(msgs []*sip.Message, rules []Rule) []Finding {
+	order, calls := sip.GroupByCallID(msgs)
+
+	ruleOrder := make(map[string]int, len(rules))
+	for i, r := range rules {
+		ruleOrder[r.ID] = i
+	}
+
+	findings := []Finding{}
+	for _, id := range order {
+		c := &Call{ID: id, Messages: calls[id]}
+		var callFindings []Finding
+		for _, rule := range rules {
+			for _, h := range rule.check(c) {
+				m := c.Messages[h.index]
+				f := Finding{
+					Rule:      rule.ID,
+					Severity:  rule.Severity,
+					CallID:    c.ID,
+					Message:   h.index + 1,
+					StartLine: m.Summary(),
+					Text:      h.text,
+					Reference: rule.Reference,
+				}
+				if !m.Timestamp.IsZero() {
+					f.Time = m.Timestamp.Format(time.RFC3339Nano)
+				}
+				callFindings = append(callFindings, f)
+			}
+		}
+		sort.SliceStable(callFindings, func(i, j int) bool {
+			if callFindings[i].Message != callFindings[j].Message {
+				return callFindings[i].Message < callFindings[j].Message
+			}
+			return ruleOrder[callFindings[i].Rule] < ruleOrder[callFindings[j].Rule]
+		})
+		findings = append(findings, callFindings...)
+	}
+	return findings
+}
+
+// CountAtLeast returns the number of findings with severity >= min.
+func CountAtLeast(findings []Finding, min Severity) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity >= min {
+			n++
+		}
+	}
+	return n
+}
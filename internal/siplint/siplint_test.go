@@ -0,0 +1,271 @@
+package siplint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"hepic-cli/internal/sip"
+)
+
+var t0 = time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// msg builds a message from header lines, sent from src to dst at t0+offset.
+func msg(t *testing.T, offset time.Duration, src, dst string, lines ...string) *sip.Message {
+	t.Helper()
+	m, err := sip.Parse(strings.Join(lines, "\r\n") + "\r\n\r\n")
+	if err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	m.Timestamp = t0.Add(offset)
+	m.SrcIP, m.SrcPort = src, 5060
+	m.DstIP, m.DstPort = dst, 5060
+	return m
+}
+
+const (
+	alice = "192.0.2.10"
+	bob   = "192.0.2.20"
+)
+
+// basicCall returns a compliant INVITE / 200 / ACK / BYE / 200 exchange.
+func basicCall(t *testing.T) []*sip.Message {
+	common := []string{"Call-ID: call-1", "Max-Forwards: 70"}
+	req := func(start, via, from, to, cseq string, extra ...string) []string {
+		return append(append([]string{start, "Via: SIP/2.0/UDP " + via, "From: " + from, "To: " + to, "CSeq: " + cseq}, common...), extra...)
+	}
+	resp := func(start, via, from, to, cseq string, extra ...string) []string {
+		return append([]string{start, "Via: SIP/2.0/UDP " + via, "From: " + from, "To: " + to, "CSeq: " + cseq, "Call-ID: call-1"}, extra...)
+	}
+	a := "<sip:alice@example.com>;tag=a1"
+	b := "<sip:bob@example.com>"
+	bt := b + ";tag=b1"
+
+	return []*sip.Message{
+		msg(t, 0, alice, bob, req("INVITE sip:bob@example.com SIP/2.0", alice+";branch=z9hG4bK1", a, b, "1 INVITE", "Contact: <sip:alice@192.0.2.10>")...),
+		msg(t, 100*time.Millisecond, bob, alice, resp("SIP/2.0 100 Trying", alice+";branch=z9hG4bK1", a, b, "1 INVITE")...),
+		msg(t, time.Second, bob, alice, resp("SIP/2.0 200 OK", alice+";branch=z9hG4bK1", a, bt, "1 INVITE", "Contact: <sip:bob@192.0.2.20>")...),
+		msg(t, 1100*time.Millisecond, alice, bob, req("ACK sip:bob@192.0.2.20 SIP/2.0", alice+";branch=z9hG4bK2", a, bt, "1 ACK")...),
+		msg(t, 10*time.Second, alice, bob, req("BYE sip:bob@192.0.2.20 SIP/2.0", alice+";branch=z9hG4bK3", a, bt, "2 BYE")...),
+		msg(t, 10100*time.Millisecond, bob, alice, resp("SIP/2.0 200 OK", alice+";branch=z9hG4bK3", a, bt, "2 BYE")...),
+	}
+}
+
+func lintAll(msgs []*sip.Message) []Finding {
+	return Lint(msgs, Rules())
+}
+
+func rulesHit(findings []Finding) map[string]int {
+	hits := make(map[string]int)
+	for _, f := range findings {
+		hits[f.Rule]++
+	}
+	return hits
+}
+
+func TestLint_CleanCall(t *testing.T) {
+	findings := lintAll(basicCall(t))
+	if len(findings) != 0 {
+		t.Errorf("expected no findings for a compliant call, got %+v", findings)
+	}
+}
+
+func TestLint_MissingACK(t *testing.T) {
+	msgs := basicCall(t)
+	msgs = append(msgs[:3], msgs[4:]...)
+
+	findings := lintAll(msgs)
+	if rulesHit(findings)["missing-ack"] != 1 {
+		t.Fatalf("expected one missing-ack finding, got %+v", findings)
+	}
+	f := findings[0]
+	if f.Message != 3 || f.StartLine != "200 OK" || f.Severity != Error || f.CallID != "call-1" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+	if f.Reference == "" || f.Time == "" {
+		t.Errorf("expected reference and time in finding, got %+v", f)
+	}
+}
+
+func TestLint_ViaBranchAndMandatoryHeaders(t *testing.T) {
+	m := msg(t, 0, alice, bob,
+		"OPTIONS sip:bob@example.com SIP/2.0",
+		"Via: SIP/2.0/UDP 192.0.2.10;branch=abc",
+		"From: <sip:alice@example.com>;tag=x",
+		"To: <sip:bob@example.com>",
+		"Call-ID: call-2",
+	)
+	hits := rulesHit(lintAll([]*sip.Message{m}))
+	if hits["via-branch"] != 1 {
+		t.Errorf("expected via-branch finding, got %v", hits)
+	}
+	if hits["mandatory-headers"] != 2 {
+		t.Errorf("expected missing CSeq and Max-Forwards, got %v", hits)
+	}
+}
+
+func TestLint_CSeqOrder(t *testing.T) {
+	msgs := basicCall(t)
+	msgs[4].Headers = replaceHeader(msgs[4].Headers, "CSeq", "1 BYE")
+
+	hits := rulesHit(lintAll(msgs))
+	if hits["cseq-order"] != 1 {
+		t.Errorf("expected cseq-order finding for a reused CSeq, got %v", hits)
+	}
+}
+
+func TestLint_ToTag(t *testing.T) {
+	msgs := basicCall(t)
+	msgs[2].Headers = replaceHeader(msgs[2].Headers, "To", "<sip:bob@example.com>")
+
+	hits := rulesHit(lintAll(msgs))
+	if hits["to-tag"] == 0 {
+		t.Errorf("expected to-tag finding for a 200 without tag, got %v", hits)
+	}
+}
+
+func TestLint_SessionTimer(t *testing.T) {
+	msgs := basicCall(t)
+	msgs[2].Headers = append(msgs[2].Headers, sip.Header{Name: "Session-Expires", Value: "60"})
+
+	findings := lintAll(msgs)
+	var texts []string
+	for _, f := range findings {
+		if f.Rule == "session-timer" {
+			texts = append(texts, f.Text)
+		}
+	}
+	// below Min-SE, no refresher, no Require: timer
+	if len(texts) != 3 {
+		t.Errorf("expected 3 session-timer findings, got %v", texts)
+	}
+}
+
+func TestLint_SessionNotRefreshed(t *testing.T) {
+	msgs := basicCall(t)
+	msgs[2].Headers = append(msgs[2].Headers,
+		sip.Header{Name: "Session-Expires", Value: "90;refresher=uac"},
+		sip.Header{Name: "Require", Value: "timer"})
+	msgs[4].Timestamp = t0.Add(5 * time.Minute)
+	msgs[5].Timestamp = t0.Add(5*time.Minute + 100*time.Millisecond)
+
+	hits := rulesHit(lintAll(msgs))
+	if hits["session-timer"] != 1 {
+		t.Errorf("expected one refresh violation, got %v", hits)
+	}
+}
+
+func TestLint_ContactRoute(t *testing.T) {
+	msgs := basicCall(t)
+	msgs[0].Headers = replaceHeader(msgs[0].Headers, "Contact", "<sip:alice@10.1.1.1>")
+	msgs[0].Headers = append(msgs[0].Headers, sip.Header{Name: "Record-Route", Value: "<sip:proxy.example.com>"})
+
+	findings := lintAll(msgs)
+	var texts []string
+	for _, f := range findings {
+		if f.Rule == "contact-route" {
+			texts = append(texts, f.Text)
+		}
+	}
+	if len(texts) != 3 {
+		t.Fatalf("expected NAT, strict-route and RR-mirror findings, got %v", texts)
+	}
+}
+
+func TestLint_UnansweredPRACK(t *testing.T) {
+	msgs := basicCall(t)
+	rel := msg(t, 500*time.Millisecond, bob, alice,
+		"SIP/2.0 183 Session Progress",
+		"Via: SIP/2.0/UDP 192.0.2.10;branch=z9hG4bK1",
+		"From: <sip:alice@example.com>;tag=a1",
+		"To: <sip:bob@example.com>;tag=b1",
+		"CSeq: 1 INVITE",
+		"Call-ID: call-1",
+		"Require: 100rel",
+		"RSeq: 1",
+	)
+	msgs = append(msgs[:2], append([]*sip.Message{rel}, msgs[2:]...)...)
+
+	hits := rulesHit(lintAll(msgs))
+	if hits["unanswered-prack"] != 1 {
+		t.Errorf("expected unanswered-prack finding, got %v", hits)
+	}
+}
+
+func TestLint_ReinviteGlare(t *testing.T) {
+	msgs := basicCall(t)[:4]
+	reinvite := func(offset time.Duration, src, dst, from, to, cseq string) *sip.Message {
+		return msg(t, offset, src, dst,
+			"INVITE sip:x@example.com SIP/2.0",
+			"Via: SIP/2.0/UDP "+src+";branch=z9hG4bK"+cseq,
+			"From: "+from, "To: "+to,
+			"CSeq: "+cseq+" INVITE",
+			"Call-ID: call-1", "Max-Forwards: 70",
+			"Contact: <sip:x@"+src+">",
+		)
+	}
+	msgs = append(msgs,
+		reinvite(5*time.Second, alice, bob, "<sip:alice@example.com>;tag=a1", "<sip:bob@example.com>;tag=b1", "2"),
+		reinvite(5100*time.Millisecond, bob, alice, "<sip:bob@example.com>;tag=b1", "<sip:alice@example.com>;tag=a1", "7"),
+	)
+
+	hits := rulesHit(Lint(msgs, mustSelect(t, []string{"reinvite-glare"}, nil)))
+	if hits["reinvite-glare"] != 1 {
+		t.Errorf("expected glare finding, got %v", hits)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	rules := mustSelect(t, []string{"cseq-order", "missing-ack"}, []string{"missing-ack"})
+	if len(rules) != 1 || rules[0].ID != "cseq-order" {
+		t.Errorf("unexpected selection %+v", rules)
+	}
+
+	all := mustSelect(t, nil, []string{"session-timer"})
+	if len(all) != len(Rules())-1 {
+		t.Errorf("expected all rules but one, got %d", len(all))
+	}
+
+	if _, err := Select([]string{"no-such-rule"}, nil); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	s, err := ParseSeverity("Warning")
+	if err != nil || s != Warning {
+		t.Fatalf("unexpected result %v %v", s, err)
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+
+	data, _ := json.Marshal(Finding{Severity: Error})
+	if !strings.Contains(string(data), `"severity":"error"`) {
+		t.Errorf("expected severity to marshal by name, got %s", data)
+	}
+	if CountAtLeast([]Finding{{Severity: Info}, {Severity: Warning}, {Severity: Error}}, Warning) != 2 {
+		t.Error("expected 2 findings at warning or above")
+	}
+}
+
+func mustSelect(t *testing.T, only, disable []string) []Rule {
+	t.Helper()
+	rules, err := Select(only, disable)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	return rules
+}
+
+func replaceHeader(headers []sip.Header, name, value string) []sip.Header {
+	out := make([]sip.Header, 0, len(headers))
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			h.Value = value
+		}
+		out = append(out, h)
+	}
+	return out
+}
@@ -3,7 +3,14 @@ package statistic
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/ops"
 )
 
 // GetDashboard retrieves a Grafana dashboard by UID. GET /proxy/grafana/dashboards/uid/{uid}
@@ -27,7 +34,7 @@ func Org(ctx context.Context, client *api.Client) (json.RawMessage, error) {
 	return result, err
 }
 
-// SearchDashboard searches for a Grafana dashboard by UID. GET /proxy/grafana/search/{uid}
+// SearchDashboard searches for the dashboards of a Grafana folder by its ID. GET /proxy/grafana/search/{uid}
 func SearchDashboard(ctx context.Context, client *api.Client, uid string) (json.RawMessage, error) {
 	var result json.RawMessage
 	err := client.Get(ctx, "/proxy/grafana/search/"+api.PathEscape(uid), &result)
@@ -40,3 +47,239 @@ func Status(ctx context.Context, client *api.Client) (json.RawMessage, error) {
 	err := client.Get(ctx, "/proxy/grafana/status", &result)
 	return result, err
 }
+
+// URL retrieves the Grafana URL configured on the server. GET /proxy/grafana/url
+func URL(ctx context.Context, client *api.Client) (json.RawMessage, error) {
+	_, raw, err := ops.GetProxyGrafanaURL(ctx, client)
+	return raw, err
+}
+
+// Request fetches a dashboard page through the proxy, such as a rendered
+// panel. GET /proxy/grafana/request/d/{uid}/{param}
+//
+// The spec documents neither the query nor the image response, so this
+// does not go through ops.
+func Request(ctx context.Context, client *api.Client, uid, param string, query url.Values) (io.ReadCloser, error) {
+	path := "/proxy/grafana/request/d/" + api.PathEscape(uid) + "/" + api.PathEscape(param)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return client.GetRaw(ctx, path)
+}
+
+// BaseURL returns the Grafana URL with a trailing slash. The server
+// returns it bare or under "data" or "url".
+func BaseURL(ctx context.Context, client *api.Client) (string, error) {
+	raw, err := URL(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	var base string
+	if json.Unmarshal(raw, &base) != nil {
+		var wrapped map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &wrapped); err != nil {
+			return "", fmt.Errorf("unexpected Grafana URL: %s", raw)
+		}
+		for _, key := range []string{"data", "url"} {
+			if json.Unmarshal(wrapped[key], &base) == nil && base != "" {
+				break
+			}
+			var nested struct {
+				URL string `json:"url"`
+			}
+			if json.Unmarshal(wrapped[key], &nested) == nil && nested.URL != "" {
+				base = nested.URL
+				break
+			}
+		}
+	}
+	if base == "" {
+		return "", fmt.Errorf("the server has no Grafana URL configured")
+	}
+	return strings.TrimSuffix(base, "/") + "/", nil
+}
+
+// Dashboard is a Grafana dashboard as returned by GetDashboard: the
+// dashboard model and its metadata.
+type Dashboard struct {
+	Model map[string]interface{} `json:"dashboard"`
+	Meta  struct {
+		Slug        string `json:"slug"`
+		URL         string `json:"url"`
+		FolderTitle string `json:"folderTitle"`
+	} `json:"meta"`
+}
+
+// Title returns the dashboard title.
+func (d Dashboard) Title() string {
+	title, _ := d.Model["title"].(string)
+	return title
+}
+
+// Slug returns the dashboard slug, which Grafana URLs carry after the
+// UID.
+func (d Dashboard) Slug() string {
+	if d.Meta.Slug != "" {
+		return d.Meta.Slug
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(d.Title()) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	if slug := strings.TrimSuffix(b.String(), "-"); slug != "" {
+		return slug
+	}
+	return "dashboard"
+}
+
+// Panel finds a panel by ID, including the panels of collapsed rows.
+func (d Dashboard) Panel(id int) (map[string]interface{}, bool) {
+	var find func(panels interface{}) (map[string]interface{}, bool)
+	find = func(panels interface{}) (map[string]interface{}, bool) {
+		list, _ := panels.([]interface{})
+		for _, p := range list {
+			panel, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if n, ok := panel["id"].(float64); ok && int(n) == id {
+				return panel, true
+			}
+			if found, ok := find(panel["panels"]); ok {
+				return found, true
+			}
+		}
+		return nil, false
+	}
+	return find(d.Model["panels"])
+}
+
+// LoadDashboard retrieves a dashboard by UID.
+func LoadDashboard(ctx context.Context, client *api.Client, uid string) (*Dashboard, error) {
+	raw, err := GetDashboard(ctx, client, uid)
+	if err != nil {
+		return nil, err
+	}
+	var d Dashboard
+	if err := json.Unmarshal(raw, &d); err != nil || d.Model == nil {
+		return nil, fmt.Errorf("unexpected dashboard %s", uid)
+	}
+	return &d, nil
+}
+
+// DashboardURL builds a link to a dashboard. from and to are Grafana time
+// expressions (unix ms or now-6h); vars set template variables; a panel
+// ID above 0 opens that panel alone.
+func DashboardURL(base, uid, slug, from, to string, vars []string, panel int) (string, error) {
+	query := url.Values{}
+	if from != "" {
+		query.Set("from", from)
+	}
+	if to != "" {
+		query.Set("to", to)
+	}
+	if err := AddVars(query, vars); err != nil {
+		return "", err
+	}
+	if panel > 0 {
+		query.Set("viewPanel", strconv.Itoa(panel))
+	}
+	link := strings.TrimSuffix(base, "/") + "/d/" + url.PathEscape(uid) + "/" + url.PathEscape(slug)
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link, nil
+}
+
+// AddVars adds name=value template variables to a dashboard query as
+// the var-name parameters Grafana reads.
+func AddVars(query url.Values, vars []string) error {
+	for _, v := range vars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid variable %q: use name=value", v)
+		}
+		query.Add("var-"+name, value)
+	}
+	return nil
+}
+
+// Folder is a Grafana folder.
+type Folder struct {
+	ID    int64  `json:"id"`
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+// DashboardRef is a dashboard found by a search.
+type DashboardRef struct {
+	UID         string `json:"uid"`
+	Title       string `json:"title"`
+	FolderTitle string `json:"folderTitle"`
+	Type        string `json:"type"`
+}
+
+// ListFolders lists the Grafana folders, starting with the General folder
+// (ID 0) that Grafana does not list.
+func ListFolders(ctx context.Context, client *api.Client) ([]Folder, error) {
+	raw, err := Folders(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	folders := []Folder{{ID: 0, Title: "General"}}
+	var list []Folder
+	if err := json.Unmarshal(unwrap(raw), &list); err != nil {
+		return nil, fmt.Errorf("unexpected folder list: %w", err)
+	}
+	return append(folders, list...), nil
+}
+
+// FindFolder finds a folder by title (ignoring case), UID or ID.
+func FindFolder(folders []Folder, name string) (Folder, error) {
+	for _, f := range folders {
+		if strings.EqualFold(f.Title, name) || f.UID == name || strconv.FormatInt(f.ID, 10) == name {
+			return f, nil
+		}
+	}
+	return Folder{}, fmt.Errorf("folder %q not found", name)
+}
+
+// FolderDashboards lists the dashboards of a folder.
+func FolderDashboards(ctx context.Context, client *api.Client, folder Folder) ([]DashboardRef, error) {
+	raw, err := SearchDashboard(ctx, client, strconv.FormatInt(folder.ID, 10))
+	if err != nil {
+		return nil, err
+	}
+	var found []DashboardRef
+	if err := json.Unmarshal(unwrap(raw), &found); err != nil {
+		return nil, fmt.Errorf("unexpected search result: %w", err)
+	}
+	var dashboards []DashboardRef
+	for _, d := range found {
+		if d.Type != "" && d.Type != "dash-db" {
+			continue
+		}
+		if d.FolderTitle == "" {
+			d.FolderTitle = folder.Title
+		}
+		dashboards = append(dashboards, d)
+	}
+	return dashboards, nil
+}
+
+// unwrap returns the "data" of a response wrapped in {"data": ...}, or
+// the response itself.
+func unwrap(raw json.RawMessage) json.RawMessage {
+	var wrapped struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if json.Unmarshal(raw, &wrapped) == nil && len(wrapped.Data) > 0 {
+		return wrapped.Data
+	}
+	return raw
+}
@@ -0,0 +1,307 @@
+package statistic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+)
+
+// Target selects what the statistic backend (InfluxDB) is asked about: a
+// database, optionally a retention policy and measurement, and for data
+// queries the metrics (fields) and the tags to group by.
+type Target struct {
+	Database    string
+	Retention   string
+	Measurement string
+	Metrics     []string
+	GroupBy     []string
+}
+
+// param builds the "param" of a request about the target.
+func (t Target) param() map[string]interface{} {
+	query := map[string]interface{}{"database": t.Database}
+	if t.Retention != "" {
+		query["retention"] = t.Retention
+	}
+	if t.Measurement != "" {
+		query["main"] = t.Measurement
+	}
+	if len(t.Metrics) > 0 {
+		query["rawquery"] = ""
+		query["type"] = t.Metrics
+		query["tag"] = t.GroupBy
+		if t.GroupBy == nil {
+			query["tag"] = []string{}
+		}
+	}
+	return map[string]interface{}{
+		"search": map[string]interface{}{"database": t.Database},
+		"query":  []interface{}{query},
+	}
+}
+
+// NewDataQuery builds the body of a data query: the target's metrics from
+// from to to, aggregated every interval.
+func NewDataQuery(t Target, from, to time.Time, interval time.Duration, limit int) models.StatisticObject {
+	precision := int64(interval / time.Second)
+	if precision < 1 {
+		precision = 1
+	}
+	param := t.param()
+	delete(param, "search")
+	param["precision"] = precision
+	param["limit"] = limit
+	param["total"] = false
+	return models.StatisticObject{
+		Param:     param,
+		Timestamp: map[string]interface{}{"from": from.UnixMilli(), "to": to.UnixMilli()},
+	}
+}
+
+// Databases lists the statistic databases.
+func Databases(ctx context.Context, client *api.Client) ([]string, error) {
+	raw, err := DBStats(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return Names(raw)
+}
+
+// RetentionNames lists the retention policies of a database.
+func RetentionNames(ctx context.Context, client *api.Client, db string) ([]string, error) {
+	raw, err := Retentions(ctx, client, models.StatisticSearchObject{Param: Target{Database: db}.param()})
+	if err != nil {
+		return nil, err
+	}
+	return Names(raw)
+}
+
+// MeasurementNames lists the measurements of a database.
+func MeasurementNames(ctx context.Context, client *api.Client, db string) ([]string, error) {
+	raw, err := Measurements(ctx, client, db, models.StatisticSearchObject{Param: Target{Database: db}.param()})
+	if err != nil {
+		return nil, err
+	}
+	return Names(raw)
+}
+
+// MetricNames lists the metrics (field keys) of the target's measurement.
+func MetricNames(ctx context.Context, client *api.Client, t Target) ([]string, error) {
+	raw, err := Metrics(ctx, client, models.StatisticObject{Param: t.param()})
+	if err != nil {
+		return nil, err
+	}
+	return Names(raw)
+}
+
+// TagNames lists the tag keys of the target's measurement.
+func TagNames(ctx context.Context, client *api.Client, t Target) ([]string, error) {
+	raw, err := Tags(ctx, client, models.StatisticObject{Param: t.param()})
+	if err != nil {
+		return nil, err
+	}
+	return Names(raw)
+}
+
+// influxSeries is a series of an InfluxDB result: SHOW queries return
+// one column of names, SELECT queries a time column and value columns.
+type influxSeries struct {
+	Name    string
+	Tags    map[string]string
+	Columns []string
+	Values  [][]interface{}
+}
+
+// parseInflux collects the series of an InfluxDB result. The server wraps
+// the results differently by endpoint and version, so any object with
+// "columns" and "values" is taken as a series.
+func parseInflux(raw json.RawMessage) ([]influxSeries, error) {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	var series []influxSeries
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]interface{}:
+			columns, ok := field(v, "columns").([]interface{})
+			if !ok {
+				for _, item := range v {
+					walk(item)
+				}
+				return
+			}
+			s := influxSeries{Tags: map[string]string{}}
+			s.Name, _ = field(v, "name").(string)
+			for _, c := range columns {
+				s.Columns = append(s.Columns, fmt.Sprint(c))
+			}
+			if tags, ok := field(v, "tags").(map[string]interface{}); ok {
+				for k, t := range tags {
+					s.Tags[k] = fmt.Sprint(t)
+				}
+			}
+			rows, _ := field(v, "values").([]interface{})
+			for _, row := range rows {
+				if r, ok := row.([]interface{}); ok {
+					s.Values = append(s.Values, r)
+				}
+			}
+			series = append(series, s)
+		}
+	}
+	walk(doc)
+	return series, nil
+}
+
+// field reads a key of an InfluxDB result, which Go clients marshal
+// either lower-case or capitalized.
+func field(m map[string]interface{}, key string) interface{} {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return m[strings.ToUpper(key[:1])+key[1:]]
+}
+
+// Names extracts the names of a SHOW result, such as the databases or the
+// measurements: the first column of every row, sorted and without
+// duplicates. A plain list of strings is accepted too.
+func Names(raw json.RawMessage) ([]string, error) {
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		sort.Strings(list)
+		return list, nil
+	}
+	var wrapped struct {
+		Data []string `json:"data"`
+	}
+	if json.Unmarshal(raw, &wrapped) == nil && wrapped.Data != nil {
+		sort.Strings(wrapped.Data)
+		return wrapped.Data, nil
+	}
+	series, err := parseInflux(raw)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, s := range series {
+		for _, row := range s.Values {
+			if len(row) == 0 || row[0] == nil {
+				continue
+			}
+			name := fmt.Sprint(row[0])
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ParseData extracts the series from a /statistic/data response. Every
+// value column of an InfluxDB series becomes a series named
+// measurement.column and labelled with the series tags.
+func ParseData(raw json.RawMessage) ([]Series, error) {
+	result, err := parseInflux(raw)
+	if err != nil {
+		return nil, err
+	}
+	var series []Series
+	for _, in := range result {
+		timeCol := -1
+		for i, c := range in.Columns {
+			if c == "time" {
+				timeCol = i
+			}
+		}
+		if timeCol < 0 {
+			continue
+		}
+		for col, name := range in.Columns {
+			if col == timeCol {
+				continue
+			}
+			s := Series{Labels: map[string]string{"__name__": name}}
+			if in.Name != "" {
+				s.Labels["__name__"] = in.Name + "." + name
+			}
+			for k, v := range in.Tags {
+				s.Labels[k] = v
+			}
+			for _, row := range in.Values {
+				if len(row) != len(in.Columns) || row[col] == nil {
+					continue
+				}
+				t, err := influxTime(row[timeCol])
+				if err != nil {
+					return nil, err
+				}
+				v, err := influxValue(row[col])
+				if err != nil {
+					return nil, fmt.Errorf("series %s: %w", s.Name(), err)
+				}
+				s.Points = append(s.Points, Point{Time: t, Value: v})
+			}
+			series = append(series, s)
+		}
+	}
+	sort.SliceStable(series, func(i, j int) bool { return series[i].Name() < series[j].Name() })
+	return series, nil
+}
+
+// influxTime reads a time column: RFC3339, or an epoch whose unit is
+// guessed from its magnitude.
+func influxTime(v interface{}) (time.Time, error) {
+	switch x := v.(type) {
+	case string:
+		return time.Parse(time.RFC3339Nano, x)
+	case float64:
+		n := int64(x)
+		switch {
+		case n > 1e17:
+			return time.Unix(0, n), nil
+		case n > 1e14:
+			return time.UnixMicro(n), nil
+		case n > 1e11:
+			return time.UnixMilli(n), nil
+		default:
+			return time.Unix(n, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %v", v)
+}
+
+// influxValue reads a value column.
+func influxValue(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case string:
+		f, err := strconv.ParseFloat(x, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q", x)
+		}
+		return f, nil
+	case bool:
+		if x {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, fmt.Errorf("invalid value %v", v)
+}
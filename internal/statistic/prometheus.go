@@ -3,7 +3,13 @@ package statistic
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
 )
 
 // QueryData queries Prometheus data. POST /prometheus/data
@@ -33,3 +39,131 @@ func LabelDetail(ctx context.Context, client *api.Client, label string) (json.Ra
 	err := client.Get(ctx, "/prometheus/label/"+api.PathEscape(label), &result)
 	return result, err
 }
+
+// NewPrometheusQuery builds the body of a range query: each expression is
+// evaluated from from to to, every step.
+func NewPrometheusQuery(exprs []string, from, to time.Time, step time.Duration) models.PrometheusObject {
+	precision := int64(step / time.Second)
+	if precision < 1 {
+		precision = 1
+	}
+	return models.PrometheusObject{
+		Param:     map[string]interface{}{"metrics": exprs, "precision": precision},
+		Timestamp: map[string]interface{}{"from": from.UnixMilli(), "to": to.UnixMilli()},
+	}
+}
+
+// LabelNames lists the Prometheus label names.
+func LabelNames(ctx context.Context, client *api.Client) ([]string, error) {
+	raw, err := Labels(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return stringList(raw)
+}
+
+// LabelValues lists the values of a Prometheus label.
+func LabelValues(ctx context.Context, client *api.Client, label string) ([]string, error) {
+	raw, err := LabelDetail(ctx, client, label)
+	if err != nil {
+		return nil, err
+	}
+	return stringList(raw)
+}
+
+// stringList decodes a list of strings, bare or under "data".
+func stringList(raw json.RawMessage) ([]string, error) {
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		var wrapped struct {
+			Data []string `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &wrapped); err != nil {
+			return nil, fmt.Errorf("unexpected label list: %s", raw)
+		}
+		list = wrapped.Data
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// ParseMatrix extracts the series from a /prometheus/data or
+// /prometheus/value response. The server wraps the Prometheus results
+// differently by version, so any object with a "metric" and "values" (a
+// matrix) or "value" (a vector) is taken as a series.
+func ParseMatrix(raw json.RawMessage) ([]Series, error) {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	var series []Series
+	var walk func(v interface{}) error
+	walk = func(v interface{}) error {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, item := range v {
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+		case map[string]interface{}:
+			metric, ok := v["metric"].(map[string]interface{})
+			if !ok {
+				for _, item := range v {
+					if err := walk(item); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			s := Series{Labels: make(map[string]string, len(metric))}
+			for k, l := range metric {
+				s.Labels[k] = fmt.Sprint(l)
+			}
+			samples, _ := v["values"].([]interface{})
+			if value, ok := v["value"]; ok {
+				samples = []interface{}{value}
+			}
+			for _, sample := range samples {
+				p, err := parsePoint(sample)
+				if err != nil {
+					return fmt.Errorf("series %s: %w", s.Name(), err)
+				}
+				s.Points = append(s.Points, p)
+			}
+			series = append(series, s)
+		}
+		return nil
+	}
+	if err := walk(doc); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(series, func(i, j int) bool { return series[i].Name() < series[j].Name() })
+	return series, nil
+}
+
+// parsePoint reads a [seconds, "value"] sample.
+func parsePoint(v interface{}) (Point, error) {
+	pair, ok := v.([]interface{})
+	if !ok || len(pair) != 2 {
+		return Point{}, fmt.Errorf("invalid sample %v", v)
+	}
+	ts, ok := pair[0].(float64)
+	if !ok {
+		return Point{}, fmt.Errorf("invalid sample time %v", pair[0])
+	}
+	var value float64
+	switch x := pair[1].(type) {
+	case string:
+		f, err := strconv.ParseFloat(x, 64)
+		if err != nil {
+			return Point{}, fmt.Errorf("invalid sample value %q", x)
+		}
+		value = f
+	case float64:
+		value = x
+	default:
+		return Point{}, fmt.Errorf("invalid sample value %v", pair[1])
+	}
+	return Point{Time: time.UnixMilli(int64(ts * 1000)), Value: value}, nil
+}
@@ -0,0 +1,180 @@
+package statistic
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var matcherRe = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*(=~|!~|!=|=)\s*(.*)$`)
+
+// ParseMatcher turns a --match flag such as job=hepic or
+// instance!~"10\..*" into a PromQL label matcher. A quoted value is kept
+// as written, so PromQL reads its escapes; any other value is quoted.
+func ParseMatcher(s string) (string, error) {
+	m := matcherRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", fmt.Errorf("invalid matcher %q: use label=value, label!=value, label=~regex or label!~regex", s)
+	}
+	value := m[3]
+	if !quoted(value) {
+		value = strconv.Quote(value)
+	}
+	return m[1] + m[2] + value, nil
+}
+
+// quoted reports whether s is a single PromQL string literal.
+func quoted(s string) bool {
+	if s == "" || !strings.ContainsRune("\"'`", rune(s[0])) {
+		return false
+	}
+	end, err := skipString(s, 0)
+	return err == nil && end == len(s)
+}
+
+// keywords are the PromQL identifiers that are not metric names.
+var keywords = map[string]bool{
+	"and": true, "or": true, "unless": true, "bool": true, "offset": true,
+	"by": true, "without": true, "on": true, "ignoring": true,
+	"group_left": true, "group_right": true, "inf": true, "nan": true,
+	"sum": true, "min": true, "max": true, "avg": true, "group": true,
+	"stddev": true, "stdvar": true, "count": true, "count_values": true,
+	"bottomk": true, "topk": true, "quantile": true, "limitk": true, "limit_ratio": true,
+}
+
+// grouping are the keywords followed by a list of label names.
+var grouping = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true,
+	"group_left": true, "group_right": true,
+}
+
+// AddMatchers adds label matchers to every vector selector in a PromQL
+// expression: rate(x[5m]) with job="a" becomes rate(x{job="a"}[5m]), and
+// x{a="b"} becomes x{a="b",job="a"}.
+func AddMatchers(expr string, matchers []string) (string, error) {
+	if len(matchers) == 0 {
+		return expr, nil
+	}
+	extra := strings.Join(matchers, ",")
+	var out strings.Builder
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end, err := skipString(expr, i)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(expr[i:end])
+			i = end
+		case c == '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("unclosed [ in %q", expr)
+			}
+			out.WriteString(expr[i : i+end+1])
+			i += end + 1
+		case c == '{':
+			// A selector without a metric name.
+			end, err := mergeSelector(&out, expr, i, extra)
+			if err != nil {
+				return "", err
+			}
+			i = end
+		case isIdentStart(c):
+			j := i
+			for j < len(expr) && (isIdentChar(expr[j]) || expr[j] == ':') {
+				j++
+			}
+			word := expr[i:j]
+			out.WriteString(word)
+			next := j
+			for next < len(expr) && expr[next] == ' ' {
+				next++
+			}
+			switch {
+			case grouping[strings.ToLower(word)] && next < len(expr) && expr[next] == '(':
+				end := strings.IndexByte(expr[next:], ')')
+				if end < 0 {
+					return "", fmt.Errorf("unclosed ( in %q", expr)
+				}
+				out.WriteString(expr[j : next+end+1])
+				j = next + end + 1
+			case keywords[strings.ToLower(word)] || next < len(expr) && expr[next] == '(':
+				// An operator or a function call.
+			case next < len(expr) && expr[next] == '{':
+				out.WriteString(expr[j:next])
+				end, err := mergeSelector(&out, expr, next, extra)
+				if err != nil {
+					return "", err
+				}
+				j = end
+			default:
+				out.WriteString("{" + extra + "}")
+			}
+			i = j
+		case c >= '0' && c <= '9' || c == '.':
+			// Numbers and durations, such as offset 5m.
+			j := i
+			for j < len(expr) && (isIdentChar(expr[j]) || expr[j] == '.') {
+				j++
+			}
+			out.WriteString(expr[i:j])
+			i = j
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String(), nil
+}
+
+// mergeSelector copies the {...} at expr[start] with extra appended and
+// returns the index after it.
+func mergeSelector(out *strings.Builder, expr string, start int, extra string) (int, error) {
+	i := start + 1
+	for i < len(expr) && expr[i] != '}' {
+		if expr[i] == '"' || expr[i] == '\'' || expr[i] == '`' {
+			end, err := skipString(expr, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+			continue
+		}
+		i++
+	}
+	if i >= len(expr) {
+		return 0, fmt.Errorf("unclosed { in %q", expr)
+	}
+	inner := strings.TrimSpace(expr[start+1 : i])
+	inner = strings.TrimSuffix(inner, ",")
+	if inner != "" {
+		inner += ","
+	}
+	out.WriteString("{" + inner + extra + "}")
+	return i + 1, nil
+}
+
+func skipString(expr string, start int) (int, error) {
+	quote := expr[start]
+	for i := start + 1; i < len(expr); i++ {
+		switch {
+		case expr[i] == '\\' && quote != '`':
+			i++
+		case expr[i] == quote:
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unclosed string in %q", expr)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
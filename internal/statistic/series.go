@@ -0,0 +1,95 @@
+package statistic
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Point is one sample of a series.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is one time series of a query result.
+type Series struct {
+	Labels map[string]string
+	Points []Point
+}
+
+// Name formats the series in PromQL notation: name{label="value",...}.
+func (s Series) Name() string {
+	var labels []string
+	for k, v := range s.Labels {
+		if k != "__name__" {
+			labels = append(labels, k+"="+strconv.Quote(v))
+		}
+	}
+	sort.Strings(labels)
+	name := s.Labels["__name__"]
+	if len(labels) == 0 && name != "" {
+		return name
+	}
+	return name + "{" + strings.Join(labels, ",") + "}"
+}
+
+// Rows lays the series out as one row per timestamp and one column per
+// series; a series without a sample at a timestamp has an empty cell.
+func Rows(series []Series) (header []string, rows [][]string) {
+	var times []time.Time
+	seen := make(map[int64]bool)
+	values := make([]map[int64]float64, len(series))
+	header = []string{"time"}
+	for i, s := range series {
+		header = append(header, s.Name())
+		values[i] = make(map[int64]float64, len(s.Points))
+		for _, p := range s.Points {
+			ms := p.Time.UnixMilli()
+			values[i][ms] = p.Value
+			if !seen[ms] {
+				seen[ms] = true
+				times = append(times, p.Time)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	for _, t := range times {
+		row := []string{t.UTC().Format(time.RFC3339)}
+		for i := range series {
+			cell := ""
+			if v, ok := values[i][t.UnixMilli()]; ok {
+				cell = strconv.FormatFloat(v, 'g', -1, 64)
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// WriteCSV writes the series as CSV, laid out as by Rows.
+func WriteCSV(w io.Writer, series []Series) error {
+	header, rows := Rows(series)
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	cw.WriteAll(rows)
+	return cw.Error()
+}
+
+// WriteTable writes the series as aligned columns, laid out as by Rows.
+func WriteTable(w io.Writer, series []Series) error {
+	header, rows := Rows(series)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
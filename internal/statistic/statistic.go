@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/ops"
 )
 
 // DBStats retrieves database statistics. GET /statistic/_db
@@ -40,3 +42,21 @@ func Data(ctx context.Context, client *api.Client, data interface{}) (json.RawMe
 	err := client.Post(ctx, "/statistic/data", data, &result)
 	return result, err
 }
+
+// Tags queries the tag keys of a measurement. POST /statistic/_tags
+func Tags(ctx context.Context, client *api.Client, data models.StatisticObject) (json.RawMessage, error) {
+	_, raw, err := ops.PostStatisticTags(ctx, client, data)
+	return raw, err
+}
+
+// DatabaseInfo retrieves information about the statistic database. POST /statistic/database/info
+func DatabaseInfo(ctx context.Context, client *api.Client) (json.RawMessage, error) {
+	_, raw, err := ops.PostStatisticDatabaseInfo(ctx, client)
+	return raw, err
+}
+
+// ConfigDBInfo retrieves information about the configuration database. POST /statistic/configdb/info
+func ConfigDBInfo(ctx context.Context, client *api.Client) (json.RawMessage, error) {
+	_, raw, err := ops.PostStatisticConfigdbInfo(ctx, client)
+	return raw, err
+}
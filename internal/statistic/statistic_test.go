@@ -6,7 +6,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"hepic-cli/internal/api"
 )
@@ -368,3 +370,263 @@ func TestStatus_Grafana(t *testing.T) {
 		t.Errorf("expected status ok, got %v", parsed["status"])
 	}
 }
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"now":                  now,
+		"now-6h":               now.Add(-6 * time.Hour),
+		"now-1w12h":            now.Add(-180 * time.Hour),
+		"2025-01-01":           time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2025-01-01T10:00:00Z": time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := ParseTime(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"now-", "now-6x", "yesterday"} {
+		if _, err := ParseTime(in, now); err == nil {
+			t.Errorf("ParseTime(%q): expected an error", in)
+		}
+	}
+}
+
+func TestAddMatchers(t *testing.T) {
+	m, err := ParseMatcher("job=hepic")
+	if err != nil || m != `job="hepic"` {
+		t.Fatalf("ParseMatcher = %q, %v", m, err)
+	}
+	for _, in := range []string{`instance!~"10\\..*"`, `instance!~"10\..*"`, `instance=~'a|b'`} {
+		if m, _ := ParseMatcher(in); m != in {
+			t.Errorf("quoted value changed: %q", m)
+		}
+	}
+	if m, _ := ParseMatcher(`instance!~10\..*`); m != `instance!~"10\\..*"` {
+		t.Errorf("unquoted value quoted as %q", m)
+	}
+	if _, err := ParseMatcher("job"); err == nil {
+		t.Error("expected an error for a matcher without operator")
+	}
+
+	tests := []struct{ in, want string }{
+		{`up`, `up{job="a"}`},
+		{`rate(x[5m])`, `rate(x{job="a"}[5m])`},
+		{`x{code="200"} / ignoring(code) y offset 5m`, `x{code="200",job="a"} / ignoring(code) y{job="a"} offset 5m`},
+		{`sum by (method) (rate(sip_total{} [1m]))`, `sum by (method) (rate(sip_total{job="a"} [1m]))`},
+		{`{__name__=~"sip_.*"}`, `{__name__=~"sip_.*",job="a"}`},
+		{`count_values("v", build_info) > 1`, `count_values("v", build_info{job="a"}) > 1`},
+		{`SUM BY (job) (x)`, `SUM BY (job) (x{job="a"})`},
+	}
+	for _, tt := range tests {
+		got, err := AddMatchers(tt.in, []string{`job="a"`})
+		if err != nil || got != tt.want {
+			t.Errorf("AddMatchers(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := AddMatchers(`x{a="b"`, []string{`job="a"`}); err == nil {
+		t.Error("expected an error for an unclosed selector")
+	}
+}
+
+func TestParseMatrix(t *testing.T) {
+	raw := json.RawMessage(`{"data":[{"data":{"resultType":"matrix","result":[
+		{"metric":{"__name__":"up","job":"b"},"values":[[1700000000,"1"],[1700000060,"0"]]},
+		{"metric":{"__name__":"up","job":"a"},"values":[[1700000060,"1"]]}
+	]}}]}`)
+	series, err := ParseMatrix(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || series[0].Name() != `up{job="a"}` || len(series[1].Points) != 2 || series[1].Points[1].Value != 0 {
+		t.Fatalf("unexpected series %+v", series)
+	}
+
+	var buf strings.Builder
+	if err := WriteCSV(&buf, series); err != nil {
+		t.Fatal(err)
+	}
+	want := "time,\"up{job=\"\"a\"\"}\",\"up{job=\"\"b\"\"}\"\n" +
+		"2023-11-14T22:13:20Z,,1\n" +
+		"2023-11-14T22:14:20Z,1,0\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+
+	if _, err := ParseMatrix(json.RawMessage(`[{"metric":{},"value":[1,"x"]}]`)); err == nil {
+		t.Error("expected an error for a non-numeric sample")
+	}
+}
+
+func TestNewPrometheusQuery(t *testing.T) {
+	q := NewPrometheusQuery([]string{"up"}, time.UnixMilli(1000), time.UnixMilli(61000), 30*time.Second)
+	if q.Param["precision"] != int64(30) || q.Timestamp["from"] != int64(1000) || q.Timestamp["to"] != int64(61000) {
+		t.Errorf("unexpected query %+v", q)
+	}
+}
+
+func TestTagNames_Post(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/statistic/_tags" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Param struct {
+				Query []map[string]interface{} `json:"query"`
+			} `json:"param"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Param.Query) != 1 || body.Param.Query[0]["main"] != "heplify_method_response" || body.Param.Query[0]["database"] != "homer" {
+			t.Errorf("unexpected body %+v", body)
+		}
+		io.WriteString(w, `{"data":{"results":[{"series":[{"name":"heplify_method_response","columns":["tagKey"],"values":[["response"],["method"]]}]}]}}`)
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.URL, "test-token")
+	tags, err := TagNames(context.Background(), client, Target{Database: "homer", Measurement: "heplify_method_response"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(tags, ",") != "method,response" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := map[string]string{
+		`["b","a"]`:          "a,b",
+		`{"data":["b","a"]}`: "a,b",
+		`{"Results":[{"Series":[{"name":"databases","columns":["name"],"values":[["homer"],["_internal"]]}]}]}`: "_internal,homer",
+		`{"results":[]}`: "",
+	}
+	for in, want := range tests {
+		names, err := Names(json.RawMessage(in))
+		if err != nil || strings.Join(names, ",") != want {
+			t.Errorf("Names(%s) = %v, %v; want %s", in, names, err, want)
+		}
+	}
+}
+
+func TestParseData(t *testing.T) {
+	raw := json.RawMessage(`{"data":{"results":[{"series":[
+		{"name":"m","tags":{"method":"INVITE"},"columns":["time","counter"],"values":[["2023-11-14T22:13:20Z",3],["2023-11-14T22:14:20Z",null]]},
+		{"name":"m","tags":{"method":"BYE"},"columns":["time","counter"],"values":[[1700000060000,"1.5"]]}
+	]}]}}`)
+	series, err := ParseData(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || series[0].Name() != `m.counter{method="BYE"}` || series[1].Name() != `m.counter{method="INVITE"}` {
+		t.Fatalf("unexpected series %+v", series)
+	}
+	if len(series[1].Points) != 1 || series[0].Points[0].Value != 1.5 || !series[0].Points[0].Time.Equal(time.Unix(1700000060, 0)) {
+		t.Errorf("unexpected points %+v", series)
+	}
+
+	var buf strings.Builder
+	if err := WriteTable(&buf, series); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`time                  m.counter{method="BYE"}  m.counter{method="INVITE"}`,
+		`2023-11-14T22:13:20Z                           3`,
+		`2023-11-14T22:14:20Z  1.5`,
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+}
+
+func TestNewDataQuery(t *testing.T) {
+	target := Target{Database: "homer", Retention: "60s", Measurement: "m", Metrics: []string{"counter"}, GroupBy: []string{"method"}}
+	q := NewDataQuery(target, time.UnixMilli(1000), time.UnixMilli(61000), 5*time.Minute, 100)
+	body, _ := json.Marshal(q)
+	want := `{"param":{"limit":100,"precision":300,"query":[{"database":"homer","main":"m","rawquery":"","retention":"60s","tag":["method"],"type":["counter"]}],"total":false},"timestamp":{"from":1000,"to":61000}}`
+	if string(body) != want {
+		t.Errorf("unexpected query\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestBaseURL_Grafana(t *testing.T) {
+	for _, body := range []string{`"http://grafana:3000"`, `{"data":"http://grafana:3000/"}`, `{"data":{"url":"http://grafana:3000"}}`} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/proxy/grafana/url" {
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+			io.WriteString(w, body)
+		}))
+		base, err := BaseURL(context.Background(), api.NewClientWith(server.URL, "test-token"))
+		server.Close()
+		if err != nil || base != "http://grafana:3000/" {
+			t.Errorf("BaseURL(%s) = %q, %v", body, base, err)
+		}
+	}
+}
+
+func TestDashboardURL_Grafana(t *testing.T) {
+	link, err := DashboardURL("http://grafana:3000/", "abc123", "sip-overview", "now-6h", "now", []string{"node=hepic01", "node=hepic02"}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "http://grafana:3000/d/abc123/sip-overview?from=now-6h&to=now&var-node=hepic01&var-node=hepic02&viewPanel=4"
+	if link != want {
+		t.Errorf("DashboardURL = %s; want %s", link, want)
+	}
+	if _, err := DashboardURL("http://grafana:3000/", "abc123", "x", "", "", []string{"node"}, 0); err == nil {
+		t.Error("expected an error for a variable without value")
+	}
+}
+
+func TestDashboard_Grafana(t *testing.T) {
+	var d Dashboard
+	json.Unmarshal([]byte(`{"dashboard":{"title":"SIP / Overview!","panels":[
+		{"id":1,"type":"graph"},
+		{"id":2,"type":"row","panels":[{"id":7,"title":"Nested"}]}
+	]},"meta":{}}`), &d)
+	if d.Slug() != "sip-overview" {
+		t.Errorf("Slug = %q", d.Slug())
+	}
+	if p, ok := d.Panel(7); !ok || p["title"] != "Nested" {
+		t.Errorf("Panel(7) = %v, %v", p, ok)
+	}
+	if _, ok := d.Panel(3); ok {
+		t.Error("found a panel that does not exist")
+	}
+}
+
+func TestFolderDashboards_Grafana(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/proxy/grafana/folders":
+			io.WriteString(w, `[{"id":3,"uid":"f3","title":"HEPIC"}]`)
+		case "/proxy/grafana/search/3":
+			io.WriteString(w, `[{"uid":"abc","title":"SIP","type":"dash-db"},{"uid":"sub","title":"Sub","type":"dash-folder"}]`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClientWith(server.URL, "test-token")
+	folders, err := ListFolders(context.Background(), client)
+	if err != nil || len(folders) != 2 || folders[0].Title != "General" {
+		t.Fatalf("ListFolders = %v, %v", folders, err)
+	}
+	folder, err := FindFolder(folders, "hepic")
+	if err != nil || folder.ID != 3 {
+		t.Fatalf("FindFolder = %v, %v", folder, err)
+	}
+	if _, err := FindFolder(folders, "missing"); err == nil {
+		t.Error("expected an error for a missing folder")
+	}
+	dashboards, err := FolderDashboards(context.Background(), client, folder)
+	if err != nil || len(dashboards) != 1 || dashboards[0].UID != "abc" || dashboards[0].FolderTitle != "HEPIC" {
+		t.Errorf("FolderDashboards = %v, %v", dashboards, err)
+	}
+}
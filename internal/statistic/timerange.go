@@ -0,0 +1,66 @@
+package statistic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/models"
+)
+
+// ParseTime parses a time flag relative to now: "now", "now-6h" or
+// "now-7d" (units s, m, h, d and w), or an absolute time as accepted by
+// models.ParseTime.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "now" {
+		return now, nil
+	}
+	if rest, ok := strings.CutPrefix(s, "now-"); ok {
+		d, err := ParseDuration(rest)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-d), nil
+	}
+	t, err := models.ParseTime(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Time, nil
+}
+
+// ParseDuration is time.ParseDuration with days (d) and weeks (w), as in
+// "7d" or "1w12h".
+func ParseDuration(s string) (time.Duration, error) {
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		i := strings.IndexAny(rest, "dw")
+		if i < 0 {
+			break
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		unit := 24 * time.Hour
+		if rest[i] == 'w' {
+			unit *= 7
+		}
+		total += time.Duration(n) * unit
+		rest = rest[i+1:]
+	}
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += d
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return total, nil
+}
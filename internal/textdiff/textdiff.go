@@ -0,0 +1,178 @@
+// Package textdiff produces line-based unified diffs.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Lines splits text into lines without their terminators. A trailing
+// newline does not produce an empty last line.
+func Lines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+}
+
+// edits returns the shortest edit script turning a into b, computed from
+// the longest common subsequence.
+func edits(a, b []string) []op {
+	// Trim the common prefix and suffix; most edits are local.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, l := range a[:pre] {
+		ops = append(ops, op{opEqual, l})
+	}
+	i, j := 0, 0
+	for i < len(ma) && j < len(mb) {
+		switch {
+		case ma[i] == mb[j]:
+			ops = append(ops, op{opEqual, ma[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{opDelete, ma[i]})
+			i++
+		default:
+			ops = append(ops, op{opInsert, mb[j]})
+			j++
+		}
+	}
+	for ; i < len(ma); i++ {
+		ops = append(ops, op{opDelete, ma[i]})
+	}
+	for ; j < len(mb); j++ {
+		ops = append(ops, op{opInsert, mb[j]})
+	}
+	for _, l := range a[len(a)-suf:] {
+		ops = append(ops, op{opEqual, l})
+	}
+	return ops
+}
+
+// Stat returns the number of added and removed lines between a and b.
+func Stat(a, b string) (added, removed int) {
+	for _, o := range edits(Lines(a), Lines(b)) {
+		switch o.kind {
+		case opInsert:
+			added++
+		case opDelete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// Unified returns a unified diff of a and b with the given number of
+// context lines, or "" if they are equal.
+func Unified(fromName, toName, a, b string, context int) string {
+	ops := edits(Lines(a), Lines(b))
+
+	// Find the ranges of ops to print: every change plus context.
+	type hunk struct{ start, end int }
+	var hunks []hunk
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+		start, end := max(i-context, 0), min(i+context+1, len(ops))
+		if n := len(hunks); n > 0 && start <= hunks[n-1].end {
+			hunks[n-1].end = end
+		} else {
+			hunks = append(hunks, hunk{start, end})
+		}
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	// Line numbers in a and b at the start of ops[k].
+	aLine, bLine, k := 1, 1, 0
+	for _, h := range hunks {
+		for ; k < h.start; k++ {
+			if ops[k].kind != opInsert {
+				aLine++
+			}
+			if ops[k].kind != opDelete {
+				bLine++
+			}
+		}
+		aCount, bCount := 0, 0
+		for _, o := range ops[h.start:h.end] {
+			if o.kind != opInsert {
+				aCount++
+			}
+			if o.kind != opDelete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", span(aLine, aCount), span(bLine, bCount))
+		for _, o := range ops[h.start:h.end] {
+			switch o.kind {
+			case opEqual:
+				sb.WriteString(" ")
+			case opDelete:
+				sb.WriteString("-")
+			case opInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(o.line)
+			sb.WriteString("\n")
+		}
+		aLine, bLine, k = aLine+aCount, bLine+bCount, h.end
+	}
+	return sb.String()
+}
+
+// span formats a hunk range the way diff -u does: an empty range starts at
+// the line before it.
+func span(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
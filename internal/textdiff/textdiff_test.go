@@ -0,0 +1,43 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n"
+
+	got := Unified("server", "local", a, b, 1)
+	want := `--- server
++++ local
+@@ -2,3 +2,3 @@
+ 2
+-3
++three
+ 4
+@@ -10 +10,2 @@
+ 10
++11
+`
+	if got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	if d := Unified("a", "b", a, a, 3); d != "" {
+		t.Errorf("expected empty diff for equal input, got %q", d)
+	}
+}
+
+func TestUnified_Empty(t *testing.T) {
+	got := Unified("a", "b", "", "x\ny\n", 3)
+	want := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x\n+y\n"
+	if got != want {
+		t.Errorf("unexpected diff:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestStat(t *testing.T) {
+	added, removed := Stat("a\nb\nc\n", "a\nc\nd\ne\n")
+	if added != 2 || removed != 1 {
+		t.Errorf("expected +2 -1, got +%d -%d", added, removed)
+	}
+}
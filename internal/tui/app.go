@@ -0,0 +1,567 @@
+// Package tui implements a full-screen terminal UI for searching calls and
+// inspecting their message flow. The App is a plain state machine driven
+// by Handle and drawn by Render; Run connects it to a terminal.
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"hepic-cli/internal/ladder"
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/sip"
+)
+
+// Query is the search form.
+type Query struct {
+	From   string
+	To     string
+	Caller string
+	Callee string
+	CallID string
+}
+
+// Backend performs the API requests the UI needs. Export formats are
+// "pcap" and "text"; report kinds are "qos" and "dtmf".
+type Backend interface {
+	Search(ctx context.Context, q Query) ([]models.CallElement, error)
+	Messages(ctx context.Context, q Query, callID string) ([]*sip.Message, error)
+	Export(ctx context.Context, q Query, callID, format, path string) (int64, error)
+	Report(ctx context.Context, q Query, callID, kind string) (json.RawMessage, error)
+	Share(ctx context.Context, callID string) (string, error)
+}
+
+type view int
+
+const (
+	viewForm view = iota
+	viewList
+	viewDetail
+	viewReport
+)
+
+var fieldLabels = []string{"From", "To", "Caller", "Callee", "Call-ID"}
+
+// prompt is a one-line input shown in the status line.
+type prompt struct {
+	label  string
+	value  string
+	submit func(string)
+}
+
+// App is the UI state.
+type App struct {
+	// Busy, when set, is called before each backend request so the
+	// status can be drawn while the request runs.
+	Busy func(status string)
+
+	ctx     context.Context
+	backend Backend
+	names   ladder.Namer
+
+	view, back view
+	fields     []string
+	focus      int
+	query      Query
+
+	calls       []models.CallElement
+	cursor, top int
+
+	callID    string
+	msgs      []*sip.Message
+	selected  int
+	ladderTop int
+	rawTop    int
+
+	report      []string
+	reportTitle string
+	reportTop   int
+
+	prompt    *prompt
+	status    string
+	clipboard string
+	height    int
+}
+
+// New returns an App showing the search form prefilled from q. From
+// defaults to one hour ago. names may be nil.
+func New(ctx context.Context, backend Backend, q Query, names ladder.Namer) *App {
+	if q.From == "" {
+		q.From = time.Now().Add(-time.Hour).Format(time.RFC3339)
+	}
+	return &App{
+		ctx:     ctx,
+		backend: backend,
+		names:   names,
+		fields:  []string{q.From, q.To, q.Caller, q.Callee, q.CallID},
+		status:  "Fill in the search form and press Enter.",
+	}
+}
+
+// Clipboard returns text to copy to the clipboard, once.
+func (a *App) Clipboard() string {
+	s := a.clipboard
+	a.clipboard = ""
+	return s
+}
+
+// Search runs the query in the form and shows the results.
+func (a *App) Search() {
+	a.query = Query{From: a.fields[0], To: a.fields[1], Caller: a.fields[2], Callee: a.fields[3], CallID: a.fields[4]}
+	a.busy("Searching...")
+	calls, err := a.backend.Search(a.ctx, a.query)
+	if err != nil {
+		a.status = "Error: " + err.Error()
+		return
+	}
+	a.calls, a.cursor, a.top = calls, 0, 0
+	a.view = viewList
+	a.status = fmt.Sprintf("%d call(s) found.", len(calls))
+}
+
+// Handle processes one key press and reports whether the UI should exit.
+func (a *App) Handle(k Key) bool {
+	if k.Code == KeyCtrlC {
+		return true
+	}
+	if a.prompt != nil {
+		a.handlePrompt(k)
+		return false
+	}
+	switch a.view {
+	case viewForm:
+		return a.handleForm(k)
+	case viewList:
+		return a.handleList(k)
+	case viewDetail:
+		a.handleDetail(k)
+	case viewReport:
+		a.handleReport(k)
+	}
+	return false
+}
+
+func (a *App) handleForm(k Key) bool {
+	switch k.Code {
+	case KeyEnter:
+		a.Search()
+	case KeyTab, KeyDown:
+		a.focus = (a.focus + 1) % len(a.fields)
+	case KeyBacktab, KeyUp:
+		a.focus = (a.focus + len(a.fields) - 1) % len(a.fields)
+	case KeyBackspace:
+		a.fields[a.focus] = dropLast(a.fields[a.focus])
+	case KeyRune:
+		a.fields[a.focus] += string(k.Rune)
+	case KeyEsc:
+		if a.calls == nil {
+			return true
+		}
+		a.view = viewList
+	}
+	return false
+}
+
+func (a *App) handleList(k Key) bool {
+	switch {
+	case k.Code == KeyEnter:
+		a.open()
+	case k.Code == KeyEsc || k.Rune == '/' || k.Rune == 's':
+		a.view = viewForm
+	case k.Rune == 'q':
+		return true
+	case len(a.calls) > 0:
+		if !a.move(k, &a.cursor, len(a.calls)) {
+			a.callKey(k, a.calls[a.cursor].Sid)
+		}
+	}
+	return false
+}
+
+func (a *App) handleDetail(k Key) {
+	switch {
+	case k.Code == KeyEsc || k.Rune == 'q':
+		a.view = viewList
+	case k.Code == KeyPgUp:
+		a.rawTop -= a.page()
+	case k.Code == KeyPgDn:
+		a.rawTop += a.page()
+	case a.move(k, &a.selected, len(a.msgs)):
+		a.rawTop = 0
+	default:
+		a.callKey(k, a.callID)
+	}
+}
+
+func (a *App) handleReport(k Key) {
+	switch {
+	case k.Code == KeyEsc || k.Rune == 'q':
+		a.view = a.back
+	default:
+		a.move(k, &a.reportTop, len(a.report))
+	}
+}
+
+func (a *App) handlePrompt(k Key) {
+	p := a.prompt
+	switch k.Code {
+	case KeyEnter:
+		a.prompt = nil
+		if v := strings.TrimSpace(p.value); v != "" {
+			p.submit(v)
+		}
+	case KeyEsc:
+		a.prompt = nil
+		a.status = "Cancelled."
+	case KeyBackspace:
+		p.value = dropLast(p.value)
+	case KeyRune:
+		p.value += string(k.Rune)
+	}
+}
+
+// move applies a cursor key to *pos within [0, n) and reports whether k
+// was one.
+func (a *App) move(k Key, pos *int, n int) bool {
+	switch {
+	case k.Code == KeyUp || k.Rune == 'k':
+		*pos--
+	case k.Code == KeyDown || k.Rune == 'j':
+		*pos++
+	case k.Code == KeyPgUp:
+		*pos -= a.page()
+	case k.Code == KeyPgDn:
+		*pos += a.page()
+	case k.Code == KeyHome || k.Rune == 'g':
+		*pos = 0
+	case k.Code == KeyEnd || k.Rune == 'G':
+		*pos = n - 1
+	default:
+		return false
+	}
+	*pos = clamp(*pos, 0, n-1)
+	return true
+}
+
+// callKey runs the per-call actions available in the list and detail views.
+func (a *App) callKey(k Key, callID string) {
+	if k.Code != KeyRune || callID == "" {
+		return
+	}
+	switch k.Rune {
+	case 'p':
+		a.ask("Save PCAP to: ", fileName(callID)+".pcap", func(path string) { a.export(callID, "pcap", path) })
+	case 't':
+		a.ask("Save text to: ", fileName(callID)+".txt", func(path string) { a.export(callID, "text", path) })
+	case 'r':
+		a.showReport(callID, "qos")
+	case 'd':
+		a.showReport(callID, "dtmf")
+	case 'c':
+		a.share(callID)
+	}
+}
+
+func (a *App) open() {
+	if len(a.calls) == 0 {
+		return
+	}
+	callID := a.calls[a.cursor].Sid
+	if callID == "" {
+		a.status = "This row has no Call-ID."
+		return
+	}
+	a.busy("Loading messages...")
+	msgs, err := a.backend.Messages(a.ctx, a.query, callID)
+	if err != nil {
+		a.status = "Error: " + err.Error()
+		return
+	}
+	a.callID, a.msgs = callID, msgs
+	a.selected, a.ladderTop, a.rawTop = 0, 0, 0
+	a.view = viewDetail
+	a.status = fmt.Sprintf("%d message(s).", len(msgs))
+}
+
+func (a *App) ask(label, value string, submit func(string)) {
+	a.prompt = &prompt{label: label, value: value, submit: submit}
+}
+
+func (a *App) export(callID, format, path string) {
+	a.busy("Exporting...")
+	n, err := a.backend.Export(a.ctx, a.query, callID, format, path)
+	if err != nil {
+		a.status = "Error: " + err.Error()
+		return
+	}
+	a.status = fmt.Sprintf("Saved %d bytes to %s.", n, path)
+}
+
+func (a *App) showReport(callID, kind string) {
+	a.busy("Loading " + strings.ToUpper(kind) + " report...")
+	raw, err := a.backend.Report(a.ctx, a.query, callID, kind)
+	if err != nil {
+		a.status = "Error: " + err.Error()
+		return
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		buf.Reset()
+		buf.Write(raw)
+	}
+	a.report = strings.Split(buf.String(), "\n")
+	a.reportTitle = fmt.Sprintf("%s report for %s", strings.ToUpper(kind), callID)
+	a.reportTop = 0
+	if a.view != viewReport {
+		a.back = a.view
+	}
+	a.view = viewReport
+	a.status = ""
+}
+
+func (a *App) share(callID string) {
+	a.busy("Creating share link...")
+	link, err := a.backend.Share(a.ctx, callID)
+	if err != nil {
+		a.status = "Error: " + err.Error()
+		return
+	}
+	a.clipboard = link
+	a.status = "Share link copied to clipboard: " + link
+}
+
+func (a *App) busy(status string) {
+	a.status = status
+	if a.Busy != nil {
+		a.Busy(status)
+	}
+}
+
+func (a *App) page() int {
+	if a.height > 6 {
+		return a.height - 6
+	}
+	return 1
+}
+
+// Render draws the UI as exactly height lines of at most width columns.
+func (a *App) Render(width, height int) []string {
+	a.height = height
+	body := height - 3
+	if body < 1 {
+		body = 1
+	}
+
+	var title, help string
+	var lines []string
+	switch a.view {
+	case viewForm:
+		title, help = "Search calls", "Tab/Up/Down field  Enter search  Esc results  Ctrl-C quit"
+		lines = a.renderForm(width)
+	case viewList:
+		title = fmt.Sprintf("%d call(s)  %s .. %s", len(a.calls), a.query.From, orNow(a.query.To))
+		help = "Enter open  / search  p pcap  t text  r qos  d dtmf  c share  q quit"
+		lines = a.renderList(width, body)
+	case viewDetail:
+		title = "Call " + a.callID
+		help = "Up/Down message  PgUp/PgDn scroll  p pcap  t text  r qos  d dtmf  c share  Esc back"
+		lines = a.renderDetail(width, body)
+	case viewReport:
+		title, help = a.reportTitle, "Up/Down/PgUp/PgDn scroll  Esc back"
+		a.reportTop = clamp(a.reportTop, 0, len(a.report)-body)
+		for _, l := range window(a.report, a.reportTop, body) {
+			lines = append(lines, fit(l, width))
+		}
+	}
+
+	status := a.status
+	if a.prompt != nil {
+		status = a.prompt.label + a.prompt.value + "_"
+	}
+	out := []string{reverse(fit(" hepic ui | "+title, width))}
+	for i := 0; i < body; i++ {
+		if i < len(lines) {
+			out = append(out, lines[i])
+		} else {
+			out = append(out, "")
+		}
+	}
+	out = append(out, fit(status, width), reverse(fit(" "+help, width)))
+	return out[:height]
+}
+
+func (a *App) renderForm(width int) []string {
+	lines := []string{""}
+	for i, label := range fieldLabels {
+		marker, cursor := "  ", ""
+		if i == a.focus {
+			marker, cursor = "> ", "_"
+		}
+		lines = append(lines, fit(fmt.Sprintf("%s%-9s %s%s", marker, label+":", a.fields[i], cursor), width))
+	}
+	return append(lines, "", fit("  Times are RFC3339 or YYYY-MM-DD; an empty To means now.", width))
+}
+
+const listFormat = "%-19s  %-9s  %-24s  %-16s  %-21s  %s"
+
+func (a *App) renderList(width, body int) []string {
+	if len(a.calls) == 0 {
+		return []string{"", "  No calls found. Press / to change the search."}
+	}
+	rows := body - 1
+	a.top = scroll(a.top, a.cursor, rows)
+	lines := []string{bold(fit(fmt.Sprintf(listFormat, "TIME", "METHOD", "CALL-ID", "RURI USER", "SOURCE", "DESTINATION"), width))}
+	for i := a.top; i < len(a.calls) && i < a.top+rows; i++ {
+		e := a.calls[i]
+		method := e.Method
+		if method == "" {
+			method = e.MethodText
+		}
+		line := fit(fmt.Sprintf(listFormat, callTime(e), method, fit(e.Sid, 24), e.RuriUser,
+			a.name(e.AliasSrc, e.SrcIP, e.SrcPort), a.name(e.AliasDst, e.DstIP, e.DstPort)), width)
+		if i == a.cursor {
+			line = reverse(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (a *App) renderDetail(width, body int) []string {
+	if len(a.msgs) == 0 {
+		return []string{"", "  No messages found for this call."}
+	}
+	d := ladder.Render(a.msgs, width, a.names)
+	rows := len(d.Lines)
+	if limit := body/2 - 1; rows > limit {
+		rows = limit
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	a.ladderTop = scroll(a.ladderTop, a.selected, rows)
+	lines := []string{bold(fit(d.Header, width))}
+	for i := a.ladderTop; i < len(d.Lines) && i < a.ladderTop+rows; i++ {
+		line := fit(d.Lines[i], width)
+		if i == a.selected {
+			line = reverse(line)
+		}
+		lines = append(lines, line)
+	}
+
+	m := a.msgs[a.selected]
+	sep := fmt.Sprintf("-- %d/%d  %s:%d -> %s:%d ", a.selected+1, len(a.msgs), m.SrcIP, m.SrcPort, m.DstIP, m.DstPort)
+	if !m.Timestamp.IsZero() {
+		sep += m.Timestamp.Local().Format("2006-01-02 15:04:05.000000") + " "
+	}
+	lines = append(lines, fit(sep+strings.Repeat("-", width), width))
+
+	raw := strings.Split(strings.TrimRight(strings.ReplaceAll(m.Raw, "\r\n", "\n"), "\n"), "\n")
+	rawRows := body - len(lines)
+	a.rawTop = clamp(a.rawTop, 0, len(raw)-rawRows)
+	for _, l := range window(raw, a.rawTop, rawRows) {
+		lines = append(lines, fit(l, width))
+	}
+	return lines
+}
+
+// name shows an endpoint by its alias when one is known.
+func (a *App) name(alias, ip string, port float64) string {
+	if alias != "" {
+		return alias
+	}
+	if a.names != nil {
+		if n := a.names(ip, int(port)); n != "" {
+			return n
+		}
+	}
+	if ip == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", ip, int(port))
+}
+
+func callTime(e models.CallElement) string {
+	switch {
+	case e.CreateDate > 0:
+		return time.UnixMilli(e.CreateDate).Local().Format("2006-01-02 15:04:05")
+	case e.MicroTs > 0:
+		return time.UnixMicro(e.MicroTs).Local().Format("2006-01-02 15:04:05")
+	}
+	return ""
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fileName turns a Call-ID into a safe default file name.
+func fileName(callID string) string {
+	return unsafeChars.ReplaceAllString(callID, "_")
+}
+
+func orNow(s string) string {
+	if s == "" {
+		return "now"
+	}
+	return s
+}
+
+// scroll returns the first visible row so that cursor stays in view.
+func scroll(top, cursor, rows int) int {
+	if cursor < top {
+		top = cursor
+	}
+	if cursor >= top+rows {
+		top = cursor - rows + 1
+	}
+	if top < 0 {
+		top = 0
+	}
+	return top
+}
+
+func window(lines []string, top, rows int) []string {
+	if top >= len(lines) || rows <= 0 {
+		return nil
+	}
+	end := top + rows
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return lines[top:end]
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
+
+func dropLast(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	return string(r[:len(r)-1])
+}
+
+// fit pads or cuts s to exactly width columns.
+func fit(s string, width int) string {
+	r := []rune(strings.ReplaceAll(s, "\t", "    "))
+	if len(r) > width {
+		return string(r[:width])
+	}
+	return string(r) + strings.Repeat(" ", width-len(r))
+}
+
+func reverse(s string) string { return "\x1b[7m" + s + "\x1b[0m" }
+
+func bold(s string) string { return "\x1b[1m" + s + "\x1b[0m" }
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"bufio"
+	"strings"
+)
+
+// Code identifies a key that is not a printable character.
+type Code int
+
+// Key codes. KeyRune means Key.Rune holds a printable character.
+const (
+	KeyUnknown Code = iota
+	KeyRune
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyTab
+	KeyBacktab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPgUp
+	KeyPgDn
+	KeyDelete
+	KeyCtrlC
+	KeyCtrlD
+	KeyCtrlU
+)
+
+// Key is one key press.
+type Key struct {
+	Code Code
+	Rune rune
+}
+
+// Rune returns the key for a printable character.
+func Rune(r rune) Key {
+	return Key{Code: KeyRune, Rune: r}
+}
+
+// ReadKey reads one key press from a terminal in raw mode, decoding the
+// ANSI escape sequences terminals send for cursor and editing keys.
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	switch b {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case '\t':
+		return Key{Code: KeyTab}, nil
+	case 127, 8:
+		return Key{Code: KeyBackspace}, nil
+	case 3:
+		return Key{Code: KeyCtrlC}, nil
+	case 4:
+		return Key{Code: KeyCtrlD}, nil
+	case 21:
+		return Key{Code: KeyCtrlU}, nil
+	case 1:
+		return Key{Code: KeyHome}, nil
+	case 5:
+		return Key{Code: KeyEnd}, nil
+	case 0x1b:
+		// A lone Esc arrives on its own; sequences arrive in one read.
+		if r.Buffered() == 0 {
+			return Key{Code: KeyEsc}, nil
+		}
+		return readEscape(r)
+	}
+	if b < 0x20 {
+		return Key{}, nil
+	}
+	if err := r.UnreadByte(); err != nil {
+		return Key{}, err
+	}
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	return Rune(ch), nil
+}
+
+func readEscape(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	switch b {
+	case 'O':
+		// SS3, sent by some terminals for cursor keys in application mode.
+		f, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		return finalKey(f, ""), nil
+	case '[':
+		var params strings.Builder
+		for {
+			f, err := r.ReadByte()
+			if err != nil {
+				return Key{}, err
+			}
+			if f >= 0x40 && f <= 0x7e {
+				return finalKey(f, params.String()), nil
+			}
+			params.WriteByte(f)
+		}
+	}
+	// Alt+key and other sequences are not used.
+	return Key{}, nil
+}
+
+func finalKey(f byte, params string) Key {
+	switch f {
+	case 'A':
+		return Key{Code: KeyUp}
+	case 'B':
+		return Key{Code: KeyDown}
+	case 'C':
+		return Key{Code: KeyRight}
+	case 'D':
+		return Key{Code: KeyLeft}
+	case 'H':
+		return Key{Code: KeyHome}
+	case 'F':
+		return Key{Code: KeyEnd}
+	case 'Z':
+		return Key{Code: KeyBacktab}
+	case '~':
+		switch strings.SplitN(params, ";", 2)[0] {
+		case "1", "7":
+			return Key{Code: KeyHome}
+		case "4", "8":
+			return Key{Code: KeyEnd}
+		case "3":
+			return Key{Code: KeyDelete}
+		case "5":
+			return Key{Code: KeyPgUp}
+		case "6":
+			return Key{Code: KeyPgDn}
+		}
+	}
+	return Key{}
+}
@@ -0,0 +1,19 @@
+package tui
+
+import (
+	"bufio"
+	"os"
+	"time"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// Stdin returns the reader over os.Stdin that Run and line editors share,
+// so input one of them has buffered is not lost to the next.
+func Stdin() *bufio.Reader {
+	return stdin
+}
+
+// pollInterval is how often a waiting key reader checks whether it
+// should stop.
+const pollInterval = 100 * time.Millisecond
@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package tui
+
+import "time"
+
+// waitInput cannot wait with a timeout here, so the reader blocks in its
+// next read and stops after the following key.
+func waitInput(fd int, timeout time.Duration) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package tui
+
+import (
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// waitInput reports whether the console handle fd is signalled within
+// timeout.
+func waitInput(fd int, timeout time.Duration) (bool, error) {
+	event, err := windows.WaitForSingleObject(windows.Handle(fd), uint32(timeout/time.Millisecond))
+	if err != nil {
+		return false, err
+	}
+	return event == windows.WAIT_OBJECT_0, nil
+}
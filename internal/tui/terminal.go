@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Run takes over the terminal on stdin/stdout and drives app until the
+// user quits or ctx is cancelled. The terminal is restored on return.
+func Run(ctx context.Context, app *App) error {
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return fmt.Errorf("the UI needs an interactive terminal")
+	}
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer term.Restore(in, state)
+
+	// Alternate screen, hidden cursor.
+	fmt.Fprint(os.Stdout, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(os.Stdout, "\x1b[?25h\x1b[?1049l")
+
+	w := bufio.NewWriter(os.Stdout)
+	var width, height int
+	draw := func() {
+		width, height, err = term.GetSize(out)
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		w.WriteString("\x1b[H")
+		lines := app.Render(width, height)
+		w.WriteString(strings.Join(lines, "\x1b[K\r\n"))
+		w.WriteString("\x1b[K\x1b[J")
+		if text := app.Clipboard(); text != "" {
+			// OSC 52 asks the terminal to set the system clipboard.
+			fmt.Fprintf(w, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+		}
+		w.Flush()
+	}
+	app.Busy = func(string) { draw() }
+
+	// The key reader waits for input in short polls so that it notices
+	// when Run returns; Run waits for it, so no key is read after that.
+	keys := make(chan Key)
+	errs := make(chan error, 1)
+	done, stopped := make(chan struct{}), make(chan struct{})
+	defer func() {
+		close(done)
+		<-stopped
+	}()
+	go func() {
+		defer close(stopped)
+		r := Stdin()
+		for {
+			if r.Buffered() == 0 {
+				ready, err := waitInput(in, pollInterval)
+				if err != nil {
+					errs <- err
+					return
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if !ready {
+					continue
+				}
+			}
+			k, err := ReadKey(r)
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case keys <- k:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// Terminal size changes are picked up by polling, which works the
+	// same on every platform.
+	resize := time.NewTicker(250 * time.Millisecond)
+	defer resize.Stop()
+
+	draw()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case k := <-keys:
+			if app.Handle(k) {
+				return nil
+			}
+			draw()
+		case <-resize.C:
+			if w, h, err := term.GetSize(out); err == nil && (w != width || h != height) {
+				draw()
+			}
+		}
+	}
+}
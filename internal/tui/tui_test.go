@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"hepic-cli/internal/models"
+	"hepic-cli/internal/sip"
+)
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("a\r\x1b[A\x1b[6~\x1bOB\x1b[Zé\x7f\x03"))
+	want := []Key{Rune('a'), {Code: KeyEnter}, {Code: KeyUp}, {Code: KeyPgDn}, {Code: KeyDown}, {Code: KeyBacktab}, Rune('é'), {Code: KeyBackspace}, {Code: KeyCtrlC}}
+	for i, w := range want {
+		k, err := ReadKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k != w {
+			t.Errorf("key %d: got %+v, want %+v", i, k, w)
+		}
+	}
+	if k, _ := ReadKey(bufio.NewReader(strings.NewReader("\x1b"))); k.Code != KeyEsc {
+		t.Errorf("expected a lone Esc, got %+v", k)
+	}
+}
+
+type fakeBackend struct {
+	query   Query
+	exports []string
+}
+
+func (f *fakeBackend) Search(ctx context.Context, q Query) ([]models.CallElement, error) {
+	f.query = q
+	return []models.CallElement{
+		{Sid: "call-1@host", Method: "INVITE", RuriUser: "bob", SrcIP: "10.0.0.1", SrcPort: 5060, DstIP: "10.0.0.2", DstPort: 5060},
+		{Sid: "call-2", Method: "REGISTER", AliasSrc: "pbx"},
+	}, nil
+}
+
+func (f *fakeBackend) Messages(ctx context.Context, q Query, callID string) ([]*sip.Message, error) {
+	invite, _ := sip.Parse("INVITE sip:bob@b SIP/2.0\r\nCall-ID: call-1@host\r\n\r\n")
+	invite.SrcIP, invite.SrcPort, invite.DstIP, invite.DstPort = "10.0.0.1", 5060, "10.0.0.2", 5060
+	ok, _ := sip.Parse("SIP/2.0 200 OK\r\nCall-ID: call-1@host\r\n\r\n")
+	ok.SrcIP, ok.SrcPort, ok.DstIP, ok.DstPort = "10.0.0.2", 5060, "10.0.0.1", 5060
+	return []*sip.Message{invite, ok}, nil
+}
+
+func (f *fakeBackend) Export(ctx context.Context, q Query, callID, format, path string) (int64, error) {
+	f.exports = append(f.exports, format+" "+callID+" "+path)
+	return 42, nil
+}
+
+func (f *fakeBackend) Report(ctx context.Context, q Query, callID, kind string) (json.RawMessage, error) {
+	return json.RawMessage(`{"mos":4.2}`), nil
+}
+
+func (f *fakeBackend) Share(ctx context.Context, callID string) (string, error) {
+	return "https://hepic.example.com/share/" + callID, nil
+}
+
+func screen(a *App) string {
+	return strings.Join(a.Render(100, 30), "\n")
+}
+
+func typeText(a *App, s string) {
+	for _, r := range s {
+		a.Handle(Rune(r))
+	}
+}
+
+func TestApp(t *testing.T) {
+	b := &fakeBackend{}
+	a := New(context.Background(), b, Query{From: "2025-01-01"}, nil)
+	if lines := a.Render(100, 30); len(lines) != 30 || !strings.Contains(lines[2], "2025-01-01") {
+		t.Fatalf("unexpected form:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// Fill in the caller and search.
+	a.Handle(Key{Code: KeyTab})
+	a.Handle(Key{Code: KeyTab})
+	typeText(a, "alicex")
+	a.Handle(Key{Code: KeyBackspace})
+	a.Handle(Key{Code: KeyEnter})
+	if b.query.Caller != "alice" || b.query.From != "2025-01-01" {
+		t.Errorf("unexpected query %+v", b.query)
+	}
+	s := screen(a)
+	if !strings.Contains(s, "2 call(s)") || !strings.Contains(s, "call-1@host") || !strings.Contains(s, "pbx") {
+		t.Errorf("unexpected list:\n%s", s)
+	}
+
+	// Open the first call: ladder and raw message.
+	a.Handle(Key{Code: KeyEnter})
+	s = screen(a)
+	if !strings.Contains(s, "INVITE") || !strings.Contains(s, "->|") || !strings.Contains(s, "INVITE sip:bob@b SIP/2.0") {
+		t.Errorf("unexpected detail:\n%s", s)
+	}
+	a.Handle(Key{Code: KeyDown})
+	if s = screen(a); !strings.Contains(s, "2/2") || !strings.Contains(s, "SIP/2.0 200 OK") {
+		t.Errorf("expected the second message to be shown:\n%s", s)
+	}
+
+	// Export PCAP with the suggested file name.
+	a.Handle(Rune('p'))
+	if s = screen(a); !strings.Contains(s, "Save PCAP to: call-1_host.pcap_") {
+		t.Errorf("expected the export prompt:\n%s", s)
+	}
+	a.Handle(Key{Code: KeyEnter})
+	if len(b.exports) != 1 || b.exports[0] != "pcap call-1@host call-1_host.pcap" || !strings.Contains(screen(a), "Saved 42 bytes") {
+		t.Errorf("unexpected exports %v", b.exports)
+	}
+	a.Handle(Rune('t'))
+	a.Handle(Key{Code: KeyEsc})
+	if len(b.exports) != 1 {
+		t.Error("a cancelled prompt should not export")
+	}
+
+	// Reports and share links.
+	a.Handle(Rune('r'))
+	if s = screen(a); !strings.Contains(s, "QOS report for call-1@host") || !strings.Contains(s, `"mos": 4.2`) {
+		t.Errorf("unexpected report:\n%s", s)
+	}
+	a.Handle(Key{Code: KeyEsc})
+	a.Handle(Rune('c'))
+	if got := a.Clipboard(); got != "https://hepic.example.com/share/call-1@host" {
+		t.Errorf("unexpected clipboard %q", got)
+	}
+	if a.Clipboard() != "" {
+		t.Error("clipboard text should be handed out once")
+	}
+
+	// Back to the list, then quit.
+	a.Handle(Key{Code: KeyEsc})
+	if !strings.Contains(screen(a), "RURI USER") {
+		t.Error("expected the list after Esc")
+	}
+	if !a.Handle(Rune('q')) {
+		t.Error("q should quit from the list")
+	}
+}
@@ -10,8 +10,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"hepic-cli/internal/api"
+	"hepic-cli/internal/importfile"
 )
 
 // List retrieves all users. GET /users
@@ -51,6 +54,18 @@ func UpdatePassword(ctx context.Context, client *api.Client, uuid string, data i
 
 // Import imports users from a CSV file. POST /users/import (multipart file upload)
 func Import(ctx context.Context, client *api.Client, filePath string) (json.RawMessage, error) {
+	return ImportMode(ctx, client, filePath, importfile.ModeMerge, importfile.SourceCSV)
+}
+
+// ImportMode imports users with the given mode (merge or replace) and source
+// (csv or h2).
+// POST /users/import, /users/import/replace, /users/import/h2, /users/import/h2/replace
+func ImportMode(ctx context.Context, client *api.Client, filePath, mode, source string) (json.RawMessage, error) {
+	path, err := importfile.Endpoint("/users", mode, source)
+	if err != nil {
+		return nil, err
+	}
+
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
@@ -68,7 +83,7 @@ func Import(ctx context.Context, client *api.Client, filePath string) (json.RawM
 	}
 	writer.Close()
 
-	url := client.BaseURL + "/users/import"
+	url := client.BaseURL + path
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -95,6 +110,48 @@ func Import(ctx context.Context, client *api.Client, filePath string) (json.RawM
 	return json.RawMessage(respBody), nil
 }
 
+// CSVSchema validates user import files. Rows are keyed by username.
+var CSVSchema = importfile.Schema{
+	Required: []string{"username"},
+	Checks: map[string]func(string) error{
+		"email": func(v string) error {
+			if at := strings.Index(v, "@"); at <= 0 || at == len(v)-1 {
+				return fmt.Errorf("%q is not an email address", v)
+			}
+			return nil
+		},
+		"partid": func(v string) error {
+			if _, err := strconv.ParseUint(v, 10, 16); err != nil {
+				return fmt.Errorf("%q is not a number in range 0-65535", v)
+			}
+			return nil
+		},
+	},
+	Key: func(f *importfile.File, r importfile.Row) (string, error) {
+		if name := f.Get(r, "username"); name != "" {
+			return name, nil
+		}
+		return "", fmt.Errorf("no username")
+	},
+}
+
+// Usernames extracts the usernames from a List response.
+func Usernames(list json.RawMessage) ([]string, error) {
+	var resp struct {
+		Data []struct {
+			Username string `json:"username"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(list, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	names := make([]string, 0, len(resp.Data))
+	for _, u := range resp.Data {
+		names = append(names, u.Username)
+	}
+	return names, nil
+}
+
 // Export exports users as CSV. GET /users/export
 func Export(ctx context.Context, client *api.Client) (io.ReadCloser, error) {
 	return client.GetRaw(ctx, "/users/export")
@@ -427,6 +427,52 @@ func TestImport_FileNotFound(t *testing.T) {
 	}
 }
 
+func TestImportMode_Endpoints(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	tmpFile := filepath.Join(t.TempDir(), "users.csv")
+	if err := os.WriteFile(tmpFile, []byte("username\ntest\n"), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	client := api.NewClientWith(server.URL, "test-token")
+	tests := []struct {
+		mode, source, path string
+	}{
+		{"replace", "csv", "/users/import/replace"},
+		{"merge", "h2", "/users/import/h2"},
+		{"replace", "h2", "/users/import/h2/replace"},
+	}
+	for _, tt := range tests {
+		if _, err := ImportMode(context.Background(), client, tmpFile, tt.mode, tt.source); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotPath != tt.path {
+			t.Errorf("%s/%s: expected path %s, got %s", tt.mode, tt.source, tt.path, gotPath)
+		}
+	}
+
+	if _, err := ImportMode(context.Background(), client, tmpFile, "append", "csv"); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
+
+func TestUsernames(t *testing.T) {
+	names, err := Usernames(json.RawMessage(`{"count":2,"data":[{"username":"admin"},{"username":"bob"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 2 || names[0] != "admin" || names[1] != "bob" {
+		t.Errorf("unexpected usernames %v", names)
+	}
+}
+
 func TestCreateToken_SendsCorrectPost(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -1,11 +1,18 @@
 package main
 
 import (
+	_ "embed"
 	"hepic-cli/cmd"
 	"os"
 )
 
+// swaggerSpec is served by "hepic mock serve".
+//
+//go:embed swagger.json
+var swaggerSpec []byte
+
 func main() {
+	cmd.SwaggerSpec = swaggerSpec
 	if err := cmd.Execute(); err != nil {
 		os.Exit(1)
 	}
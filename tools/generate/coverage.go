@@ -0,0 +1,392 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// clientMethods maps the api.Client methods to the HTTP method they send.
+var clientMethods = map[string]string{
+	"Get":          "GET",
+	"GetRaw":       "GET",
+	"Post":         "POST",
+	"PostRaw":      "POST",
+	"PostFormFile": "POST",
+	"Put":          "PUT",
+	"Delete":       "DELETE",
+}
+
+// dynamic stands for the parts of a path only known at run time.
+const dynamic = "{}"
+
+// call is an API request found in the source: a method and a path with
+// dynamic parts.
+type call struct {
+	Method string
+	Path   string
+}
+
+// reportCoverage lists the operations that no code in cmd or internal
+// calls, either through the api.Client methods with a path or through
+// the generated ops functions.
+func reportCoverage(swagger Swagger, w io.Writer) error {
+	ops, err := operations(swagger)
+	if err != nil {
+		return err
+	}
+	calls, funcs, err := scanCalls("cmd", "internal")
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tOPERATION\tTAG")
+	missing := 0
+	for _, g := range ops {
+		if funcs[g.FuncName] || covered(g, calls) {
+			continue
+		}
+		missing++
+		tag := ""
+		if len(g.Tags) > 0 {
+			tag = g.Tags[0]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", g.Method, g.Path, g.OperationID, tag)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d of %d operations have no command\n", missing, len(ops))
+	return nil
+}
+
+func covered(g op, calls []call) bool {
+	for _, c := range calls {
+		if c.Method == g.Method && pathMatches(g.Path, c.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathMatches compares a spec path with a path found in the source
+// segment by segment; parameters and dynamic segments match anything.
+func pathMatches(spec, found string) bool {
+	a := strings.Split(strings.Trim(spec, "/"), "/")
+	b := strings.Split(strings.Trim(found, "/"), "/")
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if _, ok := pathParam(a[i]); ok || strings.Contains(b[i], dynamic) {
+			continue
+		}
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// scanCalls parses the non-test Go files under dirs, except the generated
+// ops package, and returns the client calls and the ops functions used.
+func scanCalls(dirs ...string) ([]call, map[string]bool, error) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path == filepath.Join("internal", "ops") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+			f, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			files = append(files, f)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// First the functions returning paths built from their arguments,
+	// such as importfile.Endpoint, so calls through them are followed.
+	s := &scanner{funcs: make(map[string]bool), builders: make(map[string][]string)}
+	for _, f := range files {
+		s.pkg = f.Name.Name
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil && fn.Recv == nil {
+				if paths := s.returnedPaths(fn); paths != nil {
+					s.builders[s.pkg+"."+fn.Name.Name] = paths
+				}
+			}
+		}
+	}
+	for _, f := range files {
+		s.pkg = f.Name.Name
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Body != nil {
+					s.scanFunc(decl.Body, nil)
+				}
+			case *ast.GenDecl:
+				// Function literals in package-level values, such as
+				// the RunE of a var xCmd = &cobra.Command{...}.
+				ast.Inspect(decl, func(n ast.Node) bool {
+					if lit, ok := n.(*ast.FuncLit); ok {
+						s.scanFunc(lit.Body, nil)
+						return false
+					}
+					return true
+				})
+			}
+		}
+	}
+	return s.calls, s.funcs, nil
+}
+
+type scanner struct {
+	pkg   string
+	calls []call
+	funcs map[string]bool
+	// builders are the paths functions return, with \x00 and the index
+	// of an argument standing for that argument.
+	builders map[string][]string
+}
+
+// returnedPaths returns the paths a function returns as its first result.
+func (s *scanner) returnedPaths(fn *ast.FuncDecl) []string {
+	vars := make(map[string][]string)
+	i := 0
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			vars[name.Name] = []string{"\x00" + strconv.Itoa(i)}
+			i++
+		}
+	}
+	var paths []string
+	s.scanFunc(fn.Body, vars)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if ret, ok := n.(*ast.ReturnStmt); ok && len(ret.Results) > 0 {
+			for _, p := range s.pathPatterns(ret.Results[0], vars) {
+				if strings.Contains(p, "/") {
+					paths = append(paths, p)
+				}
+			}
+		}
+		return true
+	})
+	return paths
+}
+
+// scanFunc records the calls in one function body. Paths held in local
+// variables are followed through :=, = and += assignments; vars, if not
+// nil, starts with the function's parameters.
+func (s *scanner) scanFunc(body *ast.BlockStmt, vars map[string][]string) {
+	record := vars == nil
+	if vars == nil {
+		vars = make(map[string][]string)
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				id, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				var paths []string
+				switch {
+				case len(n.Lhs) == len(n.Rhs):
+					paths = s.pathPatterns(n.Rhs[i], vars)
+				case len(n.Rhs) == 1 && i == 0:
+					// path, err := importfile.Endpoint(...)
+					paths = s.pathPatterns(n.Rhs[0], vars)
+				}
+				if paths == nil {
+					continue
+				}
+				if n.Tok == token.ADD_ASSIGN {
+					// A conditional suffix: keep the path without it too.
+					for _, prev := range vars[id.Name] {
+						for _, p := range paths {
+							vars[id.Name] = append(vars[id.Name], prev+p)
+						}
+					}
+					continue
+				}
+				vars[id.Name] = paths
+			}
+		case *ast.CallExpr:
+			if !record {
+				return true
+			}
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "ops" {
+				s.funcs[sel.Sel.Name] = true
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "http" && strings.HasPrefix(sel.Sel.Name, "NewRequest") {
+				// http.NewRequestWithContext(ctx, http.MethodPost, client.BaseURL+path, body)
+				args := n.Args
+				if sel.Sel.Name == "NewRequestWithContext" && len(args) > 0 {
+					args = args[1:]
+				}
+				if len(args) < 2 {
+					return true
+				}
+				method := httpMethod(args[0])
+				for _, p := range s.pathPatterns(args[1], vars) {
+					if p = strings.TrimPrefix(p, dynamic); method != "" && strings.HasPrefix(p, "/") {
+						s.calls = append(s.calls, call{Method: method, Path: p})
+					}
+				}
+				return true
+			}
+			method, ok := clientMethods[sel.Sel.Name]
+			if !ok || len(n.Args) < 2 {
+				return true
+			}
+			for _, p := range s.pathPatterns(n.Args[1], vars) {
+				if strings.HasPrefix(p, "/") || strings.HasPrefix(p, dynamic+"/") {
+					s.calls = append(s.calls, call{Method: method, Path: p})
+				}
+			}
+		}
+		return true
+	})
+}
+
+// pathPatterns returns the paths an expression may evaluate to, with
+// dynamic parts replaced by {} and query strings removed, or nil if the
+// expression is not a string built from literals.
+func (s *scanner) pathPatterns(e ast.Expr, vars map[string][]string) []string {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return nil
+		}
+		v, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return nil
+		}
+		if i := strings.Index(v, "?"); i >= 0 {
+			v = v[:i]
+		}
+		return []string{v}
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return nil
+		}
+		left, right := s.pathPatterns(e.X, vars), s.pathPatterns(e.Y, vars)
+		if left == nil && right == nil {
+			return nil
+		}
+		if left == nil {
+			left = []string{dynamic}
+		}
+		if right == nil {
+			right = []string{dynamic}
+		}
+		var out []string
+		for _, l := range left {
+			for _, r := range right {
+				out = append(out, l+r)
+			}
+		}
+		return out
+	case *ast.Ident:
+		return vars[e.Name]
+	case *ast.CallExpr:
+		name := ""
+		switch fun := e.Fun.(type) {
+		case *ast.Ident:
+			name = s.pkg + "." + fun.Name
+		case *ast.SelectorExpr:
+			if pkg, ok := fun.X.(*ast.Ident); ok {
+				name = pkg.Name + "." + fun.Sel.Name
+			}
+		}
+		if paths, ok := s.builders[name]; ok {
+			return s.substitute(paths, e.Args, vars)
+		}
+		// fmt.Sprintf("/troubleshooting/log/%s/%s", ...)
+		if name == "fmt.Sprintf" && len(e.Args) > 0 {
+			formats := s.pathPatterns(e.Args[0], vars)
+			for i, f := range formats {
+				for _, verb := range []string{"%s", "%d", "%v"} {
+					f = strings.ReplaceAll(f, verb, dynamic)
+				}
+				formats[i] = f
+			}
+			return formats
+		}
+	}
+	return nil
+}
+
+// substitute fills a builder's paths with the patterns of the arguments
+// it is called with.
+func (s *scanner) substitute(paths []string, args []ast.Expr, vars map[string][]string) []string {
+	var out []string
+	for _, p := range paths {
+		variants := []string{p}
+		for i := len(args) - 1; i >= 0; i-- {
+			placeholder := "\x00" + strconv.Itoa(i)
+			values := s.pathPatterns(args[i], vars)
+			if values == nil {
+				values = []string{dynamic}
+			}
+			var next []string
+			for _, v := range variants {
+				if !strings.Contains(v, placeholder) {
+					next = append(next, v)
+					continue
+				}
+				for _, val := range values {
+					next = append(next, strings.ReplaceAll(v, placeholder, val))
+				}
+			}
+			variants = next
+		}
+		for _, v := range variants {
+			if !strings.Contains(v, "\x00") {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// httpMethod returns the method of an http.MethodX constant or a string
+// literal, or "".
+func httpMethod(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.SelectorExpr:
+		return strings.ToUpper(strings.TrimPrefix(e.Sel.Name, "Method"))
+	case *ast.BasicLit:
+		m, _ := strconv.Unquote(e.Value)
+		return m
+	}
+	return ""
+}
@@ -1,12 +1,28 @@
-// Command generate reads swagger.json and produces Go model structs.
+// Command generate reads swagger.json and produces Go model structs and a
+// function for every API operation.
 //
 // Usage:
 //
 //	go run ./tools/generate
+//	go run ./tools/generate -coverage
+//
+// With -coverage, nothing is generated; instead the operations no command
+// calls are listed.
+//
+// The spec documents few constraints, so constraints.json adds some:
+// per definition, a "required" list replacing the spec's for validation
+// (the JSON tags still follow the spec) and property constraints merged
+// into the spec's properties. The file is maintained by hand and overrides
+// the spec; the generated Validate methods check only what the two state.
+// The spec has no enums, so the enum types come from constraints.json:
+// ScriptType, and UserLoginType with the internal and ldap values the
+// spec describes. servertype and the agent types stay plain strings until
+// their values are documented.
 package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,7 +32,8 @@ import (
 )
 
 type Swagger struct {
-	Definitions map[string]Definition `json:"definitions"`
+	Definitions map[string]Definition           `json:"definitions"`
+	Paths       map[string]map[string]Operation `json:"paths"`
 }
 
 type Definition struct {
@@ -24,6 +41,10 @@ type Definition struct {
 	Properties map[string]Property `json:"properties"`
 	Required   []string            `json:"required"`
 	Items      *Property           `json:"items"`
+
+	// validate lists the fields Validate requires: Required, unless
+	// constraints.json replaces it.
+	validate []string
 }
 
 type Property struct {
@@ -33,9 +54,41 @@ type Property struct {
 	Ref         string    `json:"$ref"`
 	Items       *Property `json:"items"`
 	Properties  map[string]Property `json:"properties"`
+	Enum        []string  `json:"enum"`
+	Minimum     *float64  `json:"minimum"`
+	Maximum     *float64  `json:"maximum"`
+	Pattern     string    `json:"pattern"`
+	// GoType names the type generated for an enum.
+	GoType string `json:"x-go-type"`
+}
+
+// skip lists definitions that map to primitive Go types or external types.
+var skip = map[string]bool{
+	"Duration":     true,
+	"File":         true,
+	"file":         true,
+	"IP":           true,
+	"Int":          true,
+	"JSONText":     true,
+	"RemoteLabels": true,
+	"RemoteValues": true,
+}
+
+// isModel reports whether a definition becomes a struct in models.
+func isModel(name string, def Definition) bool {
+	if skip[name] {
+		return false
+	}
+	if def.Type != "object" && def.Type != "" {
+		return false
+	}
+	return len(def.Properties) > 0
 }
 
 func main() {
+	coverage := flag.Bool("coverage", false, "List the operations no command calls instead of generating code")
+	flag.Parse()
+
 	data, err := os.ReadFile("swagger.json")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading swagger.json: %v\n", err)
@@ -48,15 +101,35 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := applyConstraints(&swagger, filepath.Join("tools", "generate", "constraints.json")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *coverage {
+		if err := reportCoverage(swagger, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	generateModels(swagger)
+	if err := generateValidate(swagger); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := generateOps(swagger); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generateModels(swagger Swagger) {
 	outDir := filepath.Join("internal", "models")
 
 	var buf strings.Builder
-	buf.WriteString("// Code generated from swagger.json — DO NOT EDIT.\n")
-	buf.WriteString("// Regenerate with: go run ./tools/generate\n\n")
-	buf.WriteString("package models\n\n")
-	buf.WriteString("import \"encoding/json\"\n\n")
-	buf.WriteString("// Ensure json import is used.\n")
-	buf.WriteString("var _ json.RawMessage\n\n")
+	used := make(map[string]bool) // packages the field types refer to
 
 	// Sorted definition names for deterministic output
 	names := make([]string, 0, len(swagger.Definitions))
@@ -65,27 +138,9 @@ func main() {
 	}
 	sort.Strings(names)
 
-	// Skip definitions that map to primitive Go types or external types
-	skip := map[string]bool{
-		"Duration":     true,
-		"File":         true,
-		"file":         true,
-		"IP":           true,
-		"Int":          true,
-		"JSONText":     true,
-		"RemoteLabels": true,
-		"RemoteValues": true,
-	}
-
 	for _, name := range names {
 		def := swagger.Definitions[name]
-		if skip[name] {
-			continue
-		}
-		if def.Type != "object" && def.Type != "" {
-			continue
-		}
-		if len(def.Properties) == 0 {
+		if !isModel(name, def) {
 			continue
 		}
 
@@ -108,6 +163,13 @@ func main() {
 		for _, pname := range propNames {
 			prop := def.Properties[pname]
 			goType := resolveType(prop)
+			if goType == "Time" && !requiredSet[pname] {
+				// Optional dates are left out rather than sent as null.
+				goType = "*Time"
+			}
+			if i := strings.Index(goType, "."); i >= 0 {
+				used[strings.TrimLeft(goType[:i], "[]*")] = true
+			}
 			goFieldName := toGoName(pname)
 			omit := ""
 			if !requiredSet[pname] {
@@ -120,8 +182,22 @@ func main() {
 		buf.WriteString("}\n\n")
 	}
 
+	var head strings.Builder
+	head.WriteString("// Code generated from swagger.json — DO NOT EDIT.\n")
+	head.WriteString("// Regenerate with: go run ./tools/generate\n\n")
+	head.WriteString("package models\n\n")
+	head.WriteString("import (\n\t\"encoding/json\"\n")
+	for _, imp := range []string{"math/big", "net/netip", "time"} {
+		if used[imp[strings.LastIndex(imp, "/")+1:]] {
+			head.WriteString(fmt.Sprintf("\t%q\n", imp))
+		}
+	}
+	head.WriteString(")\n\n")
+	head.WriteString("// Ensure json import is used.\n")
+	head.WriteString("var _ json.RawMessage\n\n")
+
 	outPath := filepath.Join(outDir, "models_generated.go")
-	if err := os.WriteFile(outPath, []byte(buf.String()), 0644); err != nil {
+	if err := os.WriteFile(outPath, []byte(head.String()+buf.String()), 0644); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outPath, err)
 		os.Exit(1)
 	}
@@ -136,13 +212,14 @@ func resolveType(p Property) string {
 		case "JSONText":
 			return "json.RawMessage"
 		case "IP":
-			return "string"
+			// net.IP marshals as text, like netip.Addr.
+			return "netip.Addr"
 		case "Int":
-			return "int64"
+			return "*big.Int"
 		case "File", "file":
 			return "string"
 		case "Duration":
-			return "int64"
+			return "time.Duration"
 		default:
 			return toGoName(refName)
 		}
@@ -151,7 +228,10 @@ func resolveType(p Property) string {
 	switch p.Type {
 	case "string":
 		if p.Format == "date-time" {
-			return "string"
+			return "Time"
+		}
+		if p.GoType != "" {
+			return p.GoType
 		}
 		return "string"
 	case "integer":
@@ -215,7 +295,7 @@ func toGoName(s string) string {
 		// Handle common acronyms
 		upper := strings.ToUpper(part)
 		switch upper {
-		case "ID", "UUID", "IP", "URL", "HTTP", "API", "JSON", "SIP", "RTP", "RTCP", "QOS", "DB", "UI", "PCAP", "CSV", "DTMF", "HEP", "TTL", "GID":
+		case "ID", "UUID", "IP", "URL", "HTTP", "API", "JSON", "SIP", "RTP", "RTCP", "QOS", "DB", "UI", "PCAP", "CSV", "DTMF", "HEP", "TTL", "GID", "LDAP":
 			result.WriteString(upper)
 		default:
 			runes := []rune(part)
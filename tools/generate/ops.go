@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Description string              `json:"description"`
+	Tags        []string            `json:"tags"`
+	Produces    []string            `json:"produces"`
+	Parameters  []Parameter         `json:"parameters"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string    `json:"name"`
+	In          string    `json:"in"`
+	Type        string    `json:"type"`
+	Description string    `json:"description"`
+	Schema      *Property `json:"schema"`
+}
+
+type Response struct {
+	Description string    `json:"description"`
+	Schema      *Property `json:"schema"`
+}
+
+// op is an operation with everything the generated function needs.
+type op struct {
+	Method   string
+	Path     string
+	FuncName string
+	Params   []string // Go names of the path parameters, in path order
+	Body     string   // Go type of the JSON body, or ""
+	File     bool     // multipart file upload
+	Binary   bool     // the response is a file
+	Result   string   // models type of the response, or ""
+	Operation
+}
+
+var methods = []string{"get", "post", "put", "delete"}
+
+// operations lists the spec's operations sorted by path and method.
+func operations(swagger Swagger) ([]op, error) {
+	paths := make([]string, 0, len(swagger.Paths))
+	for p := range swagger.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var ops []op
+	seen := make(map[string]string)
+	for _, path := range paths {
+		for _, method := range methods {
+			o, ok := swagger.Paths[path][method]
+			if !ok {
+				continue
+			}
+			g := op{Method: strings.ToUpper(method), Path: path, Operation: o}
+			g.FuncName, g.Params = funcName(g.Method, path)
+			if prev, dup := seen[g.FuncName]; dup {
+				return nil, fmt.Errorf("%s %s and %s both generate %s", g.Method, path, prev, g.FuncName)
+			}
+			seen[g.FuncName] = g.Method + " " + path
+
+			for _, p := range o.Parameters {
+				switch {
+				case p.In == "formData" && p.Type == "file":
+					g.File = true
+				case p.In == "body" && method != "get" && method != "delete":
+					// Some GETs document a body; the server ignores it.
+					g.Body = "interface{}"
+					if p.Schema != nil && p.Schema.Ref != "" {
+						if name := refName(p.Schema.Ref); isModel(name, swagger.Definitions[name]) {
+							g.Body = "models." + toGoName(name)
+						}
+					}
+				}
+			}
+			for _, p := range o.Produces {
+				if p == "application/octet-stream" {
+					g.Binary = true
+				}
+			}
+			if r := success(o); r != nil && r.Schema != nil && r.Schema.Ref != "" {
+				if name := refName(r.Schema.Ref); isModel(name, swagger.Definitions[name]) {
+					g.Result = toGoName(name)
+				}
+			}
+			ops = append(ops, g)
+		}
+	}
+	return ops, nil
+}
+
+// funcName derives a function name from the method and path, e.g.
+// DELETE /ipalias/{uuid} → DeleteIpaliasByUUID, and returns the Go names
+// of the path parameters.
+func funcName(method, path string) (string, []string) {
+	name := toGoName(strings.ToLower(method))
+	var params []string
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if p, ok := pathParam(seg); ok {
+			name += "By" + toGoName(p)
+			params = append(params, paramName(p))
+			continue
+		}
+		name += toGoName(seg)
+	}
+	return name, params
+}
+
+// pathParam returns the name of a {name} or :name path segment.
+func pathParam(seg string) (string, bool) {
+	switch {
+	case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+		return seg[1 : len(seg)-1], true
+	case strings.HasPrefix(seg, ":"):
+		return seg[1:], true
+	}
+	return "", false
+}
+
+// paramName turns a path parameter into a Go identifier: dashboardId →
+// dashboardID, type → typ.
+func paramName(p string) string {
+	parts := splitOnSeparators(p)
+	name := parts[0]
+	for _, part := range parts[1:] {
+		name += toGoName(part)
+	}
+	if token.IsKeyword(name) {
+		if name == "type" {
+			return "typ"
+		}
+		return name + "Param"
+	}
+	return name
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// success returns the first 2xx response.
+func success(o Operation) *Response {
+	codes := make([]string, 0, len(o.Responses))
+	for code := range o.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			r := o.Responses[code]
+			return &r
+		}
+	}
+	return nil
+}
+
+// pathExpr is the Go expression building the request path.
+func (g op) pathExpr() string {
+	var parts []string
+	lit := ""
+	i := 0
+	for _, seg := range strings.Split(strings.Trim(g.Path, "/"), "/") {
+		lit += "/"
+		if _, ok := pathParam(seg); ok {
+			parts = append(parts, fmt.Sprintf("%q", lit), "api.PathEscape("+g.Params[i]+")")
+			lit = ""
+			i++
+			continue
+		}
+		lit += seg
+	}
+	if lit != "" {
+		parts = append(parts, fmt.Sprintf("%q", lit))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func generateOps(swagger Swagger) error {
+	ops, err := operations(swagger)
+	if err != nil {
+		return err
+	}
+
+	var buf strings.Builder
+	buf.WriteString("// Code generated from swagger.json — DO NOT EDIT.\n")
+	buf.WriteString("// Regenerate with: go run ./tools/generate\n\n")
+	buf.WriteString(`// Package ops has a function for every operation in swagger.json, named
+// after the method and path: GET /search/remote/label is
+// GetSearchRemoteLabel, DELETE /ipalias/{uuid} is DeleteIpaliasByUUID.
+// Path parameters are escaped; bodies use the documented models type.
+//
+// The response schemas in the spec do not always match what the server
+// sends, so functions with a documented model return it together with the
+// raw body; the model is nil if the body does not decode into it.
+//
+// Packages written since these functions were generated send a request
+// through them whenever the spec describes it completely, and call
+// api.Client directly only for what it leaves out: undocumented query
+// parameters, a body model the server does not accept, or a raw proxied
+// path. Older packages keep their own requests.
+package ops
+
+`)
+	var body strings.Builder
+	imports := map[string]bool{}
+	for _, g := range ops {
+		writeOp(&body, g)
+		if g.Binary {
+			imports["io"] = true
+		} else {
+			imports["encoding/json"] = true
+		}
+		if g.Result != "" || strings.HasPrefix(g.Body, "models.") {
+			imports["hepic-cli/internal/models"] = true
+		}
+	}
+	buf.WriteString("import (\n\t\"context\"\n")
+	for _, imp := range []string{"encoding/json", "io"} {
+		if imports[imp] {
+			fmt.Fprintf(&buf, "\t%q\n", imp)
+		}
+	}
+	buf.WriteString("\n\t\"hepic-cli/internal/api\"\n")
+	if imports["hepic-cli/internal/models"] {
+		buf.WriteString("\t\"hepic-cli/internal/models\"\n")
+	}
+	buf.WriteString(")\n\n")
+	buf.WriteString(body.String())
+
+	src, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return fmt.Errorf("formatting generated ops: %w", err)
+	}
+	outPath := filepath.Join("internal", "ops", "ops_generated.go")
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, src, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Generated %s (%d operations)\n", outPath, len(ops))
+	return nil
+}
+
+func writeOp(buf *strings.Builder, g op) {
+	fmt.Fprintf(buf, "// %s calls %s %s", g.FuncName, g.Method, g.Path)
+	if g.OperationID != "" {
+		fmt.Fprintf(buf, " (%s)", g.OperationID)
+	}
+	buf.WriteString(".\n")
+	if d := strings.TrimSpace(g.Description); d != "" {
+		// Without a full stop, gofmt would turn a one-line description
+		// into a heading.
+		if !strings.ContainsAny(d[len(d)-1:], ".!?:") {
+			d += "."
+		}
+		buf.WriteString("//\n")
+		for _, line := range strings.Split(d, "\n") {
+			buf.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+		}
+	}
+	if g.Result != "" {
+		fmt.Fprintf(buf, "//\n// Documented response: %s.\n", g.Result)
+	}
+
+	args := []string{"ctx context.Context", "client *api.Client"}
+	for _, p := range g.Params {
+		args = append(args, p+" string")
+	}
+	switch {
+	case g.File:
+		args = append(args, "filePath string")
+	case g.Body != "":
+		args = append(args, "body "+g.Body)
+	}
+	body := "nil"
+	if g.Body != "" {
+		body = "body"
+	}
+	path := g.pathExpr()
+
+	if g.Binary {
+		fmt.Fprintf(buf, "func %s(%s) (io.ReadCloser, error) {\n", g.FuncName, strings.Join(args, ", "))
+		if g.Method == "GET" {
+			fmt.Fprintf(buf, "\treturn client.GetRaw(ctx, %s)\n}\n\n", path)
+		} else {
+			fmt.Fprintf(buf, "\treturn client.PostRaw(ctx, %s, %s)\n}\n\n", path, body)
+		}
+		return
+	}
+
+	var call string
+	switch {
+	case g.File:
+		// The server reads the "file" field, whatever the spec calls it.
+		call = fmt.Sprintf("client.PostFormFile(ctx, %s, \"file\", filePath, &raw)", path)
+	case g.Method == "GET":
+		call = fmt.Sprintf("client.Get(ctx, %s, &raw)", path)
+	case g.Method == "DELETE":
+		call = fmt.Sprintf("client.Delete(ctx, %s, &raw)", path)
+	default:
+		call = fmt.Sprintf("client.%s(ctx, %s, %s, &raw)", toGoName(strings.ToLower(g.Method)), path, body)
+	}
+
+	if g.Result == "" {
+		fmt.Fprintf(buf, "func %s(%s) (json.RawMessage, error) {\n", g.FuncName, strings.Join(args, ", "))
+		fmt.Fprintf(buf, "\tvar raw json.RawMessage\n\terr := %s\n\treturn raw, err\n}\n\n", call)
+		return
+	}
+	fmt.Fprintf(buf, "func %s(%s) (*models.%s, json.RawMessage, error) {\n", g.FuncName, strings.Join(args, ", "), g.Result)
+	fmt.Fprintf(buf, "\tvar raw json.RawMessage\n\tif err := %s; err != nil {\n\t\treturn nil, nil, err\n\t}\n", call)
+	fmt.Fprintf(buf, "\tvar result models.%s\n\tif err := json.Unmarshal(raw, &result); err != nil {\n\t\treturn nil, raw, nil\n\t}\n\treturn &result, raw, nil\n}\n\n", g.Result)
+}
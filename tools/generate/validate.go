@@ -0,0 +1,355 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// constraint is a definition's entry in constraints.json.
+type constraint struct {
+	Required   *[]string           `json:"required"`
+	Properties map[string]Property `json:"properties"`
+}
+
+// applyConstraints merges constraints.json into the spec's definitions and
+// names the enum types.
+func applyConstraints(swagger *Swagger, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var constraints map[string]constraint
+	if err := json.Unmarshal(data, &constraints); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for name, def := range swagger.Definitions {
+		def.validate = def.Required
+		swagger.Definitions[name] = def
+	}
+	for name, c := range constraints {
+		def, ok := swagger.Definitions[name]
+		if !ok {
+			return fmt.Errorf("%s: no definition %s", path, name)
+		}
+		if c.Required != nil {
+			def.validate = *c.Required
+		}
+		for pname, extra := range c.Properties {
+			prop, ok := def.Properties[pname]
+			if !ok {
+				return fmt.Errorf("%s: %s has no property %s", path, name, pname)
+			}
+			if extra.Format != "" {
+				prop.Format = extra.Format
+			}
+			if extra.Enum != nil {
+				prop.Enum = extra.Enum
+			}
+			if extra.Minimum != nil {
+				prop.Minimum = extra.Minimum
+			}
+			if extra.Maximum != nil {
+				prop.Maximum = extra.Maximum
+			}
+			if extra.Pattern != "" {
+				prop.Pattern = extra.Pattern
+			}
+			if extra.GoType != "" {
+				prop.GoType = extra.GoType
+			}
+			def.Properties[pname] = prop
+		}
+		swagger.Definitions[name] = def
+	}
+
+	for name, def := range swagger.Definitions {
+		for pname, prop := range def.Properties {
+			if len(prop.Enum) == 0 {
+				continue
+			}
+			if prop.Type != "string" {
+				return fmt.Errorf("%s.%s: only string enums are supported", name, pname)
+			}
+			if prop.GoType == "" {
+				prop.GoType = toGoName(name) + toGoName(pname)
+				def.Properties[pname] = prop
+			}
+		}
+	}
+	return nil
+}
+
+// enum is a string type with a fixed set of values.
+type enum struct {
+	Name   string
+	Values []string
+	Owners []string // the definition properties using it
+}
+
+// enums collects the enum types; properties sharing a type must list the
+// same values.
+func enums(swagger Swagger) ([]enum, error) {
+	byName := make(map[string]*enum)
+	for _, name := range sortedDefinitions(swagger) {
+		def := swagger.Definitions[name]
+		for _, pname := range sortedProperties(def) {
+			prop := def.Properties[pname]
+			if len(prop.Enum) == 0 {
+				continue
+			}
+			owner := name + "." + pname
+			e, ok := byName[prop.GoType]
+			if !ok {
+				byName[prop.GoType] = &enum{Name: prop.GoType, Values: prop.Enum, Owners: []string{owner}}
+				continue
+			}
+			if strings.Join(e.Values, "\x00") != strings.Join(prop.Enum, "\x00") {
+				return nil, fmt.Errorf("%s and %s both use %s with different values", e.Owners[0], owner, prop.GoType)
+			}
+			e.Owners = append(e.Owners, owner)
+		}
+	}
+	list := make([]enum, 0, len(byName))
+	for _, e := range byName {
+		list = append(list, *e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func sortedDefinitions(swagger Swagger) []string {
+	names := make([]string, 0, len(swagger.Definitions))
+	for name, def := range swagger.Definitions {
+		if isModel(name, def) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedProperties(def Definition) []string {
+	names := make([]string, 0, len(def.Properties))
+	for name := range def.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// generateValidate writes the enum types and a Validate method for every
+// model with constraints.
+func generateValidate(swagger Swagger) error {
+	list, err := enums(swagger)
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	imports := map[string]bool{}
+	for _, e := range list {
+		writeEnum(&body, e)
+		imports["strings"] = true
+	}
+	var patterns []string
+	count := 0
+	for _, name := range sortedDefinitions(swagger) {
+		var code strings.Builder
+		for _, pname := range sortedProperties(swagger.Definitions[name]) {
+			p := fieldChecks(swagger, name, pname)
+			if p.code == "" {
+				continue
+			}
+			code.WriteString(p.code)
+			patterns = append(patterns, p.pattern...)
+			for imp := range p.imports {
+				imports[imp] = true
+			}
+		}
+		if code.Len() == 0 {
+			continue
+		}
+		imports["fmt"] = true
+		count++
+		goName := toGoName(name)
+		fmt.Fprintf(&body, "// Validate checks m against the %s constraints.\n", name)
+		fmt.Fprintf(&body, "func (m %s) Validate() error {\n\treturn m.validate(false)\n}\n\n", goName)
+		fmt.Fprintf(&body, "// ValidatePartial is Validate without the required fields, for updates.\n")
+		fmt.Fprintf(&body, "func (m %s) ValidatePartial() error {\n\treturn m.validate(true)\n}\n\n", goName)
+		fmt.Fprintf(&body, "func (m %s) validate(partial bool) error {\n%s\treturn nil\n}\n\n", goName, code.String())
+	}
+
+	var buf strings.Builder
+	buf.WriteString("// Code generated from swagger.json — DO NOT EDIT.\n")
+	buf.WriteString("// Regenerate with: go run ./tools/generate\n\n")
+	buf.WriteString("package models\n\n")
+	if len(patterns) > 0 {
+		imports["regexp"] = true
+	}
+	buf.WriteString("import (\n")
+	for _, imp := range []string{"fmt", "net/netip", "regexp", "strings"} {
+		if imports[imp] {
+			fmt.Fprintf(&buf, "\t%q\n", imp)
+		}
+	}
+	buf.WriteString(")\n\n")
+	if len(patterns) > 0 {
+		buf.WriteString("var (\n")
+		for _, p := range patterns {
+			buf.WriteString(p)
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.WriteString(body.String())
+
+	src, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return fmt.Errorf("formatting generated validation: %w", err)
+	}
+	outPath := filepath.Join("internal", "models", "validate_generated.go")
+	if err := os.WriteFile(outPath, src, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Generated %s (%d enums, %d models with Validate)\n", outPath, len(list), count)
+	return nil
+}
+
+func writeEnum(buf *strings.Builder, e enum) {
+	owners := strings.Join(e.Owners, ", ")
+	fmt.Fprintf(buf, "// %s is the type of %s.\ntype %s string\n\n", e.Name, owners, e.Name)
+	buf.WriteString("const (\n")
+	for _, v := range e.Values {
+		fmt.Fprintf(buf, "\t%s%s %s = %q\n", e.Name, toGoName(v), e.Name, v)
+	}
+	buf.WriteString(")\n\n")
+	fmt.Fprintf(buf, "// %sValues lists the valid %s values.\nvar %sValues = []%s{", e.Name, e.Name, e.Name, e.Name)
+	for i, v := range e.Values {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(e.Name + toGoName(v))
+	}
+	buf.WriteString("}\n\n")
+	fmt.Fprintf(buf, "// Valid reports whether v is one of the %s values.\n", e.Name)
+	fmt.Fprintf(buf, "func (v %s) Valid() bool {\n\tfor _, x := range %sValues {\n\t\tif v == x {\n\t\t\treturn true\n\t\t}\n\t}\n\treturn false\n}\n\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "func (v %s) choices() string {\n\tnames := make([]string, len(%sValues))\n", e.Name, e.Name)
+	fmt.Fprintf(buf, "\tfor i, x := range %sValues {\n\t\tnames[i] = string(x)\n\t}\n\treturn strings.Join(names, \", \")\n}\n\n", e.Name)
+}
+
+// checks is the validation code for one field.
+type checks struct {
+	code    string
+	pattern []string // package-level regexp declarations
+	imports map[string]bool
+}
+
+// fieldChecks returns the checks for a property: required, then enum,
+// range, pattern and IP address format. Only required fields are checked
+// when empty, as the zero value also stands for "not set".
+func fieldChecks(swagger Swagger, defName, pname string) checks {
+	def := swagger.Definitions[defName]
+	prop := def.Properties[pname]
+	field := "m." + toGoName(pname)
+	goType := resolveType(prop)
+	required := false
+	for _, r := range def.Required {
+		if r == pname {
+			required = true
+		}
+	}
+	if goType == "Time" && !required {
+		goType = "*Time"
+	}
+
+	c := checks{imports: map[string]bool{}}
+	var code strings.Builder
+	for _, r := range def.validate {
+		if r != pname {
+			continue
+		}
+		if empty := emptyCheck(field, goType, len(prop.Enum) > 0); empty != "" {
+			fmt.Fprintf(&code, "\tif !partial && %s {\n\t\treturn fmt.Errorf(\"%s is required\")\n\t}\n", empty, pname)
+		}
+	}
+
+	switch {
+	case len(prop.Enum) > 0:
+		fmt.Fprintf(&code, "\tif %s != \"\" && !%s.Valid() {\n", field, field)
+		fmt.Fprintf(&code, "\t\treturn fmt.Errorf(\"invalid %s %%q: must be one of %%s\", %s, %s.choices())\n\t}\n", pname, field, field)
+	case prop.Type == "integer" || prop.Type == "number":
+		if prop.Minimum != nil {
+			fmt.Fprintf(&code, "\tif %s != 0 && %s < %s {\n", field, field, number(*prop.Minimum))
+			fmt.Fprintf(&code, "\t\treturn fmt.Errorf(\"invalid %s %%v: must be at least %s\", %s)\n\t}\n", pname, number(*prop.Minimum), field)
+		}
+		if prop.Maximum != nil {
+			fmt.Fprintf(&code, "\tif %s > %s {\n", field, number(*prop.Maximum))
+			fmt.Fprintf(&code, "\t\treturn fmt.Errorf(\"invalid %s %%v: must be at most %s\", %s)\n\t}\n", pname, number(*prop.Maximum), field)
+		}
+	case goType == "string":
+		if prop.Pattern != "" {
+			re := toGoName(defName) + toGoName(pname) + "Pattern"
+			re = strings.ToLower(re[:1]) + re[1:]
+			c.pattern = append(c.pattern, fmt.Sprintf("\t%s = regexp.MustCompile(%s)\n", re, strconv.Quote(prop.Pattern)))
+			fmt.Fprintf(&code, "\tif %s != \"\" && !%s.MatchString(%s) {\n", field, re, field)
+			fmt.Fprintf(&code, "\t\treturn fmt.Errorf(\"invalid %s %%q: must match %%s\", %s, %s)\n\t}\n", pname, field, re)
+		}
+		if test, ok := addrFormats[prop.Format]; ok {
+			c.imports["net/netip"] = true
+			addr := "addr"
+			if test == "" {
+				addr = "_"
+			}
+			fmt.Fprintf(&code, "\tif %s != \"\" {\n\t\tif %s, err := netip.ParseAddr(%s); err != nil%s {\n", field, addr, field, test)
+			fmt.Fprintf(&code, "\t\t\treturn fmt.Errorf(\"invalid %s %%q: not an %s address\", %s)\n\t\t}\n\t}\n", pname, addrNames[prop.Format], field)
+		}
+	}
+	c.code = code.String()
+	return c
+}
+
+// addrFormats are the string formats checked with netip.ParseAddr, with
+// the extra condition rejecting a parsed address.
+var addrFormats = map[string]string{
+	"ip":   "",
+	"ipv4": " || !addr.Is4()",
+	"ipv6": " || !addr.Is6()",
+}
+
+var addrNames = map[string]string{
+	"ip":   "IP",
+	"ipv4": "IPv4",
+	"ipv6": "IPv6",
+}
+
+// emptyCheck returns the condition for an unset field of the given type,
+// or "" if unset cannot be told apart from a valid value, as with bools
+// and structs.
+func emptyCheck(field, goType string, enum bool) string {
+	switch {
+	case goType == "string" || enum:
+		return field + " == \"\""
+	case strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["), goType == "json.RawMessage":
+		return "len(" + field + ") == 0"
+	case strings.HasPrefix(goType, "*"):
+		return field + " == nil"
+	case goType == "Time":
+		return field + ".IsZero()"
+	case goType == "netip.Addr":
+		return "!" + field + ".IsValid()"
+	case goType == "time.Duration", goType == "float64", strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"):
+		return field + " == 0"
+	}
+	return ""
+}
+
+// number formats a constraint for Go source: 128, not 128.000000.
+func number(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}